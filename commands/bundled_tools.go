@@ -0,0 +1,95 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package commands
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arduino/arduino-cli/internal/arduino/cores"
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
+	semver "go.bug.st/relaxed-semver"
+)
+
+var (
+	bundledCtagsOnce sync.Once
+	bundledCtags     *paths.Path
+)
+
+// bundledCtagsDir returns the folder containing the ctags bundled with the
+// CLI, that is the "tools/ctags" folder next to the CLI executable. It returns
+// nil if the folder doesn't contain a working ctags executable.
+func bundledCtagsDir() *paths.Path {
+	bundledCtagsOnce.Do(func() {
+		bundledCtags = findBundledCtags()
+	})
+	return bundledCtags
+}
+
+func findBundledCtags() *paths.Path {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	dir := paths.New(exe).Parent().Join("tools", "ctags")
+	ctags := dir.Join("ctags")
+	if !ctags.Exist() {
+		ctags = dir.Join("ctags.exe")
+	}
+	if !ctags.Exist() {
+		return nil
+	}
+
+	// Check that the bundled executable is a working ctags
+	proc, err := paths.NewProcess(nil, ctags.String(), "--version")
+	if err != nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stdout, _, err := proc.RunAndCaptureOutput(ctx)
+	if err != nil || !strings.Contains(string(stdout), "Ctags") {
+		logrus.WithField("path", ctags).Warn("Ignoring invalid bundled ctags")
+		return nil
+	}
+	return dir
+}
+
+// useBundledCtags makes the "builtin" ctags tool point to the copy bundled
+// with the CLI, if available, so that it's not downloaded. It returns true if
+// the bundled ctags is used.
+func useBundledCtags(builtinPackage *cores.Package) bool {
+	dir := bundledCtagsDir()
+	if dir == nil {
+		return false
+	}
+	tool := builtinPackage.GetOrCreateTool("ctags")
+	release := tool.LatestRelease()
+	if release == nil {
+		release = tool.GetOrCreateRelease(semver.ParseRelaxed("0.0.0-bundled"))
+	}
+	logrus.WithField("path", dir).Info("Using bundled ctags")
+	release.InstallDir = dir
+	return true
+}
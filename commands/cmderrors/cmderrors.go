@@ -698,6 +698,49 @@ func (e *CompileFailedError) ToRPCStatus() *status.Status {
 	return status.New(codes.Internal, e.Error())
 }
 
+// CompileInterruptedError is returned when the compilation is interrupted before completion
+type CompileInterruptedError struct {
+	Cause error
+}
+
+func (e *CompileInterruptedError) Error() string {
+	return composeErrorMsg(tr("Compilation interrupted"), e.Cause)
+}
+
+func (e *CompileInterruptedError) Unwrap() error {
+	return e.Cause
+}
+
+// ToRPCStatus converts the error into a *status.Status
+func (e *CompileInterruptedError) ToRPCStatus() *status.Status {
+	return status.New(codes.Canceled, e.Error())
+}
+
+// OutOfDiskSpaceError is returned when the disk containing the build path runs out of space
+type OutOfDiskSpaceError struct {
+	Path *paths.Path
+	// Available is the number of bytes available on the disk, -1 if unknown
+	Available int64
+	Cause     error
+}
+
+func (e *OutOfDiskSpaceError) Error() string {
+	if e.Available < 0 {
+		return composeErrorMsg(tr("Out of disk space in build path %s", e.Path), e.Cause)
+	}
+	msg := tr("Out of disk space in build path %[1]s (%[2]d bytes available)", e.Path, e.Available)
+	return composeErrorMsg(msg, e.Cause)
+}
+
+func (e *OutOfDiskSpaceError) Unwrap() error {
+	return e.Cause
+}
+
+// ToRPCStatus converts the error into a *status.Status
+func (e *OutOfDiskSpaceError) ToRPCStatus() *status.Status {
+	return status.New(codes.ResourceExhausted, e.Error())
+}
+
 // InvalidArgumentError is returned when an invalid argument is passed to the command
 type InvalidArgumentError struct {
 	Message string
@@ -0,0 +1,138 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+
+	"github.com/arduino/go-paths-helper"
+)
+
+// archiveManifestName is the name of the manifest describing the contents of
+// the archive created with the Archive option
+const archiveManifestName = "manifest.json"
+
+// archiveManifest describes the build artifacts collected in an archive
+type archiveManifest struct {
+	Sketch    string                     `json:"sketch"`
+	Fqbn      string                     `json:"fqbn"`
+	Artifacts []*archiveManifestArtifact `json:"artifacts"`
+}
+
+type archiveManifestArtifact struct {
+	Name   string `json:"name"`
+	Type   string `json:"type,omitempty"`
+	Size   int64  `json:"size"`
+	Sha256 string `json:"sha256"`
+}
+
+// archiveArtifacts writes a zip file containing the given artifacts and a
+// manifest describing them, all inside a top-level folder. The type of each
+// artifact is obtained with artifactType. The archive is written in a
+// temporary file that replaces the archive only when complete, so an existing
+// archive is never left half-written.
+func archiveArtifacts(archivePath *paths.Path, folder, sketchName, fqbn string, artifacts paths.PathList, artifactType func(*paths.Path) string) error {
+	manifest := &archiveManifest{
+		Sketch:    sketchName,
+		Fqbn:      fqbn,
+		Artifacts: []*archiveManifestArtifact{},
+	}
+	for _, artifact := range artifacts {
+		info, err := artifact.Stat()
+		if err != nil {
+			return err
+		}
+		sha256, _, err := artifactChecksums(artifact, false)
+		if err != nil {
+			return err
+		}
+		manifest.Artifacts = append(manifest.Artifacts, &archiveManifestArtifact{
+			Name:   artifact.Base(),
+			Type:   artifactType(artifact),
+			Size:   info.Size(),
+			Sha256: sha256,
+		})
+	}
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := archivePath.Parent().MkdirAll(); err != nil {
+		return err
+	}
+	tmpArchivePath := archivePath.Parent().Join(archivePath.Base() + ".tmp")
+	if err := writeArchive(tmpArchivePath, folder, artifacts, manifestData); err != nil {
+		tmpArchivePath.Remove()
+		return err
+	}
+	if err := tmpArchivePath.Rename(archivePath); err != nil {
+		tmpArchivePath.Remove()
+		return err
+	}
+	return nil
+}
+
+// writeArchive writes the zip archive with the given artifacts and manifest
+func writeArchive(archivePath *paths.Path, folder string, artifacts paths.PathList, manifestData []byte) error {
+	archive, err := archivePath.Create()
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+	zipWriter := zip.NewWriter(archive)
+	for _, artifact := range artifacts {
+		if err := addFileToArchive(zipWriter, artifact, folder+"/"+artifact.Base()); err != nil {
+			return err
+		}
+	}
+	if w, err := zipWriter.Create(folder + "/" + archiveManifestName); err != nil {
+		return err
+	} else if _, err := w.Write(manifestData); err != nil {
+		return err
+	}
+	if err := zipWriter.Close(); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+// addFileToArchive adds the file to the zip archive with the given name
+func addFileToArchive(zipWriter *zip.Writer, file *paths.Path, name string) error {
+	f, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	header.Method = zip.Deflate
+	w, err := zipWriter.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
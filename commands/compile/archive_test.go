@@ -0,0 +1,64 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveArtifacts(t *testing.T) {
+	tmp := paths.New(t.TempDir())
+	hex := tmp.Join("Blink.ino.hex")
+	elf := tmp.Join("Blink.ino.elf")
+	require.NoError(t, hex.WriteFile([]byte("hex")))
+	require.NoError(t, elf.WriteFile([]byte("elf")))
+
+	archivePath := tmp.Join("release", "Blink.zip")
+	artifactType := func(p *paths.Path) string { return p.Ext()[1:] }
+	err := archiveArtifacts(archivePath, "Blink_arduino.avr.uno", "Blink", "arduino:avr:uno", paths.NewPathList(hex.String(), elf.String()), artifactType)
+	require.NoError(t, err)
+	require.NoFileExists(t, tmp.Join("release", "Blink.zip.tmp").String())
+
+	archive, err := zip.OpenReader(archivePath.String())
+	require.NoError(t, err)
+	defer archive.Close()
+	names := []string{}
+	for _, f := range archive.File {
+		names = append(names, f.Name)
+	}
+	require.Equal(t, []string{"Blink_arduino.avr.uno/Blink.ino.hex", "Blink_arduino.avr.uno/Blink.ino.elf", "Blink_arduino.avr.uno/manifest.json"}, names)
+
+	f, err := archive.File[2].Open()
+	require.NoError(t, err)
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	require.NoError(t, err)
+	var manifest archiveManifest
+	require.NoError(t, json.Unmarshal(data, &manifest))
+	require.Equal(t, "Blink", manifest.Sketch)
+	require.Equal(t, "arduino:avr:uno", manifest.Fqbn)
+	require.Len(t, manifest.Artifacts, 2)
+	require.Equal(t, "Blink.ino.hex", manifest.Artifacts[0].Name)
+	require.Equal(t, "hex", manifest.Artifacts[0].Type)
+	require.Equal(t, int64(3), manifest.Artifacts[0].Size)
+	require.Equal(t, "128df13c1e54ffaaafcc9d07ec7427d61f764214e6ae0321de23c94d261d0860", manifest.Artifacts[0].Sha256)
+}
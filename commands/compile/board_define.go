@@ -0,0 +1,65 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/arduino/go-properties-orderedmap"
+)
+
+// boardDefinePrefix is the prefix that the platform recipes add to the value
+// of build.board to compose the board define, as in "-DARDUINO_{build.board}"
+const boardDefinePrefix = "ARDUINO_"
+
+var validBuildBoardRegexp = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// buildBoardFromDefine returns the value of build.board producing the given
+// board define. The define may be given with or without the "ARDUINO_"
+// prefix.
+func buildBoardFromDefine(define string) (string, error) {
+	buildBoard := strings.TrimPrefix(define, boardDefinePrefix)
+	if !validBuildBoardRegexp.MatchString(buildBoard) {
+		return "", fmt.Errorf(tr("invalid board define '%s': only letters, digits and underscores are allowed"), define)
+	}
+	return buildBoard, nil
+}
+
+// recipesDefineBoard returns true if all the compile recipes of the platform,
+// once expanded with the given build properties, contain the given board
+// define. The platforms usually derive the define from build.board with a
+// "-DARDUINO_{build.board}" flag, but some of them hardcode it.
+func recipesDefineBoard(buildProperties *properties.Map, define string) bool {
+	for _, recipe := range []string{"recipe.c.o.pattern", "recipe.cpp.o.pattern", "recipe.S.o.pattern"} {
+		pattern, ok := buildProperties.GetOk(recipe)
+		if !ok {
+			continue
+		}
+		defined := false
+		for _, flag := range strings.Fields(buildProperties.ExpandPropsInString(pattern)) {
+			if flag == "-D"+define || strings.HasPrefix(flag, "-D"+define+"=") {
+				defined = true
+				break
+			}
+		}
+		if !defined {
+			return false
+		}
+	}
+	return true
+}
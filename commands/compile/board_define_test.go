@@ -0,0 +1,60 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"testing"
+
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildBoardFromDefine(t *testing.T) {
+	for define, expected := range map[string]string{
+		"MY_BOARD":         "MY_BOARD",
+		"ARDUINO_MY_BOARD": "MY_BOARD",
+		"AVR_UNO":          "AVR_UNO",
+		"ARDUINO_3D_BOARD": "3D_BOARD",
+	} {
+		buildBoard, err := buildBoardFromDefine(define)
+		require.NoError(t, err, define)
+		require.Equal(t, expected, buildBoard, define)
+	}
+	for _, define := range []string{"", "ARDUINO_", "MY-BOARD", "MY BOARD", "BOARD=1"} {
+		_, err := buildBoardFromDefine(define)
+		require.Error(t, err, define)
+	}
+}
+
+func TestRecipesDefineBoard(t *testing.T) {
+	props := properties.NewFromHashmap(map[string]string{
+		"build.board":          "AVR_UNO",
+		"recipe.c.o.pattern":   "gcc -c -DARDUINO_{build.board} {source_file}",
+		"recipe.cpp.o.pattern": "g++ -c -DARDUINO_{build.board} {source_file}",
+		"recipe.S.o.pattern":   "gcc -c -x assembler-with-cpp -DARDUINO_{build.board} {source_file}",
+	})
+	require.True(t, recipesDefineBoard(props, "ARDUINO_AVR_UNO"))
+	require.False(t, recipesDefineBoard(props, "ARDUINO_AVR_NANO"))
+
+	// A hardcoded define doesn't follow build.board
+	props.Set("recipe.S.o.pattern", "gcc -c -x assembler-with-cpp -DARDUINO_AVR_UNO {source_file}")
+	props.Set("build.board", "MY_BOARD")
+	require.False(t, recipesDefineBoard(props, "ARDUINO_MY_BOARD"))
+
+	// Missing recipes are ignored
+	props.Remove("recipe.S.o.pattern")
+	require.True(t, recipesDefineBoard(props, "ARDUINO_MY_BOARD"))
+}
@@ -0,0 +1,48 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/arduino/arduino-cli/internal/arduino/cores"
+)
+
+// bootloaderMenu is the board menu used by the platforms to define the
+// bootloaders available for a board. Each option of the menu contains the
+// build properties (memory size, fuses, etc.) that depend on the bootloader.
+const bootloaderMenu = "bootloader"
+
+// bootloaderFQBN returns a copy of the given FQBN that selects the given
+// bootloader in the bootloader menu of the board. The returned error lists
+// the valid bootloaders.
+func bootloaderFQBN(board *cores.Board, fqbn *cores.FQBN, bootloader string) (*cores.FQBN, error) {
+	values := board.GetConfigOptionValues(bootloaderMenu)
+	if values == nil || values.Size() == 0 {
+		return nil, fmt.Errorf(tr("board %[1]s doesn't define any bootloader in the '%[2]s' menu"), board.FQBN(), bootloaderMenu)
+	}
+	if !values.ContainsKey(bootloader) {
+		return nil, fmt.Errorf(tr("invalid bootloader '%[1]s' for board %[2]s, valid bootloaders are: %[3]s"),
+			bootloader, board.FQBN(), strings.Join(values.Keys(), ", "))
+	}
+	if selected, ok := fqbn.Configs.GetOk(bootloaderMenu); ok && selected != bootloader {
+		return nil, fmt.Errorf(tr("the bootloader '%[1]s' conflicts with the option %[2]s=%[3]s of the FQBN"), bootloader, bootloaderMenu, selected)
+	}
+	res := fqbn.Clone()
+	res.Configs.Set(bootloaderMenu, bootloader)
+	return res, nil
+}
@@ -0,0 +1,60 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/internal/arduino/cores"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBootloaderFQBN(t *testing.T) {
+	platform := &cores.PlatformRelease{
+		Platform: &cores.Platform{Architecture: "avr", Package: &cores.Package{Name: "test"}},
+		Menus:    properties.NewFromHashmap(map[string]string{"bootloader": "Bootloader"}),
+	}
+	boardProperties := properties.NewMap()
+	boardProperties.Set("menu.bootloader.optiboot", "Optiboot")
+	boardProperties.Set("menu.bootloader.optiboot.upload.maximum_size", "32256")
+	boardProperties.Set("menu.bootloader.optiboot.bootloader.high_fuses", "0xD6")
+	boardProperties.Set("menu.bootloader.none", "No bootloader")
+	boardProperties.Set("menu.bootloader.none.upload.maximum_size", "32768")
+	boardProperties.Set("menu.bootloader.none.bootloader.high_fuses", "0xD7")
+	board := &cores.Board{BoardID: "mini", Properties: boardProperties, PlatformRelease: platform}
+	fqbn := cores.MustParseFQBN("test:avr:mini")
+
+	res, err := bootloaderFQBN(board, fqbn, "none")
+	require.NoError(t, err)
+	require.Equal(t, "test:avr:mini:bootloader=none", res.String())
+	require.Equal(t, "test:avr:mini", fqbn.String())
+
+	// The same bootloader may already be selected in the FQBN
+	res, err = bootloaderFQBN(board, cores.MustParseFQBN("test:avr:mini:bootloader=none"), "none")
+	require.NoError(t, err)
+	require.Equal(t, "test:avr:mini:bootloader=none", res.String())
+
+	_, err = bootloaderFQBN(board, cores.MustParseFQBN("test:avr:mini:bootloader=optiboot"), "none")
+	require.ErrorContains(t, err, "conflicts with the option bootloader=optiboot")
+
+	_, err = bootloaderFQBN(board, fqbn, "missing")
+	require.ErrorContains(t, err, "valid bootloaders are: optiboot, none")
+
+	noMenuBoard := &cores.Board{BoardID: "uno", Properties: properties.NewMap(), PlatformRelease: platform}
+	_, err = bootloaderFQBN(noMenuBoard, cores.MustParseFQBN("test:avr:uno"), "none")
+	require.ErrorContains(t, err, "board test:avr:uno doesn't define any bootloader")
+}
@@ -0,0 +1,418 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/arduino/arduino-cli/commands/cmderrors"
+	"github.com/arduino/arduino-cli/commands/internal/instances"
+	"github.com/arduino/arduino-cli/internal/arduino/builder"
+	"github.com/arduino/arduino-cli/internal/arduino/cores"
+	"github.com/arduino/arduino-cli/internal/arduino/cores/packagemanager"
+	"github.com/arduino/arduino-cli/internal/arduino/sketch"
+	"github.com/arduino/arduino-cli/internal/cli/configuration"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	paths "github.com/arduino/go-paths-helper"
+	properties "github.com/arduino/go-properties-orderedmap"
+)
+
+// BuildProperties returns the build properties resolved for the given
+// request, the same listed by the ShowProperties option, without compiling
+// the sketch and without writing anything to disk. The board options, the
+// custom build properties and the other settings of the request are applied.
+// The values are not expanded, use ExpandPropsInString to expand them. The
+// warnings are written to errStream.
+func BuildProperties(ctx context.Context, req *rpc.CompileRequest, errStream io.Writer) (*properties.Map, error) {
+	pme, release, err := instances.GetPackageManagerExplorer(req.GetInstance())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	target, err := resolveBuildTarget(pme, req, errStream)
+	if err != nil {
+		return nil, err
+	}
+	return target.buildProperties, nil
+}
+
+// buildTarget is what a compile request resolves to before the build starts:
+// the sketch, the board and its platforms, the tools and the build properties.
+type buildTarget struct {
+	sketch                 *sketch.Sketch
+	fqbn                   *cores.FQBN
+	targetBoard            *cores.Board
+	targetPlatform         *cores.PlatformRelease
+	buildPlatform          *cores.PlatformRelease
+	boardBuildProperties   *properties.Map
+	requiredTools          []*cores.ToolRelease
+	hardwareDirs           paths.PathList
+	buildPath              *paths.Path
+	requestBuildProperties []string
+	fileFlags              map[string]string
+	overriddenRecipes      []recipeOverride
+	buildProperties        *properties.Map
+}
+
+// resolveBuildTarget resolves the sketch, the board, the platforms and the
+// build properties of the given request. It has no side effects: neither the
+// build path nor the build cache are created, so it can be used to inspect
+// the build properties without running a build.
+func resolveBuildTarget(pme *packagemanager.Explorer, req *rpc.CompileRequest, errStream io.Writer) (*buildTarget, error) {
+	if req.GetSketchPath() == "" {
+		return nil, &cmderrors.MissingSketchPathError{}
+	}
+	sketchPath := paths.New(req.GetSketchPath())
+	var sk *sketch.Sketch
+	var err error
+	if mainFile := req.GetMainFile(); mainFile != "" {
+		sk, err = sketch.NewWithMainFile(sketchPath, mainFile, !req.GetNoFollowSymlinks())
+	} else if req.GetNoFollowSymlinks() {
+		sk, err = sketch.NewWithoutFollowingSymlinks(sketchPath)
+	} else {
+		sk, err = sketch.New(sketchPath)
+	}
+	if err != nil {
+		return nil, &cmderrors.CantOpenSketchError{Cause: err}
+	}
+	// The excluded files are removed from the sketch files recorded in the
+	// build options, so a change of the excluded files forces a full rebuild
+	// and the copies left in the build path by a previous build are removed
+	if _, err := sk.ExcludeFiles(req.GetExcludeFiles()...); err != nil {
+		return nil, &cmderrors.InvalidArgumentError{Message: tr("Invalid excluded file"), Cause: err}
+	}
+
+	fqbnIn := req.GetFqbn()
+	if fqbnIn == "" && sk != nil {
+		if pme.GetProfile() != nil {
+			fqbnIn = pme.GetProfile().FQBN
+		} else {
+			fqbnIn = sk.GetDefaultFQBN()
+		}
+	}
+	if fqbnIn == "" {
+		return nil, &cmderrors.MissingFQBNError{}
+	}
+
+	// Without any hardware directory no platform can be found, fail early
+	// instead of reporting a missing platform. The platforms of a profile are
+	// installed in the profiles cache, outside of the hardware directories.
+	hardwareDirs := configuration.HardwareDirectories(configuration.Settings)
+	if len(hardwareDirs) == 0 && pme.GetProfile() == nil {
+		return nil, &cmderrors.NotFoundError{
+			Message: tr("No hardware directory found: install a platform with '%[1]s' or check the '%[2]s' and '%[3]s' settings", "core install", "directories.data", "directories.user"),
+		}
+	}
+
+	fqbn, err := cores.ParseFQBN(fqbnIn)
+	if err != nil {
+		return nil, &cmderrors.InvalidFQBNError{Cause: err}
+	}
+	if req.GetStrictFqbn() {
+		if err := checkStrictFQBN(pme, fqbn); err != nil {
+			return nil, &cmderrors.InvalidFQBNError{Cause: err}
+		}
+	}
+	_, targetPlatform, targetBoard, boardBuildProperties, buildPlatform, err := pme.ResolveFQBN(fqbn)
+	if err != nil {
+		if targetPlatform == nil {
+			return nil, &cmderrors.PlatformNotFoundError{
+				Platform: fmt.Sprintf("%s:%s", fqbn.Package, fqbn.PlatformArch),
+				Cause:    fmt.Errorf(tr("platform not installed")),
+			}
+		}
+		return nil, &cmderrors.InvalidFQBNError{Cause: err}
+	}
+	if bootloader := req.GetBootloader(); bootloader != "" {
+		// The bootloader is selected through the board menu, the build
+		// properties of the board are resolved again with the new option
+		fqbn, err = bootloaderFQBN(targetBoard, fqbn, bootloader)
+		if err != nil {
+			return nil, &cmderrors.InvalidArgumentError{Message: tr("Invalid bootloader"), Cause: err}
+		}
+		_, targetPlatform, targetBoard, boardBuildProperties, buildPlatform, err = pme.ResolveFQBN(fqbn)
+		if err != nil {
+			return nil, &cmderrors.InvalidFQBNError{Cause: err}
+		}
+	}
+
+	requiredTools, err := pme.FindToolsRequiredForBuild(targetPlatform, buildPlatform)
+	if err != nil {
+		return nil, err
+	}
+	if requiredTools, err = overrideTools(pme.GetAllInstalledToolsReleases(), requiredTools, req.GetToolOverrides(), boardBuildProperties); err != nil {
+		return nil, err
+	}
+
+	// Setup sign keys if requested
+	if req.GetKeysKeychain() != "" {
+		boardBuildProperties.Set("build.keys.keychain", req.GetKeysKeychain())
+	}
+	if req.GetSignKey() != "" {
+		boardBuildProperties.Set("build.keys.sign_key", req.GetSignKey())
+	}
+	if req.GetEncryptKey() != "" {
+		boardBuildProperties.Set("build.keys.encrypt_key", req.GetEncryptKey())
+	}
+	// At the current time we do not have a way of knowing if a board supports the secure boot or not,
+	// so, if the flags to override the default keys are used, we try override the corresponding platform property nonetheless.
+	// It's not possible to use the default name for the keys since there could be more tools to sign and encrypt.
+	// So it's mandatory to use all three flags to sign and encrypt the binary
+	keychainProp := boardBuildProperties.ContainsKey("build.keys.keychain")
+	signProp := boardBuildProperties.ContainsKey("build.keys.sign_key")
+	encryptProp := boardBuildProperties.ContainsKey("build.keys.encrypt_key")
+	// we verify that all the properties for the secure boot keys are defined or none of them is defined.
+	if !(keychainProp == signProp && signProp == encryptProp) {
+		return nil, fmt.Errorf(tr("Firmware encryption/signing requires all the following properties to be defined: %s", "build.keys.keychain, build.keys.sign_key, build.keys.encrypt_key"))
+	}
+
+	// Generate or retrieve build path
+	var buildPath *paths.Path
+	if buildPathArg := req.GetBuildPath(); buildPathArg != "" {
+		if req.GetNoFollowSymlinks() {
+			buildPath, err = paths.New(buildPathArg).Abs()
+		} else {
+			buildPath, err = resolveSymlinks(paths.New(buildPathArg))
+		}
+		if err != nil {
+			return nil, &cmderrors.InvalidArgumentError{Message: tr("Invalid build path"), Cause: err}
+		}
+		// The intermediate files in a build path inside the sketch folder
+		// could be picked up as sketch sources
+		if buildPath.Canonical().EqualsTo(sk.FullPath.Canonical()) {
+			return nil, &cmderrors.InvalidArgumentError{Message: tr("The build path can't be the sketch folder %s, please specify a different build path", sk.FullPath)}
+		}
+		if in, _ := buildPath.Canonical().IsInsideDir(sk.FullPath.Canonical()); in {
+			return nil, &cmderrors.InvalidArgumentError{Message: tr("The build path can't be inside the sketch folder %s, please specify a different build path", sk.FullPath)}
+		}
+	}
+	if buildPath == nil {
+		buildPath = sk.DefaultBuildPath()
+	}
+
+	requestBuildProperties, err := newRequestProperties(req.GetBuildProperties())
+	if err != nil {
+		return nil, &cmderrors.InvalidArgumentError{Message: tr("Invalid build properties"), Cause: err}
+	}
+	if ideVersion := req.GetIdeVersion(); ideVersion != "" {
+		number, err := ideVersionNumber(ideVersion)
+		if err != nil {
+			return nil, &cmderrors.InvalidArgumentError{Message: tr("Invalid IDE version"), Cause: err}
+		}
+		// ide_version is the deprecated name of runtime.ide.version, still
+		// used by some platforms
+		requestBuildProperties.add("runtime.ide.version="+number, "ide_version="+number)
+	}
+	var fileFlags map[string]string
+	if len(req.GetFileFlags()) > 0 {
+		excludedDirs := paths.PathList{}
+		for _, key := range []string{"build.core.path", "build.variant.path"} {
+			if dir := boardBuildProperties.GetPath(key); dir != nil && boardBuildProperties.Get(key) != "" {
+				excludedDirs.Add(dir)
+			}
+		}
+		fileFlags, err = parseFileFlags(req.GetFileFlags(), sk.FullPath, excludedDirs)
+		if err != nil {
+			return nil, &cmderrors.InvalidArgumentError{Message: tr("Invalid file flags"), Cause: err}
+		}
+		requestBuildProperties.add("build.file_flags=" + fileFlagsBuildProperty(fileFlags))
+	}
+	extraFlags := req.GetExtraFlags()
+	if len(req.GetDefines()) > 0 {
+		// Defines are added to the extra flags of all the compilers
+		defines := []string{}
+		for _, define := range req.GetDefines() {
+			flag, err := defineFlag(define)
+			if err != nil {
+				return nil, &cmderrors.InvalidArgumentError{Message: tr("Invalid define"), Cause: err}
+			}
+			defines = append(defines, "all:"+flag)
+		}
+		extraFlags = append(defines, extraFlags...)
+	}
+	if boardDefine := req.GetBoardDefine(); boardDefine != "" {
+		// The board define is derived by the platform recipes from build.board,
+		// the define is added explicitly only if the recipes don't use it
+		buildBoard, err := buildBoardFromDefine(boardDefine)
+		if err != nil {
+			return nil, &cmderrors.InvalidArgumentError{Message: tr("Invalid board define"), Cause: err}
+		}
+		if value, ok := requestBuildProperties.custom.GetOk("build.board"); ok && value != buildBoard {
+			return nil, &cmderrors.InvalidArgumentError{Message: tr("The board define conflicts with the build property %[1]s=%[2]s", "build.board", value)}
+		}
+		requestBuildProperties.add("build.board=" + buildBoard)
+		if define := boardDefinePrefix + buildBoard; !recipesDefineBoard(requestBuildProperties.over(boardBuildProperties), define) {
+			errStream.Write([]byte(tr("Warning: the platform recipes don't use %[1]s to define %[2]s, the define has been added to the compiler flags", "build.board", define) + "\n"))
+			extraFlags = append([]string{"all:-D" + define}, extraFlags...)
+		}
+	}
+	if compilerPath := req.GetCompilerPath(); compilerPath != "" {
+		// The platform recipes run the compiler executables as
+		// {compiler.path}{compiler.*.cmd}
+		dir, err := paths.New(compilerPath).Abs()
+		if err != nil {
+			return nil, &cmderrors.InvalidArgumentError{Message: tr("Invalid compiler path"), Cause: err}
+		}
+		if value, ok := requestBuildProperties.custom.GetOk("compiler.path"); ok {
+			return nil, &cmderrors.InvalidArgumentError{Message: tr("The compiler path conflicts with the build property %[1]s=%[2]s", "compiler.path", value)}
+		}
+		if err := checkCompilerPath(dir, requestBuildProperties.over(boardBuildProperties)); err != nil {
+			return nil, &cmderrors.InvalidArgumentError{Message: tr("Invalid compiler path"), Cause: err}
+		}
+		requestBuildProperties.add("compiler.path=" + dir.String() + string(os.PathSeparator))
+	}
+	if sketchName := req.GetSketchName(); sketchName != "" {
+		// The artifacts are named after build.project_name, the sketch files
+		// are still looked up with the actual name of the sketch
+		if err := validateSketchName(sketchName); err != nil {
+			return nil, &cmderrors.InvalidArgumentError{Message: tr("Invalid sketch name"), Cause: err}
+		}
+		if value, ok := requestBuildProperties.custom.GetOk("build.project_name"); ok {
+			return nil, &cmderrors.InvalidArgumentError{Message: tr("The sketch name conflicts with the build property %[1]s=%[2]s", "build.project_name", value)}
+		}
+		requestBuildProperties.add("build.project_name=" + sketchName + ".ino")
+	}
+	// The language standards are added before the user's extra flags, so
+	// they can still be overridden
+	for _, std := range []struct {
+		scope, value string
+		known        map[string]int
+	}{
+		{"c", req.GetCStd(), cStandards},
+		{"cpp", req.GetCppStd(), cppStandards},
+	} {
+		if std.value == "" {
+			continue
+		}
+		flag, err := languageStandardFlag(std.value, std.known)
+		if err != nil {
+			return nil, &cmderrors.InvalidArgumentError{Message: tr("Invalid language standard"), Cause: err}
+		}
+		if warning := languageStandardWarning(std.value, std.known, requiredTools); warning != "" {
+			errStream.Write([]byte(tr("Warning: %s", warning) + "\n"))
+		}
+		extraFlags = append([]string{std.scope + ":" + flag}, extraFlags...)
+	}
+	if lto := req.GetLinkTimeOptimization(); lto != rpc.LinkTimeOptimization_LINK_TIME_OPTIMIZATION_DEFAULT {
+		ltoProperties, warning := ltoBuildProperties(lto == rpc.LinkTimeOptimization_LINK_TIME_OPTIMIZATION_ENABLED, requestBuildProperties.over(boardBuildProperties))
+		if warning != "" {
+			errStream.Write([]byte(tr("Warning: %s", warning) + "\n"))
+		}
+		requestBuildProperties.add(ltoProperties...)
+	}
+	if req.GetExportDeps() {
+		requestBuildProperties.add(depsBuildProperties(requestBuildProperties.over(boardBuildProperties))...)
+	}
+	if len(extraFlags) > 0 {
+		// Extra flags are appended to the value of the properties, including the
+		// ones overridden by the user
+		extraFlagsProperties, err := extraFlagsBuildProperties(extraFlags, requestBuildProperties.over(boardBuildProperties))
+		if err != nil {
+			return nil, &cmderrors.InvalidArgumentError{Message: tr("Invalid extra flags"), Cause: err}
+		}
+		requestBuildProperties.add(extraFlagsProperties...)
+	}
+
+	// An override of a recipe not defined by the platform is likely a typo
+	// and would be silently ignored by the build
+	overriddenRecipes := recipeOverrides(boardBuildProperties, requestBuildProperties.custom)
+	for _, recipe := range overriddenRecipes {
+		if !recipe.Defined {
+			errStream.Write([]byte(tr("Warning: the build property %s overrides a recipe not defined by the platform, it may be misspelled", recipe.Key) + "\n"))
+		}
+	}
+
+	buildProperties, err := builder.NewBuildProperties(sk, boardBuildProperties, buildPath, req.GetOptimizeForDebug(), requestBuildProperties.custom, builder.Options{
+		Reproducible:   req.GetReproducible(),
+		FixedBuildTime: req.GetNoBuildTime(),
+	})
+	if err != nil {
+		return nil, &cmderrors.InvalidArgumentError{Message: tr("Invalid build properties"), Cause: err}
+	}
+
+	return &buildTarget{
+		sketch:                 sk,
+		fqbn:                   fqbn,
+		targetBoard:            targetBoard,
+		targetPlatform:         targetPlatform,
+		buildPlatform:          buildPlatform,
+		boardBuildProperties:   boardBuildProperties,
+		requiredTools:          requiredTools,
+		hardwareDirs:           hardwareDirs,
+		buildPath:              buildPath,
+		requestBuildProperties: requestBuildProperties.list,
+		fileFlags:              fileFlags,
+		overriddenRecipes:      overriddenRecipes,
+		buildProperties:        buildProperties,
+	}, nil
+}
+
+// buildPropertiesList returns the given build properties as a list of
+// key=value strings sorted by key, with the values expanded if requested.
+func buildPropertiesList(buildProperties *properties.Map, expand bool) []string {
+	keys := buildProperties.Keys()
+	sort.Strings(keys)
+	list := []string{}
+	for _, key := range keys {
+		value := buildProperties.Get(key)
+		if expand {
+			value = buildProperties.ExpandPropsInString(value)
+		}
+		list = append(list, key+"="+value)
+	}
+	return list
+}
+
+// requestProperties are the custom build properties of a compile request, both
+// as the list of key=value strings passed to the builder and as a map. The
+// properties derived from the other settings of the request are layered on
+// top of the ones given by the user.
+type requestProperties struct {
+	list   []string
+	custom *properties.Map
+}
+
+// newRequestProperties parses the given key=value build properties.
+func newRequestProperties(list []string) (*requestProperties, error) {
+	custom, err := properties.LoadFromSlice(list)
+	if err != nil {
+		return nil, err
+	}
+	return &requestProperties{list: slices.Clone(list), custom: custom}, nil
+}
+
+// add layers the given key=value build properties on top of the current ones.
+func (p *requestProperties) add(props ...string) {
+	for _, prop := range props {
+		key, value, _ := strings.Cut(prop, "=")
+		p.custom.Set(strings.TrimSpace(key), strings.TrimSpace(value))
+	}
+	p.list = append(p.list, props...)
+}
+
+// over returns the given build properties with the request ones merged in.
+func (p *requestProperties) over(buildProperties *properties.Map) *properties.Map {
+	res := buildProperties.Clone()
+	res.Merge(p.custom)
+	return res
+}
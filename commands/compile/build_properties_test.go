@@ -0,0 +1,89 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/arduino/arduino-cli/commands/cmderrors"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	properties "github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildPropertiesList(t *testing.T) {
+	buildProperties := properties.NewFromHashmap(map[string]string{
+		"build.mcu":                  "atmega328p",
+		"compiler.path":              "{runtime.tools.avr-gcc.path}/bin/",
+		"build.extra_flags":          "-mmcu={build.mcu}",
+		"runtime.tools.avr-gcc.path": "/tools/avr-gcc",
+	})
+
+	require.Equal(t, []string{
+		"build.extra_flags=-mmcu=atmega328p",
+		"build.mcu=atmega328p",
+		"compiler.path=/tools/avr-gcc/bin/",
+		"runtime.tools.avr-gcc.path=/tools/avr-gcc",
+	}, buildPropertiesList(buildProperties, true))
+
+	require.Equal(t, []string{
+		"build.extra_flags=-mmcu={build.mcu}",
+		"build.mcu=atmega328p",
+		"compiler.path={runtime.tools.avr-gcc.path}/bin/",
+		"runtime.tools.avr-gcc.path=/tools/avr-gcc",
+	}, buildPropertiesList(buildProperties, false))
+
+	// The list can be loaded back into a map
+	loaded, err := properties.LoadFromSlice(buildPropertiesList(buildProperties, true))
+	require.NoError(t, err)
+	require.Equal(t, "-mmcu=atmega328p", loaded.Get("build.extra_flags"))
+}
+
+func TestRequestProperties(t *testing.T) {
+	boardBuildProperties := properties.NewFromHashmap(map[string]string{
+		"build.mcu":   "atmega328p",
+		"build.board": "AVR_UNO",
+	})
+	requestBuildProperties := []string{"build.board=CUSTOM"}
+
+	p, err := newRequestProperties(requestBuildProperties)
+	require.NoError(t, err)
+	merged := p.over(boardBuildProperties)
+	require.Equal(t, "CUSTOM", merged.Get("build.board"))
+	require.Equal(t, "atmega328p", merged.Get("build.mcu"))
+	require.Equal(t, []string{"build.board"}, p.custom.Keys())
+	// The board build properties are left untouched
+	require.Equal(t, "AVR_UNO", boardBuildProperties.Get("build.board"))
+
+	// The added properties are layered on top of the given ones, in order
+	p.add("build.board=ADDED", "build.extra_flags=-DADDED")
+	require.Equal(t, "ADDED", p.over(boardBuildProperties).Get("build.board"))
+	require.Equal(t, []string{"build.board", "build.extra_flags"}, p.custom.Keys())
+	require.Equal(t, []string{"build.board=CUSTOM", "build.board=ADDED", "build.extra_flags=-DADDED"}, p.list)
+	// The properties of the request are left untouched
+	require.Equal(t, []string{"build.board=CUSTOM"}, requestBuildProperties)
+
+	// Malformed request properties are reported
+	_, err = newRequestProperties([]string{"build.board"})
+	require.Error(t, err)
+}
+
+func TestBuildPropertiesWithInvalidInstance(t *testing.T) {
+	_, err := BuildProperties(context.Background(), &rpc.CompileRequest{Instance: &rpc.Instance{Id: -1}}, io.Discard)
+	require.ErrorAs(t, err, new(*cmderrors.InvalidInstanceError))
+}
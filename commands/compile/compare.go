@@ -0,0 +1,61 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"bufio"
+	"errors"
+	"io"
+
+	"github.com/arduino/go-paths-helper"
+)
+
+// compareArtifact compares byte by byte the artifact with the reference
+// file. The offset of the first different byte is returned, or -1 if the
+// files are equal. If one of the files is a prefix of the other, the offset
+// is the size of the shorter one.
+func compareArtifact(artifact, reference *paths.Path) (int64, error) {
+	a, err := artifact.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer a.Close()
+	b, err := reference.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer b.Close()
+
+	ra := bufio.NewReader(a)
+	rb := bufio.NewReader(b)
+	for offset := int64(0); ; offset++ {
+		ca, errA := ra.ReadByte()
+		cb, errB := rb.ReadByte()
+		if errA != nil && !errors.Is(errA, io.EOF) {
+			return 0, errA
+		}
+		if errB != nil && !errors.Is(errB, io.EOF) {
+			return 0, errB
+		}
+		if errA != nil && errB != nil {
+			// Both files ended
+			return -1, nil
+		}
+		if errA != nil || errB != nil || ca != cb {
+			return offset, nil
+		}
+	}
+}
@@ -0,0 +1,54 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareArtifact(t *testing.T) {
+	dir := paths.New(t.TempDir())
+	artifact := dir.Join("sketch.ino.hex")
+	require.NoError(t, artifact.WriteFile([]byte(":100000000C945C000C946E000C946E000C946E00CA")))
+
+	write := func(name, content string) *paths.Path {
+		p := dir.Join(name)
+		require.NoError(t, p.WriteFile([]byte(content)))
+		return p
+	}
+
+	offset, err := compareArtifact(artifact, write("same.hex", ":100000000C945C000C946E000C946E000C946E00CA"))
+	require.NoError(t, err)
+	require.Equal(t, int64(-1), offset)
+
+	offset, err = compareArtifact(artifact, write("different.hex", ":100000000C945C000C946F000C946E000C946E00CA"))
+	require.NoError(t, err)
+	require.Equal(t, int64(22), offset)
+
+	offset, err = compareArtifact(artifact, write("shorter.hex", ":10000000"))
+	require.NoError(t, err)
+	require.Equal(t, int64(9), offset)
+
+	offset, err = compareArtifact(write("empty.hex", ""), write("empty2.hex", ""))
+	require.NoError(t, err)
+	require.Equal(t, int64(-1), offset)
+
+	_, err = compareArtifact(artifact, dir.Join("missing.hex"))
+	require.Error(t, err)
+}
@@ -26,10 +26,9 @@ import (
 	"github.com/arduino/arduino-cli/commands/cmderrors"
 	"github.com/arduino/arduino-cli/commands/internal/instances"
 	"github.com/arduino/arduino-cli/internal/arduino/builder"
-	"github.com/arduino/arduino-cli/internal/arduino/cores"
+	"github.com/arduino/arduino-cli/internal/arduino/libraries"
 	"github.com/arduino/arduino-cli/internal/arduino/libraries/librariesmanager"
-	"github.com/arduino/arduino-cli/internal/arduino/sketch"
-	"github.com/arduino/arduino-cli/internal/arduino/utils"
+	"github.com/arduino/arduino-cli/internal/arduino/libraries/librariesresolver"
 	"github.com/arduino/arduino-cli/internal/buildcache"
 	"github.com/arduino/arduino-cli/internal/cli/configuration"
 	"github.com/arduino/arduino-cli/internal/i18n"
@@ -41,8 +40,21 @@ import (
 
 var tr = i18n.Tr
 
-// Compile FIXMEDOC
-func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream io.Writer, progressCB rpc.TaskProgressCB) (r *rpc.BuilderResult, e error) {
+// Compile builds the sketch as specified in the request. The given observer,
+// if not nil, is notified of the events of the build.
+func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream io.Writer, progressCB rpc.TaskProgressCB, observer Observer) (r *rpc.BuilderResult, e error) {
+	if progressCB != nil && !req.GetDetailedProgress() {
+		progressCB = filterDetailedProgress(progressCB)
+	}
+	var commandCB func([]string)
+	var diagnosticCB func(*rpc.CompileDiagnostic)
+	if observer != nil {
+		observer = &syncObserver{observer: observer}
+		defer func() { observer.OnComplete(r, e) }()
+		progressCB = observeProgress(progressCB, observer)
+		commandCB = observer.OnCommand
+		diagnosticCB = observer.OnDiagnostic
+	}
 
 	// There is a binding between the export binaries setting and the CLI flag to explicitly set it,
 	// since we want this binding to work also for the gRPC interface we must read it here in this
@@ -57,6 +69,10 @@ func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream
 		exportBinaries = reqExportBinaries.GetValue()
 	}
 
+	if req.GetNoOverwrite() && req.GetBackup() {
+		return nil, &cmderrors.InvalidArgumentError{Message: tr("Can't use the following flags together: %s", "--no-overwrite, --backup")}
+	}
+
 	pme, release, err := instances.GetPackageManagerExplorer(req.GetInstance())
 	if err != nil {
 		return nil, err
@@ -69,81 +85,31 @@ func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream
 	}
 
 	logrus.Tracef("Compile %s for %s started", req.GetSketchPath(), req.GetFqbn())
-	if req.GetSketchPath() == "" {
-		return nil, &cmderrors.MissingSketchPathError{}
-	}
-	sketchPath := paths.New(req.GetSketchPath())
-	sk, err := sketch.New(sketchPath)
+	target, err := resolveBuildTarget(pme, req, errStream)
 	if err != nil {
-		return nil, &cmderrors.CantOpenSketchError{Cause: err}
-	}
-
-	fqbnIn := req.GetFqbn()
-	if fqbnIn == "" && sk != nil {
-		if pme.GetProfile() != nil {
-			fqbnIn = pme.GetProfile().FQBN
-		} else {
-			fqbnIn = sk.GetDefaultFQBN()
-		}
-	}
-	if fqbnIn == "" {
-		return nil, &cmderrors.MissingFQBNError{}
-	}
-
-	fqbn, err := cores.ParseFQBN(fqbnIn)
-	if err != nil {
-		return nil, &cmderrors.InvalidFQBNError{Cause: err}
-	}
-	_, targetPlatform, targetBoard, boardBuildProperties, buildPlatform, err := pme.ResolveFQBN(fqbn)
-	if err != nil {
-		if targetPlatform == nil {
-			return nil, &cmderrors.PlatformNotFoundError{
-				Platform: fmt.Sprintf("%s:%s", fqbn.Package, fqbn.PlatformArch),
-				Cause:    fmt.Errorf(tr("platform not installed")),
-			}
-		}
-		return nil, &cmderrors.InvalidFQBNError{Cause: err}
+		return nil, err
 	}
+	sk, fqbn, buildPath := target.sketch, target.fqbn, target.buildPath
+	targetPlatform, buildPlatform, targetBoard := target.targetPlatform, target.buildPlatform, target.targetBoard
 
 	r = &rpc.BuilderResult{}
 	r.BoardPlatform = targetPlatform.ToRPCPlatformReference()
 	r.BuildPlatform = buildPlatform.ToRPCPlatformReference()
+	for _, tool := range target.requiredTools {
+		r.UsedTools = append(r.UsedTools, tool.String())
+	}
+	sort.Strings(r.UsedTools)
 
-	// Setup sign keys if requested
-	if req.GetKeysKeychain() != "" {
-		boardBuildProperties.Set("build.keys.keychain", req.GetKeysKeychain())
-	}
-	if req.GetSignKey() != "" {
-		boardBuildProperties.Set("build.keys.sign_key", req.GetSignKey())
-	}
-	if req.GetEncryptKey() != "" {
-		boardBuildProperties.Set("build.keys.encrypt_key", req.GetEncryptKey())
-	}
-	// At the current time we do not have a way of knowing if a board supports the secure boot or not,
-	// so, if the flags to override the default keys are used, we try override the corresponding platform property nonetheless.
-	// It's not possible to use the default name for the keys since there could be more tools to sign and encrypt.
-	// So it's mandatory to use all three flags to sign and encrypt the binary
-	keychainProp := boardBuildProperties.ContainsKey("build.keys.keychain")
-	signProp := boardBuildProperties.ContainsKey("build.keys.sign_key")
-	encryptProp := boardBuildProperties.ContainsKey("build.keys.encrypt_key")
-	// we verify that all the properties for the secure boot keys are defined or none of them is defined.
-	if !(keychainProp == signProp && signProp == encryptProp) {
-		return nil, fmt.Errorf(tr("Firmware encryption/signing requires all the following properties to be defined: %s", "build.keys.keychain, build.keys.sign_key, build.keys.encrypt_key"))
-	}
-
-	// Generate or retrieve build path
-	var buildPath *paths.Path
-	if buildPathArg := req.GetBuildPath(); buildPathArg != "" {
-		buildPath = paths.New(req.GetBuildPath()).Canonical()
-		if in, _ := buildPath.IsInsideDir(sk.FullPath); in && buildPath.IsDir() {
-			if sk.AdditionalFiles, err = removeBuildFromSketchFiles(sk.AdditionalFiles, buildPath); err != nil {
-				return nil, err
-			}
+	// Just get, or explain, the build properties and exit
+	if req.GetShowProperties() || req.GetExplainProperty() != "" {
+		r.BuildPath = buildPath.String()
+		r.BuildProperties = buildPropertiesList(target.buildProperties, !req.GetDoNotExpandBuildProperties())
+		if key := req.GetExplainProperty(); key != "" {
+			r.PropertyExplanation = explainProperty(key, target.boardBuildProperties, target.buildProperties, targetBoard, fqbn, buildPlatform, targetPlatform)
 		}
+		return r, nil
 	}
-	if buildPath == nil {
-		buildPath = sk.DefaultBuildPath()
-	}
+
 	if err = buildPath.MkdirAll(); err != nil {
 		return nil, &cmderrors.PermissionDeniedError{Message: tr("Cannot create build directory"), Cause: err}
 	}
@@ -159,36 +125,151 @@ func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream
 		if err != nil {
 			return nil, &cmderrors.PermissionDeniedError{Message: tr("Cannot create build cache directory"), Cause: err}
 		}
+		if buildCachePath.Canonical().EqualsTo(sk.FullPath.Canonical()) {
+			return nil, &cmderrors.InvalidArgumentError{Message: tr("The build cache path can't be the sketch folder %s, please specify a different build cache path", sk.FullPath)}
+		}
 		if err := buildCachePath.MkdirAll(); err != nil {
 			return nil, &cmderrors.PermissionDeniedError{Message: tr("Cannot create build cache directory"), Cause: err}
 		}
 		coreBuildCachePath = buildCachePath.Join("core")
 	}
 
-	if _, err := pme.FindToolsRequiredForBuild(targetPlatform, buildPlatform); err != nil {
+	actualPlatform := buildPlatform
+	otherLibrariesDirs, builtInLibrariesDir, err := librariesFolders(req, pme.GetProfile() != nil)
+	if err != nil {
 		return nil, err
 	}
 
-	actualPlatform := buildPlatform
-	otherLibrariesDirs := paths.NewPathList(req.GetLibraries()...)
-	otherLibrariesDirs.Add(configuration.LibrariesDir(configuration.Settings))
+	compareTo := paths.New(req.GetCompareTo())
+	if compareTo != nil {
+		if exist, err := compareTo.ExistCheck(); err != nil {
+			return nil, &cmderrors.InvalidArgumentError{Message: tr("Invalid reference artifact"), Cause: err}
+		} else if !exist || compareTo.IsDir() {
+			return nil, &cmderrors.InvalidArgumentError{Message: tr("The reference artifact %s doesn't exist", compareTo)}
+		} else if compareTo.Ext() == "" {
+			return nil, &cmderrors.InvalidArgumentError{Message: tr("The reference artifact %s has no extension, it's needed to select the artifact to compare", compareTo)}
+		}
+	}
+	archivePath := paths.New(req.GetArchive())
+	if archivePath != nil {
+		if archivePath.Ext() == "" {
+			archivePath = paths.New(archivePath.String() + ".zip")
+		} else if !strings.EqualFold(archivePath.Ext(), ".zip") {
+			return nil, &cmderrors.InvalidArgumentError{Message: tr("Unsupported archive format %s, only zip archives can be created", archivePath.Ext())}
+		}
+	}
+	depGraphFile := paths.New(req.GetExportDepGraph())
+	if depGraphFile != nil {
+		if err := checkDependencyGraphFile(depGraphFile); err != nil {
+			return nil, &cmderrors.InvalidArgumentError{Message: tr("Invalid dependency graph file"), Cause: err}
+		}
+	}
+
+	librariesLocationsOrder, err := librariesresolver.ParseLocationsOrder(req.GetLibraryPathOrder())
+	if err != nil {
+		return nil, &cmderrors.InvalidArgumentError{Message: tr("Invalid library path order"), Cause: err}
+	}
+	var allowedLibrarySources map[libraries.LibraryLocation]bool
+	if len(req.GetAllowedLibrarySources()) > 0 {
+		if allowedLibrarySources, err = parseAllowedLibrarySources(req.GetAllowedLibrarySources()); err != nil {
+			return nil, &cmderrors.InvalidArgumentError{Message: tr("Invalid allowed library sources"), Cause: err}
+		}
+	}
 
 	var libsManager *librariesmanager.LibrariesManager
-	if pme.GetProfile() != nil {
+	if profile := pme.GetProfile(); profile != nil {
 		libsManager = lm
+		if err := checkProfileLibrariesInstalled(profile, lm.FindAllInstalled()); err != nil {
+			return nil, err
+		}
+	}
+
+	sketchEncoding, err := builder.ParseSketchEncoding(req.GetSketchEncoding())
+	if err != nil {
+		return nil, &cmderrors.InvalidArgumentError{Message: tr("Invalid sketch encoding"), Cause: err}
+	}
+
+	var builderPath *paths.Path
+	if p := req.GetBuilderPath(); p != "" {
+		builderPath = paths.New(p)
+		if !builderPath.IsNotDir() {
+			return nil, &cmderrors.InvalidArgumentError{Message: tr("Invalid builder path"), Cause: errors.New(tr("%s is not a file", builderPath))}
+		}
+	}
+
+	var compilationDatabasePath *paths.Path
+	if p := req.GetCompilationDatabasePath(); p != "" {
+		if compilationDatabasePath, err = paths.New(p).Abs(); err != nil {
+			return nil, &cmderrors.InvalidArgumentError{Message: tr("Invalid compilation database path"), Cause: err}
+		}
+	}
+
+	if req.GetTrace() {
+		tracePaths(errStream, tr("sketch"), sk.FullPath)
+		tracePaths(errStream, tr("sketch main file"), sk.MainFile)
+		tracePaths(errStream, tr("build path"), buildPath)
+		tracePaths(errStream, tr("core build cache"), coreBuildCachePath)
+		if compilationDatabasePath != nil {
+			tracePaths(errStream, tr("compilation database"), compilationDatabasePath)
+		} else {
+			tracePaths(errStream, tr("compilation database"), buildPath.Join("compile_commands.json"))
+		}
+		if exportDir := req.GetExportDir(); exportDir != "" {
+			tracePaths(errStream, tr("export folder"), paths.New(exportDir))
+		}
+		tracePaths(errStream, tr("board platform"), targetPlatform.InstallDir)
+		tracePaths(errStream, tr("build platform"), buildPlatform.InstallDir)
+		tracePaths(errStream, tr("hardware folder"), target.hardwareDirs...)
+		for _, tool := range target.requiredTools {
+			tracePaths(errStream, tr("tool %s", tool), tool.InstallDir)
+		}
+		tracePaths(errStream, tr("libraries folder"), otherLibrariesDirs...)
+		tracePaths(errStream, tr("built-in libraries folder"), builtInLibrariesDir)
+		for _, library := range req.GetLibrary() {
+			tracePaths(errStream, tr("library"), paths.New(library))
+		}
+	}
+
+	// The build is delegated to an external arduino-builder, to compare the
+	// output of different builder versions
+	if builderPath != nil {
+		if len(req.GetLibrary()) > 0 {
+			errStream.Write([]byte(tr("Warning: the libraries given by path are not supported by arduino-builder and are ignored") + "\n"))
+		}
+		external := &externalBuilder{
+			builderPath:         builderPath,
+			sketchMainFile:      sk.MainFile,
+			fqbn:                fqbn.String(),
+			buildPath:           buildPath,
+			coreBuildCachePath:  coreBuildCachePath,
+			hardwareDirs:        target.hardwareDirs,
+			librariesDirs:       otherLibrariesDirs,
+			builtInLibrariesDir: builtInLibrariesDir,
+			buildProperties:     target.requestBuildProperties,
+			warnings:            req.GetWarnings(),
+			verbose:             req.GetVerbose(),
+		}
+		if err := external.run(ctx, outStream, errStream); err != nil {
+			if ctx.Err() != nil {
+				return r, &cmderrors.CompileInterruptedError{Cause: ctx.Err()}
+			}
+			return r, &cmderrors.CompileFailedError{Message: tr("Error running %[1]s: %[2]v", builderPath, err)}
+		}
+		r.BuildPath = buildPath.String()
+		return r, nil
 	}
 
 	sketchBuilder, err := builder.NewBuilder(
 		sk,
-		boardBuildProperties,
+		target.boardBuildProperties,
 		buildPath,
 		req.GetOptimizeForDebug(),
 		coreBuildCachePath,
 		int(req.GetJobs()),
-		req.GetBuildProperties(),
-		configuration.HardwareDirectories(configuration.Settings),
+		target.requestBuildProperties,
+		target.hardwareDirs,
 		otherLibrariesDirs,
-		configuration.IDEBuiltinLibrariesDir(configuration.Settings),
+		builtInLibrariesDir,
 		fqbn,
 		req.GetClean(),
 		req.GetSourceOverride(),
@@ -199,11 +280,22 @@ func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream
 		paths.NewPathList(req.GetLibrary()...),
 		outStream, errStream, req.GetVerbose(), req.GetWarnings(),
 		progressCB,
+		builder.Options{
+			Context:                 ctx,
+			Reproducible:            req.GetReproducible(),
+			FixedBuildTime:          req.GetNoBuildTime(),
+			SketchEncoding:          sketchEncoding,
+			FileFlags:               target.fileFlags,
+			CompilationDatabasePath: compilationDatabasePath,
+			LibrariesLocationsOrder: librariesLocationsOrder,
+			OnlyExplicitLibraries:   req.GetOnlyExplicitLibraries(),
+			StrictIncludes:          req.GetStrictIncludes(),
+			VerifySize:              req.GetVerifySize(),
+			CommandCB:               commandCB,
+			DiagnosticCB:            diagnosticCB,
+		},
 	)
 	if err != nil {
-		if strings.Contains(err.Error(), "invalid build properties") {
-			return nil, &cmderrors.InvalidArgumentError{Message: tr("Invalid build properties"), Cause: err}
-		}
 		if errors.Is(err, builder.ErrSketchCannotBeLocatedInBuildPath) {
 			return r, &cmderrors.CompileFailedError{
 				Message: tr("Sketch cannot be located in build path. Please specify a different build path"),
@@ -212,6 +304,8 @@ func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream
 		return r, &cmderrors.CompileFailedError{Message: err.Error()}
 	}
 
+	r.CoreCacheKey = sketchBuilder.CoreCacheKey()
+
 	defer func() {
 		if p := sketchBuilder.GetBuildPath(); p != nil {
 			r.BuildPath = p.String()
@@ -219,7 +313,12 @@ func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream
 	}()
 
 	defer func() {
-		r.Diagnostics = sketchBuilder.CompilerDiagnostics().ToRPC()
+		if req.GetOnlyNewDiagnostics() {
+			r.Diagnostics = sketchBuilder.NewCompilerDiagnostics().ToRPC()
+		} else {
+			r.Diagnostics = sketchBuilder.CompilerDiagnostics().ToRPC()
+		}
+		r.LinkCommand = sketchBuilder.LinkCommand()
 	}()
 
 	defer func() {
@@ -227,29 +326,27 @@ func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream
 		if buildProperties == nil {
 			return
 		}
-		keys := buildProperties.Keys()
-		sort.Strings(keys)
-		for _, key := range keys {
-			r.BuildProperties = append(r.GetBuildProperties(), key+"="+buildProperties.Get(key))
-		}
-		if !req.GetDoNotExpandBuildProperties() {
-			r.BuildProperties, _ = utils.ExpandBuildProperties(r.GetBuildProperties())
-		}
+		r.BuildProperties = buildPropertiesList(buildProperties, !req.GetDoNotExpandBuildProperties())
 	}()
 
-	// Just get build properties and exit
-	if req.GetShowProperties() {
-		return r, nil
-	}
-
 	if req.GetPreprocess() {
 		// Just output preprocessed source code and exit
-		preprocessedSketch, err := sketchBuilder.Preprocess()
+		preprocessedFiles, err := sketchBuilder.PreprocessFiles()
+		for _, file := range preprocessedFiles {
+			rpcFile := &rpc.PreprocessedFile{
+				SourcePath: file.Source.String(),
+				Content:    string(file.Content),
+			}
+			if file.Err != nil {
+				rpcFile.Error = file.Err.Error()
+			}
+			r.PreprocessedFiles = append(r.PreprocessedFiles, rpcFile)
+		}
 		if err != nil {
 			err = &cmderrors.CompileFailedError{Message: err.Error()}
 			return r, err
 		}
-		_, err = outStream.Write(preprocessedSketch)
+		_, err = outStream.Write(preprocessedFiles[0].Content)
 		return r, err
 	}
 
@@ -264,8 +361,18 @@ func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream
 			importedLibs = append(importedLibs, rpcLib)
 		}
 		r.UsedLibraries = importedLibs
+		includeFolders := sketchBuilder.IncludeFolders()
+		r.IncludePaths = includeFolders.AsStrings()
 	}()
 
+	if checkFile := req.GetCheckFile(); checkFile != "" {
+		// Just compile the given file to report its diagnostics and exit
+		if err := sketchBuilder.CheckFile(paths.New(checkFile)); err != nil {
+			return r, &cmderrors.CompileFailedError{Message: err.Error()}
+		}
+		return r, nil
+	}
+
 	// if it's a regular build, go on...
 
 	if req.GetVerbose() {
@@ -283,18 +390,110 @@ func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream
 		core = core[strings.Index(core, ":")+1:]
 		outStream.Write([]byte(tr("Using board '%[1]s' from platform in folder: %[2]s", targetBoard.BoardID, targetPlatform.InstallDir) + "\n"))
 		outStream.Write([]byte(tr("Using core '%[1]s' from platform in folder: %[2]s", core, buildPlatform.InstallDir) + "\n"))
+		for _, recipe := range target.overriddenRecipes {
+			if !recipe.Defined {
+				continue
+			}
+			outStream.Write([]byte(tr("Overriding recipe %s", recipe.Key) + "\n"))
+			outStream.Write([]byte("  " + tr("before: %s", recipe.Before) + "\n"))
+			outStream.Write([]byte("  " + tr("after: %s", recipe.After) + "\n"))
+		}
+		if builtInLibrariesDir != nil {
+			// The built-in libraries folder is usually set by the IDE, report it
+			// since a wrong path leads to a confusing libraries resolution
+			outStream.Write([]byte(tr("Using built-in libraries from folder: %[1]s (set by '%[2]s')", builtInLibrariesDir, "directories.builtin.libraries") + "\n"))
+			if !builtInLibrariesDir.IsDir() {
+				outStream.Write([]byte(tr("Warning: the built-in libraries folder %s does not exist", builtInLibrariesDir) + "\n"))
+			}
+		}
 		outStream.Write([]byte("\n"))
 	}
-	if !targetBoard.Properties.ContainsKey("build.board") {
+	if !targetBoard.Properties.ContainsKey("build.board") && req.GetBoardDefine() == "" {
 		outStream.Write([]byte(
 			tr("Warning: Board %[1]s doesn't define a %[2]s preference. Auto-set to: %[3]s",
 				targetBoard.String(), "'build.board'", sketchBuilder.GetBuildProperties().Get("build.board")) + "\n"))
 	}
 
+	// The sizes of the previous build are used to report the effect of the
+	// link time optimization, they must be read before the build path is wiped
+	ltoRequested := req.GetLinkTimeOptimization() != rpc.LinkTimeOptimization_LINK_TIME_OPTIMIZATION_DEFAULT
+	var previousSizes *buildSizes
+	if ltoRequested {
+		previousSizes = loadBuildSizes(buildPath)
+	}
+
 	if err := sketchBuilder.Build(); err != nil {
+		if ctx.Err() != nil {
+			return r, &cmderrors.CompileInterruptedError{Cause: ctx.Err()}
+		}
+		var failedOutput []byte
+		if failed := sketchBuilder.FailedCommand(); failed != nil {
+			failedOutput = failed.Output
+		}
+		if diskErr := checkOutOfDiskSpace(err, failedOutput, buildPath); diskErr != nil {
+			return r, diskErr
+		}
+		if failed := sketchBuilder.FailedCommand(); failed != nil && req.GetVerboseOnFailure() && !req.GetVerbose() {
+			msg := fmt.Sprintln(tr("Failed command:"))
+			msg += fmt.Sprintln(failed.CommandLine())
+			if len(failed.Output) > 0 {
+				msg += fmt.Sprintln(tr("Output:"))
+				msg += fmt.Sprintln(strings.TrimRight(string(failed.Output), "\r\n"))
+			}
+			errStream.Write([]byte(msg))
+		}
 		return r, &cmderrors.CompileFailedError{Message: err.Error()}
 	}
 
+	if profile := pme.GetProfile(); profile != nil {
+		if err := checkProfileLibrariesUsed(profile, sketchBuilder.ImportedLibraries()); err != nil {
+			return r, err
+		}
+	}
+	if allowedLibrarySources != nil {
+		if err := checkLibrarySources(sketchBuilder.ImportedLibraries(), allowedLibrarySources); err != nil {
+			return r, err
+		}
+	}
+
+	if req.GetDumpDefines() {
+		r.Defines = sketchBuilder.Defines()
+	}
+
+	if req.GetSummary() {
+		r.Summary = sketchBuilder.CompilationSummary()
+	}
+
+	if req.GetValidateCompilationDatabase() {
+		problems, err := sketchBuilder.ValidateCompilationDatabase()
+		if err != nil {
+			return r, &cmderrors.CompileFailedError{Message: tr("Error reading the compilation database"), Cause: err}
+		}
+		for _, problem := range problems {
+			errStream.Write([]byte(tr("Warning: compilation database: %s", problem) + "\n"))
+		}
+		if len(problems) > 0 {
+			return r, &cmderrors.CompileFailedError{Message: tr("The compilation database %s references files or directories that don't exist", sketchBuilder.CompilationDatabasePath())}
+		}
+	}
+
+	if tool := req.GetVerifyTool(); tool != "" && !req.GetCreateCompilationDatabaseOnly() {
+		if err := sketchBuilder.RunVerifyTool(tool); err != nil {
+			if ctx.Err() != nil {
+				return r, &cmderrors.CompileInterruptedError{Cause: ctx.Err()}
+			}
+			return r, &cmderrors.CompileFailedError{Message: tr("Verification with the tool %s failed", tool), Cause: err}
+		}
+	}
+
+	if !req.GetCreateCompilationDatabaseOnly() {
+		for _, format := range req.GetOutputFormats() {
+			if err := sketchBuilder.GenerateOutputFormat(format); err != nil {
+				return r, &cmderrors.CompileFailedError{Message: tr("Error generating the output format %s", format), Cause: err}
+			}
+		}
+	}
+
 	// If the export directory is set we assume you want to export the binaries
 	if req.GetExportDir() != "" {
 		exportBinaries = true
@@ -302,7 +501,11 @@ func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream
 	// If CreateCompilationDatabaseOnly is set, we do not need to export anything
 	if req.GetCreateCompilationDatabaseOnly() {
 		exportBinaries = false
+		if !req.GetQuiet() {
+			outStream.Write([]byte(tr("Compilation database saved to: %s", sketchBuilder.CompilationDatabasePath()) + "\n"))
+		}
 	}
+	outputDir := buildPath
 	if exportBinaries {
 		err := sketchBuilder.RunRecipe("recipe.hooks.savehex.presavehex", ".pattern", false)
 		if err != nil {
@@ -312,9 +515,13 @@ func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream
 		exportPath := paths.New(req.GetExportDir())
 		if exportPath == nil {
 			// Add FQBN (without configs part) to export path
-			fqbnSuffix := strings.ReplaceAll(fqbn.StringWithoutConfig(), ":", ".")
+			fqbnSuffix, err := exportDirName(fqbn, req.GetFqbnSeparator())
+			if err != nil {
+				return r, &cmderrors.InvalidArgumentError{Message: tr("Invalid FQBN separator"), Cause: err}
+			}
 			exportPath = sk.FullPath.Join("build", fqbnSuffix)
 		}
+		outputDir = exportPath
 
 		// Copy all "sketch.ino.*" artifacts to the export directory
 		if !buildPath.EqualsTo(exportPath) {
@@ -323,19 +530,32 @@ func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream
 				return r, &cmderrors.PermissionDeniedError{Message: tr("Error creating output dir"), Cause: err}
 			}
 
-			baseName, ok := sketchBuilder.GetBuildProperties().GetOk("build.project_name") // == "sketch.ino"
-			if !ok {
+			if !sketchBuilder.GetBuildProperties().ContainsKey("build.project_name") {
 				return r, &cmderrors.MissingPlatformPropertyError{Property: "build.project_name"}
 			}
-			buildFiles, err := sketchBuilder.GetBuildPath().ReadDir()
+			buildFiles, err := sketchBuilder.ListArtifacts(sketchBuilder.GetBuildPath())
 			if err != nil {
 				return r, &cmderrors.PermissionDeniedError{Message: tr("Error reading build directory"), Cause: err}
 			}
-			buildFiles.FilterPrefix(baseName)
+			if req.GetNoOverwrite() {
+				if err := checkExportedFilesExist(buildFiles, exportPath); err != nil {
+					return r, &cmderrors.CompileFailedError{Message: tr("Error copying the artifacts to %s", exportPath), Cause: err}
+				}
+			}
+			exportedFiles := paths.NewPathList()
 			for _, buildFile := range buildFiles {
+				if ctx.Err() != nil {
+					// The artifacts already copied are removed to avoid
+					// leaving a mix of new and stale files in the export path
+					removeExportedFiles(exportedFiles)
+					return r, &cmderrors.CompileInterruptedError{Cause: ctx.Err()}
+				}
 				exportedFile := exportPath.Join(buildFile.Base())
+				exportedFiles.Add(exportedFile)
 				logrus.WithField("src", buildFile).WithField("dest", exportedFile).Trace("Copying artifact.")
-				if err = buildFile.CopyTo(exportedFile); err != nil {
+				if err = exportArtifact(buildFile, exportedFile, req.GetNoOverwrite(), req.GetBackup()); errors.Is(err, errExportedFileExists) {
+					return r, &cmderrors.CompileFailedError{Message: tr("Error copying output file %s", buildFile), Cause: fmt.Errorf("%s: %w", exportedFile, err)}
+				} else if err != nil {
 					return r, &cmderrors.PermissionDeniedError{Message: tr("Error copying output file %s", buildFile), Cause: err}
 				}
 			}
@@ -347,9 +567,155 @@ func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream
 		}
 	}
 
+	if req.GetExportPreprocessedSketch() {
+		preprocessedSketch := sketchBuilder.PreprocessedSketchPath()
+		exportedSketch := outputDir.Join(preprocessedSketch.Base())
+		if err := outputDir.MkdirAll(); err != nil {
+			return r, &cmderrors.PermissionDeniedError{Message: tr("Error creating output dir"), Cause: err}
+		}
+		if err := preprocessedSketch.CopyTo(exportedSketch); err != nil {
+			return r, &cmderrors.PermissionDeniedError{Message: tr("Error copying output file %s", preprocessedSketch), Cause: err}
+		}
+	}
+
+	if req.GetKeepObjects() && !req.GetCreateCompilationDatabaseOnly() {
+		objectsDir := outputDir.Join("objects")
+		if err := sketchBuilder.ExportObjectFiles(objectsDir); err != nil {
+			return r, &cmderrors.PermissionDeniedError{Message: tr("Error copying object files to %s", objectsDir), Cause: err}
+		}
+	}
+
+	if depGraphFile != nil {
+		graph := sketchBuilder.LibrariesDependencyGraph()
+		if err := writeDependencyGraph(depGraphFile, sk.Name, graph); err != nil {
+			return r, &cmderrors.PermissionDeniedError{Message: tr("Error writing the dependency graph to %s", depGraphFile), Cause: err}
+		}
+		if !req.GetQuiet() {
+			outStream.Write([]byte(tr("Dependency graph saved to: %s", depGraphFile) + "\n"))
+		}
+	}
+
+	if req.GetExportDeps() && !req.GetCreateCompilationDatabaseOnly() {
+		manifest := outputDir.Join("dependencies.d")
+		if err := exportDependencies(sk, buildPath, sketchBuilder.PreprocessedSketchPath(), manifest); err != nil {
+			return r, &cmderrors.PermissionDeniedError{Message: tr("Error exporting the dependency files to %s", manifest), Cause: err}
+		}
+		if !req.GetQuiet() {
+			outStream.Write([]byte(tr("Dependencies saved to: %s", manifest) + "\n"))
+		}
+	}
+
+	if len(req.GetCopyTo()) > 0 && !req.GetCreateCompilationDatabaseOnly() {
+		artifacts := paths.NewPathList()
+		if mainArtifact, err := sketchBuilder.OutputArtifactPath(); err == nil && mainArtifact.Exist() {
+			artifacts.Add(mainArtifact)
+			if elf := sketchBuilder.ElfArtifactPath(mainArtifact); elf.Exist() && !artifacts.Contains(elf) {
+				artifacts.Add(elf)
+			}
+		}
+		if baseName, err := sketchBuilder.ArtifactBaseName(); err == nil {
+			for _, ext := range copyToArtifactExtensions {
+				if artifact := buildPath.Join(baseName + ext); artifact.Exist() && !artifacts.Contains(artifact) {
+					artifacts.Add(artifact)
+				}
+			}
+		}
+		if errs := copyArtifactsTo(artifacts, req.GetCopyTo(), req.GetNoOverwrite(), req.GetBackup()); len(errs) > 0 {
+			// Each failed destination is reported on its own line
+			return r, &cmderrors.CompileFailedError{Message: tr("Error copying the artifacts to %d of the %d destinations", len(errs), len(req.GetCopyTo())), Cause: errors.Join(errs...)}
+		}
+	}
+
+	if archivePath != nil && !req.GetCreateCompilationDatabaseOnly() {
+		fqbnSuffix, err := exportDirName(fqbn, req.GetFqbnSeparator())
+		if err != nil {
+			return r, &cmderrors.InvalidArgumentError{Message: tr("Invalid FQBN separator"), Cause: err}
+		}
+		artifacts, err := sketchBuilder.ListArtifacts(outputDir)
+		if err != nil {
+			return r, &cmderrors.PermissionDeniedError{Message: tr("Error reading build directory"), Cause: err}
+		}
+		if err := archiveArtifacts(archivePath, sk.Name+"_"+fqbnSuffix, sk.Name, fqbn.String(), artifacts, sketchBuilder.ArtifactType); err != nil {
+			return r, &cmderrors.PermissionDeniedError{Message: tr("Error creating the archive %s", archivePath), Cause: err}
+		}
+		if !req.GetQuiet() {
+			outStream.Write([]byte(tr("Artifacts archived to: %s", archivePath) + "\n"))
+		}
+	}
+
+	if compareTo != nil && !req.GetCreateCompilationDatabaseOnly() {
+		// The artifact is selected by the extension of the reference, the main
+		// artifact is preferred when it has the same extension
+		baseName, _ := sketchBuilder.ArtifactBaseName()
+		artifact := buildPath.Join(baseName + compareTo.Ext())
+		if mainArtifact, err := sketchBuilder.OutputArtifactPath(); err == nil && mainArtifact.Ext() == compareTo.Ext() && mainArtifact.Exist() {
+			artifact = mainArtifact
+		}
+		if !artifact.Exist() {
+			return r, &cmderrors.CompileFailedError{Message: tr("The build didn't produce an artifact with the extension %[1]s to compare with %[2]s", compareTo.Ext(), compareTo)}
+		}
+		offset, err := compareArtifact(artifact, compareTo)
+		if err != nil {
+			return r, fmt.Errorf("%s: %w", tr("Error comparing %[1]s with %[2]s", artifact, compareTo), err)
+		}
+		if offset >= 0 {
+			msg := tr("The artifact %[1]s differs from the reference %[2]s at byte %[3]d", artifact.Base(), compareTo, offset)
+			if !req.GetReproducible() {
+				msg += "\n" + tr("Use the %s flag to produce the same output from the same sources.", "--reproducible")
+			}
+			return r, &cmderrors.CompileFailedError{Message: msg}
+		}
+		if !req.GetQuiet() {
+			outStream.Write([]byte(tr("The artifact %[1]s matches the reference %[2]s", artifact.Base(), compareTo) + "\n"))
+		}
+	}
+
 	r.ExecutableSectionsSize = sketchBuilder.ExecutableSectionsSize().ToRPCExecutableSectionSizeArray()
 
-	logrus.Tracef("Compile %s for %s successful", sk.Name, fqbnIn)
+	// The sizes are recorded only by the builds toggling the link time
+	// optimization, the only ones that report the size difference
+	if sections := sketchBuilder.ExecutableSectionsSize(); ltoRequested && len(sections) > 0 {
+		sizes := &buildSizes{LTO: ltoEnabled(sketchBuilder.GetBuildProperties()), Sections: sections}
+		if diff := ltoSizeDifference(sizes, previousSizes); diff != "" && !req.GetQuiet() {
+			outStream.Write([]byte(diff + "\n"))
+		}
+		if err := sizes.save(buildPath); err != nil {
+			logrus.WithError(err).Warn("Error saving the build sizes")
+		}
+	}
+
+	// List the artifacts available in the output directory
+	if artifacts, err := sketchBuilder.ListArtifacts(outputDir); err == nil {
+		for _, artifact := range artifacts {
+			if absArtifact, err := artifact.Abs(); err == nil {
+				artifact = absArtifact
+			}
+			var size int64
+			if info, err := artifact.Stat(); err == nil {
+				size = info.Size()
+			}
+			sha256Sum, md5Sum, err := artifactChecksums(artifact, req.GetMd5Checksums())
+			if err != nil {
+				return r, &cmderrors.PermissionDeniedError{Message: tr("Error computing the checksum of %s", artifact), Cause: err}
+			}
+			if req.GetWriteChecksums() {
+				if err := writeChecksumFile(artifact, sha256Sum); err != nil {
+					return r, &cmderrors.PermissionDeniedError{Message: tr("Error writing the checksum of %s", artifact), Cause: err}
+				}
+			}
+			r.Artifacts = append(r.Artifacts, &rpc.BuildArtifact{
+				Path:   artifact.String(),
+				Size:   size,
+				Type:   sketchBuilder.ArtifactType(artifact),
+				Sha256: sha256Sum,
+				Md5:    md5Sum,
+			})
+		}
+	} else {
+		logrus.WithError(err).Warn("Error listing build artifacts")
+	}
+
+	logrus.Tracef("Compile %s for %s successful", sk.Name, fqbn)
 
 	return r, nil
 }
@@ -373,23 +739,5 @@ func maybePurgeBuildCache() {
 	cacheTTL := configuration.Settings.GetDuration("build_cache.ttl").Abs()
 	buildcache.New(paths.TempDir().Join("arduino", "cores")).Purge(cacheTTL)
 	buildcache.New(paths.TempDir().Join("arduino", "sketches")).Purge(cacheTTL)
-}
-
-// removeBuildFromSketchFiles removes the files contained in the build directory from
-// the list of the sketch files
-func removeBuildFromSketchFiles(files paths.PathList, build *paths.Path) (paths.PathList, error) {
-	var res paths.PathList
-	ignored := false
-	for _, file := range files {
-		if isInside, _ := file.IsInsideDir(build); !isInside {
-			res = append(res, file)
-		} else if !ignored {
-			ignored = true
-		}
-	}
-	// log only if at least a file is ignored
-	if ignored {
-		logrus.Tracef("Build path %s is a child of sketch path and it is ignored for additional files.", build.String())
-	}
-	return res, nil
+	buildcache.New(paths.TempDir().Join("arduino", "cores-from-git")).Purge(cacheTTL)
 }
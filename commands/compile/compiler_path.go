@@ -0,0 +1,67 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+)
+
+// compilerCommands returns the executables of the compiler toolchain used
+// by the platform, taken from the compiler.*.cmd build properties
+func compilerCommands(buildProperties *properties.Map) []string {
+	res := []string{}
+	for _, key := range buildProperties.Keys() {
+		if !strings.HasPrefix(key, "compiler.") || !strings.HasSuffix(key, ".cmd") {
+			continue
+		}
+		cmd := buildProperties.ExpandPropsInString(buildProperties.Get(key))
+		if cmd != "" && !slices.Contains(res, cmd) {
+			res = append(res, cmd)
+		}
+	}
+	slices.Sort(res)
+	return res
+}
+
+// checkCompilerPath verifies that dir is a directory containing all the
+// executables of the compiler toolchain used by the platform
+func checkCompilerPath(dir *paths.Path, buildProperties *properties.Map) error {
+	if isDir, err := dir.IsDirCheck(); err != nil {
+		return err
+	} else if !isDir {
+		return fmt.Errorf(tr("%s is not a directory"), dir)
+	}
+	missing := []string{}
+	for _, cmd := range compilerCommands(buildProperties) {
+		executable := dir.Join(cmd)
+		if runtime.GOOS == "windows" && executable.Ext() == "" {
+			executable = dir.Join(cmd + ".exe")
+		}
+		if !executable.Exist() {
+			missing = append(missing, cmd)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf(tr("the following compiler executables are missing in %[1]s: %[2]s"), dir, strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckCompilerPath(t *testing.T) {
+	props := properties.NewMap()
+	props.Set("compiler.path", "{runtime.tools.avr-gcc.path}/bin/")
+	props.Set("compiler.prefix", "avr-")
+	props.Set("compiler.c.cmd", "{compiler.prefix}gcc")
+	props.Set("compiler.c.elf.cmd", "avr-gcc")
+	props.Set("compiler.cpp.cmd", "avr-g++")
+	props.Set("compiler.size.cmd", "avr-size")
+	props.Set("compiler.c.flags", "-c -g")
+	require.Equal(t, []string{"avr-g++", "avr-gcc", "avr-size"}, compilerCommands(props))
+
+	dir := paths.New(t.TempDir())
+	exe := ""
+	if runtime.GOOS == "windows" {
+		exe = ".exe"
+	}
+	require.NoError(t, dir.Join("avr-gcc"+exe).WriteFile([]byte{}))
+	require.NoError(t, dir.Join("avr-g++"+exe).WriteFile([]byte{}))
+	require.ErrorContains(t, checkCompilerPath(dir, props), "the following compiler executables are missing in "+dir.String()+": avr-size")
+
+	require.NoError(t, dir.Join("avr-size"+exe).WriteFile([]byte{}))
+	require.NoError(t, checkCompilerPath(dir, props))
+
+	require.ErrorContains(t, checkCompilerPath(dir.Join("avr-gcc"+exe), props), "is not a directory")
+	require.Error(t, checkCompilerPath(dir.Join("missing"), props))
+}
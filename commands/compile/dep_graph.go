@@ -0,0 +1,97 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/arduino/go-paths-helper"
+)
+
+// dependencyGraphFormats are the formats of the libraries dependency graph,
+// chosen by the extension of the file
+var dependencyGraphFormats = []string{".dot", ".json"}
+
+// checkDependencyGraphFile returns an error if the format of the dependency
+// graph can't be inferred from the extension of the file
+func checkDependencyGraphFile(file *paths.Path) error {
+	if !slices.Contains(dependencyGraphFormats, strings.ToLower(file.Ext())) {
+		return fmt.Errorf(tr("unsupported dependency graph format '%[1]s', the file extension must be one of: %[2]s"), file.Ext(), strings.Join(dependencyGraphFormats, ", "))
+	}
+	return nil
+}
+
+type dependencyGraphJSON struct {
+	Sketch string                    `json:"sketch"`
+	Nodes  []string                  `json:"nodes"`
+	Edges  []dependencyGraphJSONEdge `json:"edges"`
+}
+
+type dependencyGraphJSONEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// writeDependencyGraph writes the graph of the libraries included by the
+// sketch and by each library, in DOT or JSON format depending on the file
+// extension. The sketch is the first node, the libraries follow sorted by name.
+func writeDependencyGraph(file *paths.Path, sketchName string, graph map[string][]string) error {
+	nodes := []string{}
+	for node := range graph {
+		if node != sketchName {
+			nodes = append(nodes, node)
+		}
+	}
+	slices.Sort(nodes)
+	nodes = append([]string{sketchName}, nodes...)
+
+	var data []byte
+	if strings.ToLower(file.Ext()) == ".json" {
+		res := &dependencyGraphJSON{Sketch: sketchName, Nodes: nodes, Edges: []dependencyGraphJSONEdge{}}
+		for _, node := range nodes {
+			for _, dep := range graph[node] {
+				res.Edges = append(res.Edges, dependencyGraphJSONEdge{From: node, To: dep})
+			}
+		}
+		d, err := json.MarshalIndent(res, "", "  ")
+		if err != nil {
+			return err
+		}
+		data = d
+	} else {
+		var res bytes.Buffer
+		fmt.Fprintf(&res, "digraph %s {\n", strconv.Quote(sketchName))
+		for _, node := range nodes {
+			fmt.Fprintf(&res, "  %s;\n", strconv.Quote(node))
+		}
+		for _, node := range nodes {
+			for _, dep := range graph[node] {
+				fmt.Fprintf(&res, "  %s -> %s;\n", strconv.Quote(node), strconv.Quote(dep))
+			}
+		}
+		res.WriteString("}\n")
+		data = res.Bytes()
+	}
+	if err := file.Parent().MkdirAll(); err != nil {
+		return err
+	}
+	return file.WriteFile(data)
+}
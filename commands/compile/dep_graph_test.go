@@ -0,0 +1,63 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteDependencyGraph(t *testing.T) {
+	tmp := paths.New(t.TempDir())
+	graph := map[string][]string{
+		"Blink":  {"Servo", "Wire"},
+		"Wire":   {},
+		"Servo":  {"Wire"},
+		"Unused": {},
+	}
+
+	dot := tmp.Join("graph.dot")
+	require.NoError(t, checkDependencyGraphFile(dot))
+	require.NoError(t, writeDependencyGraph(dot, "Blink", graph))
+	data, err := dot.ReadFile()
+	require.NoError(t, err)
+	require.Equal(t, `digraph "Blink" {
+  "Blink";
+  "Servo";
+  "Unused";
+  "Wire";
+  "Blink" -> "Servo";
+  "Blink" -> "Wire";
+  "Servo" -> "Wire";
+}
+`, string(data))
+
+	jsonFile := tmp.Join("reports", "graph.JSON")
+	require.NoError(t, checkDependencyGraphFile(jsonFile))
+	require.NoError(t, writeDependencyGraph(jsonFile, "Blink", graph))
+	data, err = jsonFile.ReadFile()
+	require.NoError(t, err)
+	var res dependencyGraphJSON
+	require.NoError(t, json.Unmarshal(data, &res))
+	require.Equal(t, "Blink", res.Sketch)
+	require.Equal(t, []string{"Blink", "Servo", "Unused", "Wire"}, res.Nodes)
+	require.Equal(t, []dependencyGraphJSONEdge{{"Blink", "Servo"}, {"Blink", "Wire"}, {"Servo", "Wire"}}, res.Edges)
+
+	require.ErrorContains(t, checkDependencyGraphFile(tmp.Join("graph.txt")), "unsupported dependency graph format '.txt'")
+}
@@ -0,0 +1,94 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+
+	"github.com/arduino/arduino-cli/internal/arduino/sketch"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+)
+
+// depsCompilerFlagsKeys are the build properties holding the flags of the
+// compilers that generate the dependency files
+var depsCompilerFlagsKeys = []string{"compiler.c.flags", "compiler.cpp.flags", "compiler.S.flags"}
+
+// isDepsFlag returns true if the given compiler flag enables the generation of
+// the dependency files
+func isDepsFlag(flag string) bool {
+	return flag == "-MMD" || flag == "-MD"
+}
+
+// depsBuildProperties returns the build properties that enable the generation
+// of the dependency files, adding "-MMD" to the compiler flags lacking it.
+func depsBuildProperties(buildProperties *properties.Map) []string {
+	res := []string{}
+	for _, key := range depsCompilerFlagsKeys {
+		flags, ok := buildProperties.GetOk(key)
+		if !ok || slices.ContainsFunc(strings.Fields(flags), isDepsFlag) {
+			continue
+		}
+		res = append(res, key+"="+strings.TrimSpace(flags+" -MMD"))
+	}
+	return res
+}
+
+// exportDependencies merges all the dependency files found in the build path
+// into a single Makefile-compatible manifest. A rule stating that the
+// preprocessed sketch depends on the sketch files is added, since the
+// dependency files of the sketch refer to the preprocessed sketch only.
+func exportDependencies(sk *sketch.Sketch, buildPath, preprocessedSketch, manifest *paths.Path) error {
+	depsFiles, err := buildPath.ReadDirRecursiveFiltered(nil,
+		paths.FilterOutDirectories(),
+		paths.FilterSuffixes(".d"))
+	if err != nil {
+		return err
+	}
+	depsFiles.Sort()
+	// The manifest may be saved in the build path, it must not include itself
+	depsFiles.Filter(func(p *paths.Path) bool { return !p.EqualsTo(manifest) })
+
+	var res bytes.Buffer
+	sketchFiles := append(paths.PathList{sk.MainFile}, sk.OtherSketchFiles...)
+	res.WriteString(escapeMakePath(preprocessedSketch.String()) + ":")
+	for _, file := range sketchFiles {
+		res.WriteString(" " + escapeMakePath(file.String()))
+	}
+	res.WriteString("\n")
+	for _, depsFile := range depsFiles {
+		data, err := depsFile.ReadFile()
+		if err != nil {
+			return err
+		}
+		res.Write(data)
+		if len(data) > 0 && data[len(data)-1] != '\n' {
+			res.WriteString("\n")
+		}
+	}
+	if err := manifest.Parent().MkdirAll(); err != nil {
+		return err
+	}
+	return manifest.WriteFile(res.Bytes())
+}
+
+// escapeMakePath escapes the spaces in the given path, as done by the
+// compiler in the dependency files.
+func escapeMakePath(path string) string {
+	return strings.ReplaceAll(path, " ", "\\ ")
+}
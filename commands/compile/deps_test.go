@@ -0,0 +1,70 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/internal/arduino/sketch"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDepsBuildProperties(t *testing.T) {
+	props := properties.NewFromHashmap(map[string]string{
+		"compiler.c.flags":   "-c -g -Os -MMD",
+		"compiler.cpp.flags": "-c -g -Os",
+		"compiler.S.flags":   "-c -g -x assembler-with-cpp -MD",
+	})
+	require.Equal(t, []string{"compiler.cpp.flags=-c -g -Os -MMD"}, depsBuildProperties(props))
+
+	// Missing properties are not added
+	require.Empty(t, depsBuildProperties(properties.NewMap()))
+}
+
+func TestExportDependencies(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	sketchDir := tmp.Join("My Sketch")
+	sk := &sketch.Sketch{
+		MainFile:         sketchDir.Join("My Sketch.ino"),
+		OtherSketchFiles: paths.PathList{sketchDir.Join("other.ino")},
+	}
+	buildPath := tmp.Join("build")
+	preprocessed := buildPath.Join("sketch", "My Sketch.ino.cpp")
+	require.NoError(t, buildPath.Join("sketch").MkdirAll())
+	require.NoError(t, buildPath.Join("core").MkdirAll())
+	require.NoError(t, buildPath.Join("sketch", "My Sketch.ino.cpp.d").WriteFile([]byte("sketch.o: sketch.cpp lib.h\n")))
+	require.NoError(t, buildPath.Join("core", "main.cpp.d").WriteFile([]byte("main.o: main.cpp")))
+	require.NoError(t, buildPath.Join("core", "main.cpp.o").WriteFile([]byte{}))
+
+	// The manifest is saved in the build path, it must not be merged on rebuild
+	manifest := buildPath.Join("dependencies.d")
+	for i := 0; i < 2; i++ {
+		require.NoError(t, exportDependencies(sk, buildPath, preprocessed, manifest))
+		data, err := manifest.ReadFile()
+		require.NoError(t, err)
+		require.Equal(t,
+			escapeMakePath(preprocessed.String())+": "+escapeMakePath(sk.MainFile.String())+" "+escapeMakePath(sk.OtherSketchFiles[0].String())+"\n"+
+				"main.o: main.cpp\n"+
+				"sketch.o: sketch.cpp lib.h\n",
+			string(data))
+	}
+	require.Equal(t, "/My\\ Sketch/My\\ Sketch.ino", escapeMakePath("/My Sketch/My Sketch.ino"))
+}
@@ -0,0 +1,67 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+
+	"github.com/arduino/arduino-cli/commands/cmderrors"
+	"github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
+)
+
+// diskFullMessages are the messages printed by the tools, or by the operating
+// system, when the disk runs out of space
+var diskFullMessages = []string{
+	"No space left on device",
+	"There is not enough space on the disk",
+}
+
+// checkOutOfDiskSpace returns an OutOfDiskSpaceError if the given build error,
+// or the output of the failed command, shows that the disk containing the
+// build path ran out of space, otherwise it returns nil. The free space is
+// only reported: a disk that is almost full doesn't cause a build failure by
+// itself.
+func checkOutOfDiskSpace(buildErr error, output []byte, buildPath *paths.Path) error {
+	if !isDiskFull(buildErr, output) {
+		return nil
+	}
+	diskErr := &cmderrors.OutOfDiskSpaceError{Path: buildPath, Available: -1, Cause: buildErr}
+	if available, err := availableDiskSpace(buildPath); err != nil {
+		logrus.Debugf("Could not determine the space available in %s: %s", buildPath, err)
+	} else {
+		diskErr.Available = int64(available)
+	}
+	return diskErr
+}
+
+// isDiskFull returns true if the given build error, or the output of the
+// failed command, shows that the disk ran out of space.
+func isDiskFull(buildErr error, output []byte) bool {
+	for _, errno := range diskFullErrnos {
+		if errors.Is(buildErr, errno) {
+			return true
+		}
+	}
+	for _, msg := range diskFullMessages {
+		if strings.Contains(buildErr.Error(), msg) || bytes.Contains(output, []byte(msg)) {
+			return true
+		}
+	}
+	return false
+}
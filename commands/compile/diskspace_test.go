@@ -0,0 +1,66 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/arduino/arduino-cli/commands/cmderrors"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckOutOfDiskSpace(t *testing.T) {
+	buildPath := paths.New(t.TempDir())
+
+	err := checkOutOfDiskSpace(errors.New("exit status 1"), []byte("error: 'foo' was not declared in this scope"), buildPath)
+	require.NoError(t, err)
+
+	err = checkOutOfDiskSpace(errors.New("exit status 1"), []byte("Assembler messages:\nFatal error: can't write 40 bytes to section .text: No space left on device"), buildPath)
+	require.ErrorAs(t, err, new(*cmderrors.OutOfDiskSpaceError))
+
+	err = checkOutOfDiskSpace(errors.New("exit status 1"), []byte("ld.exe: final link failed: There is not enough space on the disk."), buildPath)
+	require.ErrorAs(t, err, new(*cmderrors.OutOfDiskSpaceError))
+
+	// The errors of the system calls are detected on each operating system
+	var diskErr *cmderrors.OutOfDiskSpaceError
+	for _, errno := range diskFullErrnos {
+		err = checkOutOfDiskSpace(fmt.Errorf("writing object file: %w", errno), nil, buildPath)
+		require.ErrorAs(t, err, &diskErr)
+		require.Equal(t, buildPath, diskErr.Path)
+		require.ErrorIs(t, err, errno)
+		require.Contains(t, err.Error(), buildPath.String())
+	}
+
+	// The available space is left out if it can't be determined
+	missingPath := buildPath.Join("missing")
+	err = checkOutOfDiskSpace(fmt.Errorf("writing object file: %w", diskFullErrnos[0]), nil, missingPath)
+	require.ErrorAs(t, err, &diskErr)
+	require.Equal(t, int64(-1), diskErr.Available)
+	require.Contains(t, err.Error(), missingPath.String())
+	require.NotContains(t, err.Error(), "bytes available")
+}
+
+func TestAvailableDiskSpace(t *testing.T) {
+	available, err := availableDiskSpace(paths.New(t.TempDir()))
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("the available disk space is not supported on this platform")
+	}
+	require.NoError(t, err)
+	require.NotZero(t, available)
+}
@@ -0,0 +1,39 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+//go:build linux || darwin
+
+package compile
+
+import (
+	"syscall"
+
+	"github.com/arduino/go-paths-helper"
+	"golang.org/x/sys/unix"
+)
+
+// diskFullErrnos are the errors returned by the system calls when the disk
+// runs out of space
+var diskFullErrnos = []error{syscall.ENOSPC}
+
+// availableDiskSpace returns the number of bytes available to the current
+// user on the filesystem containing the given path.
+func availableDiskSpace(path *paths.Path) (uint64, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path.String(), &stat); err != nil {
+		return 0, err
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
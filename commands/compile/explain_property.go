@@ -0,0 +1,109 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"github.com/arduino/arduino-cli/internal/arduino/cores"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+)
+
+// explainProperty returns the explanation of the build property key: the
+// default value, taken from the properties of the platform and the board,
+// where it's defined, and the final value after applying the overrides.
+func explainProperty(key string, defaults, final *properties.Map, board *cores.Board, fqbn *cores.FQBN, platforms ...*cores.PlatformRelease) *rpc.PropertyExplanation {
+	res := &rpc.PropertyExplanation{Key: key}
+	if defaultValue, ok := defaults.GetOk(key); ok {
+		res.DefaultValue = defaultValue
+		res.Source = propertySource(key, defaultValue, board, fqbn, platforms...)
+	}
+	if value, ok := final.GetOk(key); ok {
+		res.Defined = true
+		res.Value = value
+		res.ExpandedValue = final.ExpandPropsInString(value)
+	}
+	return res
+}
+
+// propertySource returns the platform or board file where the property key
+// is defined with the given value. The files are checked in the same order
+// used to compose the build properties, so the last one defining the key
+// wins. If the value doesn't come from any file, the property has been set
+// at runtime.
+func propertySource(key, value string, board *cores.Board, fqbn *cores.FQBN, platforms ...*cores.PlatformRelease) string {
+	source := ""
+	sourceValue := ""
+	check := func(file *paths.Path, props *properties.Map) {
+		if v, ok := props.GetOk(key); ok {
+			source = file.String()
+			sourceValue = v
+		}
+	}
+
+	seen := map[*cores.PlatformRelease]bool{}
+	for _, platform := range platforms {
+		if platform == nil || platform.InstallDir == nil || seen[platform] {
+			continue
+		}
+		seen[platform] = true
+		for _, name := range []string{"platform.txt", "platform.local.txt"} {
+			file := platform.InstallDir.Join(name)
+			if props, err := properties.SafeLoadFromPath(file); err == nil {
+				check(file, props)
+			}
+		}
+	}
+
+	if board != nil && board.PlatformRelease != nil && board.PlatformRelease.InstallDir != nil {
+		// The options selected in the FQBN, or the first value of each
+		// option if not selected, are applied over the board properties
+		selectedOptions := []string{}
+		for _, option := range board.GetConfigOptions().Keys() {
+			selected := fqbn.Configs.Get(option)
+			if selected == "" {
+				if values := board.Properties.SubTree("menu." + option).FirstLevelKeys(); len(values) > 0 {
+					selected = values[0]
+				}
+			}
+			selectedOptions = append(selectedOptions, "menu."+option+"."+selected)
+		}
+		type boardFile struct {
+			path  *paths.Path
+			props *properties.Map
+		}
+		boardFiles := []boardFile{}
+		for _, name := range []string{"boards.txt", "boards.local.txt"} {
+			file := board.PlatformRelease.InstallDir.Join(name)
+			if props, err := properties.SafeLoadFromPath(file); err == nil {
+				boardFiles = append(boardFiles, boardFile{file, props.SubTree(board.BoardID)})
+			}
+		}
+		for _, f := range boardFiles {
+			check(f.path, f.props)
+		}
+		for _, option := range selectedOptions {
+			for _, f := range boardFiles {
+				check(f.path, f.props.SubTree(option))
+			}
+		}
+	}
+
+	if source == "" || sourceValue != value {
+		return tr("set at runtime by arduino-cli")
+	}
+	return source
+}
@@ -0,0 +1,89 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/internal/arduino/cores"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainProperty(t *testing.T) {
+	dir := paths.New(t.TempDir())
+	require.NoError(t, dir.Join("platform.txt").WriteFile([]byte("compiler.c.flags=-c -g\ncompiler.warning_flags=-w\nbuild.extra_flags=\n")))
+	require.NoError(t, dir.Join("platform.local.txt").WriteFile([]byte("compiler.warning_flags=-Wall\n")))
+	require.NoError(t, dir.Join("boards.txt").WriteFile([]byte(""+
+		"menu.cpu=Processor\n"+
+		"uno.name=Uno\n"+
+		"uno.build.mcu=atmega328p\n"+
+		"uno.menu.cpu.atmega328=ATmega328P\n"+
+		"uno.menu.cpu.atmega328.build.f_cpu=16000000L\n"+
+		"uno.menu.cpu.atmega168=ATmega168\n"+
+		"uno.menu.cpu.atmega168.build.f_cpu=8000000L\n")))
+	require.NoError(t, dir.Join("boards.local.txt").WriteFile([]byte("uno.build.mcu=atmega328pb\n")))
+
+	platform := &cores.PlatformRelease{
+		Platform:   &cores.Platform{Architecture: "avr", Package: &cores.Package{Name: "test"}},
+		Menus:      properties.NewFromHashmap(map[string]string{"cpu": "Processor"}),
+		InstallDir: dir,
+	}
+	boardProperties := properties.NewMap()
+	boardProperties.Set("name", "Uno")
+	boardProperties.Set("build.mcu", "atmega328pb")
+	boardProperties.Set("menu.cpu.atmega328", "ATmega328P")
+	boardProperties.Set("menu.cpu.atmega328.build.f_cpu", "16000000L")
+	boardProperties.Set("menu.cpu.atmega168", "ATmega168")
+	boardProperties.Set("menu.cpu.atmega168.build.f_cpu", "8000000L")
+	board := &cores.Board{BoardID: "uno", Properties: boardProperties, PlatformRelease: platform}
+	fqbn := cores.MustParseFQBN("test:avr:uno:cpu=atmega168")
+
+	source := func(key, value string) string {
+		return propertySource(key, value, board, fqbn, platform, platform)
+	}
+	require.Equal(t, dir.Join("platform.txt").String(), source("compiler.c.flags", "-c -g"))
+	require.Equal(t, dir.Join("platform.local.txt").String(), source("compiler.warning_flags", "-Wall"))
+	require.Equal(t, dir.Join("boards.local.txt").String(), source("build.mcu", "atmega328pb"))
+	require.Equal(t, dir.Join("boards.txt").String(), source("build.f_cpu", "8000000L"))
+	require.Equal(t, dir.Join("boards.txt").String(), propertySource("build.f_cpu", "16000000L", board, cores.MustParseFQBN("test:avr:uno"), platform))
+	require.Equal(t, "set at runtime by arduino-cli", source("build.arch", "AVR"))
+	require.Equal(t, "set at runtime by arduino-cli", source("build.mcu", "atmega2560"))
+
+	defaults := properties.NewMap()
+	defaults.Set("compiler.warning_flags", "-Wall")
+	defaults.Set("build.mcu", "atmega328pb")
+	final := defaults.Clone()
+	final.Set("compiler.warning_flags", "-Wall -Wextra")
+	final.Set("build.extra_flags", "-DMCU={build.mcu}")
+
+	res := explainProperty("compiler.warning_flags", defaults, final, board, fqbn, platform)
+	require.Equal(t, "-Wall", res.GetDefaultValue())
+	require.Equal(t, dir.Join("platform.local.txt").String(), res.GetSource())
+	require.Equal(t, "-Wall -Wextra", res.GetValue())
+	require.True(t, res.GetDefined())
+
+	res = explainProperty("build.extra_flags", defaults, final, board, fqbn, platform)
+	require.Empty(t, res.GetDefaultValue())
+	require.Empty(t, res.GetSource())
+	require.Equal(t, "-DMCU={build.mcu}", res.GetValue())
+	require.Equal(t, "-DMCU=atmega328pb", res.GetExpandedValue())
+
+	res = explainProperty("missing.key", defaults, final, board, fqbn, platform)
+	require.False(t, res.GetDefined())
+	require.Empty(t, res.GetSource())
+}
@@ -0,0 +1,172 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/arduino/arduino-cli/internal/arduino/cores"
+	"github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
+)
+
+// errExportedFileExists is returned when an exported artifact would overwrite
+// an existing file
+var errExportedFileExists = errors.New(tr("the file already exists"))
+
+// exportArtifact copies the build artifact to the given destination. If the
+// destination already exists it's overwritten, unless noOverwrite is set, in
+// which case errExportedFileExists is returned, or backup is set, in which
+// case the existing file is first renamed with the ".bak" suffix.
+func exportArtifact(artifact, dest *paths.Path, noOverwrite, backup bool) error {
+	if dest.Exist() {
+		if noOverwrite {
+			return errExportedFileExists
+		}
+		if backup {
+			if err := dest.Rename(dest.Parent().Join(dest.Base() + ".bak")); err != nil {
+				return err
+			}
+		}
+	}
+	return artifact.CopyTo(dest)
+}
+
+// checkExportedFilesExist returns an error listing the artifacts that already
+// exist in the given directory. It's used when noOverwrite is set to fail
+// before copying any artifact, instead of leaving a partial export.
+func checkExportedFilesExist(artifacts paths.PathList, dir *paths.Path) error {
+	existing := []string{}
+	for _, artifact := range artifacts {
+		if dest := dir.Join(artifact.Base()); dest.Exist() {
+			existing = append(existing, dest.String())
+		}
+	}
+	if len(existing) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s: %w", strings.Join(existing, ", "), errExportedFileExists)
+}
+
+// copyToArtifactExtensions are the extensions of the final artifacts copied
+// to the destinations given with CopyTo, besides the main build artifact
+var copyToArtifactExtensions = []string{".hex", ".bin", ".elf"}
+
+// copyArtifactsTo copies the artifacts to each of the destinations, that must
+// be existing directories. The destinations are independent: if the copy to
+// one of them fails, the error is returned and the others are still copied.
+func copyArtifactsTo(artifacts paths.PathList, destinations []string, noOverwrite, backup bool) []error {
+	errs := []error{}
+	for _, destination := range destinations {
+		dir := paths.New(destination)
+		if dir == nil {
+			errs = append(errs, errors.New(tr("empty destination directory")))
+			continue
+		}
+		if isDir, err := dir.IsDirCheck(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", dir, err))
+			continue
+		} else if !isDir {
+			errs = append(errs, fmt.Errorf(tr("%s is not a directory"), dir))
+			continue
+		}
+		if noOverwrite {
+			if err := checkExportedFilesExist(artifacts, dir); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+		for _, artifact := range artifacts {
+			if err := exportArtifact(artifact, dir.Join(artifact.Base()), noOverwrite, backup); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", dir.Join(artifact.Base()), err))
+				break
+			}
+		}
+	}
+	return errs
+}
+
+// removeExportedFiles removes the given exported files, it's used to clean
+// up the export path when the export is interrupted
+func removeExportedFiles(files paths.PathList) {
+	for _, file := range files {
+		if err := file.RemoveAll(); err != nil {
+			logrus.WithError(err).WithField("path", file).Warn("Error removing exported file")
+		}
+	}
+}
+
+// exportDirName returns the name of the default export directory of the
+// given board, obtained by joining the parts of the FQBN (without the board
+// configuration) with the given separator, for example "arduino.avr.uno".
+// The separator must be safe to use in a file name.
+func exportDirName(fqbn *cores.FQBN, separator string) (string, error) {
+	if separator == "" {
+		separator = "."
+	}
+	if strings.ContainsAny(separator, `<>:"/\|?*`) || strings.IndexFunc(separator, unicode.IsControl) != -1 {
+		return "", fmt.Errorf(tr("invalid FQBN separator '%s': it must be usable in a file name"), separator)
+	}
+	return strings.ReplaceAll(fqbn.StringWithoutConfig(), ":", separator), nil
+}
+
+// validateSketchName checks that the name given to replace the sketch name
+// in the artifacts is usable in a file name
+func validateSketchName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return errors.New(tr("the sketch name cannot be empty"))
+	}
+	if strings.ContainsAny(name, `<>:"/\|?*`) || strings.IndexFunc(name, unicode.IsControl) != -1 {
+		return fmt.Errorf(tr("invalid sketch name '%s': it must be usable in a file name"), name)
+	}
+	return nil
+}
+
+// artifactChecksums returns the hex encoded SHA-256 checksum of the given
+// artifact and, if withMD5 is true, its hex encoded MD5 checksum
+func artifactChecksums(artifact *paths.Path, withMD5 bool) (string, string, error) {
+	f, err := artifact.Open()
+	if err != nil {
+		return "", "", err
+	}
+	defer f.Close()
+	sha256Hash := sha256.New()
+	if !withMD5 {
+		if _, err := io.Copy(sha256Hash, f); err != nil {
+			return "", "", err
+		}
+		return hex.EncodeToString(sha256Hash.Sum(nil)), "", nil
+	}
+	md5Hash := md5.New()
+	if _, err := io.Copy(io.MultiWriter(sha256Hash, md5Hash), f); err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(sha256Hash.Sum(nil)), hex.EncodeToString(md5Hash.Sum(nil)), nil
+}
+
+// writeChecksumFile writes the SHA-256 checksum of the artifact in a sidecar
+// file with the ".sha256" suffix, in the format used by the sha256sum tool.
+func writeChecksumFile(artifact *paths.Path, checksum string) error {
+	sidecar := artifact.Parent().Join(artifact.Base() + ".sha256")
+	return sidecar.WriteFile([]byte(checksum + "  " + artifact.Base() + "\n"))
+}
@@ -0,0 +1,135 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/internal/arduino/cores"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportArtifact(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	artifact := tmp.Join("Blink.ino.hex")
+	require.NoError(t, artifact.WriteFile([]byte("new")))
+	dest := tmp.Join("export", "Blink.ino.hex")
+	require.NoError(t, dest.Parent().MkdirAll())
+
+	// Missing destination
+	require.NoError(t, exportArtifact(artifact, dest, true, false))
+	require.FileExists(t, dest.String())
+
+	// Existing destination
+	require.NoError(t, dest.WriteFile([]byte("old")))
+	require.ErrorIs(t, exportArtifact(artifact, dest, true, false), errExportedFileExists)
+	data, err := dest.ReadFile()
+	require.NoError(t, err)
+	require.Equal(t, "old", string(data))
+
+	require.NoError(t, exportArtifact(artifact, dest, false, true))
+	data, err = dest.ReadFile()
+	require.NoError(t, err)
+	require.Equal(t, "new", string(data))
+	data, err = dest.Parent().Join("Blink.ino.hex.bak").ReadFile()
+	require.NoError(t, err)
+	require.Equal(t, "old", string(data))
+
+	require.NoError(t, dest.WriteFile([]byte("old")))
+	require.NoError(t, exportArtifact(artifact, dest, false, false))
+	data, err = dest.ReadFile()
+	require.NoError(t, err)
+	require.Equal(t, "new", string(data))
+}
+
+func TestCopyArtifactsTo(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	hex := tmp.Join("build", "Blink.ino.hex")
+	elf := tmp.Join("build", "Blink.ino.elf")
+	require.NoError(t, hex.Parent().MkdirAll())
+	require.NoError(t, hex.WriteFile([]byte("hex")))
+	require.NoError(t, elf.WriteFile([]byte("elf")))
+	release := tmp.Join("release")
+	staging := tmp.Join("staging")
+	require.NoError(t, release.MkdirAll())
+	require.NoError(t, staging.MkdirAll())
+
+	errs := copyArtifactsTo(paths.NewPathList(hex.String(), elf.String()), []string{release.String(), staging.String()}, false, false)
+	require.Empty(t, errs)
+	for _, dir := range []*paths.Path{release, staging} {
+		require.FileExists(t, dir.Join("Blink.ino.hex").String())
+		require.FileExists(t, dir.Join("Blink.ino.elf").String())
+	}
+
+	// A failing destination doesn't stop the copy to the others
+	require.NoError(t, staging.RemoveAll())
+	require.NoError(t, release.RemoveAll())
+	require.NoError(t, release.MkdirAll())
+	errs = copyArtifactsTo(paths.NewPathList(hex.String(), elf.String()), []string{staging.String(), hex.String(), release.String()}, false, false)
+	require.Len(t, errs, 2)
+	require.ErrorContains(t, errs[0], staging.String())
+	require.ErrorContains(t, errs[1], "is not a directory")
+	require.FileExists(t, release.Join("Blink.ino.hex").String())
+	require.FileExists(t, release.Join("Blink.ino.elf").String())
+
+	// With noOverwrite nothing is copied to a destination already containing
+	// one of the artifacts
+	require.NoError(t, staging.MkdirAll())
+	require.NoError(t, staging.Join("Blink.ino.elf").WriteFile([]byte("old")))
+	errs = copyArtifactsTo(paths.NewPathList(hex.String(), elf.String()), []string{staging.String()}, true, false)
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], errExportedFileExists)
+	require.ErrorContains(t, errs[0], staging.Join("Blink.ino.elf").String())
+	require.NoFileExists(t, staging.Join("Blink.ino.hex").String())
+}
+
+func TestExportDirName(t *testing.T) {
+	fqbn, err := cores.ParseFQBN("arduino:avr:nano:cpu=atmega328old")
+	require.NoError(t, err)
+
+	name, err := exportDirName(fqbn, "")
+	require.NoError(t, err)
+	require.Equal(t, "arduino.avr.nano", name)
+
+	name, err = exportDirName(fqbn, "_")
+	require.NoError(t, err)
+	require.Equal(t, "arduino_avr_nano", name)
+
+	name, err = exportDirName(fqbn, "--")
+	require.NoError(t, err)
+	require.Equal(t, "arduino--avr--nano", name)
+
+	for _, separator := range []string{"/", "\\", ":", "*", "\n"} {
+		_, err = exportDirName(fqbn, separator)
+		require.Error(t, err, separator)
+	}
+}
+
+func TestValidateSketchName(t *testing.T) {
+	for _, name := range []string{"firmware", "My Firmware", "fw-1.2.0"} {
+		require.NoError(t, validateSketchName(name), name)
+	}
+	for _, name := range []string{"", "  ", "fw/1", "fw\\1", "fw:1", "fw\n"} {
+		require.Error(t, validateSketchName(name), name)
+	}
+}
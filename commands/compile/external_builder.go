@@ -0,0 +1,88 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/arduino/go-paths-helper"
+)
+
+// externalBuilder describes the context of a build delegated to an external
+// arduino-builder binary.
+type externalBuilder struct {
+	builderPath         *paths.Path
+	sketchMainFile      *paths.Path
+	fqbn                string
+	buildPath           *paths.Path
+	coreBuildCachePath  *paths.Path
+	hardwareDirs        paths.PathList
+	librariesDirs       paths.PathList
+	builtInLibrariesDir *paths.Path
+	buildProperties     []string
+	warnings            string
+	verbose             bool
+}
+
+// args returns the command line of the arduino-builder binary. The hardware
+// folders are also searched for tools, like the IDE used to do.
+func (b *externalBuilder) args() []string {
+	args := []string{b.builderPath.String(), "-compile", "-logger", "human"}
+	for _, dir := range b.hardwareDirs {
+		args = append(args, "-hardware", dir.String())
+	}
+	for _, dir := range b.hardwareDirs {
+		args = append(args, "-tools", dir.String())
+	}
+	if b.builtInLibrariesDir != nil {
+		args = append(args, "-built-in-libraries", b.builtInLibrariesDir.String())
+	}
+	for _, dir := range b.librariesDirs {
+		args = append(args, "-libraries", dir.String())
+	}
+	args = append(args, "-fqbn", b.fqbn, "-build-path", b.buildPath.String())
+	if b.coreBuildCachePath != nil {
+		args = append(args, "-build-cache", b.coreBuildCachePath.String())
+	}
+	if b.warnings != "" {
+		args = append(args, "-warnings", b.warnings)
+	}
+	if b.verbose {
+		args = append(args, "-verbose")
+	}
+	for _, prop := range b.buildProperties {
+		args = append(args, "-prefs", prop)
+	}
+	return append(args, b.sketchMainFile.String())
+}
+
+// run runs the arduino-builder binary, streaming its output. The command
+// line is printed first in verbose mode.
+func (b *externalBuilder) run(ctx context.Context, outStream, errStream io.Writer) error {
+	args := b.args()
+	if b.verbose {
+		outStream.Write([]byte(strings.Join(args, " ") + "\n"))
+	}
+	proc, err := paths.NewProcess(nil, args...)
+	if err != nil {
+		return err
+	}
+	proc.RedirectStdoutTo(outStream)
+	proc.RedirectStderrTo(errStream)
+	return proc.RunWithinContext(ctx)
+}
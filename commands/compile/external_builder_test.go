@@ -0,0 +1,65 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExternalBuilderArgs(t *testing.T) {
+	builder := &externalBuilder{
+		builderPath:         paths.New("/opt/arduino-builder"),
+		sketchMainFile:      paths.New("/sketches/Blink/Blink.ino"),
+		fqbn:                "arduino:avr:uno",
+		buildPath:           paths.New("/tmp/build"),
+		coreBuildCachePath:  paths.New("/tmp/cores"),
+		hardwareDirs:        paths.NewPathList("/data/packages", "/user/hardware"),
+		librariesDirs:       paths.NewPathList("/user/libraries"),
+		builtInLibrariesDir: paths.New("/ide/libraries"),
+		buildProperties:     []string{"build.extra_flags=-DDEBUG"},
+		warnings:            "all",
+		verbose:             true,
+	}
+	require.Equal(t, []string{
+		"/opt/arduino-builder", "-compile", "-logger", "human",
+		"-hardware", "/data/packages", "-hardware", "/user/hardware",
+		"-tools", "/data/packages", "-tools", "/user/hardware",
+		"-built-in-libraries", "/ide/libraries",
+		"-libraries", "/user/libraries",
+		"-fqbn", "arduino:avr:uno", "-build-path", "/tmp/build",
+		"-build-cache", "/tmp/cores",
+		"-warnings", "all",
+		"-verbose",
+		"-prefs", "build.extra_flags=-DDEBUG",
+		"/sketches/Blink/Blink.ino",
+	}, builder.args())
+
+	// The optional arguments are left out when not set
+	builder = &externalBuilder{
+		builderPath:    paths.New("/opt/arduino-builder"),
+		sketchMainFile: paths.New("/sketches/Blink/Blink.ino"),
+		fqbn:           "arduino:avr:uno",
+		buildPath:      paths.New("/tmp/build"),
+	}
+	require.Equal(t, []string{
+		"/opt/arduino-builder", "-compile", "-logger", "human",
+		"-fqbn", "arduino:avr:uno", "-build-path", "/tmp/build",
+		"/sketches/Blink/Blink.ino",
+	}, builder.args())
+}
@@ -0,0 +1,95 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/arduino/go-properties-orderedmap"
+)
+
+// extraFlagsScopes maps the scopes allowed in the extra flags to the build
+// property that receives the flags.
+var extraFlagsScopes = map[string]string{
+	"all": "build.extra_flags",
+	"c":   "compiler.c.extra_flags",
+	"cpp": "compiler.cpp.extra_flags",
+	"S":   "compiler.S.extra_flags",
+	"ld":  "compiler.c.elf.extra_flags",
+}
+
+// extraFlagsBuildProperties converts the given extra flags, in the form
+// "[scope:]flags", into build properties that append the flags to the value
+// that the corresponding property has in the given build properties.
+func extraFlagsBuildProperties(extraFlags []string, buildProperties *properties.Map) ([]string, error) {
+	// The keys are kept in the order they are first used: setting again a key
+	// of a properties.Map would move it to the end
+	keys := []string{}
+	appended := map[string]string{}
+	for _, extra := range extraFlags {
+		key := extraFlagsScopes["all"]
+		flags := extra
+		if scope, scopedFlags, ok := strings.Cut(extra, ":"); ok && !strings.HasPrefix(scope, "-") {
+			scopeKey, ok := extraFlagsScopes[scope]
+			if !ok {
+				return nil, fmt.Errorf(tr("invalid scope '%[1]s' in extra flags '%[2]s'"), scope, extra)
+			}
+			key = scopeKey
+			flags = scopedFlags
+		}
+		current, ok := appended[key]
+		if !ok {
+			current = buildProperties.Get(key)
+			keys = append(keys, key)
+		}
+		appended[key] = strings.TrimSpace(current + " " + flags)
+	}
+
+	res := []string{}
+	for _, key := range keys {
+		res = append(res, key+"="+appended[key])
+	}
+	return res, nil
+}
+
+// defineFlag returns the compiler flag defining the given preprocessor macro,
+// in the form "NAME" or "NAME=VALUE". The flag is quoted, if needed, to be
+// kept as a single argument when the recipe command line is split.
+func defineFlag(define string) (string, error) {
+	name, _, _ := strings.Cut(define, "=")
+	if name == "" || strings.ContainsAny(name, " \t\"'") {
+		return "", fmt.Errorf(tr("invalid define '%s'"), define)
+	}
+	flag := "-D" + define
+	if !strings.Contains(flag, " ") {
+		return flag, nil
+	}
+	// The recipe splitter ends a quoted argument on the first word ending
+	// with the quote char: pick a quote char not used that way in the value
+	for _, quote := range []string{"\"", "'"} {
+		usable := true
+		for _, word := range strings.Split(flag, " ")[:strings.Count(flag, " ")] {
+			if strings.HasSuffix(word, quote) {
+				usable = false
+			}
+		}
+		if usable {
+			return quote + flag + quote, nil
+		}
+	}
+	return "", fmt.Errorf(tr("invalid define '%s'"), define)
+}
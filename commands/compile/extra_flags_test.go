@@ -0,0 +1,76 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"testing"
+
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtraFlagsBuildProperties(t *testing.T) {
+	props := properties.NewFromHashmap(map[string]string{
+		"build.extra_flags":        "-DARDUINO_USB",
+		"compiler.cpp.extra_flags": "",
+	})
+
+	res, err := extraFlagsBuildProperties([]string{
+		"-DFOO",
+		"cpp:-fno-exceptions",
+		"all:-DBAR",
+		"ld:-Wl,--gc-sections",
+	}, props)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"build.extra_flags=-DARDUINO_USB -DFOO -DBAR",
+		"compiler.cpp.extra_flags=-fno-exceptions",
+		"compiler.c.elf.extra_flags=-Wl,--gc-sections",
+	}, res)
+
+	res, err = extraFlagsBuildProperties(nil, props)
+	require.NoError(t, err)
+	require.Empty(t, res)
+
+	_, err = extraFlagsBuildProperties([]string{"rust:-O3"}, props)
+	require.Error(t, err)
+}
+
+func TestDefineFlag(t *testing.T) {
+	for define, expected := range map[string]string{
+		"DEBUG":             "-DDEBUG",
+		"LEVEL=2":           "-DLEVEL=2",
+		"URL=http://a.b/c":  "-DURL=http://a.b/c",
+		`NAME="led"`:        `-DNAME="led"`,
+		`MSG="hello world"`: `"-DMSG="hello world""`,
+		`MSG="say" "hi"`:    `'-DMSG="say" "hi"'`,
+		"EMPTY=":            "-DEMPTY=",
+	} {
+		flag, err := defineFlag(define)
+		require.NoError(t, err, define)
+		require.Equal(t, expected, flag, define)
+
+		// The flag must be split back to a single argument
+		args, err := properties.SplitQuotedString(flag, `"'`, false)
+		require.NoError(t, err, define)
+		require.Equal(t, []string{"-D" + define}, args, define)
+	}
+
+	for _, define := range []string{"", "=1", "MY DEFINE=1"} {
+		_, err := defineFlag(define)
+		require.Error(t, err, define)
+	}
+}
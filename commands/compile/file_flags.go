@@ -0,0 +1,70 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/arduino/go-paths-helper"
+)
+
+// parseFileFlags parses the extra compiler flags of single source files, in
+// the form "path=flags", into a map from the absolute paths of the files to
+// their flags. The relative paths are resolved from the sketch folder and
+// the flags given more than once for a file are joined. The files in the
+// excluded folders, the ones of the core and of the variant that are compiled
+// once and cached, can't be targeted.
+func parseFileFlags(fileFlags []string, sketchPath *paths.Path, excludedDirs paths.PathList) (map[string]string, error) {
+	res := map[string]string{}
+	for _, fileFlag := range fileFlags {
+		file, flags, ok := strings.Cut(fileFlag, "=")
+		flags = strings.TrimSpace(flags)
+		if !ok || file == "" || flags == "" {
+			return nil, fmt.Errorf(tr("invalid file flags '%s', they must be in the form path=flags"), fileFlag)
+		}
+		path := paths.New(file)
+		if !path.IsAbs() {
+			path = sketchPath.JoinPath(path)
+		}
+		if !path.IsNotDir() {
+			return nil, fmt.Errorf(tr("the file %s doesn't exist"), file)
+		}
+		for _, dir := range excludedDirs {
+			if inside, _ := path.IsInsideDir(dir); inside {
+				return nil, fmt.Errorf(tr("the file %s belongs to the core or to the variant of the board, the flags can be applied only to the files of the sketch and of the libraries"), file)
+			}
+		}
+		if previous, ok := res[path.String()]; ok {
+			flags = previous + " " + flags
+		}
+		res[path.String()] = flags
+	}
+	return res, nil
+}
+
+// fileFlagsBuildProperty returns the value of the build property recording
+// the given per-file flags: a change of the flags changes the build options
+// and forces a full rebuild.
+func fileFlagsBuildProperty(fileFlags map[string]string) string {
+	files := []string{}
+	for file, flags := range fileFlags {
+		files = append(files, file+"="+flags)
+	}
+	sort.Strings(files)
+	return strings.Join(files, ";")
+}
@@ -0,0 +1,52 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFileFlags(t *testing.T) {
+	dir := paths.New(t.TempDir())
+	sketchPath := dir.Join("Blink")
+	corePath := dir.Join("cores", "arduino")
+	for _, file := range []*paths.Path{sketchPath.Join("Blink.ino"), sketchPath.Join("src", "driver.cpp"), dir.Join("Servo.cpp"), corePath.Join("main.cpp")} {
+		require.NoError(t, file.Parent().MkdirAll())
+		require.NoError(t, file.WriteFile([]byte{}))
+	}
+
+	fileFlags, err := parseFileFlags([]string{"src/driver.cpp=-O0", dir.Join("Servo.cpp").String() + "=-Wno-unused", "src/driver.cpp=-g3"}, sketchPath, paths.PathList{corePath})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		sketchPath.Join("src", "driver.cpp").String(): "-O0 -g3",
+		dir.Join("Servo.cpp").String():                "-Wno-unused",
+	}, fileFlags)
+	require.Equal(t, sketchPath.Join("src", "driver.cpp").String()+"=-O0 -g3;"+dir.Join("Servo.cpp").String()+"=-Wno-unused", fileFlagsBuildProperty(fileFlags))
+
+	_, err = parseFileFlags([]string{"src/driver.cpp"}, sketchPath, nil)
+	require.EqualError(t, err, "invalid file flags 'src/driver.cpp', they must be in the form path=flags")
+	_, err = parseFileFlags([]string{"src/driver.cpp= "}, sketchPath, nil)
+	require.Error(t, err)
+	_, err = parseFileFlags([]string{"src/missing.cpp=-O0"}, sketchPath, nil)
+	require.EqualError(t, err, "the file src/missing.cpp doesn't exist")
+	_, err = parseFileFlags([]string{"src=-O0"}, sketchPath, nil)
+	require.EqualError(t, err, "the file src doesn't exist")
+	_, err = parseFileFlags([]string{corePath.Join("main.cpp").String() + "=-O0"}, sketchPath, paths.PathList{corePath})
+	require.ErrorContains(t, err, "belongs to the core or to the variant of the board")
+}
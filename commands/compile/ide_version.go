@@ -0,0 +1,46 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+var (
+	numericIDEVersionRegexp = regexp.MustCompile(`^[1-9][0-9]{2,5}$`)
+	dottedIDEVersionRegexp  = regexp.MustCompile(`^([0-9]{1,2})\.([0-9]{1,2})\.([0-9]{1,2})$`)
+)
+
+// ideVersionNumber returns the value of runtime.ide.version, and of the
+// ARDUINO macro defined by the platform recipes, for the given IDE version.
+// The version may be given in the numeric format, for example "10819", or
+// in the dotted format, for example "1.8.19".
+func ideVersionNumber(version string) (string, error) {
+	if numericIDEVersionRegexp.MatchString(version) {
+		return version, nil
+	}
+	if match := dottedIDEVersionRegexp.FindStringSubmatch(version); match != nil {
+		major, _ := strconv.Atoi(match[1])
+		minor, _ := strconv.Atoi(match[2])
+		patch, _ := strconv.Atoi(match[3])
+		if major > 0 {
+			return strconv.Itoa(major*10000 + minor*100 + patch), nil
+		}
+	}
+	return "", fmt.Errorf(tr("invalid IDE version '%s': use a number like 10819 or a version like 1.8.19"), version)
+}
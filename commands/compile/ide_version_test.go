@@ -0,0 +1,42 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIDEVersionNumber(t *testing.T) {
+	for version, expected := range map[string]string{
+		"10819":  "10819",
+		"10607":  "10607",
+		"105":    "105",
+		"1.8.19": "10819",
+		"1.6.7":  "10607",
+		"2.3.2":  "20302",
+	} {
+		number, err := ideVersionNumber(version)
+		require.NoError(t, err, version)
+		require.Equal(t, expected, number, version)
+	}
+
+	for _, version := range []string{"", "0", "01819", "1.8", "1.8.19.1", "0.1.2", "1.100.0", "v1.8.19", "10819a", "1234567"} {
+		_, err := ideVersionNumber(version)
+		require.Error(t, err, version)
+	}
+}
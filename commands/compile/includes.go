@@ -0,0 +1,157 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"context"
+	"io"
+
+	"github.com/arduino/arduino-cli/commands/cmderrors"
+	"github.com/arduino/arduino-cli/commands/internal/instances"
+	"github.com/arduino/arduino-cli/internal/arduino/builder"
+	"github.com/arduino/arduino-cli/internal/arduino/libraries/librariesmanager"
+	"github.com/arduino/arduino-cli/internal/arduino/libraries/librariesresolver"
+	"github.com/arduino/arduino-cli/internal/cli/configuration"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	paths "github.com/arduino/go-paths-helper"
+	properties "github.com/arduino/go-properties-orderedmap"
+)
+
+// ResolveIncludes returns the libraries a build of the given request would use
+// for the given includes, without compiling the sketch and without writing
+// anything to disk. The includes provided by the core, the variant or the
+// toolchain are not in the result, the ones not provided by any library are
+// mapped to nil.
+func ResolveIncludes(ctx context.Context, req *rpc.CompileRequest, includes []string) (map[string]*rpc.Library, error) {
+	pme, release, err := instances.GetPackageManagerExplorer(req.GetInstance())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	lm, err := instances.GetLibraryManager(req.GetInstance())
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := resolveBuildTarget(pme, req, io.Discard)
+	if err != nil {
+		return nil, err
+	}
+	otherLibrariesDirs, builtInLibrariesDir, err := librariesFolders(req, pme.GetProfile() != nil)
+	if err != nil {
+		return nil, err
+	}
+	var libsManager *librariesmanager.LibrariesManager
+	if pme.GetProfile() != nil {
+		libsManager = lm
+	}
+	librariesLocationsOrder, err := librariesresolver.ParseLocationsOrder(req.GetLibraryPathOrder())
+	if err != nil {
+		return nil, &cmderrors.InvalidArgumentError{Message: tr("Invalid library path order"), Cause: err}
+	}
+	resolver, err := builder.NewLibrariesResolver(
+		libsManager,
+		builtInLibrariesDir, paths.NewPathList(req.GetLibrary()...), otherLibrariesDirs,
+		target.buildPlatform, target.targetPlatform,
+		builder.Options{
+			LibrariesLocationsOrder: librariesLocationsOrder,
+			OnlyExplicitLibraries:   req.GetOnlyExplicitLibraries(),
+		},
+	)
+	if err != nil {
+		return nil, &cmderrors.InvalidArgumentError{Message: tr("Invalid libraries folder"), Cause: err}
+	}
+
+	// The build searches the core, the variant and the toolchain before the
+	// libraries, their headers are never resolved to a library
+	systemIncludeDirs := platformIncludeDirs(target.buildProperties)
+	res := map[string]*rpc.Library{}
+	for _, include := range includes {
+		if isSystemInclude(include, systemIncludeDirs) {
+			continue
+		}
+		res[include] = nil
+		lib := resolver.ResolveFor(include, target.targetPlatform.Platform.Architecture)
+		if lib == nil {
+			continue
+		}
+		if res[include], err = lib.ToRPCLibrary(); err != nil {
+			return nil, &cmderrors.PermissionDeniedError{Message: tr("Error getting information for library %s", lib.Name), Cause: err}
+		}
+	}
+	return res, nil
+}
+
+// librariesFolders returns the folders searched for the libraries by a build
+// of the given request: the libraries folders and the built-in libraries one.
+func librariesFolders(req *rpc.CompileRequest, withProfile bool) (paths.PathList, *paths.Path, error) {
+	otherLibrariesDirs := paths.NewPathList(req.GetLibraries()...)
+	builtInLibrariesDir := configuration.IDEBuiltinLibrariesDir(configuration.Settings)
+	if req.GetOnlyExplicitLibraries() {
+		if withProfile {
+			return nil, nil, &cmderrors.InvalidArgumentError{Message: tr("Only explicitly specified libraries can't be used while compiling with a profile")}
+		}
+		builtInLibrariesDir = nil
+	} else if userLibrariesDir := configuration.LibrariesDir(configuration.Settings); userLibrariesDir != nil {
+		otherLibrariesDirs.Add(userLibrariesDir)
+	}
+	return otherLibrariesDirs, builtInLibrariesDir, nil
+}
+
+// platformIncludeDirs returns the folders searched for the includes before the
+// libraries: the core, the variant and the include folders of the toolchain.
+func platformIncludeDirs(buildProperties *properties.Map) paths.PathList {
+	expandedPath := func(key string) *paths.Path {
+		return paths.New(buildProperties.ExpandPropsInString(buildProperties.Get(key)))
+	}
+	dirs := paths.PathList{}
+	for _, key := range []string{"build.core.path", "build.variant.path"} {
+		if dir := expandedPath(key); dir != nil && dir.IsDir() {
+			dirs.Add(dir)
+		}
+	}
+	// The toolchain include folders are not listed by the platform, they are
+	// searched in the installation folder of the compiler
+	compilerPath := expandedPath("compiler.path")
+	if compilerPath == nil {
+		return dirs
+	}
+	toolchainDir := compilerPath.Clean()
+	if toolchainDir.Base() == "bin" {
+		toolchainDir = toolchainDir.Parent()
+	}
+	toolchainIncludeDirs, err := toolchainDir.ReadDirRecursiveFiltered(
+		paths.FilterOutNames("include", "include-fixed"),
+		paths.FilterDirectories(),
+		paths.FilterNames("include", "include-fixed"))
+	if err != nil {
+		return dirs
+	}
+	dirs.AddAll(toolchainIncludeDirs)
+	return dirs
+}
+
+// isSystemInclude returns true if the given include is found in one of the
+// given folders.
+func isSystemInclude(include string, dirs paths.PathList) bool {
+	for _, dir := range dirs {
+		if dir.Join(include).Exist() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlatformIncludeDirs(t *testing.T) {
+	root := paths.New(t.TempDir())
+	corePath := root.Join("hardware", "arduino", "avr", "cores", "arduino")
+	variantPath := root.Join("hardware", "arduino", "avr", "variants", "standard")
+	toolchainPath := root.Join("tools", "avr-gcc", "7.3.0")
+	for _, f := range []*paths.Path{
+		corePath.Join("Arduino.h"),
+		variantPath.Join("pins_arduino.h"),
+		toolchainPath.Join("avr", "include", "avr", "io.h"),
+		toolchainPath.Join("lib", "gcc", "avr", "7.3.0", "include", "stdint.h"),
+	} {
+		require.NoError(t, f.Parent().MkdirAll())
+		require.NoError(t, f.WriteFile([]byte{}))
+	}
+	require.NoError(t, toolchainPath.Join("bin").MkdirAll())
+
+	buildProperties := properties.NewFromHashmap(map[string]string{
+		"build.core.path":    corePath.String(),
+		"build.variant.path": variantPath.String(),
+		// The paths are expanded as the build would do
+		"compiler.path":              "{runtime.tools.avr-gcc.path}/bin/",
+		"runtime.tools.avr-gcc.path": toolchainPath.String(),
+	})
+	dirs := platformIncludeDirs(buildProperties)
+	require.True(t, isSystemInclude("Arduino.h", dirs))
+	require.True(t, isSystemInclude("pins_arduino.h", dirs))
+	require.True(t, isSystemInclude("avr/io.h", dirs))
+	require.True(t, isSystemInclude("stdint.h", dirs))
+	require.False(t, isSystemInclude("Servo.h", dirs))
+	require.False(t, isSystemInclude("io.h", dirs))
+
+	// Without the build properties only the libraries are searched
+	require.False(t, isSystemInclude("Arduino.h", platformIncludeDirs(properties.NewMap())))
+}
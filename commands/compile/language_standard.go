@@ -0,0 +1,104 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/arduino/arduino-cli/internal/arduino/cores"
+)
+
+// cppStandards maps the C++ standards accepted by --cpp-std to the first
+// major version of GCC that supports them.
+var cppStandards = map[string]int{
+	"c++98": 3, "gnu++98": 3,
+	"c++03": 3, "gnu++03": 3,
+	"c++11": 5, "gnu++11": 5,
+	"c++14": 5, "gnu++14": 5,
+	"c++17": 7, "gnu++17": 7,
+	"c++20": 10, "gnu++20": 10,
+	"c++23": 11, "gnu++23": 11,
+}
+
+// cStandards maps the C standards accepted by --c-std to the first major
+// version of GCC that supports them.
+var cStandards = map[string]int{
+	"c89": 3, "gnu89": 3,
+	"c90": 3, "gnu90": 3,
+	"c99": 5, "gnu99": 5,
+	"c11": 5, "gnu11": 5,
+	"c17": 8, "gnu17": 8,
+	"c18": 8, "gnu18": 8,
+	"c23": 14, "gnu23": 14,
+}
+
+// languageStandardFlag returns the compiler flag selecting the given
+// language standard, that must be one of the given known standards.
+func languageStandardFlag(std string, knownStandards map[string]int) (string, error) {
+	std = strings.ToLower(strings.TrimSpace(std))
+	if _, ok := knownStandards[std]; !ok {
+		known := []string{}
+		for s := range knownStandards {
+			known = append(known, s)
+		}
+		sort.Strings(known)
+		return "", fmt.Errorf(tr("unknown language standard '%[1]s', must be one of: %[2]s"), std, strings.Join(known, ", "))
+	}
+	return "-std=" + std, nil
+}
+
+// languageStandardWarning returns a warning if the GCC toolchain among the
+// given tools is likely too old to support the requested language standard.
+// An empty string is returned if the toolchain supports it or if its version
+// can't be determined.
+func languageStandardWarning(std string, knownStandards map[string]int, tools []*cores.ToolRelease) string {
+	std = strings.ToLower(strings.TrimSpace(std))
+	for _, tool := range tools {
+		if !strings.HasSuffix(tool.Tool.Name, "gcc") || tool.Version == nil {
+			continue
+		}
+		major, ok := gccMajorVersion(tool.Version.String())
+		if !ok || major >= knownStandards[std] {
+			return ""
+		}
+		return tr("The toolchain %[1]s likely doesn't support the %[2]s standard, GCC %[3]d or later is required.", tool, std, knownStandards[std])
+	}
+	return ""
+}
+
+// gccMajorVersion extracts the major version from the version of a GCC
+// toolchain, for example 7 from "7.3.0-atmel3.6.1-arduino7". The toolchains
+// versioned after their vendor release end with the GCC version, for example
+// 8 is extracted from "esp-2021r2-patch5-8.4.0".
+func gccMajorVersion(version string) (int, bool) {
+	leadingNumber := func(s string) (int, bool) {
+		if i := strings.IndexFunc(s, func(r rune) bool { return r < '0' || r > '9' }); i != -1 {
+			s = s[:i]
+		}
+		n, err := strconv.Atoi(s)
+		return n, err == nil
+	}
+	if major, ok := leadingNumber(version); ok {
+		return major, true
+	}
+	if i := strings.LastIndex(version, "-"); i != -1 && strings.Contains(version[i+1:], ".") {
+		return leadingNumber(version[i+1:])
+	}
+	return 0, false
+}
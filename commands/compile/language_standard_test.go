@@ -0,0 +1,85 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/internal/arduino/cores"
+	"github.com/stretchr/testify/require"
+	semver "go.bug.st/relaxed-semver"
+)
+
+func TestLanguageStandardFlag(t *testing.T) {
+	flag, err := languageStandardFlag("c++17", cppStandards)
+	require.NoError(t, err)
+	require.Equal(t, "-std=c++17", flag)
+
+	flag, err = languageStandardFlag("GNU11", cStandards)
+	require.NoError(t, err)
+	require.Equal(t, "-std=gnu11", flag)
+
+	_, err = languageStandardFlag("c++17", cStandards)
+	require.Error(t, err)
+	_, err = languageStandardFlag("c++42", cppStandards)
+	require.Error(t, err)
+}
+
+func TestGCCMajorVersion(t *testing.T) {
+	major, ok := gccMajorVersion("7.3.0-atmel3.6.1-arduino7")
+	require.True(t, ok)
+	require.Equal(t, 7, major)
+
+	major, ok = gccMajorVersion("10")
+	require.True(t, ok)
+	require.Equal(t, 10, major)
+
+	major, ok = gccMajorVersion("esp-2021r2-patch5-8.4.0")
+	require.True(t, ok)
+	require.Equal(t, 8, major)
+
+	major, ok = gccMajorVersion("esp-12.2.0_20230208")
+	require.True(t, ok)
+	require.Equal(t, 12, major)
+
+	_, ok = gccMajorVersion("latest")
+	require.False(t, ok)
+	_, ok = gccMajorVersion("esp-2021r2")
+	require.False(t, ok)
+}
+
+func TestLanguageStandardWarning(t *testing.T) {
+	pkg := &cores.Package{Name: "arduino"}
+	tool := func(name, version string) *cores.ToolRelease {
+		return &cores.ToolRelease{Tool: &cores.Tool{Name: name, Package: pkg}, Version: semver.ParseRelaxed(version)}
+	}
+	avrGCC := tool("avr-gcc", "7.3.0-atmel3.6.1-arduino7")
+	avrdude := tool("avrdude", "6.3.0-arduino17")
+
+	require.Empty(t, languageStandardWarning("gnu++17", cppStandards, []*cores.ToolRelease{avrdude, avrGCC}))
+	require.Equal(t,
+		"The toolchain arduino:avr-gcc@7.3.0-atmel3.6.1-arduino7 likely doesn't support the c++20 standard, GCC 10 or later is required.",
+		languageStandardWarning("C++20", cppStandards, []*cores.ToolRelease{avrdude, avrGCC}))
+
+	// The vendor releases are checked against the GCC version they end with
+	espGCC := tool("xtensa-esp32-elf-gcc", "esp-2021r2-patch5-8.4.0")
+	require.Empty(t, languageStandardWarning("gnu17", cStandards, []*cores.ToolRelease{espGCC}))
+	require.NotEmpty(t, languageStandardWarning("gnu++20", cppStandards, []*cores.ToolRelease{espGCC}))
+
+	// No warning if the version of the toolchain is unknown
+	require.Empty(t, languageStandardWarning("c++23", cppStandards, []*cores.ToolRelease{tool("avr-gcc", "latest")}))
+	require.Empty(t, languageStandardWarning("c++23", cppStandards, []*cores.ToolRelease{avrdude}))
+}
@@ -0,0 +1,65 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/arduino/arduino-cli/commands/cmderrors"
+	"github.com/arduino/arduino-cli/internal/arduino/libraries"
+	"github.com/arduino/arduino-cli/internal/arduino/libraries/librariesresolver"
+)
+
+// parseAllowedLibrarySources converts the names of the library sources
+// allowed in the build into the corresponding library locations. The names
+// are the same used for the libraries locations order: "sketchbook",
+// "bundled" and "core", plus "unmanaged" for the libraries given explicitly.
+func parseAllowedLibrarySources(names []string) (map[libraries.LibraryLocation]bool, error) {
+	res := map[libraries.LibraryLocation]bool{}
+	for _, name := range names {
+		if strings.EqualFold(strings.TrimSpace(name), "unmanaged") {
+			res[libraries.Unmanaged] = true
+			continue
+		}
+		locations, ok := librariesresolver.ParseLocation(name)
+		if !ok {
+			return nil, fmt.Errorf(tr("invalid library source '%[1]s', valid sources are: %[2]s"), name, "sketchbook, bundled, core, unmanaged")
+		}
+		for _, location := range locations {
+			res[location] = true
+		}
+	}
+	return res, nil
+}
+
+// checkLibrarySources verifies that all the libraries used by the build come
+// from the allowed sources, the returned error lists the other libraries
+// with their location.
+func checkLibrarySources(used libraries.List, allowed map[libraries.LibraryLocation]bool) error {
+	disallowed := []string{}
+	for _, lib := range used {
+		if !allowed[lib.Location] {
+			disallowed = append(disallowed, fmt.Sprintf("%s (%s)", lib.Name, lib.InstallDir))
+		}
+	}
+	if len(disallowed) == 0 {
+		return nil
+	}
+	return &cmderrors.CompileFailedError{
+		Message: tr("The following libraries come from a source that is not allowed: %s", strings.Join(disallowed, ", ")),
+	}
+}
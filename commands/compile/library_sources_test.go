@@ -0,0 +1,55 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/internal/arduino/libraries"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAllowedLibrarySources(t *testing.T) {
+	allowed, err := parseAllowedLibrarySources([]string{"sketchbook", " Core "})
+	require.NoError(t, err)
+	require.Equal(t, map[libraries.LibraryLocation]bool{
+		libraries.User:                      true,
+		libraries.PlatformBuiltIn:           true,
+		libraries.ReferencedPlatformBuiltIn: true,
+	}, allowed)
+
+	_, err = parseAllowedLibrarySources([]string{"sketchbook", "ide"})
+	require.EqualError(t, err, "invalid library source 'ide', valid sources are: sketchbook, bundled, core, unmanaged")
+}
+
+func TestCheckLibrarySources(t *testing.T) {
+	used := libraries.List{
+		{Name: "Servo", Location: libraries.User, InstallDir: paths.New("/sketchbook/libraries/Servo")},
+		{Name: "EEPROM", Location: libraries.PlatformBuiltIn, InstallDir: paths.New("/avr/libraries/EEPROM")},
+		{Name: "Ethernet", Location: libraries.IDEBuiltIn, InstallDir: paths.New("/ide/libraries/Ethernet")},
+	}
+	require.NoError(t, checkLibrarySources(used, map[libraries.LibraryLocation]bool{
+		libraries.User:            true,
+		libraries.PlatformBuiltIn: true,
+		libraries.IDEBuiltIn:      true,
+	}))
+
+	err := checkLibrarySources(used, map[libraries.LibraryLocation]bool{libraries.User: true})
+	require.ErrorContains(t, err, "EEPROM ("+paths.New("/avr/libraries/EEPROM").String()+")")
+	require.ErrorContains(t, err, "Ethernet ("+paths.New("/ide/libraries/Ethernet").String()+")")
+	require.NotContains(t, err.Error(), "Servo")
+}
@@ -0,0 +1,157 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/arduino/arduino-cli/internal/arduino/builder"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+)
+
+// ltoCompilerFlagsKeys are the build properties holding the flags of the
+// compiler and of the linker that may enable the link time optimization
+var ltoCompilerFlagsKeys = []string{"compiler.c.flags", "compiler.cpp.flags", "compiler.S.flags", "compiler.c.elf.flags"}
+
+// isLTOFlag returns true if the given compiler flag is related to the link
+// time optimization
+func isLTOFlag(flag string) bool {
+	return flag == "-flto" || strings.HasPrefix(flag, "-flto=") ||
+		flag == "-fuse-linker-plugin" || flag == "-ffat-lto-objects" || flag == "-fno-fat-lto-objects"
+}
+
+// ltoEnabled returns true if the link time optimization is enabled in the
+// given build properties
+func ltoEnabled(buildProperties *properties.Map) bool {
+	for _, flag := range strings.Fields(buildProperties.Get("compiler.c.elf.flags")) {
+		if flag == "-flto" || strings.HasPrefix(flag, "-flto=") {
+			return true
+		}
+	}
+	return false
+}
+
+// ltoBuildProperties returns the build properties that enable, or disable,
+// the link time optimization by changing the compiler and linker flags of the
+// platform. The platforms archiving the core with a plain "ar", instead of
+// the LTO aware "gcc-ar", get fat LTO objects and a warning is returned.
+func ltoBuildProperties(enable bool, buildProperties *properties.Map) ([]string, string) {
+	if enable == ltoEnabled(buildProperties) {
+		return nil, ""
+	}
+
+	res := []string{}
+	if !enable {
+		for _, key := range ltoCompilerFlagsKeys {
+			flags, ok := buildProperties.GetOk(key)
+			if !ok {
+				continue
+			}
+			fields := strings.Fields(flags)
+			filtered := slices.DeleteFunc(slices.Clone(fields), isLTOFlag)
+			if len(filtered) != len(fields) {
+				res = append(res, key+"="+strings.Join(filtered, " "))
+			}
+		}
+		return res, ""
+	}
+
+	compilerFlags := "-flto"
+	warning := ""
+	if !strings.HasSuffix(buildProperties.Get("compiler.ar.cmd"), "gcc-ar") {
+		compilerFlags += " -ffat-lto-objects"
+		warning = tr("the platform doesn't archive the core with '%[1]s', the link time optimization may not be effective", "gcc-ar")
+	}
+	for _, key := range ltoCompilerFlagsKeys {
+		current, ok := buildProperties.GetOk(key)
+		if !ok {
+			continue
+		}
+		flags := compilerFlags
+		if key == "compiler.c.elf.flags" {
+			flags = "-flto -fuse-linker-plugin"
+		}
+		res = append(res, key+"="+strings.TrimSpace(current+" "+flags))
+	}
+	return res, warning
+}
+
+// buildSizes are the executable sizes of a build, recorded in the build path
+// to compare the builds with and without the link time optimization
+type buildSizes struct {
+	LTO      bool                            `json:"lto"`
+	Sections builder.ExecutablesFileSections `json:"sections"`
+}
+
+// buildSizesFile returns the file where the sizes of the build are recorded
+func buildSizesFile(buildPath *paths.Path) *paths.Path {
+	return buildPath.Join("build.sizes.json")
+}
+
+// loadBuildSizes returns the sizes recorded by the previous build, or nil if
+// not available
+func loadBuildSizes(buildPath *paths.Path) *buildSizes {
+	data, err := buildSizesFile(buildPath).ReadFile()
+	if err != nil {
+		return nil
+	}
+	var sizes buildSizes
+	if err := json.Unmarshal(data, &sizes); err != nil {
+		return nil
+	}
+	return &sizes
+}
+
+// save records the sizes of the build in the build path
+func (s *buildSizes) save(buildPath *paths.Path) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return buildSizesFile(buildPath).WriteFile(data)
+}
+
+// ltoSizeDifference describes the size difference of each section of the
+// executable between the current build and a previous build with the link
+// time optimization toggled.
+func ltoSizeDifference(current, previous *buildSizes) string {
+	if previous == nil || current.LTO == previous.LTO {
+		return ""
+	}
+	previousSizes := map[string]int{}
+	for _, section := range previous.Sections {
+		previousSizes[section.Name] = section.Size
+	}
+	diffs := []string{}
+	for _, section := range current.Sections {
+		previousSize, ok := previousSizes[section.Name]
+		if !ok {
+			continue
+		}
+		diffs = append(diffs, tr("%[1]s %[2]d bytes (%[3]s bytes)", section.Name, section.Size, fmt.Sprintf("%+d", section.Size-previousSize)))
+	}
+	if len(diffs) == 0 {
+		return ""
+	}
+	if current.LTO {
+		return tr("Size with link time optimization compared to the previous build without it: %s", strings.Join(diffs, ", "))
+	}
+	return tr("Size without link time optimization compared to the previous build with it: %s", strings.Join(diffs, ", "))
+}
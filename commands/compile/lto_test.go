@@ -0,0 +1,89 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/internal/arduino/builder"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLTOBuildProperties(t *testing.T) {
+	avr := properties.NewFromHashmap(map[string]string{
+		"compiler.c.flags":     "-c -g -Os {compiler.warning_flags} -std=gnu11 -ffunction-sections -fdata-sections -MMD -flto -fno-fat-lto-objects",
+		"compiler.cpp.flags":   "-c -g -Os {compiler.warning_flags} -std=gnu++11 -fpermissive -fno-exceptions -flto -w -x c++ -E -CC",
+		"compiler.S.flags":     "-c -g -x assembler-with-cpp -flto -MMD",
+		"compiler.c.elf.flags": "{compiler.warning_flags} -Os -g -flto -fuse-linker-plugin -Wl,--gc-sections",
+		"compiler.ar.cmd":      "avr-gcc-ar",
+	})
+	require.True(t, ltoEnabled(avr))
+
+	props, warning := ltoBuildProperties(true, avr)
+	require.Empty(t, props)
+	require.Empty(t, warning)
+
+	props, warning = ltoBuildProperties(false, avr)
+	require.Empty(t, warning)
+	require.Equal(t, []string{
+		"compiler.c.flags=-c -g -Os {compiler.warning_flags} -std=gnu11 -ffunction-sections -fdata-sections -MMD",
+		"compiler.cpp.flags=-c -g -Os {compiler.warning_flags} -std=gnu++11 -fpermissive -fno-exceptions -w -x c++ -E -CC",
+		"compiler.S.flags=-c -g -x assembler-with-cpp -MMD",
+		"compiler.c.elf.flags={compiler.warning_flags} -Os -g -Wl,--gc-sections",
+	}, props)
+
+	noLTO := properties.NewFromHashmap(map[string]string{
+		"compiler.c.flags":     "-c -Os",
+		"compiler.cpp.flags":   "-c -Os",
+		"compiler.c.elf.flags": "-Os",
+		"compiler.ar.cmd":      "arm-none-eabi-ar",
+	})
+	require.False(t, ltoEnabled(noLTO))
+
+	props, warning = ltoBuildProperties(false, noLTO)
+	require.Empty(t, props)
+	require.Empty(t, warning)
+
+	props, warning = ltoBuildProperties(true, noLTO)
+	require.Contains(t, warning, "gcc-ar")
+	require.Equal(t, []string{
+		"compiler.c.flags=-c -Os -flto -ffat-lto-objects",
+		"compiler.cpp.flags=-c -Os -flto -ffat-lto-objects",
+		"compiler.c.elf.flags=-Os -flto -fuse-linker-plugin",
+	}, props)
+
+	noLTO.Set("compiler.ar.cmd", "arm-none-eabi-gcc-ar")
+	props, warning = ltoBuildProperties(true, noLTO)
+	require.Empty(t, warning)
+	require.Equal(t, "compiler.c.flags=-c -Os -flto", props[0])
+}
+
+func TestLTOSizeDifference(t *testing.T) {
+	withLTO := &buildSizes{LTO: true, Sections: builder.ExecutablesFileSections{{Name: "text", Size: 900}, {Name: "data", Size: 9}}}
+	withoutLTO := &buildSizes{LTO: false, Sections: builder.ExecutablesFileSections{{Name: "text", Size: 1030}, {Name: "data", Size: 9}}}
+
+	require.Empty(t, ltoSizeDifference(withLTO, nil))
+	require.Empty(t, ltoSizeDifference(withLTO, withLTO))
+	require.Equal(t, "Size with link time optimization compared to the previous build without it: text 900 bytes (-130 bytes), data 9 bytes (+0 bytes)", ltoSizeDifference(withLTO, withoutLTO))
+	require.Equal(t, "Size without link time optimization compared to the previous build with it: text 1030 bytes (+130 bytes), data 9 bytes (+0 bytes)", ltoSizeDifference(withoutLTO, withLTO))
+
+	buildPath := paths.New(t.TempDir())
+	require.Nil(t, loadBuildSizes(buildPath))
+	require.NoError(t, withLTO.save(buildPath))
+	require.Equal(t, withLTO, loadBuildSizes(buildPath))
+}
@@ -0,0 +1,95 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"sync"
+
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+)
+
+// Observer is notified of the events of a build, it allows the callers of
+// Compile to follow the build programmatically instead of parsing its output.
+// The methods are never called concurrently.
+type Observer interface {
+	// OnPhase is called when a new phase of the build starts, for example
+	// "Compiling sketch..."
+	OnPhase(phase string)
+	// OnCommand is called before running each command of the build
+	OnCommand(commandArgs []string)
+	// OnDiagnostic is called for each warning or error reported by the compiler,
+	// as soon as the command reporting it ends
+	OnDiagnostic(diagnostic *rpc.CompileDiagnostic)
+	// OnComplete is called when the build is finished, err is nil if the build
+	// succeeded
+	OnComplete(result *rpc.BuilderResult, err error)
+}
+
+// syncObserver serializes the calls to the wrapped Observer, since the
+// commands of the build may run concurrently
+type syncObserver struct {
+	lock     sync.Mutex
+	observer Observer
+}
+
+func (o *syncObserver) OnPhase(phase string) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.observer.OnPhase(phase)
+}
+
+func (o *syncObserver) OnCommand(commandArgs []string) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.observer.OnCommand(commandArgs)
+}
+
+func (o *syncObserver) OnDiagnostic(diagnostic *rpc.CompileDiagnostic) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.observer.OnDiagnostic(diagnostic)
+}
+
+func (o *syncObserver) OnComplete(result *rpc.BuilderResult, err error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.observer.OnComplete(result, err)
+}
+
+// observeProgress returns a TaskProgressCB that notifies the start of the
+// phases of the build to the observer and forwards the progress to the given
+// callback, if any.
+func observeProgress(progressCB rpc.TaskProgressCB, observer Observer) rpc.TaskProgressCB {
+	return func(progress *rpc.TaskProgress) {
+		if progress.GetName() != "" && progress.GetMessage() == "" {
+			observer.OnPhase(progress.GetName())
+		}
+		if progressCB != nil {
+			progressCB(progress)
+		}
+	}
+}
+
+// filterDetailedProgress returns a TaskProgressCB that drops the progress
+// reporting the phases of the build and the compiled files.
+func filterDetailedProgress(progressCB rpc.TaskProgressCB) rpc.TaskProgressCB {
+	return func(progress *rpc.TaskProgress) {
+		if progress.GetName() != "" || progress.GetMessage() != "" {
+			return
+		}
+		progressCB(progress)
+	}
+}
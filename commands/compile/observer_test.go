@@ -0,0 +1,58 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"testing"
+
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingObserver struct {
+	phases []string
+}
+
+func (o *recordingObserver) OnPhase(phase string)                 { o.phases = append(o.phases, phase) }
+func (o *recordingObserver) OnCommand([]string)                   {}
+func (o *recordingObserver) OnDiagnostic(*rpc.CompileDiagnostic)  {}
+func (o *recordingObserver) OnComplete(*rpc.BuilderResult, error) {}
+
+func TestObserveProgress(t *testing.T) {
+	observer := &recordingObserver{}
+	forwarded := []*rpc.TaskProgress{}
+	progressCB := observeProgress(func(p *rpc.TaskProgress) { forwarded = append(forwarded, p) }, observer)
+
+	progressCB(&rpc.TaskProgress{Name: "Compiling sketch..."})
+	progressCB(&rpc.TaskProgress{Name: "Compiling sketch...", Message: "sketch.ino.cpp"})
+	progressCB(&rpc.TaskProgress{Percent: 50})
+	require.Equal(t, []string{"Compiling sketch..."}, observer.phases)
+	require.Len(t, forwarded, 3)
+
+	// The progress callback is optional
+	observeProgress(nil, observer)(&rpc.TaskProgress{Name: "Compiling core..."})
+	require.Equal(t, []string{"Compiling sketch...", "Compiling core..."}, observer.phases)
+}
+
+func TestFilterDetailedProgress(t *testing.T) {
+	forwarded := []*rpc.TaskProgress{}
+	progressCB := filterDetailedProgress(func(p *rpc.TaskProgress) { forwarded = append(forwarded, p) })
+
+	progressCB(&rpc.TaskProgress{Name: "Compiling sketch..."})
+	progressCB(&rpc.TaskProgress{Name: "Compiling sketch...", Message: "sketch.ino.cpp"})
+	progressCB(&rpc.TaskProgress{Percent: 50})
+	require.Equal(t, []*rpc.TaskProgress{{Percent: 50}}, forwarded)
+}
@@ -0,0 +1,63 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"errors"
+
+	"github.com/arduino/arduino-cli/commands/cmderrors"
+	"github.com/arduino/arduino-cli/internal/arduino/libraries"
+	"github.com/arduino/arduino-cli/internal/arduino/sketch"
+)
+
+// checkProfileLibrariesInstalled verifies that each library pinned by the
+// profile is installed at exactly the pinned version, to not silently use
+// another version of the library.
+func checkProfileLibrariesInstalled(profile *sketch.Profile, installed libraries.List) error {
+	for _, ref := range profile.Libraries {
+		found := false
+		for _, lib := range installed {
+			if lib.Name == ref.Library && lib.Version != nil && lib.Version.Equal(ref.Version) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return &cmderrors.LibraryNotFoundError{
+				Library: ref.String(),
+				Cause:   errors.New(tr("the version pinned by the profile is not installed")),
+			}
+		}
+	}
+	return nil
+}
+
+// checkProfileLibrariesUsed verifies that the libraries used by the build, and
+// pinned by the profile, have the pinned version.
+func checkProfileLibrariesUsed(profile *sketch.Profile, used libraries.List) error {
+	for _, ref := range profile.Libraries {
+		for _, lib := range used {
+			if lib.Name != ref.Library || (lib.Version != nil && lib.Version.Equal(ref.Version)) {
+				continue
+			}
+			return &cmderrors.CompileFailedError{
+				Message: tr("The library %[1]s pinned by the profile has been replaced by version %[2]s found in %[3]s", ref, lib.Version, lib.InstallDir),
+				Cause:   errors.New(tr("the build is not reproducible")),
+			}
+		}
+	}
+	return nil
+}
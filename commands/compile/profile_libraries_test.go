@@ -0,0 +1,49 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/commands/cmderrors"
+	"github.com/arduino/arduino-cli/internal/arduino/libraries"
+	"github.com/arduino/arduino-cli/internal/arduino/sketch"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+	semver "go.bug.st/relaxed-semver"
+)
+
+func TestCheckProfileLibraries(t *testing.T) {
+	profile := &sketch.Profile{
+		Libraries: []*sketch.ProfileLibraryReference{
+			{Library: "Servo", Version: semver.MustParse("1.2.1")},
+		},
+	}
+	servo120 := &libraries.Library{Name: "Servo", Version: semver.MustParse("1.2.0"), InstallDir: paths.New("/platform/libraries/Servo")}
+	servo121 := &libraries.Library{Name: "Servo", Version: semver.MustParse("1.2.1"), InstallDir: paths.New("/profile/libraries/Servo")}
+	wire := &libraries.Library{Name: "Wire", Version: semver.MustParse("1.0.0"), InstallDir: paths.New("/platform/libraries/Wire")}
+
+	require.NoError(t, checkProfileLibrariesInstalled(profile, libraries.List{servo120, servo121, wire}))
+	err := checkProfileLibrariesInstalled(profile, libraries.List{servo120, wire})
+	var libErr *cmderrors.LibraryNotFoundError
+	require.ErrorAs(t, err, &libErr)
+	require.Equal(t, "Servo@1.2.1", libErr.Library)
+
+	require.NoError(t, checkProfileLibrariesUsed(profile, libraries.List{servo121, wire}))
+	require.NoError(t, checkProfileLibrariesUsed(profile, libraries.List{wire}))
+	err = checkProfileLibrariesUsed(profile, libraries.List{servo120, wire})
+	require.ErrorContains(t, err, "The library Servo@1.2.1 pinned by the profile has been replaced by version 1.2.0")
+}
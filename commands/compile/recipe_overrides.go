@@ -0,0 +1,51 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"strings"
+
+	"github.com/arduino/go-properties-orderedmap"
+)
+
+// recipeOverride is a recipe of the platform overridden by a custom build
+// property
+type recipeOverride struct {
+	Key     string
+	Before  string
+	After   string
+	Defined bool
+}
+
+// recipeOverrides returns the recipes overridden by the custom build
+// properties, in the order they are given. The hooks recipes are not
+// reported, since they are not expected to be defined by the platform.
+func recipeOverrides(buildProperties, customBuildProperties *properties.Map) []recipeOverride {
+	res := []recipeOverride{}
+	for _, key := range customBuildProperties.Keys() {
+		if !strings.HasPrefix(key, "recipe.") || strings.HasPrefix(key, "recipe.hooks.") {
+			continue
+		}
+		before, defined := buildProperties.GetOk(key)
+		res = append(res, recipeOverride{
+			Key:     key,
+			Before:  before,
+			After:   customBuildProperties.Get(key),
+			Defined: defined,
+		})
+	}
+	return res
+}
@@ -0,0 +1,43 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"testing"
+
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecipeOverrides(t *testing.T) {
+	props := properties.NewMap()
+	props.Set("recipe.objcopy.hex.pattern", "objcopy -O ihex")
+	props.Set("recipe.size.pattern", "size")
+
+	custom, err := properties.LoadFromSlice([]string{
+		"build.extra_flags=-DDEBUG",
+		"recipe.objcopy.hex.pattern=objcopy -O ihex -R .eeprom",
+		"recipe.objcopy.hexx.pattern=objcopy",
+		"recipe.hooks.prebuild.1.pattern=echo prebuild",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []recipeOverride{
+		{Key: "recipe.objcopy.hex.pattern", Before: "objcopy -O ihex", After: "objcopy -O ihex -R .eeprom", Defined: true},
+		{Key: "recipe.objcopy.hexx.pattern", After: "objcopy"},
+	}, recipeOverrides(props, custom))
+
+	require.Empty(t, recipeOverrides(props, properties.NewMap()))
+}
@@ -0,0 +1,67 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/arduino/arduino-cli/internal/arduino/cores"
+	"github.com/arduino/arduino-cli/internal/arduino/cores/packagemanager"
+)
+
+// checkStrictFQBN verifies that the given FQBN names exactly a board of an
+// installed platform, and that its configuration options are valid for the
+// board. The returned error lists the valid alternatives.
+func checkStrictFQBN(pme *packagemanager.Explorer, fqbn *cores.FQBN) error {
+	platformID := fqbn.Package + ":" + fqbn.PlatformArch
+	platform := pme.FindPlatform(&packagemanager.PlatformReference{
+		Package:              fqbn.Package,
+		PlatformArchitecture: fqbn.PlatformArch,
+	})
+	if platform == nil {
+		return fmt.Errorf(tr("unknown platform %s"), platformID)
+	}
+	platformRelease := pme.GetInstalledPlatformRelease(platform)
+	if platformRelease == nil {
+		return fmt.Errorf(tr("platform %s is not installed"), platformID)
+	}
+
+	board, ok := platformRelease.Boards[fqbn.BoardID]
+	if !ok {
+		boardIDs := []string{}
+		for id := range platformRelease.Boards {
+			boardIDs = append(boardIDs, id)
+		}
+		sort.Strings(boardIDs)
+		return fmt.Errorf(tr("board %[1]s not found in platform %[2]s, valid board IDs are: %[3]s"),
+			fqbn.BoardID, platformRelease, strings.Join(boardIDs, ", "))
+	}
+
+	for _, option := range fqbn.Configs.Keys() {
+		values := board.GetConfigOptionValues(option)
+		if values == nil {
+			return fmt.Errorf(tr("invalid option '%[1]s' for board %[2]s, valid options are: %[3]s"),
+				option, board.FQBN(), strings.Join(board.GetConfigOptions().Keys(), ", "))
+		}
+		if value := fqbn.Configs.Get(option); !values.ContainsKey(value) {
+			return fmt.Errorf(tr("invalid value '%[1]s' for option '%[2]s' of board %[3]s, valid values are: %[4]s"),
+				value, option, board.FQBN(), strings.Join(values.Keys(), ", "))
+		}
+	}
+	return nil
+}
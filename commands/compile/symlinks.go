@@ -0,0 +1,41 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"github.com/arduino/go-paths-helper"
+)
+
+// resolveSymlinks returns the absolute path of p with all the symlinks
+// resolved. Unlike paths.Path.Canonical the path may not exist yet: in this
+// case the symlinks of its deepest existing parent are resolved.
+func resolveSymlinks(p *paths.Path) (*paths.Path, error) {
+	abs, err := p.Abs()
+	if err != nil {
+		return nil, err
+	}
+	missing := []string{}
+	existing := abs
+	for !existing.Exist() {
+		parent := existing.Parent()
+		if parent.EqualsTo(existing) {
+			return abs, nil
+		}
+		missing = append([]string{existing.Base()}, missing...)
+		existing = parent
+	}
+	return existing.Canonical().Join(missing...), nil
+}
@@ -0,0 +1,47 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"os"
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSymlinks(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+	tmp = tmp.Canonical()
+
+	target := tmp.Join("target")
+	require.NoError(t, target.MkdirAll())
+	link := tmp.Join("link")
+	if err := os.Symlink(target.String(), link.String()); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+
+	resolved, err := resolveSymlinks(link)
+	require.NoError(t, err)
+	require.Equal(t, target.String(), resolved.String())
+
+	// Not existing paths are resolved up to the deepest existing parent
+	resolved, err = resolveSymlinks(link.Join("build", "sketch"))
+	require.NoError(t, err)
+	require.Equal(t, target.Join("build", "sketch").String(), resolved.String())
+}
@@ -0,0 +1,82 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/arduino/arduino-cli/commands/cmderrors"
+	"github.com/arduino/arduino-cli/internal/arduino/cores"
+	"github.com/arduino/go-properties-orderedmap"
+)
+
+// overrideTools replaces, in the tools required by the build, the tools
+// selected by the given overrides, in the form "[packager:]name@version", with
+// the requested installed releases. The runtime properties of the selected
+// releases are set in the given build properties, so that the platform
+// recipes use them. The updated list of required tools is returned.
+func overrideTools(installedTools, requiredTools []*cores.ToolRelease, overrides []string, buildProperties *properties.Map) ([]*cores.ToolRelease, error) {
+	for _, override := range overrides {
+		name, version, ok := strings.Cut(override, "@")
+		packager := ""
+		if p, n, hasPackager := strings.Cut(name, ":"); hasPackager {
+			packager, name = p, n
+		}
+		if !ok || name == "" || version == "" {
+			return nil, &cmderrors.InvalidArgumentError{Message: tr("Invalid tool override '%s', it must be in the form [packager:]name@version", override)}
+		}
+
+		var selected *cores.ToolRelease
+		availableVersions := []string{}
+		for _, tool := range installedTools {
+			if tool.Tool.Name != name || (packager != "" && tool.Tool.Package.Name != packager) {
+				continue
+			}
+			availableVersions = append(availableVersions, tool.String())
+			if tool.Version.String() == version {
+				selected = tool
+			}
+		}
+		if selected == nil {
+			sort.Strings(availableVersions)
+			hint := tr("The tools are installed together with the platforms that depend on them: install a platform that requires %s.", name+"@"+version)
+			if len(availableVersions) > 0 {
+				hint = tr("Installed versions: %s.", strings.Join(availableVersions, ", ")) + " " + hint
+			}
+			return nil, &cmderrors.InvalidArgumentError{
+				Message: tr("Tool %s is not installed", override),
+				Cause:   errors.New(hint),
+			}
+		}
+
+		// Replace the tool selected by the platform, the versioned runtime
+		// properties of the replaced releases are redirected too
+		res := []*cores.ToolRelease{}
+		for _, tool := range requiredTools {
+			if tool.Tool.Name != name {
+				res = append(res, tool)
+				continue
+			}
+			buildProperties.Set(fmt.Sprintf("runtime.tools.%s-%s.path", name, tool.Version), selected.InstallDir.String())
+		}
+		buildProperties.Merge(selected.RuntimeProperties())
+		requiredTools = append(res, selected)
+	}
+	return requiredTools, nil
+}
@@ -0,0 +1,56 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/internal/arduino/cores"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+	semver "go.bug.st/relaxed-semver"
+)
+
+func TestOverrideTools(t *testing.T) {
+	arduino := &cores.Package{Name: "arduino"}
+	gcc := &cores.Tool{Name: "avr-gcc", Package: arduino}
+	avrdude := &cores.Tool{Name: "avrdude", Package: arduino}
+	gcc5 := &cores.ToolRelease{Tool: gcc, Version: semver.ParseRelaxed("5.4.0"), InstallDir: paths.New("/tools/avr-gcc/5.4.0")}
+	gcc7 := &cores.ToolRelease{Tool: gcc, Version: semver.ParseRelaxed("7.3.0"), InstallDir: paths.New("/tools/avr-gcc/7.3.0")}
+	avrdude6 := &cores.ToolRelease{Tool: avrdude, Version: semver.ParseRelaxed("6.3.0"), InstallDir: paths.New("/tools/avrdude/6.3.0")}
+	installed := []*cores.ToolRelease{gcc5, gcc7, avrdude6}
+
+	props := properties.NewMap()
+	props.Set("runtime.tools.avr-gcc.path", "/tools/avr-gcc/7.3.0")
+	tools, err := overrideTools(installed, []*cores.ToolRelease{gcc7, avrdude6}, []string{"arduino:avr-gcc@5.4.0"}, props)
+	require.NoError(t, err)
+	require.Equal(t, []*cores.ToolRelease{avrdude6, gcc5}, tools)
+	require.Equal(t, "/tools/avr-gcc/5.4.0", props.Get("runtime.tools.avr-gcc.path"))
+	require.Equal(t, "/tools/avr-gcc/5.4.0", props.Get("runtime.tools.avr-gcc-7.3.0.path"))
+
+	// No overrides
+	tools, err = overrideTools(installed, []*cores.ToolRelease{gcc7}, nil, properties.NewMap())
+	require.NoError(t, err)
+	require.Equal(t, []*cores.ToolRelease{gcc7}, tools)
+
+	_, err = overrideTools(installed, []*cores.ToolRelease{gcc7}, []string{"avr-gcc@12.0.0"}, properties.NewMap())
+	require.ErrorContains(t, err, "Tool avr-gcc@12.0.0 is not installed")
+	require.ErrorContains(t, err, "arduino:avr-gcc@5.4.0, arduino:avr-gcc@7.3.0")
+
+	_, err = overrideTools(installed, []*cores.ToolRelease{gcc7}, []string{"avr-gcc"}, properties.NewMap())
+	require.Error(t, err)
+}
@@ -0,0 +1,45 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"io"
+
+	"github.com/arduino/go-paths-helper"
+)
+
+// tracePaths writes the given paths, one per line with the given label, to
+// help diagnosing a wrong build path or a missing library or platform folder
+func tracePaths(out io.Writer, label string, dirs ...*paths.Path) {
+	if len(dirs) == 0 {
+		out.Write([]byte(tracePathLine(label, nil) + "\n"))
+	}
+	for _, dir := range dirs {
+		out.Write([]byte(tracePathLine(label, dir) + "\n"))
+	}
+}
+
+// tracePathLine returns the line describing the given path in the trace,
+// reporting if it doesn't exist
+func tracePathLine(label string, path *paths.Path) string {
+	if path == nil {
+		return tr("Trace: %[1]s: not set", label)
+	}
+	if !path.Exist() {
+		return tr("Trace: %[1]s: %[2]s (not found)", label, path)
+	}
+	return tr("Trace: %[1]s: %[2]s", label, path)
+}
@@ -0,0 +1,41 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracePaths(t *testing.T) {
+	tmp := paths.New(t.TempDir())
+	missing := tmp.Join("missing")
+	out := &bytes.Buffer{}
+	tracePaths(out, "build path", tmp)
+	tracePaths(out, "libraries folder", tmp, missing)
+	tracePaths(out, "hardware folder")
+	tracePaths(out, "built-in libraries folder", nil)
+	require.Equal(t,
+		"Trace: build path: "+tmp.String()+"\n"+
+			"Trace: libraries folder: "+tmp.String()+"\n"+
+			"Trace: libraries folder: "+missing.String()+" (not found)\n"+
+			"Trace: hardware folder: not set\n"+
+			"Trace: built-in libraries folder: not set\n",
+		out.String())
+}
@@ -197,7 +197,7 @@ func (s *ArduinoCoreServerImpl) Compile(req *rpc.CompileRequest, stream rpc.Ardu
 			Message: &rpc.CompileResponse_Progress{Progress: p},
 		})
 	}
-	compileRes, compileErr := compile.Compile(stream.Context(), req, outStream, errStream, progressStream)
+	compileRes, compileErr := compile.Compile(stream.Context(), req, outStream, errStream, progressStream, nil)
 	outStream.Close()
 	errStream.Close()
 	var compileRespSendErr error
@@ -61,6 +61,10 @@ func installTool(pm *packagemanager.PackageManager, tool *cores.ToolRelease, dow
 
 // Create a new CoreInstance ready to be initialized, supporting directories are also created.
 func Create(req *rpc.CreateRequest, extraUserAgent ...string) (*rpc.CreateResponse, error) {
+	if err := configuration.CheckDataDir(configuration.Settings); err != nil {
+		return nil, &cmderrors.InvalidArgumentError{Cause: err}
+	}
+
 	// Setup downloads directory
 	downloadsDir := configuration.DownloadsDir(configuration.Settings)
 	if downloadsDir.NotExist() {
@@ -250,6 +254,10 @@ func Init(req *rpc.InitRequest, responseCallback func(r *rpc.InitResponse)) erro
 		// otherwise we wouldn't find them and reinstall them each time
 		// and they would never get reloaded.
 
+		// The ctags bundled with the CLI, if any, is used in place of the
+		// downloaded one
+		useBundledCtags(pmb.GetOrCreatePackage("builtin"))
+
 		builtinToolsToInstall := []*cores.ToolRelease{}
 		for name, tool := range pmb.GetOrCreatePackage("builtin").Tools {
 			latest := tool.LatestRelease()
@@ -284,6 +292,7 @@ func Init(req *rpc.InitRequest, responseCallback func(r *rpc.InitResponse)) erro
 				s := &cmderrors.PlatformLoadingError{Cause: err}
 				responseError(s.ToRPCStatus())
 			}
+			useBundledCtags(pmb.GetOrCreatePackage("builtin"))
 		}
 
 		commitPackageManager()
@@ -342,10 +351,12 @@ func Init(req *rpc.InitRequest, responseCallback func(r *rpc.InitResponse)) erro
 		}
 
 		// Add libraries directory from config file
-		lmb.AddLibrariesDir(&librariesmanager.LibrariesDir{
-			Path:     configuration.LibrariesDir(configuration.Settings),
-			Location: libraries.User,
-		})
+		if userLibrariesDir := configuration.LibrariesDir(configuration.Settings); userLibrariesDir != nil {
+			lmb.AddLibrariesDir(&librariesmanager.LibrariesDir{
+				Path:     userLibrariesDir,
+				Location: libraries.User,
+			})
+		}
 	} else {
 		// Load libraries required for profile
 		for _, libraryRef := range profile.Libraries {
@@ -27,7 +27,13 @@ import (
 // LoadSketch collects and returns all files composing a sketch
 func LoadSketch(ctx context.Context, req *rpc.LoadSketchRequest) (*rpc.LoadSketchResponse, error) {
 	// TODO: This should be a ToRpc function for the Sketch struct
-	sk, err := sketch.New(paths.New(req.GetSketchPath()))
+	var sk *sketch.Sketch
+	var err error
+	if mainFile := req.GetMainFile(); mainFile != "" {
+		sk, err = sketch.NewWithMainFile(paths.New(req.GetSketchPath()), mainFile, true)
+	} else {
+		sk, err = sketch.New(paths.New(req.GetSketchPath()))
+	}
 	if err != nil {
 		return nil, &cmderrors.CantOpenSketchError{Cause: err}
 	}
@@ -48,6 +48,9 @@ func NewSketch(ctx context.Context, req *rpc.NewSketchRequest) (*rpc.NewSketchRe
 	if len(req.GetSketchDir()) > 0 {
 		sketchesDir = req.GetSketchDir()
 	} else {
+		if err := configuration.CheckUserDir(configuration.Settings); err != nil {
+			return nil, &cmderrors.InvalidArgumentError{Cause: err}
+		}
 		sketchesDir = configuration.Settings.GetString("directories.User")
 	}
 
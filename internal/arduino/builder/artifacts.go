@@ -0,0 +1,111 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/arduino/go-paths-helper"
+)
+
+// OutputArtifactPath returns the path of the main build artifact, as defined
+// by the 'recipe.output.tmp_file' property of the platform. The property
+// 'build.primary_artifact' may be set to explicitly select the main artifact
+// for the cores where the output file can't be detected.
+func (b *Builder) OutputArtifactPath() (*paths.Path, error) {
+	outputFile, ok := b.buildProperties.GetOk("build.primary_artifact")
+	if !ok {
+		outputFile, ok = b.buildProperties.GetOk("recipe.output.tmp_file")
+	}
+	if !ok {
+		return nil, errors.New(tr("missing '%[1]s' property, set '%[2]s' to select the main build artifact", "recipe.output.tmp_file", "build.primary_artifact"))
+	}
+	return b.buildPath.Join(b.buildProperties.ExpandPropsInString(outputFile)), nil
+}
+
+// ArtifactBaseName returns the name the build artifacts start with, as defined
+// by the 'build.project_name' property, for example "Blink.ino" for the
+// artifacts "Blink.ino.hex" and "Blink.ino.with_bootloader.hex".
+func (b *Builder) ArtifactBaseName() (string, error) {
+	baseName, ok := b.buildProperties.GetOk("build.project_name") // == "sketch.ino"
+	if !ok || baseName == "" {
+		return "", errors.New(tr("missing '%s' property", "build.project_name"))
+	}
+	return baseName, nil
+}
+
+// ListArtifacts returns the build artifacts found in the given directory, that
+// are the files named after the project (for example "Blink.ino.hex").
+func (b *Builder) ListArtifacts(dir *paths.Path) (paths.PathList, error) {
+	baseName, err := b.ArtifactBaseName()
+	if err != nil {
+		return nil, err
+	}
+	files, err := dir.ReadDir()
+	if err != nil {
+		return nil, err
+	}
+	files.FilterOutDirs()
+	files.FilterPrefix(baseName)
+	// Skip the checksum files written alongside the artifacts
+	files.FilterOutSuffix(".sha256")
+	// Skip the preprocessed sketch, that may be exported alongside the artifacts
+	if b.sketch != nil {
+		preprocessedSketch := b.PreprocessedSketchPath().Base()
+		files.Filter(func(p *paths.Path) bool { return p.Base() != preprocessedSketch })
+	}
+	files.Sort()
+	return files, nil
+}
+
+// ArtifactType returns a label describing the type of the given artifact, for
+// example "hex", "elf", "bin" or "map". The type is taken from the part of the
+// file name following the project name, so that artifacts like
+// "Blink.ino.with_bootloader.hex" are handled correctly. Artifacts without an
+// extension have an empty type.
+func (b *Builder) ArtifactType(artifact *paths.Path) string {
+	suffix := b.artifactSuffix(artifact)
+	if i := strings.LastIndex(suffix, "."); i != -1 {
+		suffix = suffix[i+1:]
+	}
+	return strings.ToLower(suffix)
+}
+
+// artifactSuffix returns the part of the artifact file name following the
+// project name, for example ".with_bootloader.hex" for
+// "Blink.ino.with_bootloader.hex". If the artifact is not named after the
+// project its extension is returned.
+func (b *Builder) artifactSuffix(artifact *paths.Path) string {
+	name := artifact.Base()
+	if baseName, err := b.ArtifactBaseName(); err == nil && strings.HasPrefix(name, baseName) {
+		return strings.TrimPrefix(name, baseName)
+	}
+	return artifact.Ext()
+}
+
+// ElfArtifactPath returns the path of the .elf file produced together with the
+// given build artifact, for example "Blink.ino.elf" for "Blink.ino.uf2" or
+// "Blink.ino.with_bootloader.hex". Artifacts named after the project are
+// handled using the full file name, since the project name already contains a
+// dot and the artifact may not have an extension.
+func (b *Builder) ElfArtifactPath(outputPath *paths.Path) *paths.Path {
+	if baseName, err := b.ArtifactBaseName(); err == nil && strings.HasPrefix(outputPath.Base(), baseName) {
+		return outputPath.Parent().Join(baseName + ".elf")
+	}
+	name := strings.TrimSuffix(outputPath.Base(), outputPath.Ext())
+	return outputPath.Parent().Join(name + ".elf")
+}
@@ -0,0 +1,140 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/internal/arduino/sketch"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputArtifactPath(t *testing.T) {
+	b := &Builder{
+		buildPath: paths.New("/tmp/build"),
+		buildProperties: properties.NewFromHashmap(map[string]string{
+			"build.project_name":     "Blink.ino",
+			"recipe.output.tmp_file": "{build.project_name}.hex",
+		}),
+	}
+	outputPath, err := b.OutputArtifactPath()
+	require.NoError(t, err)
+	require.Equal(t, paths.New("/tmp/build", "Blink.ino.hex").String(), outputPath.String())
+
+	b.buildProperties.Remove("recipe.output.tmp_file")
+	_, err = b.OutputArtifactPath()
+	require.Error(t, err)
+}
+
+func TestArtifactBaseName(t *testing.T) {
+	b := &Builder{
+		buildProperties: properties.NewFromHashmap(map[string]string{
+			"build.project_name": "Blink.ino",
+		}),
+	}
+	baseName, err := b.ArtifactBaseName()
+	require.NoError(t, err)
+	require.Equal(t, "Blink.ino", baseName)
+
+	b.buildProperties.Set("build.project_name", "")
+	_, err = b.ArtifactBaseName()
+	require.Error(t, err)
+	b.buildProperties.Remove("build.project_name")
+	_, err = b.ArtifactBaseName()
+	require.Error(t, err)
+}
+
+func TestElfArtifactPath(t *testing.T) {
+	buildPath := paths.New("/tmp/build")
+	b := &Builder{
+		buildProperties: properties.NewFromHashmap(map[string]string{
+			"build.project_name": "Blink.ino",
+		}),
+	}
+	expected := buildPath.Join("Blink.ino.elf").String()
+	for _, output := range []string{"Blink.ino.hex", "Blink.ino.bin", "Blink.ino.uf2", "Blink.ino.zip", "Blink.ino.elf", "Blink.ino.with_bootloader.hex"} {
+		require.Equal(t, expected, b.ElfArtifactPath(buildPath.Join(output)).String(), output)
+	}
+	// Output without extension
+	require.Equal(t, expected, b.ElfArtifactPath(buildPath.Join("Blink.ino")).String())
+	// Output not named after the project
+	require.Equal(t, buildPath.Join("firmware.elf").String(), b.ElfArtifactPath(buildPath.Join("firmware")).String())
+	require.Equal(t, buildPath.Join("firmware.elf").String(), b.ElfArtifactPath(buildPath.Join("firmware.bin")).String())
+}
+
+func TestArtifactType(t *testing.T) {
+	b := &Builder{
+		buildProperties: properties.NewFromHashmap(map[string]string{
+			"build.project_name": "Blink.ino",
+		}),
+	}
+	require.Equal(t, "bin", b.ArtifactType(paths.New("Blink.ino.bin")))
+	require.Equal(t, "hex", b.ArtifactType(paths.New("Blink.ino.with_bootloader.HEX")))
+	require.Equal(t, "", b.ArtifactType(paths.New("Blink.ino")))
+	require.Equal(t, "bin", b.ArtifactType(paths.New("firmware.bin")))
+	require.Equal(t, "", b.ArtifactType(paths.New("firmware")))
+}
+
+func TestPrimaryArtifactOverride(t *testing.T) {
+	b := &Builder{
+		buildPath: paths.New("/tmp/build"),
+		buildProperties: properties.NewFromHashmap(map[string]string{
+			"build.project_name":     "Blink.ino",
+			"recipe.output.tmp_file": "{build.project_name}",
+			"build.primary_artifact": "{build.project_name}.bin",
+		}),
+	}
+	outputPath, err := b.OutputArtifactPath()
+	require.NoError(t, err)
+	require.Equal(t, paths.New("/tmp/build", "Blink.ino.bin").String(), outputPath.String())
+
+	b.buildProperties.Remove("build.primary_artifact")
+	outputPath, err = b.OutputArtifactPath()
+	require.NoError(t, err)
+	require.Equal(t, paths.New("/tmp/build", "Blink.ino").String(), outputPath.String())
+}
+
+func TestListArtifacts(t *testing.T) {
+	buildPath := paths.New(t.TempDir())
+	for _, f := range []string{"Blink.ino.hex", "Blink.ino.hex.sha256", "Blink.ino.elf", "Blink.ino.map", "Blink.ino.cpp", "build.options.json"} {
+		require.NoError(t, buildPath.Join(f).WriteFile([]byte{}))
+	}
+	require.NoError(t, buildPath.Join("Blink.ino.dir").MkdirAll())
+
+	b := &Builder{
+		buildPath:       buildPath,
+		sketch:          &sketch.Sketch{MainFile: paths.New("Blink", "Blink.ino")},
+		sketchBuildPath: buildPath.Join("sketch"),
+		buildProperties: properties.NewFromHashmap(map[string]string{
+			"build.project_name": "Blink.ino",
+		}),
+	}
+	artifacts, err := b.ListArtifacts(buildPath)
+	require.NoError(t, err)
+	require.Equal(t, paths.NewPathList(
+		buildPath.Join("Blink.ino.elf").String(),
+		buildPath.Join("Blink.ino.hex").String(),
+		buildPath.Join("Blink.ino.map").String(),
+	), artifacts)
+
+	types := []string{}
+	for _, a := range artifacts {
+		types = append(types, b.ArtifactType(a))
+	}
+	require.Equal(t, []string{"elf", "hex", "map"}, types)
+}
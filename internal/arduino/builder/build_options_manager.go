@@ -16,6 +16,8 @@
 package builder
 
 import (
+	"crypto/md5"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"path/filepath"
@@ -71,7 +73,7 @@ func newBuildOptions(
 	absPath := sketch.FullPath.Parent()
 	var additionalFilesRelative []string
 	for _, f := range sketch.AdditionalFiles {
-		relPath, err := f.RelTo(absPath)
+		relPath, err := absPath.RelTo(f)
 		if err != nil {
 			continue // ignore
 		}
@@ -102,6 +104,109 @@ func (b *Builder) createBuildOptionsJSON() error {
 	return b.buildOptions.buildPath.Join("build.options.json").WriteFile(buildOptionsJSON)
 }
 
+// buildInProgressMarker is the file created in the build path while the build
+// is running: if it's found at the beginning of a build then the previous build
+// has been interrupted. The marker records the board and the sketch sources of
+// the build, see buildInProgressInfo.
+const buildInProgressMarker = "build.in_progress"
+
+// buildInProgressInfo is the content of the build in progress marker
+type buildInProgressInfo struct {
+	Fqbn        string `json:"fqbn"`
+	SourcesHash string `json:"sources_hash"`
+}
+
+// currentBuildInProgressInfo returns the board and the hash of the sketch
+// sources of the current build. The source code overrides are hashed in
+// place of the files they replace.
+func (b *Builder) currentBuildInProgressInfo() (*buildInProgressInfo, error) {
+	files := paths.NewPathList(b.sketch.MainFile.String())
+	files.AddAll(b.sketch.OtherSketchFiles)
+	files.AddAll(b.sketch.AdditionalFiles)
+	files.Sort()
+	hash := md5.New()
+	for _, file := range files {
+		rel, err := b.sketch.FullPath.RelTo(file)
+		if err != nil {
+			return nil, err
+		}
+		var data []byte
+		if override, ok := b.sourceOverrides[rel.String()]; ok {
+			data = []byte(override)
+		} else if data, err = file.ReadFile(); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(hash, "%s\x00%d\x00", filepath.ToSlash(rel.String()), len(data))
+		hash.Write(data)
+	}
+	return &buildInProgressInfo{
+		Fqbn:        b.buildOptions.currentOptions.Get("fqbn"),
+		SourcesHash: hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+func (b *Builder) markBuildInProgress() error {
+	info, err := b.currentBuildInProgressInfo()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	return b.buildPath.Join(buildInProgressMarker).WriteFile(data)
+}
+
+func (b *Builder) clearBuildInProgress() error {
+	return b.buildPath.Join(buildInProgressMarker).RemoveAll()
+}
+
+// cleanupInterruptedBuild prepares the build path to resume an interrupted build.
+// If the board or the sketch sources changed since the interrupted build, or
+// the marker can't be read, the build path is wiped and everything is rebuilt.
+// Otherwise the objects, dependency files and archives produced after the
+// interrupted build started are removed, since the compiler may have been
+// killed while writing them, and will be compiled again. All the other
+// intermediate files are reused.
+func (b *Builder) cleanupInterruptedBuild() error {
+	marker := b.buildPath.Join(buildInProgressMarker)
+	markerInfo, err := marker.Stat()
+	if err != nil {
+		// No interrupted build
+		return nil
+	}
+	current, err := b.currentBuildInProgressInfo()
+	if err != nil {
+		return fmt.Errorf("%s: %w", tr("cleaning build path"), err)
+	}
+	var interrupted buildInProgressInfo
+	if data, err := marker.ReadFile(); err != nil || json.Unmarshal(data, &interrupted) != nil || interrupted != *current {
+		if b.logger.Verbose() {
+			b.logger.Info(tr("The interrupted build in %[1]s was made for a different board or sketch sources, rebuilding all", b.buildPath))
+		}
+		return b.wipeBuildPath()
+	}
+	if b.logger.Verbose() {
+		b.logger.Info(tr("Resuming interrupted build in %[1]s", b.buildPath))
+	}
+	files, err := b.buildPath.ReadDirRecursiveFiltered(nil,
+		paths.FilterOutDirectories(),
+		paths.FilterSuffixes(".o", ".d", ".a"))
+	if err != nil {
+		return fmt.Errorf("%s: %w", tr("cleaning build path"), err)
+	}
+	for _, file := range files {
+		info, err := file.Stat()
+		if err != nil || info.ModTime().Before(markerInfo.ModTime()) {
+			continue
+		}
+		if err := file.Remove(); err != nil {
+			return fmt.Errorf("%s: %w", tr("cleaning build path"), err)
+		}
+	}
+	return marker.Remove()
+}
+
 func (b *Builder) wipeBuildPath() error {
 	// FIXME: this should go outside legacy and behind a `logrus` call so users can
 	// control when this should be printed.
@@ -148,8 +253,12 @@ func (b *Builder) wipeBuildPathIfBuildOptionsChanged() error {
 		prevOpts.Remove("sketchLocation")
 	}
 
-	// If options are not changed check if core has
-	if currentOptions.Equals(prevOpts) {
+	if !currentOptions.Equals(prevOpts) {
+		if b.logger.Verbose() {
+			b.logger.Info(tr("Build options changed, rebuilding all"))
+		}
+	} else {
+		// If options are not changed check if core has:
 		// check if any of the files contained in the core folders has changed
 		// since the json was generated - like platform.txt or similar
 		// if so, trigger a "safety" wipe
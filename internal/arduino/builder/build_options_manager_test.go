@@ -0,0 +1,160 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arduino/arduino-cli/internal/arduino/builder/internal/logger"
+	"github.com/arduino/arduino-cli/internal/arduino/cores"
+	"github.com/arduino/arduino-cli/internal/arduino/sketch"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleanupInterruptedBuild(t *testing.T) {
+	sketchPath := paths.New(t.TempDir(), "Blink")
+	require.NoError(t, sketchPath.MkdirAll())
+	require.NoError(t, sketchPath.Join("Blink.ino").WriteFile([]byte("void setup() {}\nvoid loop() {}\n")))
+	sk, err := sketch.New(sketchPath)
+	require.NoError(t, err)
+	fqbn, err := cores.ParseFQBN("arduino:avr:uno")
+	require.NoError(t, err)
+
+	buildPath := paths.New(t.TempDir())
+	newBuilder := func(fqbn *cores.FQBN) *Builder {
+		return &Builder{
+			buildPath:    buildPath,
+			sketch:       sk,
+			buildOptions: newBuildOptions(nil, nil, nil, buildPath, sk, nil, fqbn, false, "", nil, nil),
+			logger:       logger.New(io.Discard, io.Discard, false, ""),
+		}
+	}
+	b := newBuilder(fqbn)
+
+	oldObject := buildPath.Join("sketch", "Blink.ino.cpp.o")
+	newObject := buildPath.Join("core", "wiring.c.o")
+	newDepFile := buildPath.Join("core", "wiring_digital.c.d")
+	// An object truncated by a killed compiler, with the dependency file
+	// already written during the preprocessing
+	truncatedObject := buildPath.Join("core", "wiring_analog.c.o")
+	truncatedDepFile := buildPath.Join("core", "wiring_analog.c.d")
+	source := buildPath.Join("sketch", "Blink.ino.cpp")
+	createFiles := func() {
+		require.NoError(t, oldObject.Parent().MkdirAll())
+		require.NoError(t, newObject.Parent().MkdirAll())
+		for _, f := range []*paths.Path{oldObject, newObject, newDepFile, truncatedObject, truncatedDepFile, source} {
+			require.NoError(t, f.WriteFile([]byte{}))
+		}
+	}
+	createFiles()
+
+	// Without the marker nothing is removed
+	require.NoError(t, b.cleanupInterruptedBuild())
+	require.FileExists(t, newObject.String())
+
+	// Simulate an interrupted build: the marker is older than the objects
+	// produced afterwards
+	marker := buildPath.Join(buildInProgressMarker)
+	interruptBuild := func(b *Builder) {
+		require.NoError(t, b.markBuildInProgress())
+		now := time.Now()
+		require.NoError(t, oldObject.Chtimes(now, now.Add(-time.Hour)))
+		require.NoError(t, marker.Chtimes(now, now.Add(-time.Minute)))
+		require.NoError(t, source.Chtimes(now, now))
+	}
+	interruptBuild(b)
+
+	require.NoError(t, b.cleanupInterruptedBuild())
+	require.FileExists(t, oldObject.String())
+	require.FileExists(t, source.String())
+	require.NoFileExists(t, newObject.String())
+	require.NoFileExists(t, newDepFile.String())
+	require.NoFileExists(t, truncatedObject.String())
+	require.NoFileExists(t, truncatedDepFile.String())
+	require.NoFileExists(t, marker.String())
+
+	// The build path is wiped if the board changed...
+	createFiles()
+	interruptBuild(b)
+	otherFqbn, err := cores.ParseFQBN("arduino:avr:nano")
+	require.NoError(t, err)
+	require.NoError(t, newBuilder(otherFqbn).cleanupInterruptedBuild())
+	require.NoFileExists(t, oldObject.String())
+	require.NoFileExists(t, source.String())
+	require.NoFileExists(t, marker.String())
+	require.DirExists(t, buildPath.String())
+
+	// ...or the sketch sources changed
+	createFiles()
+	interruptBuild(b)
+	require.NoError(t, sk.MainFile.WriteFile([]byte("void setup() {}\nvoid loop() { delay(1); }\n")))
+	require.NoError(t, b.cleanupInterruptedBuild())
+	require.NoFileExists(t, oldObject.String())
+	require.NoFileExists(t, marker.String())
+
+	// ...or the marker can't be read, like the ones of the older versions
+	createFiles()
+	require.NoError(t, marker.WriteFile([]byte{}))
+	require.NoError(t, b.cleanupInterruptedBuild())
+	require.NoFileExists(t, oldObject.String())
+	require.NoFileExists(t, marker.String())
+}
+
+func TestBuildOptionsWithExcludedFiles(t *testing.T) {
+	sketchPath := paths.New(t.TempDir(), "Blink")
+	require.NoError(t, sketchPath.MkdirAll())
+	for _, f := range []string{"Blink.ino", "helper.cpp", "helper.h"} {
+		require.NoError(t, sketchPath.Join(f).WriteFile([]byte{}))
+	}
+	fqbn, err := cores.ParseFQBN("arduino:avr:uno")
+	require.NoError(t, err)
+	options := func(sk *sketch.Sketch) string {
+		opts := newBuildOptions(nil, nil, nil, nil, sk, nil, fqbn, false, "", nil, nil)
+		return opts.currentOptions.Get("additionalFiles")
+	}
+
+	sk, err := sketch.New(sketchPath)
+	require.NoError(t, err)
+	require.Len(t, strings.Split(options(sk), ","), 2)
+
+	// The excluded files are not part of the build options
+	_, err = sk.ExcludeFiles("helper.cpp")
+	require.NoError(t, err)
+	require.Len(t, strings.Split(options(sk), ","), 1)
+}
+
+func TestBuildOptionsAdditionalFilesRelativePaths(t *testing.T) {
+	sketchPath := paths.New(t.TempDir(), "Blink")
+	require.NoError(t, sketchPath.Join("src").MkdirAll())
+	for _, f := range []string{"Blink.ino", "helper.h", "src/util.cpp"} {
+		require.NoError(t, sketchPath.Join(f).WriteFile([]byte{}))
+	}
+	fqbn, err := cores.ParseFQBN("arduino:avr:uno")
+	require.NoError(t, err)
+	sk, err := sketch.New(sketchPath)
+	require.NoError(t, err)
+
+	// The additional files are recorded relative to the folder containing the
+	// sketch, so the build options don't change if the sketch is moved
+	opts := newBuildOptions(nil, nil, nil, nil, sk, nil, fqbn, false, "", nil, nil)
+	require.Equal(t,
+		paths.New("Blink", "helper.h").String()+","+paths.New("Blink", "src", "util.cpp").String(),
+		opts.currentOptions.Get("additionalFiles"))
+}
@@ -16,6 +16,8 @@
 package builder
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -32,11 +34,13 @@ import (
 	"github.com/arduino/arduino-cli/internal/arduino/cores"
 	"github.com/arduino/arduino-cli/internal/arduino/libraries"
 	"github.com/arduino/arduino-cli/internal/arduino/libraries/librariesmanager"
+	"github.com/arduino/arduino-cli/internal/arduino/libraries/librariesresolver"
 	"github.com/arduino/arduino-cli/internal/arduino/sketch"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	"github.com/arduino/go-paths-helper"
 	"github.com/arduino/go-properties-orderedmap"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/text/encoding"
 )
 
 // ErrSketchCannotBeLocatedInBuildPath fixdoc
@@ -44,6 +48,10 @@ var ErrSketchCannotBeLocatedInBuildPath = errors.New("sketch cannot be located i
 
 // Builder is a Sketch builder.
 type Builder struct {
+	// ctx is used to interrupt the build: the running commands are killed
+	// and no other command is started once it's done
+	ctx context.Context
+
 	sketch          *sketch.Sketch
 	buildProperties *properties.Map
 
@@ -61,6 +69,16 @@ type Builder struct {
 	// core related
 	coreBuildCachePath *paths.Path
 
+	// Set to true to strip absolute paths and timestamps from the build output
+	reproducible bool
+
+	// Set to true to compile each library using only its own include paths
+	strictIncludes bool
+
+	// Set to true to fail the build if a section exceeds its maximum size,
+	// also when the advanced sizer of the platform reports it as a warning
+	verifySize bool
+
 	logger *logger.BuilderLogger
 	clean  bool
 
@@ -69,6 +87,12 @@ type Builder struct {
 	// The keys of the map are paths relative to sketch folder.
 	sourceOverrides map[string]string
 
+	// The encoding of the sketch source files, if nil it's detected
+	sketchEncoding encoding.Encoding
+
+	// Extra compiler flags of single source files (absolute path -> flags)
+	fileFlags map[string]string
+
 	// Set to true to skip build and produce only Compilation Database
 	onlyUpdateCompilationDatabase bool
 	// Compilation Database to build/update
@@ -97,6 +121,20 @@ type Builder struct {
 	compilerOutputParser diagnostics.CompilerOutputParserCB
 	// and here are the diagnostics parsed from the compiler
 	compilerDiagnostics diagnostics.Diagnostics
+	// and the source files compiled when each of them was emitted
+	recompiledSources recompiledSources
+
+	// The first command failed during the build
+	failedCommand failedCommandRecorder
+
+	// This function, if set, is called before running each command of the build
+	commandCB func(commandArgs []string)
+	// This function, if set, is called for each diagnostic parsed from the
+	// compiler output, as soon as the command emitting it ends
+	diagnosticCB func(diagnostic *rpc.CompileDiagnostic)
+
+	// The command used to link the sketch, set when the link step is reached
+	linkCommand []string
 }
 
 // buildArtifacts contains the result of various build
@@ -112,6 +150,43 @@ type buildArtifacts struct {
 	sketchObjectFiles paths.PathList
 }
 
+// Options are the optional settings of a Builder, the zero value
+// selects the default behavior for each of them.
+type Options struct {
+	// Context used to interrupt the build, if nil the build can't be interrupted
+	Context context.Context
+
+	// Set to true to strip absolute paths and timestamps from the build output
+	Reproducible bool
+	// Set to true to use a fixed build time instead of the current time
+	FixedBuildTime bool
+
+	// The encoding of the sketch source files, if nil it's detected
+	SketchEncoding encoding.Encoding
+	// Extra compiler flags of single source files (absolute path -> flags)
+	FileFlags map[string]string
+
+	// Compilation Database to build/update, if nil it's created in the build path
+	CompilationDatabasePath *paths.Path
+
+	// The priority of the library locations, if set it replaces the default one
+	LibrariesLocationsOrder []libraries.LibraryLocation
+	// Set to true to use only the libraries explicitly passed to the builder
+	OnlyExplicitLibraries bool
+	// Set to true to compile each library using only its own include paths
+	StrictIncludes bool
+	// Set to true to fail the build if a section exceeds its maximum size,
+	// also when the advanced sizer of the platform reports it as a warning.
+	// The builds using the size recipe always fail on an overflow.
+	VerifySize bool
+
+	// This function, if set, is called before running each command of the build
+	CommandCB func(commandArgs []string)
+	// This function, if set, is called for each diagnostic parsed from the
+	// compiler output, as soon as the command emitting it ends
+	DiagnosticCB func(diagnostic *rpc.CompileDiagnostic)
+}
+
 // NewBuilder creates a sketch Builder.
 func NewBuilder(
 	sk *sketch.Sketch,
@@ -133,38 +208,30 @@ func NewBuilder(
 	libraryDirs paths.PathList,
 	stdout, stderr io.Writer, verbose bool, warningsLevel string,
 	progresCB rpc.TaskProgressCB,
+	opts Options,
 ) (*Builder, error) {
-	buildProperties := properties.NewMap()
-	if boardBuildProperties != nil {
-		buildProperties.Merge(boardBuildProperties)
-	}
-	if sk != nil {
-		buildProperties.SetPath("sketch_path", sk.FullPath)
-	}
-	if buildPath != nil {
-		buildProperties.SetPath("build.path", buildPath)
-	}
-	if sk != nil {
-		buildProperties.Set("build.project_name", sk.MainFile.Base())
-		buildProperties.SetPath("build.source.path", sk.FullPath)
-	}
-	if optimizeForDebug {
-		if debugFlags, ok := buildProperties.GetOk("compiler.optimization_flags.debug"); ok {
-			buildProperties.Set("compiler.optimization_flags", debugFlags)
-		}
-	} else {
-		if releaseFlags, ok := buildProperties.GetOk("compiler.optimization_flags.release"); ok {
-			buildProperties.Set("compiler.optimization_flags", releaseFlags)
-		}
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
 	}
 
-	// Add user provided custom build properties
 	customBuildProperties, err := properties.LoadFromSlice(requestBuildProperties)
 	if err != nil {
 		return nil, fmt.Errorf("invalid build properties: %w", err)
 	}
-	buildProperties.Merge(customBuildProperties)
+	buildProperties, err := NewBuildProperties(sk, boardBuildProperties, buildPath, optimizeForDebug, customBuildProperties, opts)
+	if err != nil {
+		return nil, err
+	}
 	customBuildPropertiesArgs := append(requestBuildProperties, "build.warn_data_percentage=75")
+	if opts.Reproducible {
+		// Force a full rebuild when switching from/to reproducible builds
+		customBuildPropertiesArgs = append(customBuildPropertiesArgs, "build.reproducible=true")
+	}
+	if opts.StrictIncludes {
+		// The libraries compiled with the full include path must be rebuilt
+		customBuildPropertiesArgs = append(customBuildPropertiesArgs, "build.strict_includes=true")
+	}
 
 	sketchBuildPath, err := buildPath.Join("sketch").Abs()
 	if err != nil {
@@ -188,6 +255,8 @@ func NewBuilder(
 		useCachedLibrariesResolution, librariesManager,
 		builtInLibrariesDirs, libraryDirs, otherLibrariesDirs,
 		actualPlatform, targetPlatform,
+		opts.LibrariesLocationsOrder,
+		opts.OnlyExplicitLibraries,
 	)
 	if err != nil {
 		return nil, err
@@ -196,7 +265,13 @@ func NewBuilder(
 		logger.Warn(string(verboseOut))
 	}
 
+	compilationDatabasePath := opts.CompilationDatabasePath
+	if compilationDatabasePath == nil {
+		compilationDatabasePath = buildPath.Join("compile_commands.json")
+	}
+
 	b := &Builder{
+		ctx:                           ctx,
 		sketch:                        sk,
 		buildProperties:               buildProperties,
 		buildPath:                     buildPath,
@@ -206,12 +281,19 @@ func NewBuilder(
 		jobs:                          jobs,
 		customBuildProperties:         customBuildPropertiesArgs,
 		coreBuildCachePath:            coreBuildCachePath,
+		reproducible:                  opts.Reproducible,
+		strictIncludes:                opts.StrictIncludes,
+		verifySize:                    opts.VerifySize,
 		logger:                        logger,
 		clean:                         clean,
 		sourceOverrides:               sourceOverrides,
+		sketchEncoding:                opts.SketchEncoding,
+		fileFlags:                     opts.FileFlags,
 		onlyUpdateCompilationDatabase: onlyUpdateCompilationDatabase,
-		compilationDatabase:           compilation.NewDatabase(buildPath.Join("compile_commands.json")),
+		compilationDatabase:           compilation.NewDatabase(compilationDatabasePath),
 		Progress:                      progress.New(progresCB),
+		commandCB:                     opts.CommandCB,
+		diagnosticCB:                  opts.DiagnosticCB,
 		executableSectionsSize:        []ExecutableSectionSize{},
 		buildArtifacts:                &buildArtifacts{},
 		targetPlatform:                targetPlatform,
@@ -220,6 +302,7 @@ func NewBuilder(
 			libsManager, libsResolver,
 			useCachedLibrariesResolution,
 			onlyUpdateCompilationDatabase,
+			opts.OnlyExplicitLibraries,
 			logger,
 		),
 		buildOptions: newBuildOptions(
@@ -255,6 +338,68 @@ func NewBuilder(
 	return b, nil
 }
 
+// NewBuildProperties returns the build properties of a build of the sketch in
+// the given build path: the board ones, with the custom build properties and
+// the options of the build applied. Nothing is written to disk, so the build
+// properties can be inspected without running the build.
+func NewBuildProperties(sk *sketch.Sketch, boardBuildProperties *properties.Map, buildPath *paths.Path, optimizeForDebug bool, customBuildProperties *properties.Map, opts Options) (*properties.Map, error) {
+	buildProperties := properties.NewMap()
+	if boardBuildProperties != nil {
+		buildProperties.Merge(boardBuildProperties)
+	}
+	if sk != nil {
+		buildProperties.SetPath("sketch_path", sk.FullPath)
+	}
+	if buildPath != nil {
+		buildProperties.SetPath("build.path", buildPath)
+	}
+	if sk != nil {
+		buildProperties.Set("build.project_name", sk.MainFile.Base())
+		buildProperties.SetPath("build.source.path", sk.FullPath)
+	}
+	if optimizeForDebug {
+		if debugFlags, ok := buildProperties.GetOk("compiler.optimization_flags.debug"); ok {
+			buildProperties.Set("compiler.optimization_flags", debugFlags)
+		}
+	} else {
+		if releaseFlags, ok := buildProperties.GetOk("compiler.optimization_flags.release"); ok {
+			buildProperties.Set("compiler.optimization_flags", releaseFlags)
+		}
+	}
+
+	// Add user provided custom build properties
+	buildProperties.Merge(customBuildProperties)
+	if opts.Reproducible {
+		setupReproducibleBuild(buildProperties)
+	} else if opts.FixedBuildTime {
+		setFixedBuildTime(buildProperties)
+	}
+	// A property referencing itself can't be expanded
+	if cycle := findPropertyCycle(buildProperties); cycle != nil {
+		return nil, fmt.Errorf(tr("the build property %[1]s references itself: %[2]s"), cycle[0], strings.Join(cycle, " -> "))
+	}
+	return buildProperties, nil
+}
+
+// NewLibrariesResolver returns the resolver used by the library discovery of a
+// build with the given libraries folders and platforms, so that the library
+// providing an include can be found as the build would, without running it.
+func NewLibrariesResolver(
+	librariesManager *librariesmanager.LibrariesManager,
+	builtInLibrariesDirs *paths.Path, libraryDirs, otherLibrariesDirs paths.PathList,
+	actualPlatform, targetPlatform *cores.PlatformRelease,
+	opts Options,
+) (*librariesresolver.Cpp, error) {
+	_, resolver, _, err := detector.LibrariesLoader(
+		false, librariesManager,
+		builtInLibrariesDirs, libraryDirs, otherLibrariesDirs,
+		actualPlatform, targetPlatform,
+		opts.LibrariesLocationsOrder,
+		opts.OnlyExplicitLibraries,
+	)
+	return resolver, err
+}
+
 // GetBuildProperties returns the build properties for running this build
 func (b *Builder) GetBuildProperties() *properties.Map {
 	return b.buildProperties
@@ -275,23 +420,108 @@ func (b *Builder) ImportedLibraries() libraries.List {
 	return b.libsDetector.ImportedLibraries()
 }
 
+// IncludeFolders returns the include paths used to compile the sketch: the
+// core, the variant and the folders of the libraries used, in order.
+func (b *Builder) IncludeFolders() paths.PathList {
+	return b.libsDetector.IncludeFolders()
+}
+
 // CompilerDiagnostics returns the parsed compiler diagnostics
 func (b *Builder) CompilerDiagnostics() diagnostics.Diagnostics {
 	return b.compilerDiagnostics
 }
 
-// Preprocess fixdoc
+// CompilationDatabasePath returns the path of the compilation database file
+func (b *Builder) CompilationDatabasePath() *paths.Path {
+	return b.compilationDatabase.File
+}
+
+// CompilationSummary returns a summary of the compile commands recorded in the
+// compilation database by the build.
+func (b *Builder) CompilationSummary() *rpc.CompilationSummary {
+	stats := b.compilationDatabase.Stats()
+	filesByExtension := map[string]int32{}
+	for ext, count := range stats.FilesByExtension {
+		filesByExtension[ext] = int32(count)
+	}
+	return &rpc.CompilationSummary{
+		TotalCommands:    int32(stats.TotalCommands),
+		UniqueFiles:      int32(stats.UniqueFiles),
+		FilesByExtension: filesByExtension,
+	}
+}
+
+// ValidateCompilationDatabase reads the compilation database file written by
+// the build and checks its entries against the filesystem. The problems found
+// are returned, an error is returned if the file can't be read.
+func (b *Builder) ValidateCompilationDatabase() ([]error, error) {
+	db, err := compilation.LoadDatabase(b.compilationDatabase.File)
+	if err != nil {
+		return nil, err
+	}
+	return db.Validate(), nil
+}
+
+// PreprocessedSketchPath returns the path of the sketch source code produced
+// by the Arduino preprocessing, for example "sketch/Blink.ino.cpp".
+func (b *Builder) PreprocessedSketchPath() *paths.Path {
+	return b.sketchBuildPath.Join(b.sketch.MainFile.Base() + ".cpp")
+}
+
+// PreprocessedFile is a sketch source file produced by the preprocessing
+type PreprocessedFile struct {
+	// Source is the sketch file the preprocessed file has been generated from
+	Source *paths.Path
+	// Content is the preprocessed source code
+	Content []byte
+	// Err is the error occurred preprocessing the file, if any
+	Err error
+}
+
+// Preprocess runs the preprocessing and returns the preprocessed source code
+// of the main sketch file.
+//
+// Deprecated: use PreprocessFiles, that returns the preprocessed source code
+// of each sketch file.
 func (b *Builder) Preprocess() ([]byte, error) {
+	files, err := b.PreprocessFiles()
+	if err != nil {
+		return nil, err
+	}
+	return files[0].Content, files[0].Err
+}
+
+// PreprocessFiles runs the preprocessing and returns the preprocessed source
+// code of each sketch file: the .ino files are merged together in the main
+// file, the other source files are returned as given to the compiler. If the
+// preprocessing fails the error is returned and reported for every file.
+func (b *Builder) PreprocessFiles() ([]*PreprocessedFile, error) {
 	b.Progress.AddSubSteps(6)
 	defer b.Progress.RemoveSubSteps()
 
-	if err := b.preprocess(); err != nil {
-		return nil, err
-	}
+	preprocessErr := b.preprocess()
 
-	// Return arduino-preprocessed source
-	preprocessedSketch, err := b.sketchBuildPath.Join(b.sketch.MainFile.Base() + ".cpp").ReadFile()
-	return preprocessedSketch, err
+	res := []*PreprocessedFile{{Source: b.sketch.MainFile}}
+	for _, file := range b.sketch.AdditionalFiles {
+		res = append(res, &PreprocessedFile{Source: file})
+	}
+	for _, file := range res {
+		if preprocessErr != nil {
+			file.Err = preprocessErr
+			continue
+		}
+		preprocessedFile := b.PreprocessedSketchPath()
+		if !file.Source.EqualsTo(b.sketch.MainFile) {
+			relPath, err := b.sketch.FullPath.RelTo(file.Source)
+			if err != nil {
+				file.Err = err
+				continue
+			}
+			preprocessedFile = b.sketchBuildPath.JoinPath(relPath)
+		}
+		file.Content, file.Err = preprocessedFile.ReadFile()
+	}
+	return res, preprocessErr
 }
 
 func (b *Builder) preprocess() error {
@@ -302,6 +532,9 @@ func (b *Builder) preprocess() error {
 	if err := b.wipeBuildPathIfBuildOptionsChanged(); err != nil {
 		return err
 	}
+	if err := b.cleanupInterruptedBuild(); err != nil {
+		return err
+	}
 	if err := b.createBuildOptionsJSON(); err != nil {
 		return err
 	}
@@ -317,7 +550,7 @@ func (b *Builder) preprocess() error {
 	}
 	b.Progress.CompleteStep()
 
-	b.logIfVerbose(false, tr("Detecting libraries used..."))
+	b.startPhase(tr("Detecting libraries used..."))
 	err := b.libsDetector.FindIncludes(
 		b.buildPath,
 		b.buildProperties.GetPath("build.core.path"),
@@ -336,7 +569,7 @@ func (b *Builder) preprocess() error {
 	b.warnAboutArchIncompatibleLibraries(b.libsDetector.ImportedLibraries())
 	b.Progress.CompleteStep()
 
-	b.logIfVerbose(false, tr("Generating function prototypes..."))
+	b.startPhase(tr("Generating function prototypes..."))
 	if err := b.preprocessSketch(b.libsDetector.IncludeFolders()); err != nil {
 		return err
 	}
@@ -345,6 +578,20 @@ func (b *Builder) preprocess() error {
 	return nil
 }
 
+// startPhase notifies the start of a new phase of the build to the progress
+// callback, and logs it if verbose
+func (b *Builder) startPhase(msg string) {
+	b.logIfVerbose(false, msg)
+	b.Progress.StartPhase(msg)
+}
+
+// notifyCommand notifies the command about to be run to the command callback
+func (b *Builder) notifyCommand(command *paths.Process) {
+	if b.commandCB != nil {
+		b.commandCB(command.GetArgs())
+	}
+}
+
 func (b *Builder) logIfVerbose(warn bool, msg string) {
 	if !b.logger.Verbose() {
 		return
@@ -365,7 +612,18 @@ func (b *Builder) Build() error {
 		return err
 	}
 
+	if err := b.markBuildInProgress(); err != nil {
+		return err
+	}
 	buildErr := b.build()
+	if err := b.ctx.Err(); err != nil {
+		// The build in progress marker is kept, so the files left by the
+		// interrupted commands are removed by the next build
+		return err
+	}
+	if err := b.clearBuildInProgress(); err != nil && buildErr == nil {
+		buildErr = err
+	}
 
 	b.libsDetector.PrintUsedAndNotUsedLibraries(buildErr != nil)
 	b.Progress.CompleteStep()
@@ -388,7 +646,7 @@ func (b *Builder) Build() error {
 
 // Build fixdoc
 func (b *Builder) build() error {
-	b.logIfVerbose(false, tr("Compiling sketch..."))
+	b.startPhase(tr("Compiling sketch..."))
 	if err := b.RunRecipe("recipe.hooks.sketch.prebuild", ".pattern", false); err != nil {
 		return err
 	}
@@ -404,7 +662,7 @@ func (b *Builder) build() error {
 	}
 	b.Progress.CompleteStep()
 
-	b.logIfVerbose(false, tr("Compiling libraries..."))
+	b.startPhase(tr("Compiling libraries..."))
 	if err := b.RunRecipe("recipe.hooks.libraries.prebuild", ".pattern", false); err != nil {
 		return err
 	}
@@ -425,7 +683,7 @@ func (b *Builder) build() error {
 	}
 	b.Progress.CompleteStep()
 
-	b.logIfVerbose(false, tr("Compiling core..."))
+	b.startPhase(tr("Compiling core..."))
 	if err := b.RunRecipe("recipe.hooks.core.prebuild", ".pattern", false); err != nil {
 		return err
 	}
@@ -441,7 +699,7 @@ func (b *Builder) build() error {
 	}
 	b.Progress.CompleteStep()
 
-	b.logIfVerbose(false, tr("Linking everything together..."))
+	b.startPhase(tr("Linking everything together..."))
 	if err := b.RunRecipe("recipe.hooks.linking.prelink", ".pattern", false); err != nil {
 		return err
 	}
@@ -532,16 +790,44 @@ func (b *Builder) prepareCommandForRecipe(buildProperties *properties.Map, recip
 	return command, nil
 }
 
+// startCommand starts the given command and makes sure that it's killed if
+// the build is interrupted while it runs: the returned function must be
+// called once the command has been waited.
+func (b *Builder) startCommand(command *paths.Process) (func() bool, error) {
+	if err := command.Start(); err != nil {
+		return nil, err
+	}
+	return context.AfterFunc(b.ctx, func() { command.Kill() }), nil
+}
+
 func (b *Builder) execCommand(command *paths.Process) error {
+	if err := b.ctx.Err(); err != nil {
+		return err
+	}
+	b.notifyCommand(command)
+	// The output is also captured to be reported if the command fails
+	output := &bytes.Buffer{}
 	if b.logger.Verbose() {
 		b.logger.Info(utils.PrintableCommand(command.GetArgs()))
-		command.RedirectStdoutTo(b.logger.Stdout())
+		command.RedirectStdoutTo(io.MultiWriter(output, b.logger.Stdout()))
+	} else {
+		command.RedirectStdoutTo(output)
 	}
-	command.RedirectStderrTo(b.logger.Stderr())
+	command.RedirectStderrTo(io.MultiWriter(output, b.logger.Stderr()))
 
-	if err := command.Start(); err != nil {
+	stop, err := b.startCommand(command)
+	if err != nil {
+		b.failedCommand.record(command.GetArgs(), []byte(err.Error()))
 		return err
 	}
-
-	return command.Wait()
+	err = command.Wait()
+	stop()
+	if ctxErr := b.ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if err != nil {
+		b.failedCommand.record(command.GetArgs(), output.Bytes())
+		return err
+	}
+	return nil
 }
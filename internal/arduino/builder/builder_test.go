@@ -0,0 +1,83 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/arduino/arduino-cli/internal/arduino/builder/internal/logger"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecCommandInterrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	b := &Builder{ctx: ctx, logger: logger.New(io.Discard, io.Discard, false, "")}
+	command, err := paths.NewProcess(nil, "this-command-does-not-exist")
+	require.NoError(t, err)
+
+	// The command is started, and fails, while the build is running...
+	require.Error(t, b.execCommand(command))
+	require.NotNil(t, b.FailedCommand())
+
+	// ...while it's not started at all once the build is interrupted
+	b = &Builder{ctx: ctx, logger: logger.New(io.Discard, io.Discard, false, "")}
+	cancel()
+	require.ErrorIs(t, b.execCommand(command), context.Canceled)
+	require.Nil(t, b.FailedCommand())
+}
+
+func TestLinkCommand(t *testing.T) {
+	b := &Builder{}
+	require.Empty(t, b.LinkCommand())
+
+	b.linkCommand = []string{"avr-gcc", "-o", "/tmp/My Sketch/sketch.ino.elf", "core.a"}
+	require.Equal(t, `avr-gcc -o "/tmp/My Sketch/sketch.ino.elf" core.a`, b.LinkCommand())
+}
+
+func TestNewBuildProperties(t *testing.T) {
+	buildPath := paths.New(t.TempDir()).Join("build")
+	boardBuildProperties := properties.NewFromHashmap(map[string]string{
+		"compiler.optimization_flags.release": "-Os",
+		"compiler.optimization_flags.debug":   "-Og",
+		"compiler.c.flags":                    "-c",
+		"build.mcu":                           "atmega328p",
+	})
+	customBuildProperties := properties.NewFromHashmap(map[string]string{
+		"build.mcu": "atmega2560",
+	})
+
+	buildProperties, err := NewBuildProperties(nil, boardBuildProperties, buildPath, false, customBuildProperties, Options{})
+	require.NoError(t, err)
+	require.Equal(t, buildPath.String(), buildProperties.Get("build.path"))
+	require.Equal(t, "-Os", buildProperties.Get("compiler.optimization_flags"))
+	require.Equal(t, "atmega2560", buildProperties.Get("build.mcu"))
+	// Nothing is written to disk and the given properties are left untouched
+	require.False(t, buildPath.Exist())
+	require.False(t, boardBuildProperties.ContainsKey("build.path"))
+
+	buildProperties, err = NewBuildProperties(nil, boardBuildProperties, buildPath, true, customBuildProperties, Options{Reproducible: true})
+	require.NoError(t, err)
+	require.Equal(t, "-Og", buildProperties.Get("compiler.optimization_flags"))
+	require.Equal(t, "-c {compiler.reproducible.flags}", buildProperties.Get("compiler.c.flags"))
+
+	customBuildProperties.Set("build.mcu", "{build.mcu}")
+	_, err = NewBuildProperties(nil, boardBuildProperties, buildPath, false, customBuildProperties, Options{})
+	require.Error(t, err)
+}
@@ -0,0 +1,78 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"fmt"
+
+	f "github.com/arduino/arduino-cli/internal/algorithms"
+	"github.com/arduino/arduino-cli/internal/arduino/builder/cpp"
+	"github.com/arduino/arduino-cli/internal/arduino/globals"
+	"github.com/arduino/go-paths-helper"
+)
+
+// CheckFile compiles only the given sketch file, in the same context of a
+// complete build, to report its errors and warnings: the core and the
+// libraries are not compiled and nothing is linked. The .ino files are
+// checked by compiling the whole preprocessed sketch.
+func (b *Builder) CheckFile(file *paths.Path) error {
+	b.Progress.AddSubSteps(6 /** preprocess **/ + 1 /** compile **/)
+	defer b.Progress.RemoveSubSteps()
+
+	source, err := b.checkFileSource(file)
+	if err != nil {
+		return err
+	}
+
+	if err := b.preprocess(); err != nil {
+		return err
+	}
+
+	// Always run the compiler to report the warnings again
+	if relSource, err := b.sketchBuildPath.RelTo(source); err == nil {
+		_ = b.sketchBuildPath.Join(relSource.String() + ".o").Remove()
+	}
+
+	b.startPhase(tr("Compiling %s...", file.Base()))
+	includeFolders := b.libsDetector.IncludeFolders()
+	includes := f.Map(includeFolders.AsStrings(), cpp.WrapWithHyphenI)
+	_, err = b.compileFileWithRecipe(b.sketchBuildPath, source, b.sketchBuildPath, includes, b.sourceFileRecipe(source))
+	b.Progress.CompleteStep()
+	return err
+}
+
+// checkFileSource returns the source file, in the sketch build path, that
+// must be compiled to check the given sketch file
+func (b *Builder) checkFileSource(file *paths.Path) (*paths.Path, error) {
+	file, err := file.Abs()
+	if err != nil {
+		return nil, err
+	}
+	if file.EqualsTo(b.sketch.MainFile) || b.sketch.OtherSketchFiles.Contains(file) {
+		return b.PreprocessedSketchPath(), nil
+	}
+	if !b.sketch.AdditionalFiles.Contains(file) {
+		return nil, fmt.Errorf(tr("%s is not a file of the sketch"), file)
+	}
+	if _, ok := globals.SourceFilesValidExtensions[file.Ext()]; !ok {
+		return nil, fmt.Errorf(tr("%s is not a source file that can be compiled"), file)
+	}
+	relPath, err := b.sketch.FullPath.RelTo(file)
+	if err != nil {
+		return nil, err
+	}
+	return b.sketchBuildPath.JoinPath(relPath), nil
+}
@@ -0,0 +1,52 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/internal/arduino/sketch"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckFileSource(t *testing.T) {
+	sketchPath, err := paths.New("testdata", "TestLoadSketchFolder").Abs()
+	require.NoError(t, err)
+	sk, err := sketch.New(sketchPath)
+	require.NoError(t, err)
+
+	sketchBuildPath := paths.New("/tmp", "build", "sketch")
+	b := &Builder{sketch: sk, sketchBuildPath: sketchBuildPath}
+
+	// The .ino files are checked by compiling the preprocessed sketch
+	source, err := b.checkFileSource(sketchPath.Join("TestLoadSketchFolder.ino"))
+	require.NoError(t, err)
+	require.Equal(t, sketchBuildPath.Join("TestLoadSketchFolder.ino.cpp"), source)
+	source, err = b.checkFileSource(sketchPath.Join("other.ino"))
+	require.NoError(t, err)
+	require.Equal(t, sketchBuildPath.Join("TestLoadSketchFolder.ino.cpp"), source)
+
+	source, err = b.checkFileSource(sketchPath.Join("s_file.S"))
+	require.NoError(t, err)
+	require.Equal(t, sketchBuildPath.Join("s_file.S"), source)
+
+	_, err = b.checkFileSource(sketchPath.Join("header.h"))
+	require.ErrorContains(t, err, "is not a source file that can be compiled")
+
+	_, err = b.checkFileSource(paths.New("testdata", "TestSaveSketch.ino"))
+	require.ErrorContains(t, err, "is not a file of the sketch")
+}
@@ -18,10 +18,12 @@ package builder
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"runtime"
 	"strings"
 	"sync"
 
+	"github.com/arduino/arduino-cli/internal/arduino/builder/internal/logger"
 	"github.com/arduino/arduino-cli/internal/arduino/builder/internal/utils"
 	"github.com/arduino/arduino-cli/internal/arduino/globals"
 	"github.com/arduino/go-paths-helper"
@@ -56,11 +58,7 @@ func (b *Builder) compileFiles(
 
 	queue := make(chan *paths.Path)
 	job := func(source *paths.Path) {
-		recipe := fmt.Sprintf("recipe%s.o.pattern", source.Ext())
-		if !b.buildProperties.ContainsKey(recipe) {
-			recipe = fmt.Sprintf("recipe%s.o.pattern", globals.SourceFilesValidExtensions[source.Ext()])
-		}
-		objectFile, err := b.compileFileWithRecipe(sourceDir, source, buildPath, includes, recipe)
+		objectFile, err := b.compileFileWithRecipe(sourceDir, source, buildPath, includes, b.sourceFileRecipe(source))
 		if err != nil {
 			errorsMux.Lock()
 			errorsList = append(errorsList, err)
@@ -69,6 +67,7 @@ func (b *Builder) compileFiles(
 			objectFilesMux.Lock()
 			objectFiles.Add(objectFile)
 			objectFilesMux.Unlock()
+			b.Progress.PushMessage(source.String())
 		}
 	}
 
@@ -87,12 +86,12 @@ func (b *Builder) compileFiles(
 		}()
 	}
 
-	// Feed jobs until error or done
+	// Feed jobs until error, interruption or done
 	for _, source := range sources {
 		errorsMux.Lock()
 		gotError := len(errorsList) > 0
 		errorsMux.Unlock()
-		if gotError {
+		if gotError || b.ctx.Err() != nil {
 			break
 		}
 		queue <- source
@@ -101,6 +100,9 @@ func (b *Builder) compileFiles(
 	}
 	close(queue)
 	wg.Wait()
+	if err := b.ctx.Err(); err != nil {
+		return nil, err
+	}
 	if len(errorsList) > 0 {
 		// output the first error
 		return nil, errorsList[0]
@@ -109,6 +111,15 @@ func (b *Builder) compileFiles(
 	return objectFiles, nil
 }
 
+// sourceFileRecipe returns the recipe used to compile the given source file
+func (b *Builder) sourceFileRecipe(source *paths.Path) string {
+	recipe := fmt.Sprintf("recipe%s.o.pattern", source.Ext())
+	if !b.buildProperties.ContainsKey(recipe) {
+		recipe = fmt.Sprintf("recipe%s.o.pattern", globals.SourceFilesValidExtensions[source.Ext()])
+	}
+	return recipe
+}
+
 // CompileFilesRecursive fixdoc
 func (b *Builder) compileFileWithRecipe(
 	sourcePath *paths.Path,
@@ -139,6 +150,11 @@ func (b *Builder) compileFileWithRecipe(
 		return nil, err
 	}
 
+	if flags := b.sourceFileFlags(source); flags != "" {
+		// The flags are added at the end of the command line to take
+		// precedence over the ones of the recipe, like an -O0 over an -Os
+		properties.Set(recipe, properties.Get(recipe)+" "+flags)
+	}
 	command, err := b.prepareCommandForRecipe(properties, recipe, false)
 	if err != nil {
 		return nil, err
@@ -147,32 +163,43 @@ func (b *Builder) compileFileWithRecipe(
 		b.compilationDatabase.Add(source, command)
 	}
 	if !objIsUpToDate && !b.onlyUpdateCompilationDatabase {
+		// The command output is captured for the diagnostics parser and, at the
+		// same time, streamed: since this compile could be multithreaded, full
+		// lines of the standard output and full diagnostics of the standard
+		// error are written to avoid mixing the output of concurrent commands.
 		commandStdout, commandStderr := &bytes.Buffer{}, &bytes.Buffer{}
-		command.RedirectStdoutTo(commandStdout)
-		command.RedirectStderrTo(commandStderr)
+		stdoutStream := logger.NewLineWriter(b.logger.WriteStdout)
+		stderrStream := logger.NewDiagnosticWriter(b.logger.WriteStderr)
+		if b.logger.Verbose() {
+			command.RedirectStdoutTo(io.MultiWriter(commandStdout, stdoutStream))
+		} else {
+			command.RedirectStdoutTo(commandStdout)
+		}
+		command.RedirectStderrTo(io.MultiWriter(commandStderr, stderrStream))
 
 		if b.logger.Verbose() {
 			b.logger.Info(utils.PrintableCommand(command.GetArgs()))
 		}
-		// Since this compile could be multithreaded, we first capture the command output
-		if err := command.Start(); err != nil {
+		b.notifyCommand(command)
+		stop, err := b.startCommand(command)
+		if err != nil {
+			b.failedCommand.record(command.GetArgs(), []byte(err.Error()))
 			return nil, err
 		}
-		err := command.Wait()
-		// and transfer all at once at the end...
-		if b.logger.Verbose() {
-			b.logger.WriteStdout(commandStdout.Bytes())
+		err = command.Wait()
+		stop()
+		if ctxErr := b.ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
 		}
-		b.logger.WriteStderr(commandStderr.Bytes())
+		stdoutStream.Flush()
+		stderrStream.Flush()
 
 		// Parse the output of the compiler to gather errors and warnings...
-		if b.compilerOutputParser != nil {
-			b.compilerOutputParser(command.GetArgs(), commandStdout.Bytes())
-			b.compilerOutputParser(command.GetArgs(), commandStderr.Bytes())
-		}
+		b.parseCompilerOutput(source, command.GetArgs(), commandStdout.Bytes(), commandStderr.Bytes())
 
 		// ...and then return the error
 		if err != nil {
+			b.failedCommand.record(command.GetArgs(), commandStdout.Bytes(), commandStderr.Bytes())
 			return nil, err
 		}
 	} else if b.logger.Verbose() {
@@ -84,11 +84,7 @@ func (b *Builder) compileCore() (*paths.Path, paths.PathList, error) {
 	var targetArchivedCore *paths.Path
 	if b.coreBuildCachePath != nil {
 		realCoreFolder := coreFolder.Parent().Parent()
-		archivedCoreName := getCachedCoreArchiveDirName(
-			b.buildProperties.Get("build.fqbn"),
-			b.buildProperties.Get("compiler.optimization_flags"),
-			realCoreFolder,
-		)
+		archivedCoreName := b.CoreCacheKey()
 		targetArchivedCore = b.coreBuildCachePath.Join(archivedCoreName, "core.a")
 
 		if _, err := buildcache.New(b.coreBuildCachePath).GetOrCreate(archivedCoreName); errors.Is(err, buildcache.CreateDirErr) {
@@ -152,6 +148,31 @@ func (b *Builder) compileCore() (*paths.Path, paths.PathList, error) {
 	return archiveFile, variantObjectFiles, nil
 }
 
+// CoreCacheKey returns the key identifying the cached core.a used by this
+// build, that is also the name of the directory where it's stored in the core
+// build cache. The key covers only the FQBN (including the board options),
+// the core folder (and thus the platform version) and the optimization flags,
+// together with the reproducible flags for the reproducible builds. The other
+// build properties, like the extra flags or the defines, and the toolchain
+// version are not part of the key: the cached core.a is rebuilt when the
+// files of the core change, not when these inputs change.
+func (b *Builder) CoreCacheKey() string {
+	coreFolder := b.buildProperties.GetPath("build.core.path")
+	if coreFolder == nil {
+		return ""
+	}
+	optimizationFlags := b.buildProperties.Get("compiler.optimization_flags")
+	if b.reproducible {
+		// Reproducible cores must not be mixed up with the regular ones
+		optimizationFlags += " " + b.buildProperties.Get("compiler.reproducible.flags")
+	}
+	return getCachedCoreArchiveDirName(
+		b.buildProperties.Get("build.fqbn"),
+		optimizationFlags,
+		coreFolder.Parent().Parent(),
+	)
+}
+
 // getCachedCoreArchiveDirName returns the directory name to be used to store
 // the global cached core.a.
 func getCachedCoreArchiveDirName(fqbn string, optimizationFlags string, coreFolder *paths.Path) string {
@@ -0,0 +1,62 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoreCacheKey(t *testing.T) {
+	b := &Builder{
+		buildProperties: properties.NewFromHashmap(map[string]string{
+			"build.fqbn":                  "arduino:avr:uno",
+			"build.core.path":             paths.New("/hardware/arduino/avr/cores/arduino").String(),
+			"compiler.optimization_flags": "-Os",
+			"compiler.reproducible.flags": "-ffile-prefix-map=a=b",
+		}),
+	}
+	key := b.CoreCacheKey()
+	require.True(t, strings.HasPrefix(key, "arduino_avr_uno_"), key)
+	require.Equal(t, key, b.CoreCacheKey())
+
+	// The key changes with the compiler flags...
+	b.buildProperties.Set("compiler.optimization_flags", "-Og -g")
+	debugKey := b.CoreCacheKey()
+	require.NotEqual(t, key, debugKey)
+
+	// ...with reproducible builds...
+	b.reproducible = true
+	require.NotEqual(t, debugKey, b.CoreCacheKey())
+	b.reproducible = false
+
+	// ...with the FQBN...
+	b.buildProperties.Set("build.fqbn", "arduino:avr:nano:cpu=atmega328")
+	require.True(t, strings.HasPrefix(b.CoreCacheKey(), "arduino_avr_nano_cpu_atmega328_"))
+
+	// ...and with the platform
+	b.buildProperties.Set("build.fqbn", "arduino:avr:uno")
+	b.buildProperties.Set("compiler.optimization_flags", "-Os")
+	b.buildProperties.Set("build.core.path", paths.New("/other/arduino/avr/cores/arduino").String())
+	require.NotEqual(t, key, b.CoreCacheKey())
+
+	b.buildProperties.Remove("build.core.path")
+	require.Empty(t, b.CoreCacheKey())
+}
@@ -0,0 +1,52 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/arduino/arduino-cli/internal/arduino/builder/internal/compilation"
+)
+
+// Defines returns the preprocessor macros defined with -D in the command
+// lines of the compiler run by the build, in the form NAME or NAME=VALUE,
+// sorted and without duplicates.
+func (b *Builder) Defines() []string {
+	return definesFromCommands(b.compilationDatabase.Contents)
+}
+
+// definesFromCommands collects the macros defined with -D in the given
+// commands, both in the "-DNAME" and in the "-D NAME" forms.
+func definesFromCommands(commands []compilation.Command) []string {
+	res := []string{}
+	for _, command := range commands {
+		for i, arg := range command.Arguments {
+			if !strings.HasPrefix(arg, "-D") {
+				continue
+			}
+			define := strings.TrimPrefix(arg, "-D")
+			if define == "" && i+1 < len(command.Arguments) {
+				define = command.Arguments[i+1]
+			}
+			if define != "" && !slices.Contains(res, define) {
+				res = append(res, define)
+			}
+		}
+	}
+	slices.Sort(res)
+	return res
+}
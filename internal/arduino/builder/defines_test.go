@@ -0,0 +1,42 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/internal/arduino/builder/internal/compilation"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefinesFromCommands(t *testing.T) {
+	commands := []compilation.Command{
+		{
+			File:      "sketch.ino.cpp",
+			Arguments: []string{"avr-g++", "-c", "-DF_CPU=16000000L", "-DARDUINO=10607", "-DARDUINO_AVR_UNO", "-D", "MY_FEATURE", "sketch.ino.cpp"},
+		},
+		{
+			File:      "wiring.c",
+			Arguments: []string{"avr-gcc", "-c", "-DF_CPU=16000000L", "-DARDUINO=10607", "-DARDUINO_AVR_UNO", "-Dcore_only", "wiring.c"},
+		},
+		{
+			File:      "trailing.c",
+			Arguments: []string{"avr-gcc", "-c", "-D"},
+		},
+	}
+	require.Equal(t, []string{"ARDUINO=10607", "ARDUINO_AVR_UNO", "F_CPU=16000000L", "MY_FEATURE", "core_only"}, definesFromCommands(commands))
+	require.Empty(t, definesFromCommands(nil))
+}
@@ -0,0 +1,64 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"slices"
+
+	"github.com/arduino/go-paths-helper"
+)
+
+// LibrariesDependencyGraph returns, for the sketch and for each library used
+// by the build, the names of the libraries whose headers it includes. The
+// sketch is identified by its name. The graph is obtained from the include
+// resolution of the libraries detection: each library is linked to the
+// sketch or to the library whose source file caused it to be imported, so
+// a library included by more of them is linked only to the first one found.
+func (b *Builder) LibrariesDependencyGraph() map[string][]string {
+	importedLibraries := b.ImportedLibraries()
+	graph := map[string][]string{b.sketch.Name: {}}
+	for _, library := range importedLibraries {
+		graph[library.Name] = []string{}
+	}
+
+	// ownerOf returns the name of the sketch or of the library containing
+	// the file, or "" for the other files (core and variant)
+	ownerOf := func(file *paths.Path) string {
+		for _, dir := range []*paths.Path{b.sketch.FullPath, b.sketchBuildPath} {
+			if inside, _ := file.IsInsideDir(dir); inside {
+				return b.sketch.Name
+			}
+		}
+		for _, library := range importedLibraries {
+			if inside, _ := file.IsInsideDir(library.InstallDir); inside {
+				return library.Name
+			}
+		}
+		return ""
+	}
+
+	for _, inclusion := range b.libsDetector.LibraryInclusions() {
+		owner := ownerOf(inclusion.Source)
+		if owner == "" || owner == inclusion.Library.Name || slices.Contains(graph[owner], inclusion.Library.Name) {
+			continue
+		}
+		graph[owner] = append(graph[owner], inclusion.Library.Name)
+	}
+	for _, deps := range graph {
+		slices.Sort(deps)
+	}
+	return graph
+}
@@ -0,0 +1,66 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"sync"
+
+	"github.com/arduino/arduino-cli/internal/arduino/builder/internal/utils"
+)
+
+// FailedCommand is a command that failed during the build, together with
+// the full output it produced.
+type FailedCommand struct {
+	Args   []string
+	Output []byte
+}
+
+// CommandLine returns the failed command line, quoted to be copied in a shell.
+func (c *FailedCommand) CommandLine() string {
+	return utils.PrintableCommand(c.Args)
+}
+
+// failedCommandRecorder keeps the first command failed during the build,
+// commands may run concurrently so the access is synchronized.
+type failedCommandRecorder struct {
+	lock    sync.Mutex
+	command *FailedCommand
+}
+
+func (r *failedCommandRecorder) record(args []string, output ...[]byte) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.command != nil {
+		return
+	}
+	cmd := &FailedCommand{Args: args}
+	for _, o := range output {
+		cmd.Output = append(cmd.Output, o...)
+	}
+	r.command = cmd
+}
+
+func (r *failedCommandRecorder) get() *FailedCommand {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.command
+}
+
+// FailedCommand returns the first command that failed during the build, with
+// its full output, or nil if no command failed.
+func (b *Builder) FailedCommand() *FailedCommand {
+	return b.failedCommand.get()
+}
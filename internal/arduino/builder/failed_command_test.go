@@ -0,0 +1,36 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailedCommandRecorder(t *testing.T) {
+	var recorder failedCommandRecorder
+	require.Nil(t, recorder.get())
+
+	recorder.record([]string{"gcc", "-c", "my file.c"}, []byte("out\n"), []byte("error\n"))
+	recorder.record([]string{"ld"}, []byte("ignored"))
+
+	failed := recorder.get()
+	require.NotNil(t, failed)
+	require.Equal(t, []string{"gcc", "-c", "my file.c"}, failed.Args)
+	require.Equal(t, "out\nerror\n", string(failed.Output))
+	require.Equal(t, `gcc -c "my file.c"`, failed.CommandLine())
+}
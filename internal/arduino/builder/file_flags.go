@@ -0,0 +1,57 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"strings"
+
+	"github.com/arduino/arduino-cli/internal/arduino/sketch"
+	"github.com/arduino/go-paths-helper"
+)
+
+// sourceFileFlags returns the extra compiler flags given for the source file.
+func (b *Builder) sourceFileFlags(source *paths.Path) string {
+	return fileFlagsFor(b.fileFlags, source, b.sketch, b.sketchBuildPath)
+}
+
+// fileFlagsFor returns the flags of the given source file in fileFlags, a map
+// from the absolute paths of the source files to their extra flags. The
+// sketch files are compiled from their copies in sketchBuildPath, where the
+// .ino files are merged into a single .cpp file: the flags of all the .ino
+// files are applied to it.
+func fileFlagsFor(fileFlags map[string]string, source *paths.Path, sk *sketch.Sketch, sketchBuildPath *paths.Path) string {
+	if len(fileFlags) == 0 {
+		return ""
+	}
+	if sk == nil || sketchBuildPath == nil {
+		return fileFlags[source.String()]
+	}
+	if source.EquivalentTo(sketchBuildPath.Join(sk.MainFile.Base() + ".cpp")) {
+		flags := []string{}
+		for _, ino := range append(paths.PathList{sk.MainFile}, sk.OtherSketchFiles...) {
+			if f := fileFlags[ino.String()]; f != "" {
+				flags = append(flags, f)
+			}
+		}
+		return strings.Join(flags, " ")
+	}
+	if inside, _ := source.IsInsideDir(sketchBuildPath); inside {
+		if rel, err := sketchBuildPath.RelTo(source); err == nil {
+			return fileFlags[sk.FullPath.JoinPath(rel).String()]
+		}
+	}
+	return fileFlags[source.String()]
+}
@@ -0,0 +1,53 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/internal/arduino/sketch"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileFlagsFor(t *testing.T) {
+	sketchPath := paths.New("/sketches", "Blink")
+	sk := &sketch.Sketch{
+		FullPath:         sketchPath,
+		MainFile:         sketchPath.Join("Blink.ino"),
+		OtherSketchFiles: paths.PathList{sketchPath.Join("Other.ino")},
+	}
+	sketchBuildPath := paths.New("/tmp", "build", "sketch")
+	library := paths.New("/libraries", "Servo", "src", "Servo.cpp")
+	fileFlags := map[string]string{
+		sketchPath.Join("src", "driver.cpp").String(): "-O0",
+		sketchPath.Join("Other.ino").String():         "-DOTHER",
+		library.String():                              "-Wno-unused",
+	}
+
+	// The sketch files are compiled from their copies in the build path
+	require.Equal(t, "-O0", fileFlagsFor(fileFlags, sketchBuildPath.Join("src", "driver.cpp"), sk, sketchBuildPath))
+	require.Equal(t, "-DOTHER", fileFlagsFor(fileFlags, sketchBuildPath.Join("Blink.ino.cpp"), sk, sketchBuildPath))
+	require.Equal(t, "", fileFlagsFor(fileFlags, sketchBuildPath.Join("src", "other.cpp"), sk, sketchBuildPath))
+	// The library files are compiled in place
+	require.Equal(t, "-Wno-unused", fileFlagsFor(fileFlags, library, sk, sketchBuildPath))
+	require.Equal(t, "", fileFlagsFor(fileFlags, paths.New("/cores", "arduino", "main.cpp"), sk, sketchBuildPath))
+	require.Equal(t, "", fileFlagsFor(nil, library, sk, sketchBuildPath))
+
+	// The flags of all the .ino files are applied to the merged file
+	fileFlags[sketchPath.Join("Blink.ino").String()] = "-DMAIN"
+	require.Equal(t, "-DMAIN -DOTHER", fileFlagsFor(fileFlags, sketchBuildPath.Join("Blink.ino.cpp"), sk, sketchBuildPath))
+}
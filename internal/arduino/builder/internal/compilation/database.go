@@ -17,8 +17,12 @@ package compilation
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"sync"
 
 	"github.com/arduino/arduino-cli/internal/i18n"
 	"github.com/arduino/go-paths-helper"
@@ -30,6 +34,14 @@ var tr = i18n.Tr
 type Database struct {
 	Contents []Command
 	File     *paths.Path
+
+	// stream is the file where the entries are written as soon as they are
+	// added, it's nil if the Database is kept in memory. streamEnd is the
+	// offset of the closing bracket of the JSON array in the stream.
+	stream        *os.File
+	streamMux     sync.Mutex
+	streamEntries int
+	streamEnd     int64
 }
 
 // Command keeps track of a single run of a compile command
@@ -48,22 +60,168 @@ func NewDatabase(filename *paths.Path) *Database {
 	}
 }
 
-// LoadDatabase reads a compilation database from a file
+// NewStreamingDatabase creates an empty CompilationDatabase that writes each
+// entry to filename as soon as it's added, instead of keeping all of them in
+// Contents until SaveToFile is called. This keeps the memory usage flat on
+// builds with thousands of files, but duplicated entries are not removed and
+// the entries are not sorted. The file is a valid JSON array after each
+// entry is added, so the entries of a build interrupted before SaveToFile is
+// called can still be loaded. SaveToFile must be called to close the file.
+func NewStreamingDatabase(filename *paths.Path) (*Database, error) {
+	if err := filename.Parent().MkdirAll(); err != nil {
+		return nil, err
+	}
+	stream, err := filename.Create()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stream.WriteString("[]"); err != nil {
+		stream.Close()
+		return nil, err
+	}
+	return &Database{
+		File:      filename,
+		Contents:  []Command{},
+		stream:    stream,
+		streamEnd: 1,
+	}, nil
+}
+
+// LoadDatabase reads a compilation database from a file. An error is returned
+// if the file is malformed or if any entry lacks the required fields.
 func LoadDatabase(file *paths.Path) (*Database, error) {
 	f, err := file.ReadFile()
 	if err != nil {
 		return nil, err
 	}
 	res := NewDatabase(file)
-	return res, json.Unmarshal(f, &res.Contents)
+	if err := json.Unmarshal(f, &res.Contents); err != nil {
+		return nil, fmt.Errorf(tr("invalid compilation database %[1]s: %[2]w"), file, err)
+	}
+	for i, entry := range res.Contents {
+		if err := entry.checkRequiredFields(); err != nil {
+			return nil, fmt.Errorf(tr("invalid compilation database %[1]s: entry %[2]d: %[3]w"), file, i, err)
+		}
+	}
+	return res, nil
+}
+
+// checkRequiredFields verifies that the Command has the fields required by
+// the compilation database format.
+func (c *Command) checkRequiredFields() error {
+	if c.File == "" {
+		return errors.New(tr("missing or empty 'file' field"))
+	}
+	if c.Command == "" && len(c.Arguments) == 0 {
+		return errors.New(tr("one of 'command' or 'arguments' fields is required"))
+	}
+	return nil
+}
+
+// Validate checks the entries of the Database against the filesystem: the
+// Directory of each entry must be an existing directory and the File must
+// exist (a relative File is resolved against the Directory). This helps to
+// find a stale database that references deleted files or folders.
+// The problems found are returned, an empty list means the Database is valid.
+func (db *Database) Validate() []error {
+	problems := []error{}
+	for i, entry := range db.Contents {
+		dir := paths.New(entry.Directory)
+		if dir == nil {
+			problems = append(problems, fmt.Errorf(tr("entry %[1]d: missing or empty 'directory' field"), i))
+		} else if exist, err := dir.ExistCheck(); err != nil {
+			problems = append(problems, fmt.Errorf(tr("entry %[1]d: directory %[2]s: %[3]w"), i, dir, err))
+		} else if !exist {
+			problems = append(problems, fmt.Errorf(tr("entry %[1]d: directory %[2]s does not exist"), i, dir))
+		} else if !dir.IsDir() {
+			problems = append(problems, fmt.Errorf(tr("entry %[1]d: %[2]s is not a directory"), i, dir))
+		}
+
+		file := paths.New(entry.File)
+		if file == nil {
+			problems = append(problems, fmt.Errorf(tr("entry %[1]d: missing or empty 'file' field"), i))
+			continue
+		}
+		if !file.IsAbs() && dir != nil {
+			file = dir.JoinPath(file)
+		}
+		if exist, err := file.ExistCheck(); err != nil {
+			problems = append(problems, fmt.Errorf(tr("entry %[1]d: file %[2]s: %[3]w"), i, file, err))
+		} else if !exist {
+			problems = append(problems, fmt.Errorf(tr("entry %[1]d: file %[2]s does not exist"), i, file))
+		}
+	}
+	return problems
+}
+
+// RemoveDuplicates removes the entries referring to the same File, keeping
+// only the last command added for each one.
+func (db *Database) RemoveDuplicates() {
+	last := map[string]int{}
+	for i, entry := range db.Contents {
+		last[entry.File] = i
+	}
+	res := []Command{}
+	for i, entry := range db.Contents {
+		if last[entry.File] == i {
+			res = append(res, entry)
+		}
+	}
+	db.Contents = res
+}
+
+// DatabaseStats is a summary of the contents of a Database
+type DatabaseStats struct {
+	// TotalCommands is the number of commands in the database
+	TotalCommands int
+	// UniqueFiles is the number of distinct files compiled
+	UniqueFiles int
+	// FilesByExtension is the number of distinct files compiled, grouped by
+	// file extension (for example ".cpp" or ".c")
+	FilesByExtension map[string]int
+}
+
+// Stats returns a summary of the contents of the Database. The exit codes of
+// the commands are not tracked, so the failures can't be counted.
+// A streaming Database doesn't keep its entries in memory, so they are not
+// counted.
+func (db *Database) Stats() DatabaseStats {
+	stats := DatabaseStats{
+		TotalCommands:    len(db.Contents),
+		FilesByExtension: map[string]int{},
+	}
+	seen := map[string]bool{}
+	for _, entry := range db.Contents {
+		if seen[entry.File] {
+			continue
+		}
+		seen[entry.File] = true
+		stats.UniqueFiles++
+		stats.FilesByExtension[filepath.Ext(entry.File)]++
+	}
+	return stats
 }
 
 // SaveToFile save the CompilationDatabase to file as a clangd-compatible compile_commands.json,
 // see https://clang.llvm.org/docs/JSONCompilationDatabase.html
+// Duplicated entries are removed and the entries are sorted by File to
+// produce a stable output.
+// If the Database is streaming, the entries have already been written and the
+// file is just closed.
 func (db *Database) SaveToFile() {
+	if db.stream != nil {
+		db.closeStream()
+		return
+	}
+	db.RemoveDuplicates()
+	sort.SliceStable(db.Contents, func(i, j int) bool {
+		return db.Contents[i].File < db.Contents[j].File
+	})
 	if jsonContents, err := json.MarshalIndent(db.Contents, "", " "); err != nil {
 		fmt.Println(tr("Error serializing compilation database: %s", err))
 		return
+	} else if err := db.File.Parent().MkdirAll(); err != nil {
+		fmt.Println(tr("Error writing compilation database: %s", err))
 	} else if err := db.File.WriteFile(jsonContents); err != nil {
 		fmt.Println(tr("Error writing compilation database: %s", err))
 	}
@@ -88,5 +246,48 @@ func (db *Database) Add(target *paths.Path, command *paths.Process) {
 		File:      target.String(),
 	}
 
+	if db.stream != nil {
+		db.writeToStream(entry)
+		return
+	}
 	db.Contents = append(db.Contents, entry)
 }
+
+// writeToStream appends the entry to the JSON array being written to the
+// stream, using the same formatting of SaveToFile. The entry overwrites the
+// closing bracket of the array, that is written again after it.
+func (db *Database) writeToStream(entry Command) {
+	jsonEntry, err := json.MarshalIndent(entry, " ", " ")
+	if err != nil {
+		fmt.Println(tr("Error serializing compilation database: %s", err))
+		return
+	}
+
+	// Add may be called concurrently by the compile jobs
+	db.streamMux.Lock()
+	defer db.streamMux.Unlock()
+	if db.stream == nil {
+		return
+	}
+	separator := ",\n "
+	if db.streamEntries == 0 {
+		separator = "\n "
+	}
+	data := append([]byte(separator), jsonEntry...)
+	if _, err := db.stream.WriteAt(append(data, "\n]"...), db.streamEnd); err != nil {
+		fmt.Println(tr("Error writing compilation database: %s", err))
+		return
+	}
+	db.streamEnd += int64(len(data))
+	db.streamEntries++
+}
+
+// closeStream closes the stream, the JSON array is already complete.
+func (db *Database) closeStream() {
+	db.streamMux.Lock()
+	defer db.streamMux.Unlock()
+	if err := db.stream.Close(); err != nil {
+		fmt.Println(tr("Error writing compilation database: %s", err))
+	}
+	db.stream = nil
+}
@@ -16,7 +16,12 @@
 package compilation
 
 import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
 	"testing"
+	"time"
 
 	"github.com/arduino/go-paths-helper"
 	"github.com/stretchr/testify/require"
@@ -44,3 +49,204 @@ func TestCompilationDatabase(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, db2.Contents[0].Directory, cwd.String())
 }
+
+func TestLoadMalformedCompilationDatabase(t *testing.T) {
+	load := func(data string) error {
+		tmpfile, err := paths.WriteToTempFile([]byte(data), nil, "")
+		require.NoError(t, err)
+		defer tmpfile.Remove()
+		_, err = LoadDatabase(tmpfile)
+		return err
+	}
+
+	require.NoError(t, load(`[{"directory":"/tmp","command":"gcc -c a.c","file":"a.c"}]`))
+	require.NoError(t, load(`[{"directory":"/tmp","arguments":["gcc","-c","a.c"],"file":"a.c"}]`))
+	require.NoError(t, load(`[]`))
+
+	err := load(`{"directory":"/tmp"}`)
+	require.Error(t, err)
+
+	err = load(`[{"directory":"/tmp","command":"gcc -c a.c","file":"a.c"},{"directory":"/tmp","command":"gcc -c b.c"}]`)
+	require.ErrorContains(t, err, "entry 1")
+	require.ErrorContains(t, err, "'file'")
+
+	err = load(`[{"directory":"/tmp","file":"a.c"}]`)
+	require.ErrorContains(t, err, "entry 0")
+	require.ErrorContains(t, err, "'command' or 'arguments'")
+}
+
+func TestCompilationDatabaseRemoveDuplicatesAndSort(t *testing.T) {
+	tmpfile, err := paths.WriteToTempFile([]byte{}, nil, "")
+	require.NoError(t, err)
+	defer tmpfile.Remove()
+
+	db := NewDatabase(tmpfile)
+	db.Contents = []Command{
+		{Directory: "/tmp", Command: "gcc -O1 -c b.c", File: "b.c"},
+		{Directory: "/tmp", Command: "gcc -c a.c", File: "a.c"},
+		{Directory: "/tmp", Command: "gcc -O2 -c b.c", File: "b.c"},
+	}
+	db.RemoveDuplicates()
+	require.Equal(t, []Command{
+		{Directory: "/tmp", Command: "gcc -c a.c", File: "a.c"},
+		{Directory: "/tmp", Command: "gcc -O2 -c b.c", File: "b.c"},
+	}, db.Contents)
+
+	db.Contents = append(db.Contents,
+		Command{Directory: "/tmp", Command: "gcc -c c.c", File: "c.c"},
+		Command{Directory: "/tmp", Command: "gcc -O3 -c a.c", File: "a.c"},
+	)
+	db.SaveToFile()
+	db2, err := LoadDatabase(tmpfile)
+	require.NoError(t, err)
+	require.Equal(t, []Command{
+		{Directory: "/tmp", Command: "gcc -O3 -c a.c", File: "a.c"},
+		{Directory: "/tmp", Command: "gcc -O2 -c b.c", File: "b.c"},
+		{Directory: "/tmp", Command: "gcc -c c.c", File: "c.c"},
+	}, db2.Contents)
+}
+
+func TestCompilationDatabaseSaveCreatesFolder(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	dbFile := tmp.Join("sub", "folder", "compile_commands.json")
+	cmd, err := paths.NewProcess(nil, "gcc", "-c", "test.c")
+	require.NoError(t, err)
+	db := NewDatabase(dbFile)
+	db.Add(paths.New("test.c"), cmd)
+	db.SaveToFile()
+
+	db2, err := LoadDatabase(dbFile)
+	require.NoError(t, err)
+	require.Len(t, db2.Contents, 1)
+}
+
+func TestCompilationDatabaseStats(t *testing.T) {
+	db := NewDatabase(nil)
+	require.Equal(t, DatabaseStats{FilesByExtension: map[string]int{}}, db.Stats())
+
+	db.Contents = []Command{
+		{Directory: "/tmp", Command: "gcc -O1 -c b.c", File: "/tmp/b.c"},
+		{Directory: "/tmp", Command: "g++ -c a.cpp", File: "/tmp/a.cpp"},
+		{Directory: "/tmp", Command: "gcc -O2 -c b.c", File: "/tmp/b.c"},
+		{Directory: "/tmp", Command: "gcc -c c.c", File: "/tmp/c.c"},
+		{Directory: "/tmp", Command: "gcc -c wiring.S", File: "/tmp/wiring.S"},
+	}
+	require.Equal(t, DatabaseStats{
+		TotalCommands:    5,
+		UniqueFiles:      4,
+		FilesByExtension: map[string]int{".c": 2, ".cpp": 1, ".S": 1},
+	}, db.Stats())
+}
+
+func TestStreamingCompilationDatabase(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	gcc, err := paths.NewProcess(nil, "gcc", "-c", "a.c")
+	require.NoError(t, err)
+	gpp, err := paths.NewProcess(nil, "g++", "-c", "b.cpp")
+	require.NoError(t, err)
+
+	inMemory := NewDatabase(tmp.Join("in-memory", "compile_commands.json"))
+	streaming, err := NewStreamingDatabase(tmp.Join("streaming", "compile_commands.json"))
+	require.NoError(t, err)
+	for _, db := range []*Database{inMemory, streaming} {
+		db.Add(paths.New("a.c"), gcc)
+		db.Add(paths.New("b.cpp"), gpp)
+	}
+	// The entries are written as they are added
+	require.Empty(t, streaming.Contents)
+	inMemory.SaveToFile()
+	streaming.SaveToFile()
+
+	expected, err := inMemory.File.ReadFile()
+	require.NoError(t, err)
+	streamed, err := streaming.File.ReadFile()
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(streamed))
+
+	db, err := LoadDatabase(streaming.File)
+	require.NoError(t, err)
+	require.Len(t, db.Contents, 2)
+	require.Equal(t, "a.c", db.Contents[0].File)
+	require.Equal(t, []string{"g++", "-c", "b.cpp"}, db.Contents[1].Arguments)
+
+	// An empty streaming database is still a valid JSON array
+	empty, err := NewStreamingDatabase(tmp.Join("empty.json"))
+	require.NoError(t, err)
+	empty.SaveToFile()
+	data, err := empty.File.ReadFile()
+	require.NoError(t, err)
+	require.Equal(t, "[]", string(data))
+}
+
+func TestStreamingCompilationDatabaseInterrupted(t *testing.T) {
+	if dbFile := os.Getenv("TEST_STREAMING_COMPILATION_DATABASE"); dbFile != "" {
+		// Run as a build that is killed before the database is saved
+		db, err := NewStreamingDatabase(paths.New(dbFile))
+		require.NoError(t, err)
+		for _, file := range []string{"a.c", "b.cpp"} {
+			cmd, err := paths.NewProcess(nil, "gcc", "-c", file)
+			require.NoError(t, err)
+			db.Add(paths.New(file), cmd)
+		}
+		fmt.Println("added")
+		time.Sleep(time.Minute)
+		return
+	}
+
+	tmp, err := paths.MkTempDir("", "")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+	dbFile := tmp.Join("compile_commands.json")
+
+	build := exec.Command(os.Args[0], "-test.run=^TestStreamingCompilationDatabaseInterrupted$")
+	build.Env = append(os.Environ(), "TEST_STREAMING_COMPILATION_DATABASE="+dbFile.String())
+	stdout, err := build.StdoutPipe()
+	require.NoError(t, err)
+	require.NoError(t, build.Start())
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() && scanner.Text() != "added" {
+	}
+	require.NoError(t, build.Process.Kill())
+	build.Wait()
+
+	// The entries added before the build was killed are readable
+	db, err := LoadDatabase(dbFile)
+	require.NoError(t, err)
+	require.Len(t, db.Contents, 2)
+	require.Equal(t, "a.c", db.Contents[0].File)
+	require.Equal(t, []string{"gcc", "-c", "b.cpp"}, db.Contents[1].Arguments)
+}
+
+func TestCompilationDatabaseValidate(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+	require.NoError(t, tmp.Join("a.c").WriteFile([]byte{}))
+
+	db := NewDatabase(tmp.Join("compile_commands.json"))
+	db.Contents = []Command{
+		{Directory: tmp.String(), Command: "gcc -c a.c", File: "a.c"},
+		{Directory: tmp.String(), Command: "gcc -c a.c", File: tmp.Join("a.c").String()},
+	}
+	require.Empty(t, db.Validate())
+
+	db.Contents = []Command{
+		{Directory: tmp.String(), Command: "gcc -c b.c", File: "b.c"},
+		{Directory: tmp.Join("missing").String(), Command: "gcc -c a.c", File: tmp.Join("a.c").String()},
+		{Directory: tmp.Join("a.c").String(), Command: "gcc -c a.c", File: tmp.Join("a.c").String()},
+	}
+	problems := db.Validate()
+	require.Len(t, problems, 3)
+	require.ErrorContains(t, problems[0], "entry 0")
+	require.ErrorContains(t, problems[0], "b.c does not exist")
+	require.ErrorContains(t, problems[1], "entry 1")
+	require.ErrorContains(t, problems[1], "missing does not exist")
+	require.ErrorContains(t, problems[2], "entry 2")
+	require.ErrorContains(t, problems[2], "is not a directory")
+}
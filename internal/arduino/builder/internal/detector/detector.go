@@ -47,6 +47,13 @@ type libraryResolutionResult struct {
 	NotUsedLibraries []*libraries.Library
 }
 
+// LibraryInclusion records the source file whose #include caused a library to
+// be imported
+type LibraryInclusion struct {
+	Source  *paths.Path
+	Library *libraries.Library
+}
+
 // SketchLibrariesDetector todo
 type SketchLibrariesDetector struct {
 	librariesManager              *librariesmanager.LibrariesManager
@@ -54,8 +61,10 @@ type SketchLibrariesDetector struct {
 	useCachedLibrariesResolution  bool
 	onlyUpdateCompilationDatabase bool
 	importedLibraries             libraries.List
+	libraryInclusions             []LibraryInclusion
 	librariesResolutionResults    map[string]libraryResolutionResult
 	includeFolders                paths.PathList
+	onlyExplicitLibraries         bool
 	logger                        *logger.BuilderLogger
 }
 
@@ -65,6 +74,7 @@ func NewSketchLibrariesDetector(
 	libsResolver *librariesresolver.Cpp,
 	useCachedLibrariesResolution bool,
 	onlyUpdateCompilationDatabase bool,
+	onlyExplicitLibraries bool,
 	logger *logger.BuilderLogger,
 ) *SketchLibrariesDetector {
 	return &SketchLibrariesDetector{
@@ -75,6 +85,7 @@ func NewSketchLibrariesDetector(
 		importedLibraries:             libraries.List{},
 		includeFolders:                paths.PathList{},
 		onlyUpdateCompilationDatabase: onlyUpdateCompilationDatabase,
+		onlyExplicitLibraries:         onlyExplicitLibraries,
 		logger:                        logger,
 	}
 }
@@ -114,6 +125,10 @@ func (l *SketchLibrariesDetector) resolveLibrary(header, platformArch string) *l
 		}
 	}
 
+	if l.logger.Verbose() {
+		l.logger.Info(fmt.Sprintf("  -> %s: %s (%s)", tr("selected"), selected.InstallDir, selected.Location.String()))
+	}
+
 	candidates.Remove(selected)
 	l.librariesResolutionResults[header] = libraryResolutionResult{
 		Library:          selected,
@@ -135,6 +150,14 @@ func (l *SketchLibrariesDetector) AppendImportedLibraries(library *libraries.Lib
 	l.importedLibraries = append(l.importedLibraries, library)
 }
 
+// LibraryInclusions returns, for each imported library, the source file
+// whose #include caused it to be imported. Once imported, the headers of a
+// library are found in the include path, so the other source files including
+// it are not recorded.
+func (l *SketchLibrariesDetector) LibraryInclusions() []LibraryInclusion {
+	return l.libraryInclusions
+}
+
 // PrintUsedAndNotUsedLibraries todo
 func (l *SketchLibrariesDetector) PrintUsedAndNotUsedLibraries(sketchError bool) {
 	// Print this message:
@@ -392,6 +415,9 @@ func (l *SketchLibrariesDetector) findIncludesUntilDone(
 				}
 			}
 			l.logger.WriteStderr(preprocStderr)
+			if l.onlyExplicitLibraries {
+				return errors.New(tr("%[1]s is not provided by any of the explicitly specified libraries", missingIncludeH))
+			}
 			return preprocErr
 		}
 
@@ -399,6 +425,7 @@ func (l *SketchLibrariesDetector) findIncludesUntilDone(
 		// include path and queue its source files for further
 		// include scanning
 		l.AppendImportedLibraries(library)
+		l.libraryInclusions = append(l.libraryInclusions, LibraryInclusion{Source: sourcePath, Library: library})
 		l.appendIncludeFolder(cache, sourcePath, missingIncludeH, library.SourceDir)
 
 		if library.Precompiled && library.PrecompiledWithSources {
@@ -592,6 +619,8 @@ func LibrariesLoader(
 	librariesManager *librariesmanager.LibrariesManager,
 	builtInLibrariesDirs *paths.Path, libraryDirs, otherLibrariesDirs paths.PathList,
 	actualPlatform, targetPlatform *cores.PlatformRelease,
+	librariesLocationsOrder []libraries.LibraryLocation,
+	onlyExplicitLibraries bool,
 ) (*librariesmanager.LibrariesManager, *librariesresolver.Cpp, []byte, error) {
 	verboseOut := &bytes.Buffer{}
 	lm := librariesManager
@@ -614,18 +643,22 @@ func LibrariesLoader(
 			})
 		}
 
-		if actualPlatform != targetPlatform {
+		// Platform bundled libraries are not used if only the explicitly
+		// specified libraries are allowed
+		if !onlyExplicitLibraries {
+			if actualPlatform != targetPlatform {
+				lmb.AddLibrariesDir(&librariesmanager.LibrariesDir{
+					PlatformRelease: actualPlatform,
+					Path:            actualPlatform.GetLibrariesDir(),
+					Location:        libraries.ReferencedPlatformBuiltIn,
+				})
+			}
 			lmb.AddLibrariesDir(&librariesmanager.LibrariesDir{
-				PlatformRelease: actualPlatform,
-				Path:            actualPlatform.GetLibrariesDir(),
-				Location:        libraries.ReferencedPlatformBuiltIn,
+				PlatformRelease: targetPlatform,
+				Path:            targetPlatform.GetLibrariesDir(),
+				Location:        libraries.PlatformBuiltIn,
 			})
 		}
-		lmb.AddLibrariesDir(&librariesmanager.LibrariesDir{
-			PlatformRelease: targetPlatform,
-			Path:            targetPlatform.GetLibrariesDir(),
-			Location:        libraries.PlatformBuiltIn,
-		})
 
 		librariesFolders := otherLibrariesDirs
 		if err := librariesFolders.ToAbs(); err != nil {
@@ -665,6 +698,9 @@ func LibrariesLoader(
 
 	allLibs := lm.FindAllInstalled()
 	resolver := librariesresolver.NewCppResolver(allLibs, targetPlatform, actualPlatform)
+	if len(librariesLocationsOrder) > 0 {
+		resolver.SetLocationsOrder(librariesLocationsOrder)
+	}
 	return lm, resolver, verboseOut.Bytes(), nil
 }
 
@@ -89,6 +89,19 @@ func ParseCompilerOutput(compiler *DetectedCompiler, out []byte) ([]*Diagnostic,
 	}
 }
 
+// Parse parses the plain-text output of a gcc-compatible compiler (gcc,
+// avr-gcc, arm-none-eabi-gcc, ...) and returns the diagnostics found. Unlike
+// ParseCompilerOutput it doesn't need the compiler command line, so it can be
+// used on any captured output. Notes are attached to the diagnostic they
+// refer to.
+func Parse(output string) Diagnostics {
+	diags, err := parseGccOutput(splitLines([]byte(output)))
+	if err != nil {
+		return nil
+	}
+	return diags
+}
+
 func splitLines(in []byte) []string {
 	res := strings.Split(string(in), "\n")
 	for i, line := range res {
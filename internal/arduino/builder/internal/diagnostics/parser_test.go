@@ -55,3 +55,72 @@ func runParserTest(t *testing.T, testFile string) {
 	require.NoError(t, err)
 	require.Equal(t, string(golden), string(output))
 }
+
+func TestParse(t *testing.T) {
+	t.Run("AvrGcc", func(t *testing.T) {
+		data, err := paths.New("testdata", "parse", "avr-gcc.txt").ReadFile()
+		require.NoError(t, err)
+		diags := Parse(string(data))
+		require.Len(t, diags, 3)
+
+		require.Equal(t, SeverityError, string(diags[0].Severity))
+		require.Equal(t, "/home/user/Arduino/libraries/Servo/src/Servo.h", diags[0].File)
+		require.Equal(t, 75, diags[0].Line)
+		require.Equal(t, 2, diags[0].Column)
+		require.True(t, strings.HasPrefix(diags[0].Message, `#error "This library only supports boards`))
+		require.Len(t, diags[0].Context, 1)
+		require.Equal(t, "/home/user/Arduino/Blink/Blink.ino", diags[0].Context[0].File)
+		require.Equal(t, 1, diags[0].Context[0].Line)
+
+		require.Equal(t, SeverityError, string(diags[1].Severity))
+		require.Equal(t, "/home/user/Arduino/Blink/Blink.ino", diags[1].File)
+		require.Equal(t, 9, diags[1].Line)
+		require.Equal(t, 3, diags[1].Column)
+		require.True(t, strings.HasPrefix(diags[1].Message, "'digitalWirte' was not declared in this scope"))
+		require.Len(t, diags[1].Context, 1)
+		require.Equal(t, "In function 'void loop()':", diags[1].Context[0].Message)
+		require.Len(t, diags[1].Suggestions, 1)
+		require.Equal(t, 9, diags[1].Suggestions[0].Line)
+		require.True(t, strings.HasPrefix(diags[1].Suggestions[0].Message, "suggested alternative: 'digitalWrite'"))
+		require.True(t, strings.HasSuffix(diags[1].Suggestions[0].Message, "\n   digitalWrite"))
+
+		require.Equal(t, SeverityWarning, string(diags[2].Severity))
+		require.Equal(t, 10, diags[2].Line)
+		require.Equal(t, 7, diags[2].Column)
+		require.True(t, strings.HasPrefix(diags[2].Message, "unused variable 'count' [-Wunused-variable]"))
+	})
+
+	t.Run("ArmNoneEabiGcc", func(t *testing.T) {
+		data, err := paths.New("testdata", "parse", "arm-none-eabi-gcc.txt").ReadFile()
+		require.NoError(t, err)
+		diags := Parse(string(data))
+		require.Len(t, diags, 2)
+
+		require.Equal(t, SeverityWarning, string(diags[0].Severity))
+		require.Equal(t, "/home/user/Arduino/Motor/Motor.ino", diags[0].File)
+		require.Equal(t, 12, diags[0].Line)
+		require.Equal(t, 18, diags[0].Column)
+		require.Empty(t, diags[0].Suggestions)
+
+		require.Equal(t, SeverityError, string(diags[1].Severity))
+		require.Equal(t, 20, diags[1].Line)
+		require.Equal(t, 3, diags[1].Column)
+		require.True(t, strings.HasPrefix(diags[1].Message, "no matching function for call to 'Motor::start(int, int)'"))
+		require.Len(t, diags[1].Suggestions, 2)
+		require.Equal(t, "/home/user/Arduino/Motor/Motor.h", diags[1].Suggestions[0].File)
+		require.Equal(t, 8, diags[1].Suggestions[0].Line)
+		require.Equal(t, 8, diags[1].Suggestions[0].Column)
+		require.True(t, strings.HasPrefix(diags[1].Suggestions[0].Message, "candidate: 'void Motor::start(int)'"))
+		require.Equal(t, "  candidate expects 1 argument, 2 provided", diags[1].Suggestions[1].Message)
+	})
+
+	t.Run("WindowsLineEndings", func(t *testing.T) {
+		diags := Parse("C:\\Sketch\\Sketch.ino:3:1: error: 'foo' does not name a type\r\n foo;\r\n ^~~\r\n")
+		require.Len(t, diags, 1)
+		require.Equal(t, "C:\\Sketch\\Sketch.ino", diags[0].File)
+		require.Equal(t, 3, diags[0].Line)
+		require.Equal(t, "'foo' does not name a type\n foo;\n ^~~", diags[0].Message)
+	})
+
+	require.Empty(t, Parse(""))
+}
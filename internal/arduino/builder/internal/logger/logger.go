@@ -16,9 +16,11 @@
 package logger
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"os"
+	"regexp"
 	"sync"
 )
 
@@ -98,3 +100,118 @@ func (l *BuilderLogger) Stdout() io.Writer {
 func (l *BuilderLogger) Stderr() io.Writer {
 	return l.stderr
 }
+
+// LineWriter is an io.Writer that forwards the data written to it only when
+// one or more lines are completed. It allows to stream the output of multiple
+// processes running concurrently without mixing their lines.
+type LineWriter struct {
+	write func([]byte) (int, error)
+	buf   []byte
+}
+
+// NewLineWriter creates a LineWriter that sends the complete lines to
+// the given write function (for example BuilderLogger.WriteStdout).
+func NewLineWriter(write func([]byte) (int, error)) *LineWriter {
+	return &LineWriter{write: write}
+}
+
+// Write implements io.Writer
+func (w *LineWriter) Write(data []byte) (int, error) {
+	w.buf = append(w.buf, data...)
+	if i := bytes.LastIndexByte(w.buf, '\n'); i != -1 {
+		if _, err := w.write(w.buf[:i+1]); err != nil {
+			return 0, err
+		}
+		w.buf = append([]byte{}, w.buf[i+1:]...)
+	}
+	return len(data), nil
+}
+
+// Flush sends the remaining incomplete line, if any.
+func (w *LineWriter) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	_, err := w.write(w.buf)
+	w.buf = nil
+	return err
+}
+
+var (
+	// diagnosticMessageRegexp matches the lines with the message of a diagnostic,
+	// for example "Blink.ino:5:3: error: 'foo' was not declared in this scope"
+	diagnosticMessageRegexp = regexp.MustCompile(`^\S.*?:\d+(:\d+)?: (fatal error|error|warning|note): `)
+	// diagnosticContextRegexp matches the lines introducing the context of a
+	// diagnostic, for example "In file included from Blink.ino:1:" or
+	// "Blink.ino: In function 'void setup()':"
+	diagnosticContextRegexp = regexp.MustCompile(`^(In file included from |\S.*?: (In|At) )`)
+)
+
+// DiagnosticWriter is an io.Writer that forwards the compiler output written to
+// it one diagnostic at a time: the lines of a diagnostic (the context, the
+// message, the source excerpt and the notes) are sent together once the next
+// diagnostic starts, or on Flush. It allows to stream the output of multiple
+// compilers running concurrently without mixing their diagnostics.
+type DiagnosticWriter struct {
+	write      func([]byte) (int, error)
+	line       []byte
+	group      []byte
+	hasMessage bool
+}
+
+// NewDiagnosticWriter creates a DiagnosticWriter that sends the complete
+// diagnostics to the given write function (for example BuilderLogger.WriteStderr).
+func NewDiagnosticWriter(write func([]byte) (int, error)) *DiagnosticWriter {
+	return &DiagnosticWriter{write: write}
+}
+
+// Write implements io.Writer
+func (w *DiagnosticWriter) Write(data []byte) (int, error) {
+	w.line = append(w.line, data...)
+	for {
+		i := bytes.IndexByte(w.line, '\n')
+		if i == -1 {
+			break
+		}
+		if err := w.addLine(w.line[:i+1]); err != nil {
+			return 0, err
+		}
+		w.line = w.line[i+1:]
+	}
+	w.line = append([]byte{}, w.line...)
+	return len(data), nil
+}
+
+// addLine adds a complete line to the current diagnostic, sending the current
+// diagnostic first if the line starts a new one.
+func (w *DiagnosticWriter) addLine(line []byte) error {
+	message := diagnosticMessageRegexp.FindSubmatch(line)
+	startsDiagnostic := diagnosticContextRegexp.Match(line) || (message != nil && string(message[2]) != "note")
+	if startsDiagnostic && w.hasMessage {
+		if err := w.flushGroup(); err != nil {
+			return err
+		}
+	}
+	w.group = append(w.group, line...)
+	if message != nil {
+		w.hasMessage = true
+	}
+	return nil
+}
+
+func (w *DiagnosticWriter) flushGroup() error {
+	group := w.group
+	w.group, w.hasMessage = nil, false
+	if len(group) == 0 {
+		return nil
+	}
+	_, err := w.write(group)
+	return err
+}
+
+// Flush sends the current diagnostic and the remaining incomplete line, if any.
+func (w *DiagnosticWriter) Flush() error {
+	w.group = append(w.group, w.line...)
+	w.line = nil
+	return w.flushGroup()
+}
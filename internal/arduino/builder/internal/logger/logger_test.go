@@ -0,0 +1,124 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package logger
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineWriter(t *testing.T) {
+	writes := []string{}
+	w := NewLineWriter(func(data []byte) (int, error) {
+		writes = append(writes, string(data))
+		return len(data), nil
+	})
+
+	n, err := w.Write([]byte("first "))
+	require.NoError(t, err)
+	require.Equal(t, 6, n)
+	require.Empty(t, writes)
+
+	w.Write([]byte("line\nsecond line\nthird"))
+	require.Equal(t, []string{"first line\nsecond line\n"}, writes)
+
+	w.Write([]byte(" line\n"))
+	require.Equal(t, []string{"first line\nsecond line\n", "third line\n"}, writes)
+
+	require.NoError(t, w.Flush())
+	require.Len(t, writes, 2)
+
+	w.Write([]byte("incomplete"))
+	require.NoError(t, w.Flush())
+	require.Equal(t, "incomplete", writes[2])
+}
+
+func TestLineWriterWithLogger(t *testing.T) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	l := New(stdout, stderr, false, "")
+	w := NewLineWriter(l.WriteStderr)
+	w.Write([]byte("warning: "))
+	require.Empty(t, stderr.String())
+	w.Write([]byte("unused variable\n"))
+	require.Equal(t, "warning: unused variable\n", stderr.String())
+	require.Empty(t, stdout.String())
+}
+
+func TestDiagnosticWriter(t *testing.T) {
+	writes := []string{}
+	w := NewDiagnosticWriter(func(data []byte) (int, error) {
+		writes = append(writes, string(data))
+		return len(data), nil
+	})
+
+	first := "In file included from /sketch/Blink.ino:1:\n" +
+		"/sketch/helper.h: In function 'void blink()':\n" +
+		"/sketch/helper.h:5:3: error: 'foo' was not declared in this scope\n" +
+		"    5 |   foo();\n" +
+		"      |   ^~~\n" +
+		"/sketch/helper.h:2:6: note: suggested alternative: 'for'\n"
+	second := "/sketch/Blink.ino:9:7: warning: unused variable 'x' [-Wunused-variable]\n" +
+		"    9 |   int x;\n" +
+		"      |       ^\n"
+
+	// The lines are written in small chunks, like the output of a process
+	for _, line := range []string{first[:10], first[10:70], first[70:]} {
+		n, err := w.Write([]byte(line))
+		require.NoError(t, err)
+		require.Equal(t, len(line), n)
+	}
+	require.Empty(t, writes)
+
+	// The first diagnostic is sent when the second one starts...
+	w.Write([]byte(second))
+	require.Equal(t, []string{first}, writes)
+
+	// ...and the last one on Flush, with the incomplete line
+	w.Write([]byte("compilation terminated."))
+	require.NoError(t, w.Flush())
+	require.Equal(t, []string{first, second + "compilation terminated."}, writes)
+
+	require.NoError(t, w.Flush())
+	require.Len(t, writes, 2)
+}
+
+func TestDiagnosticWriterWithConcurrentCommands(t *testing.T) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	l := New(stdout, stderr, false, "")
+	w1 := NewDiagnosticWriter(l.WriteStderr)
+	w2 := NewDiagnosticWriter(l.WriteStderr)
+
+	// The diagnostics of the two commands are written interleaved...
+	w1.Write([]byte("/sketch/a.cpp: In function 'void a()':\n"))
+	w2.Write([]byte("/sketch/b.cpp:3:1: error: expected ';' before '}' token\n"))
+	w1.Write([]byte("/sketch/a.cpp:4:5: warning: unused variable 'y'\n"))
+	w2.Write([]byte("    3 | }\n"))
+	w1.Write([]byte("    4 |   int y;\n"))
+	require.NoError(t, w2.Flush())
+	require.NoError(t, w1.Flush())
+
+	// ...but each one is reported as a whole
+	require.Equal(t,
+		"/sketch/b.cpp:3:1: error: expected ';' before '}' token\n"+
+			"    3 | }\n"+
+			"/sketch/a.cpp: In function 'void a()':\n"+
+			"/sketch/a.cpp:4:5: warning: unused variable 'y'\n"+
+			"    4 |   int y;\n",
+		stderr.String())
+	require.Empty(t, stdout.String())
+}
@@ -15,7 +15,11 @@
 
 package progress
 
-import rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+import (
+	"sync"
+
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+)
 
 // Struct fixdoc
 type Struct struct {
@@ -23,6 +27,9 @@ type Struct struct {
 	StepAmount float32
 	Parent     *Struct
 	callback   rpc.TaskProgressCB
+	phase      string
+	// The progress may be notified by concurrent compile jobs
+	lock sync.Mutex
 }
 
 // New fixdoc
@@ -52,15 +59,44 @@ func (p *Struct) RemoveSubSteps() {
 
 // CompleteStep fixdoc
 func (p *Struct) CompleteStep() {
+	p.lock.Lock()
 	p.Progress += p.StepAmount
+	p.lock.Unlock()
 	p.pushProgress()
 }
 
+// StartPhase notifies the start of a new phase of the build, for example
+// "Compiling sketch..."
+func (p *Struct) StartPhase(name string) {
+	p.lock.Lock()
+	p.phase = name
+	p.lock.Unlock()
+	p.push(func() *rpc.TaskProgress {
+		return &rpc.TaskProgress{Name: p.phase, Percent: p.Progress}
+	})
+}
+
+// PushMessage notifies a message about the current phase, for example the
+// file that has been compiled
+func (p *Struct) PushMessage(msg string) {
+	p.push(func() *rpc.TaskProgress {
+		return &rpc.TaskProgress{Name: p.phase, Message: msg, Percent: p.Progress}
+	})
+}
+
 func (p *Struct) pushProgress() {
-	if p.callback != nil {
-		p.callback(&rpc.TaskProgress{
-			Percent:   p.Progress,
-			Completed: p.Progress >= 100.0,
-		})
+	p.push(func() *rpc.TaskProgress {
+		return &rpc.TaskProgress{Percent: p.Progress, Completed: p.Progress >= 100.0}
+	})
+}
+
+// push calls the callback with the TaskProgress created by the given function
+// while holding the lock
+func (p *Struct) push(progress func() *rpc.TaskProgress) {
+	if p.callback == nil {
+		return
 	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.callback(progress())
 }
@@ -115,8 +115,15 @@ func (b *Builder) compileLibraries(libraries libraries.List, includes []string)
 
 	objectFiles := paths.NewPathList()
 	for _, library := range libraries {
-		libraryObjectFiles, err := b.compileLibrary(library, includes)
+		libraryIncludes := includes
+		if b.strictIncludes {
+			libraryIncludes = b.strictLibraryIncludes(library, libraries)
+		}
+		libraryObjectFiles, err := b.compileLibrary(library, libraryIncludes)
 		if err != nil {
+			if b.strictIncludes {
+				return nil, b.strictIncludesError(library, err)
+			}
 			return nil, err
 		}
 		objectFiles.AddAll(libraryObjectFiles)
@@ -19,9 +19,19 @@ import (
 	"strings"
 
 	f "github.com/arduino/arduino-cli/internal/algorithms"
+	"github.com/arduino/arduino-cli/internal/arduino/builder/internal/utils"
 	"github.com/arduino/go-paths-helper"
 )
 
+// LinkCommand returns the fully expanded command line used to link the
+// sketch, or an empty string if the build didn't reach the link step
+func (b *Builder) LinkCommand() string {
+	if b.linkCommand == nil {
+		return ""
+	}
+	return utils.PrintableCommand(b.linkCommand)
+}
+
 // link fixdoc
 func (b *Builder) link() error {
 	if b.onlyUpdateCompilationDatabase {
@@ -92,6 +102,7 @@ func (b *Builder) link() error {
 	if err != nil {
 		return err
 	}
+	b.linkCommand = command.GetArgs()
 
 	return b.execCommand(command)
 }
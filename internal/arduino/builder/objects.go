@@ -0,0 +1,104 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"encoding/json"
+	"path/filepath"
+
+	"github.com/arduino/go-paths-helper"
+)
+
+// objectsManifest is the content of the manifest.json file written by
+// ExportObjectFiles, the paths of the object files are relative to the
+// manifest folder.
+type objectsManifest struct {
+	CoreArchive string         `json:"core_archive,omitempty"`
+	Objects     []objectsEntry `json:"objects"`
+}
+
+type objectsEntry struct {
+	Source string `json:"source,omitempty"`
+	Object string `json:"object"`
+}
+
+// ExportObjectFiles copies the object files of the sketch, of the libraries
+// and of the core, together with the core archive, into the given directory
+// keeping the layout of the build path. A manifest.json file, mapping each
+// source file to the corresponding object file, is written as well.
+func (b *Builder) ExportObjectFiles(destDir *paths.Path) error {
+	if err := destDir.MkdirAll(); err != nil {
+		return err
+	}
+
+	// The compilation database keeps track of the source of every object file
+	sources := map[string]string{}
+	for _, command := range b.compilationDatabase.Contents {
+		for i, arg := range command.Arguments {
+			if arg == "-o" && i+1 < len(command.Arguments) {
+				sources[paths.New(command.Arguments[i+1]).String()] = command.File
+			}
+		}
+	}
+
+	exportFile := func(file *paths.Path) (string, error) {
+		// Files outside the build path, like the cached core archive, are
+		// copied in the root of the destination directory
+		rel := paths.New(file.Base())
+		if inside, _ := file.IsInsideDir(b.buildPath); inside {
+			if r, err := file.RelFrom(b.buildPath); err == nil {
+				rel = r
+			}
+		}
+		dest := destDir.JoinPath(rel)
+		if err := dest.Parent().MkdirAll(); err != nil {
+			return "", err
+		}
+		if err := file.CopyTo(dest); err != nil {
+			return "", err
+		}
+		return filepath.ToSlash(rel.String()), nil
+	}
+
+	manifest := &objectsManifest{Objects: []objectsEntry{}}
+	objectFiles := paths.NewPathList()
+	objectFiles.AddAll(b.buildArtifacts.sketchObjectFiles)
+	objectFiles.AddAll(b.buildArtifacts.librariesObjectFiles)
+	objectFiles.AddAll(b.buildArtifacts.coreObjectsFiles)
+	for _, objectFile := range objectFiles {
+		object, err := exportFile(objectFile)
+		if err != nil {
+			return err
+		}
+		manifest.Objects = append(manifest.Objects, objectsEntry{
+			Source: sources[objectFile.String()],
+			Object: object,
+		})
+	}
+	if coreArchive := b.buildArtifacts.coreArchiveFilePath; coreArchive != nil && coreArchive.Exist() {
+		archive, err := exportFile(coreArchive)
+		if err != nil {
+			return err
+		}
+		manifest.CoreArchive = archive
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return destDir.Join("manifest.json").WriteFile(data)
+}
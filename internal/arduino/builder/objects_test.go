@@ -0,0 +1,75 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/arduino/arduino-cli/internal/arduino/builder/internal/compilation"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportObjectFiles(t *testing.T) {
+	buildPath, err := paths.MkTempDir("", "build")
+	require.NoError(t, err)
+	defer buildPath.RemoveAll()
+	cachePath, err := paths.MkTempDir("", "cache")
+	require.NoError(t, err)
+	defer cachePath.RemoveAll()
+
+	sketchObject := buildPath.Join("sketch", "Blink.ino.cpp.o")
+	coreObject := buildPath.Join("core", "wiring.c.o")
+	coreArchive := cachePath.Join("core_arduino_avr_uno_0123.a")
+	for _, f := range []*paths.Path{sketchObject, coreObject, coreArchive} {
+		require.NoError(t, f.Parent().MkdirAll())
+		require.NoError(t, f.WriteFile([]byte("object")))
+	}
+
+	sketchSource := buildPath.Join("sketch", "Blink.ino.cpp")
+	b := &Builder{
+		buildPath:           buildPath,
+		compilationDatabase: compilation.NewDatabase(buildPath.Join("compile_commands.json")),
+		buildArtifacts: &buildArtifacts{
+			sketchObjectFiles:   paths.NewPathList(sketchObject.String()),
+			coreObjectsFiles:    paths.NewPathList(coreObject.String()),
+			coreArchiveFilePath: coreArchive,
+		},
+	}
+	b.compilationDatabase.Contents = append(b.compilationDatabase.Contents, compilation.Command{
+		Arguments: []string{"g++", "-c", sketchSource.String(), "-o", sketchObject.String()},
+		File:      sketchSource.String(),
+	})
+
+	destDir := buildPath.Join("objects")
+	require.NoError(t, b.ExportObjectFiles(destDir))
+	require.FileExists(t, destDir.Join("sketch", "Blink.ino.cpp.o").String())
+	require.FileExists(t, destDir.Join("core", "wiring.c.o").String())
+	require.FileExists(t, destDir.Join("core_arduino_avr_uno_0123.a").String())
+
+	data, err := destDir.Join("manifest.json").ReadFile()
+	require.NoError(t, err)
+	var manifest objectsManifest
+	require.NoError(t, json.Unmarshal(data, &manifest))
+	require.Equal(t, objectsManifest{
+		CoreArchive: "core_arduino_avr_uno_0123.a",
+		Objects: []objectsEntry{
+			{Source: sketchSource.String(), Object: "sketch/Blink.ino.cpp.o"},
+			{Object: "core/wiring.c.o"},
+		},
+	}, manifest)
+}
@@ -0,0 +1,87 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// objcopyOutputFormats maps the output formats that can be derived from the
+// .elf file with objcopy to the corresponding objcopy target.
+var objcopyOutputFormats = map[string]string{
+	"hex":  "ihex",
+	"bin":  "binary",
+	"srec": "srec",
+}
+
+var validOutputFormat = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// GenerateOutputFormat makes sure that the artifact with the given format,
+// that is the file extension (for example "bin"), is produced by the build.
+// The artifact is generated by:
+//   - the recipe.output.FORMAT.pattern recipe, if defined;
+//   - the objcopy or hook recipes of the platform, if they already produced it;
+//   - the objcopy tool of the platform, for the formats it can convert to.
+//
+// An error is returned if the platform can't produce the requested format.
+func (b *Builder) GenerateOutputFormat(format string) error {
+	format = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(format)), ".")
+	if !validOutputFormat.MatchString(format) {
+		return fmt.Errorf(tr("invalid output format '%s'"), format)
+	}
+	baseName, err := b.ArtifactBaseName()
+	if err != nil {
+		return err
+	}
+	artifact := b.buildPath.Join(baseName + "." + format)
+
+	recipe := "recipe.output." + format + ".pattern"
+	buildProperties := b.buildProperties.Clone()
+	if buildProperties.Get(recipe) == "" {
+		// Already produced by the platform recipes during this build?
+		elf := b.ElfArtifactPath(artifact)
+		if b.buildProperties.Get("recipe.objcopy."+format+".pattern") != "" {
+			return nil
+		}
+		if artifactInfo, err := artifact.Stat(); err == nil {
+			if elfInfo, err := elf.Stat(); err != nil || !artifactInfo.ModTime().Before(elfInfo.ModTime()) {
+				return nil
+			}
+		}
+
+		target, ok := objcopyOutputFormats[format]
+		objcopy := strings.TrimSuffix(b.buildProperties.Get("compiler.objcopy.cmd"), ".exe")
+		if !ok || !strings.HasSuffix(objcopy, "objcopy") {
+			return fmt.Errorf(tr("the platform can't produce the '%s' output format"), format)
+		}
+		buildProperties.Set(recipe,
+			`"{compiler.path}{compiler.objcopy.cmd}" -O `+target+` "{build.path}/{build.project_name}.elf" "{build.path}/{build.project_name}.`+format+`"`)
+	}
+
+	command, err := b.prepareCommandForRecipe(buildProperties, recipe, false)
+	if err != nil {
+		return err
+	}
+	if err := b.execCommand(command); err != nil {
+		return fmt.Errorf(tr("generating the '%[1]s' output format: %[2]w"), format, err)
+	}
+	if !artifact.Exist() {
+		return fmt.Errorf(tr("the recipe %[1]s didn't produce %[2]s"), recipe, artifact)
+	}
+	return nil
+}
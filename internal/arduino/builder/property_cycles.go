@@ -0,0 +1,78 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"regexp"
+
+	"github.com/arduino/go-properties-orderedmap"
+)
+
+// propertyReferenceRegexp matches the references to other properties, in the
+// form {key}, in the value of a build property
+var propertyReferenceRegexp = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// findPropertyCycle looks for build properties that reference themselves,
+// directly or through other properties, that can't be expanded. The chain of
+// keys of the first cycle found is returned, for example ["a", "b", "a"], or
+// nil if there are no cycles. Only the references to defined properties are
+// followed.
+func findPropertyCycle(props *properties.Map) []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[string]int{}
+	stack := []string{}
+
+	var visit func(key string) []string
+	visit = func(key string) []string {
+		state[key] = visiting
+		stack = append(stack, key)
+		for _, match := range propertyReferenceRegexp.FindAllStringSubmatch(props.Get(key), -1) {
+			ref := match[1]
+			if !props.ContainsKey(ref) {
+				continue
+			}
+			switch state[ref] {
+			case visiting:
+				// Cut the stack from the first occurrence of the key
+				for i, k := range stack {
+					if k == ref {
+						return append(append([]string{}, stack[i:]...), ref)
+					}
+				}
+			case unvisited:
+				if cycle := visit(ref); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[key] = visited
+		return nil
+	}
+
+	for _, key := range props.Keys() {
+		if state[key] == unvisited {
+			if cycle := visit(key); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindPropertyCycle(t *testing.T) {
+	props := properties.NewMap()
+	props.Set("compiler.path", "{runtime.tools.avr-gcc.path}/bin/")
+	props.Set("compiler.c.cmd", "avr-gcc")
+	props.Set("recipe.c.o.pattern", "\"{compiler.path}{compiler.c.cmd}\" {compiler.c.flags} {includes} \"{source_file}\"")
+	props.Set("compiler.c.flags", "-c -g")
+	require.Nil(t, findPropertyCycle(props))
+
+	// A property referencing itself
+	props.Set("build.extra_flags", "-DFOO {build.extra_flags}")
+	require.Equal(t, []string{"build.extra_flags", "build.extra_flags"}, findPropertyCycle(props))
+
+	// A cycle through other properties
+	props.Remove("build.extra_flags")
+	props.Set("compiler.c.flags", "-c -g {compiler.extra_flags}")
+	props.Set("compiler.extra_flags", "{compiler.c.extra_flags}")
+	props.Set("compiler.c.extra_flags", "-Os {compiler.c.flags}")
+	require.Equal(t,
+		[]string{"compiler.c.flags", "compiler.extra_flags", "compiler.c.extra_flags", "compiler.c.flags"},
+		findPropertyCycle(props))
+}
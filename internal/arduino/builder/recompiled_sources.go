@@ -0,0 +1,73 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"sync"
+
+	"github.com/arduino/arduino-cli/internal/arduino/builder/internal/diagnostics"
+	"github.com/arduino/go-paths-helper"
+)
+
+// recompiledSources keeps track, for each of the compiler diagnostics, of the
+// source file that was being compiled when the diagnostic was emitted.
+type recompiledSources struct {
+	mux     sync.Mutex
+	sources []*paths.Path
+}
+
+// parseCompilerOutput parses the output of the command run to compile source
+// and records source as the origin of the diagnostics found. The diagnostics
+// are notified to the diagnostic callback, if any.
+func (b *Builder) parseCompilerOutput(source *paths.Path, cmdline []string, outputs ...[]byte) {
+	if b.compilerOutputParser == nil {
+		return
+	}
+	// The sources may be compiled concurrently
+	b.recompiledSources.mux.Lock()
+	defer b.recompiledSources.mux.Unlock()
+	for len(b.recompiledSources.sources) < len(b.compilerDiagnostics) {
+		b.recompiledSources.sources = append(b.recompiledSources.sources, nil)
+	}
+	first := len(b.compilerDiagnostics)
+	for _, out := range outputs {
+		b.compilerOutputParser(cmdline, out)
+	}
+	if b.diagnosticCB != nil {
+		for _, diag := range b.compilerDiagnostics[first:] {
+			b.diagnosticCB(diag.ToRPC())
+		}
+	}
+	for len(b.recompiledSources.sources) < len(b.compilerDiagnostics) {
+		b.recompiledSources.sources = append(b.recompiledSources.sources, source)
+	}
+}
+
+// NewCompilerDiagnostics returns the parsed compiler diagnostics emitted while
+// compiling the source files recompiled during this build. The diagnostics
+// are selected by the file being compiled, not by the file they refer to, so
+// the ones in the headers included by a recompiled file are returned too.
+func (b *Builder) NewCompilerDiagnostics() diagnostics.Diagnostics {
+	b.recompiledSources.mux.Lock()
+	defer b.recompiledSources.mux.Unlock()
+	var res diagnostics.Diagnostics
+	for i, diag := range b.compilerDiagnostics {
+		if i < len(b.recompiledSources.sources) && b.recompiledSources.sources[i] != nil {
+			res = append(res, diag)
+		}
+	}
+	return res
+}
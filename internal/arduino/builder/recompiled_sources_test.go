@@ -0,0 +1,79 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/internal/arduino/builder/internal/diagnostics"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCompilerDiagnostics(t *testing.T) {
+	b := &Builder{
+		compilerDiagnostics: diagnostics.Diagnostics{
+			{Severity: diagnostics.SeverityWarning, File: "/home/user/Sketch/Sketch.ino"},
+		},
+	}
+	// The fake parser returns a diagnostic referring to the file in the output
+	b.compilerOutputParser = func(cmdline []string, out []byte) {
+		b.compilerDiagnostics = append(b.compilerDiagnostics, &diagnostics.Diagnostic{Severity: diagnostics.SeverityWarning, File: string(out)})
+	}
+
+	// The diagnostic was not emitted by a compile recorded in this build
+	require.Empty(t, b.NewCompilerDiagnostics())
+
+	b.parseCompilerOutput(paths.New("/tmp/build/sketch/Sketch.ino.cpp"), nil, []byte("/home/user/Sketch/helper.h"), []byte("/home/user/Sketch/Sketch.ino"))
+	res := b.NewCompilerDiagnostics()
+	require.Len(t, res, 2)
+	require.Equal(t, "/home/user/Sketch/helper.h", res[0].File)
+	require.Equal(t, "/home/user/Sketch/Sketch.ino", res[1].File)
+
+	b.parseCompilerOutput(paths.New("/libs/Foo/Foo.cpp"), nil, []byte("/libs/Foo/Foo.h"))
+	require.Len(t, b.NewCompilerDiagnostics(), 3)
+	require.Len(t, b.CompilerDiagnostics(), 4)
+}
+
+func TestDiagnosticCB(t *testing.T) {
+	events := []string{}
+	b := &Builder{
+		commandCB: func(commandArgs []string) { events = append(events, "command "+commandArgs[1]) },
+		diagnosticCB: func(diagnostic *rpc.CompileDiagnostic) {
+			events = append(events, "diagnostic "+diagnostic.GetFile())
+		},
+	}
+	b.compilerOutputParser = func(cmdline []string, out []byte) {
+		b.compilerDiagnostics = append(b.compilerDiagnostics, &diagnostics.Diagnostic{Severity: diagnostics.SeverityWarning, File: string(out)})
+	}
+
+	// The diagnostics are notified when the output of their command is parsed,
+	// before the next commands run and before the build ends
+	first, err := paths.NewProcess(nil, "gcc", "Sketch.ino.cpp")
+	require.NoError(t, err)
+	second, err := paths.NewProcess(nil, "gcc", "Foo.cpp")
+	require.NoError(t, err)
+	b.notifyCommand(first)
+	b.parseCompilerOutput(paths.New("/tmp/build/sketch/Sketch.ino.cpp"), first.GetArgs(), []byte("/home/user/Sketch/Sketch.ino"))
+	b.notifyCommand(second)
+	b.parseCompilerOutput(paths.New("/libs/Foo/Foo.cpp"), second.GetArgs())
+	require.Equal(t, []string{
+		"command Sketch.ino.cpp",
+		"diagnostic /home/user/Sketch/Sketch.ino",
+		"command Foo.cpp",
+	}, events)
+}
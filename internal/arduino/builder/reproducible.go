@@ -0,0 +1,61 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/arduino/go-properties-orderedmap"
+)
+
+// defaultReproducibleFlags are the compiler flags used to strip the absolute
+// build and sketch paths from the debug information of the compiled objects.
+// `-fdebug-prefix-map` is supported by all the GCC versions shipped with the
+// platforms (avr-gcc is 7.3), the platforms using GCC 8 or later may opt in
+// to `-ffile-prefix-map`, that strips the paths from `__FILE__` too, by
+// redefining `compiler.reproducible.flags`.
+const defaultReproducibleFlags = `"-fdebug-prefix-map={build.path}=build" "-fdebug-prefix-map={build.source.path}=sketch"`
+
+// setupReproducibleBuild changes the build properties so that the produced
+// binaries do not depend on the location of the build and of the sketch, nor
+// on the time of the build.
+func setupReproducibleBuild(buildProperties *properties.Map) {
+	if !buildProperties.ContainsKey("compiler.reproducible.flags") {
+		buildProperties.Set("compiler.reproducible.flags", defaultReproducibleFlags)
+	}
+	for _, key := range []string{"compiler.c.flags", "compiler.cpp.flags", "compiler.S.flags"} {
+		if flags, ok := buildProperties.GetOk(key); ok {
+			buildProperties.Set(key, flags+" {compiler.reproducible.flags}")
+		}
+	}
+	setFixedBuildTime(buildProperties)
+}
+
+// setFixedBuildTime replaces the time of the build in the extra.time.*
+// properties with a fixed one, following the SOURCE_DATE_EPOCH convention:
+// https://reproducible-builds.org/specs/source-date-epoch/
+// If SOURCE_DATE_EPOCH is not set the Unix epoch is used.
+func setFixedBuildTime(buildProperties *properties.Map) {
+	epoch := "0"
+	if sourceDateEpoch, err := strconv.ParseInt(os.Getenv("SOURCE_DATE_EPOCH"), 10, 64); err == nil {
+		epoch = strconv.FormatInt(sourceDateEpoch, 10)
+	}
+	buildProperties.Set("extra.time.utc", epoch)
+	buildProperties.Set("extra.time.local", epoch)
+	buildProperties.Set("extra.time.zone", "0")
+	buildProperties.Set("extra.time.dst", "0")
+}
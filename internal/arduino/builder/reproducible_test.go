@@ -0,0 +1,74 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetupReproducibleBuild(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "")
+	props := properties.NewFromHashmap(map[string]string{
+		"build.path":         "/tmp/build",
+		"build.source.path":  "/home/user/Sketch",
+		"compiler.c.flags":   "-c -g",
+		"compiler.cpp.flags": "-c -g -fno-rtti",
+		"extra.time.utc":     "1700000000",
+		"extra.time.local":   "1700003600",
+		"extra.time.zone":    "3600",
+		"extra.time.dst":     "0",
+	})
+	setupReproducibleBuild(props)
+
+	expanded := props.ExpandPropsInString("{compiler.c.flags}")
+	require.Equal(t, `-c -g "-fdebug-prefix-map=/tmp/build=build" "-fdebug-prefix-map=/home/user/Sketch=sketch"`, expanded)
+	require.Contains(t, props.Get("compiler.cpp.flags"), "{compiler.reproducible.flags}")
+	require.False(t, props.ContainsKey("compiler.S.flags"))
+	require.Equal(t, "0", props.Get("extra.time.utc"))
+	require.Equal(t, "0", props.Get("extra.time.local"))
+	require.Equal(t, "0", props.Get("extra.time.zone"))
+
+	t.Setenv("SOURCE_DATE_EPOCH", "1600000000")
+	props = properties.NewFromHashmap(map[string]string{
+		"compiler.c.flags":            "-c",
+		"compiler.reproducible.flags": "-ffile-prefix-map={build.path}=build",
+	})
+	setupReproducibleBuild(props)
+	require.Equal(t, "-c {compiler.reproducible.flags}", props.Get("compiler.c.flags"))
+	require.Equal(t, "-ffile-prefix-map={build.path}=build", props.Get("compiler.reproducible.flags"))
+	require.Equal(t, "1600000000", props.Get("extra.time.utc"))
+}
+
+func TestSetFixedBuildTime(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1600000000")
+	props := properties.NewFromHashmap(map[string]string{
+		"compiler.c.flags": "-c -g",
+		"extra.time.utc":   "1700000000",
+		"extra.time.local": "1700003600",
+		"extra.time.zone":  "3600",
+		"extra.time.dst":   "3600",
+	})
+	setFixedBuildTime(props)
+	require.Equal(t, "1600000000", props.Get("extra.time.utc"))
+	require.Equal(t, "1600000000", props.Get("extra.time.local"))
+	require.Equal(t, "0", props.Get("extra.time.zone"))
+	require.Equal(t, "0", props.Get("extra.time.dst"))
+	// Only the time is changed
+	require.Equal(t, "-c -g", props.Get("compiler.c.flags"))
+}
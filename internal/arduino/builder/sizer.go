@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/arduino/arduino-cli/internal/arduino/builder/internal/utils"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
@@ -72,6 +73,35 @@ func (b *Builder) size() error {
 	return nil
 }
 
+// verifySectionsSize returns an error describing the overflow of the sections
+// exceeding their maximum size. The check is skipped, with a warning, if the
+// sizer of the platform doesn't report any maximum size.
+func (b *Builder) verifySectionsSize(sections ExecutablesFileSections) error {
+	checked := false
+	overflows := []string{}
+	for _, section := range sections {
+		if section.MaxSize <= 0 {
+			continue
+		}
+		checked = true
+		if overflow := section.Size - section.MaxSize; overflow > 0 {
+			overflows = append(overflows, tr("%[1]s section exceeds the maximum of %[2]d bytes by %[3]d bytes", section.Name, section.MaxSize, overflow))
+		}
+	}
+	if !checked {
+		b.warnSizeVerificationSkipped()
+		return nil
+	}
+	if len(overflows) > 0 {
+		return errors.New(strings.Join(overflows, "; "))
+	}
+	return nil
+}
+
+func (b *Builder) warnSizeVerificationSkipped() {
+	b.logger.Warn(tr("The board doesn't declare a maximum program size, size verification skipped."))
+}
+
 func (b *Builder) checkSizeAdvanced() (ExecutablesFileSections, error) {
 	command, err := b.prepareCommandForRecipe(b.buildProperties, "recipe.advanced_size.pattern", false)
 	if err != nil {
@@ -89,7 +119,14 @@ func (b *Builder) checkSizeAdvanced() (ExecutablesFileSections, error) {
 	if err := command.Wait(); err != nil {
 		return nil, errors.New(tr("Error while determining sketch size: %s", err))
 	}
+	return b.parseAdvancedSizerOutput(out.Bytes())
+}
 
+// parseAdvancedSizerOutput reports the output of the advanced sizer of the
+// platform and returns the sections sizes. The build fails if the sizer reports
+// an error or, when the size is verified, if a section exceeds its maximum
+// size even if the sizer reports it only as a warning.
+func (b *Builder) parseAdvancedSizerOutput(out []byte) (ExecutablesFileSections, error) {
 	type AdvancedSizerResponse struct {
 		// Output are the messages displayed in console to the user
 		Output string `json:"output"`
@@ -105,7 +142,7 @@ func (b *Builder) checkSizeAdvanced() (ExecutablesFileSections, error) {
 	}
 
 	var resp AdvancedSizerResponse
-	if err := json.Unmarshal(out.Bytes(), &resp); err != nil {
+	if err := json.Unmarshal(out, &resp); err != nil {
 		return nil, errors.New(tr("Error while determining sketch size: %s", err))
 	}
 
@@ -121,6 +158,9 @@ func (b *Builder) checkSizeAdvanced() (ExecutablesFileSections, error) {
 	default:
 		return executableSectionsSize, fmt.Errorf("invalid '%s' severity from sketch sizer: it must be 'error', 'warning' or 'info'", resp.Severity)
 	}
+	if b.verifySize {
+		return executableSectionsSize, b.verifySectionsSize(executableSectionsSize)
+	}
 	return executableSectionsSize, nil
 }
 
@@ -132,6 +172,9 @@ func (b *Builder) checkSize() (ExecutablesFileSections, error) {
 	maxDataSizeString := properties.Get("upload.maximum_data_size")
 
 	if maxTextSizeString == "" {
+		if b.verifySize {
+			b.warnSizeVerificationSkipped()
+		}
 		return nil, nil
 	}
 
@@ -187,11 +230,13 @@ func (b *Builder) checkSize() (ExecutablesFileSections, error) {
 
 	if textSize > maxTextSize {
 		b.logger.Warn(tr("Sketch too big; see %[1]s for tips on reducing it.", "https://support.arduino.cc/hc/en-us/articles/360013825179"))
+		b.logger.Warn(tr("Program storage space exceeded by %[1]s bytes.", strconv.Itoa(textSize-maxTextSize)))
 		return executableSectionsSize, errors.New(tr("text section exceeds available space in board"))
 	}
 
 	if maxDataSize > 0 && dataSize > maxDataSize {
 		b.logger.Warn(tr("Not enough memory; see %[1]s for tips on reducing your footprint.", "https://support.arduino.cc/hc/en-us/articles/360013825179"))
+		b.logger.Warn(tr("Dynamic memory exceeded by %[1]s bytes.", strconv.Itoa(dataSize-maxDataSize)))
 		return executableSectionsSize, errors.New(tr("data section exceeds available space in board"))
 	}
 
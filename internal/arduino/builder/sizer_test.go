@@ -16,8 +16,11 @@
 package builder
 
 import (
+	"bytes"
+	"io"
 	"testing"
 
+	"github.com/arduino/arduino-cli/internal/arduino/builder/internal/logger"
 	"github.com/stretchr/testify/require"
 )
 
@@ -86,3 +89,42 @@ func TestSizerWithInvalidRegexp(t *testing.T) {
 	_, err := computeSize(`[xx`, []byte(`xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx`))
 	require.Error(t, err)
 }
+
+func TestParseAdvancedSizerOutput(t *testing.T) {
+	overflow := []byte(`{
+		"output": "Sketch uses 300 bytes",
+		"severity": "warning",
+		"sections": [
+			{"name": "text", "size": 300, "max_size": 200},
+			{"name": "data", "size": 30, "max_size": 20}
+		]
+	}`)
+
+	// The sizer reports the overflow only as a warning
+	stderr := &bytes.Buffer{}
+	b := &Builder{logger: logger.New(io.Discard, stderr, false, "")}
+	sections, err := b.parseAdvancedSizerOutput(overflow)
+	require.NoError(t, err)
+	require.Len(t, sections, 2)
+	require.Contains(t, stderr.String(), "Sketch uses 300 bytes")
+
+	// The verification fails the build anyway
+	b.verifySize = true
+	sections, err = b.parseAdvancedSizerOutput(overflow)
+	require.EqualError(t, err, "text section exceeds the maximum of 200 bytes by 100 bytes; data section exceeds the maximum of 20 bytes by 10 bytes")
+	require.Len(t, sections, 2)
+
+	_, err = b.parseAdvancedSizerOutput([]byte(`{"severity": "info", "sections": [{"name": "text", "size": 100, "max_size": 200}]}`))
+	require.NoError(t, err)
+	require.NotContains(t, stderr.String(), "size verification skipped")
+
+	// The sections without a maximum size are skipped
+	_, err = b.parseAdvancedSizerOutput([]byte(`{"severity": "info", "sections": [{"name": "text", "size": 300}]}`))
+	require.NoError(t, err)
+	require.Contains(t, stderr.String(), "size verification skipped")
+
+	// The errors of the sizer fail the build even without verification
+	b.verifySize = false
+	_, err = b.parseAdvancedSizerOutput([]byte(`{"severity": "error", "error": "text section exceeds available space in board"}`))
+	require.EqualError(t, err, "text section exceeds available space in board")
+}
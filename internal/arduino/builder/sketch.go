@@ -77,7 +77,7 @@ func (b *Builder) sketchMergeSources(overrides map[string]string) (int, string,
 		if override, ok := overrides[path.String()]; ok {
 			return override, nil
 		}
-		data, err := f.ReadFile()
+		data, err := b.readSketchSource(f)
 		if err != nil {
 			return "", fmt.Errorf(tr("reading file %[1]s: %[2]s"), f, err)
 		}
@@ -131,7 +131,7 @@ func (b *Builder) sketchCopyAdditionalFiles(buildPath *paths.Path, overrides map
 			sourceBytes = []byte(override)
 		} else {
 			// read the source file
-			s, err := file.ReadFile()
+			s, err := b.readSketchSource(file)
 			if err != nil {
 				return fmt.Errorf("%s: %w", tr("unable to read contents of the source item"), err)
 			}
@@ -0,0 +1,111 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/arduino/go-paths-helper"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// decodeSketchSource converts the content of a sketch source file to UTF-8:
+//   - the UTF-8 BOM is removed;
+//   - the files starting with a UTF-16 BOM are decoded as UTF-16.
+//
+// The other files are left untouched: guessing the encoding of a file that is
+// not valid UTF-8 would silently change the bytes of its string literals, the
+// encoding must be explicitly given with ParseSketchEncoding instead.
+// If the file was converted, the name of the detected encoding is returned to
+// warn the user.
+func decodeSketchSource(data []byte) ([]byte, string) {
+	if bytes.HasPrefix(data, utf8BOM) {
+		return data[len(utf8BOM):], ""
+	}
+	var decoder *encoding.Decoder
+	var encodingName string
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		decoder, encodingName = unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM).NewDecoder(), "UTF-16LE"
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		decoder, encodingName = unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM).NewDecoder(), "UTF-16BE"
+	default:
+		return data, ""
+	}
+	decoded, err := decoder.Bytes(data)
+	if err != nil {
+		// Leave the content untouched and let the compiler report the errors
+		return data, ""
+	}
+	return decoded, encodingName
+}
+
+// ParseSketchEncoding returns the encoding with the given name, for example
+// "utf8", "latin1" or "windows-1252". The names are resolved as the web
+// browsers do, so "latin1" is resolved to Windows-1252, a superset of it.
+// An empty name or "auto" returns nil, meaning that the encoding of each
+// file is detected as done by decodeSketchSource.
+func ParseSketchEncoding(name string) (encoding.Encoding, error) {
+	if name == "" || strings.EqualFold(name, "auto") {
+		return nil, nil
+	}
+	enc, err := htmlindex.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf(tr("unknown sketch encoding '%s'"), name)
+	}
+	return enc, nil
+}
+
+// decodeSketchSourceAs converts the content of a sketch source file from the
+// given encoding to UTF-8, the BOM, if any, is removed.
+func decodeSketchSourceAs(data []byte, enc encoding.Encoding) ([]byte, error) {
+	decoded, err := enc.NewDecoder().Bytes(data)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimPrefix(decoded, utf8BOM), nil
+}
+
+// readSketchSource reads a sketch source file converting it to UTF-8. If the
+// sketch encoding is not set, it's detected and a warning is printed if the
+// file was converted or if it's not valid UTF-8.
+func (b *Builder) readSketchSource(file *paths.Path) ([]byte, error) {
+	data, err := file.ReadFile()
+	if err != nil {
+		return nil, err
+	}
+	if b.sketchEncoding != nil {
+		decoded, err := decodeSketchSourceAs(data, b.sketchEncoding)
+		if err != nil {
+			return nil, fmt.Errorf(tr("decoding %[1]s: %[2]s"), file, err)
+		}
+		return decoded, nil
+	}
+	data, encodingName := decodeSketchSource(data)
+	if encodingName != "" {
+		b.logger.Warn(tr("Warning: the file %[1]s is encoded as %[2]s, it has been converted to UTF-8 to compile it. Please save it with the UTF-8 encoding.", file, encodingName))
+	} else if !utf8.Valid(data) {
+		b.logger.Warn(tr("Warning: the file %[1]s is not UTF-8 encoded, it's compiled as is. Set its encoding with the %[2]s flag to convert it to UTF-8, the bytes of its string literals will change accordingly.", file, "--sketch-encoding"))
+	}
+	return data, nil
+}
@@ -16,11 +16,14 @@
 package builder
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"runtime"
 	"strings"
 	"testing"
 
+	"github.com/arduino/arduino-cli/internal/arduino/builder/internal/logger"
 	"github.com/arduino/arduino-cli/internal/arduino/sketch"
 	"github.com/arduino/go-paths-helper"
 	"github.com/stretchr/testify/require"
@@ -106,3 +109,73 @@ func TestCopyAdditionalFiles(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, info1.ModTime(), info2.ModTime())
 }
+
+func TestMergeSketchSourcesWithBOM(t *testing.T) {
+	sk, err := sketch.New(paths.New("testdata", t.Name()))
+	require.Nil(t, err)
+	require.NotNil(t, sk)
+
+	stderr := &bytes.Buffer{}
+	b := Builder{sketch: sk, logger: logger.New(io.Discard, stderr, false, "")}
+
+	_, source, err := b.sketchMergeSources(nil)
+	require.Nil(t, err)
+	require.NotContains(t, source, "\uFEFF")
+	require.Contains(t, source, "TestMergeSketchSourcesWithBOM.ino\"\nvoid setup() {")
+	require.Contains(t, source, "// Caf\xE9\n")
+	require.Contains(t, stderr.String(), "latin1.ino is not UTF-8 encoded")
+	require.NotContains(t, stderr.String(), "TestMergeSketchSourcesWithBOM.ino")
+}
+
+func TestDecodeSketchSource(t *testing.T) {
+	data, encoding := decodeSketchSource([]byte("void setup() {}"))
+	require.Equal(t, "void setup() {}", string(data))
+	require.Empty(t, encoding)
+
+	data, encoding = decodeSketchSource([]byte("\xEF\xBB\xBFvoid setup() {}"))
+	require.Equal(t, "void setup() {}", string(data))
+	require.Empty(t, encoding)
+
+	// The files that are not valid UTF-8 are left untouched
+	data, encoding = decodeSketchSource([]byte("// Caf\xE9"))
+	require.Equal(t, "// Caf\xE9", string(data))
+	require.Empty(t, encoding)
+
+	data, encoding = decodeSketchSource([]byte("\xFF\xFE/\x00/\x00 \x00\xE9\x00"))
+	require.Equal(t, "// é", string(data))
+	require.Equal(t, "UTF-16LE", encoding)
+}
+
+func TestDecodeSketchSourceAs(t *testing.T) {
+	enc, err := ParseSketchEncoding("")
+	require.NoError(t, err)
+	require.Nil(t, enc)
+	enc, err = ParseSketchEncoding("auto")
+	require.NoError(t, err)
+	require.Nil(t, enc)
+	_, err = ParseSketchEncoding("bogus")
+	require.ErrorContains(t, err, "unknown sketch encoding 'bogus'")
+
+	latin1, err := ParseSketchEncoding("latin1")
+	require.NoError(t, err)
+	data, err := decodeSketchSourceAs([]byte("// Caf\xE9"), latin1)
+	require.NoError(t, err)
+	require.Equal(t, "// Café", string(data))
+
+	// A valid UTF-8 file is transcoded anyway
+	data, err = decodeSketchSourceAs([]byte("// Café"), latin1)
+	require.NoError(t, err)
+	require.Equal(t, "// CafÃ©", string(data))
+
+	utf16, err := ParseSketchEncoding("utf-16le")
+	require.NoError(t, err)
+	data, err = decodeSketchSourceAs([]byte("\xFF\xFE/\x00/\x00 \x00\xE9\x00"), utf16)
+	require.NoError(t, err)
+	require.Equal(t, "// é", string(data))
+
+	utf8, err := ParseSketchEncoding("utf8")
+	require.NoError(t, err)
+	data, err = decodeSketchSourceAs([]byte("\xEF\xBB\xBF// Café"), utf8)
+	require.NoError(t, err)
+	require.Equal(t, "// Café", string(data))
+}
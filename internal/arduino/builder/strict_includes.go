@@ -0,0 +1,96 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"fmt"
+	"strings"
+
+	f "github.com/arduino/arduino-cli/internal/algorithms"
+	"github.com/arduino/arduino-cli/internal/arduino/builder/cpp"
+	"github.com/arduino/arduino-cli/internal/arduino/builder/internal/diagnostics"
+	"github.com/arduino/arduino-cli/internal/arduino/libraries"
+	"github.com/arduino/go-paths-helper"
+)
+
+// strictLibraryIncludes returns the include paths allowed to compile the
+// given library in strict includes mode: the core, the variant, the library
+// itself and the libraries it depends on, following the "depends" field of
+// the library.properties of each of them, since the headers of a dependency
+// may include the ones of its own dependencies.
+func (b *Builder) strictLibraryIncludes(library *libraries.Library, importedLibraries libraries.List) []string {
+	includeFolders := paths.NewPathList()
+	includeFolders.AddIfMissing(b.buildProperties.GetPath("build.core.path"))
+	if variantPath := b.buildProperties.GetPath("build.variant.path"); variantPath != nil {
+		includeFolders.AddIfMissing(variantPath)
+	}
+	includeFolders.AddIfMissing(library.SourceDir)
+
+	visited := map[string]bool{library.Name: true}
+	queue := libraryDependencies(library)
+	for len(queue) > 0 {
+		dependency := queue[0]
+		queue = queue[1:]
+		if visited[dependency] {
+			continue
+		}
+		visited[dependency] = true
+		for _, lib := range importedLibraries {
+			if lib.Name == dependency {
+				includeFolders.AddIfMissing(lib.SourceDir)
+				queue = append(queue, libraryDependencies(lib)...)
+			}
+		}
+	}
+	return f.Map(includeFolders.AsStrings(), cpp.WrapWithHyphenI)
+}
+
+// libraryDependencies returns the names of the libraries listed in the
+// "depends" field of the library.properties, without version constraints.
+func libraryDependencies(library *libraries.Library) []string {
+	if library.Properties == nil {
+		return nil
+	}
+	res := []string{}
+	for _, dep := range strings.Split(library.Properties.Get("depends"), ",") {
+		if i := strings.Index(dep, "("); i != -1 {
+			dep = dep[:i]
+		}
+		if dep = strings.TrimSpace(dep); dep != "" {
+			res = append(res, dep)
+		}
+	}
+	return res
+}
+
+// strictIncludesError returns a descriptive error if the failed compilation
+// of the given library was caused by a missing include, otherwise the
+// original error is returned.
+func (b *Builder) strictIncludesError(library *libraries.Library, err error) error {
+	const notFound = ": No such file or directory"
+	for _, diag := range b.compilerDiagnostics {
+		if diag.Severity != diagnostics.SeverityFatal || diag.File == "" || !strings.HasSuffix(diag.Message, notFound) {
+			continue
+		}
+		if inside, _ := paths.New(diag.File).IsInsideDir(library.InstallDir); !inside {
+			continue
+		}
+		include := strings.TrimSuffix(diag.Message, notFound)
+		return fmt.Errorf(tr("strict includes: %[1]s includes %[2]s (%[3]s:%[4]d), that is not provided by the library, by the core or by the libraries declared in its 'depends' field"),
+			library.Name, include, diag.File, diag.Line)
+	}
+	return err
+}
@@ -0,0 +1,101 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/arduino/arduino-cli/internal/arduino/builder/cpp"
+	"github.com/arduino/arduino-cli/internal/arduino/builder/internal/diagnostics"
+	"github.com/arduino/arduino-cli/internal/arduino/libraries"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrictLibraryIncludes(t *testing.T) {
+	b := &Builder{
+		buildProperties: properties.NewFromHashmap(map[string]string{
+			"build.core.path":    "/core",
+			"build.variant.path": "/variant",
+		}),
+	}
+	libA := &libraries.Library{
+		Name:       "LibA",
+		SourceDir:  paths.New("/libs/LibA/src"),
+		Properties: properties.NewFromHashmap(map[string]string{"depends": "LibB (>=1.0.0), Lib C"}),
+	}
+	libB := &libraries.Library{Name: "LibB", SourceDir: paths.New("/libs/LibB")}
+	libC := &libraries.Library{
+		Name:       "Lib C",
+		SourceDir:  paths.New("/libs/Lib_C/src"),
+		Properties: properties.NewFromHashmap(map[string]string{"depends": "LibD"}),
+	}
+	libD := &libraries.Library{Name: "LibD", SourceDir: paths.New("/libs/LibD/src")}
+	libE := &libraries.Library{Name: "LibE", SourceDir: paths.New("/libs/LibE/src")}
+	imported := libraries.List{libA, libB, libC, libD, libE}
+	includeFlag := func(dir string) string {
+		return cpp.WrapWithHyphenI(paths.New(dir).String())
+	}
+
+	require.Equal(t, []string{"LibB", "Lib C"}, libraryDependencies(libA))
+	require.Empty(t, libraryDependencies(libB))
+
+	require.Equal(t, []string{
+		includeFlag("/core"),
+		includeFlag("/variant"),
+		includeFlag("/libs/LibA/src"),
+		includeFlag("/libs/LibB"),
+		includeFlag("/libs/Lib_C/src"),
+		includeFlag("/libs/LibD/src"),
+	}, b.strictLibraryIncludes(libA, imported))
+	require.Equal(t, []string{
+		includeFlag("/core"),
+		includeFlag("/variant"),
+		includeFlag("/libs/LibE/src"),
+	}, b.strictLibraryIncludes(libE, imported))
+
+	// The dependencies are followed transitively, stopping on cycles
+	libD.Properties = properties.NewFromHashmap(map[string]string{"depends": "LibA"})
+	require.Equal(t, []string{
+		includeFlag("/core"),
+		includeFlag("/variant"),
+		includeFlag("/libs/LibD/src"),
+		includeFlag("/libs/LibA/src"),
+		includeFlag("/libs/LibB"),
+		includeFlag("/libs/Lib_C/src"),
+	}, b.strictLibraryIncludes(libD, imported))
+}
+
+func TestStrictIncludesError(t *testing.T) {
+	lib := &libraries.Library{Name: "LibA", InstallDir: paths.New("/libs/LibA")}
+	compileErr := errors.New("exit status 1")
+
+	b := &Builder{}
+	require.Equal(t, compileErr, b.strictIncludesError(lib, compileErr))
+
+	b.compilerDiagnostics = diagnostics.Diagnostics{
+		{Severity: diagnostics.SeverityError, Message: "expected ';'", File: "/libs/LibA/src/LibA.cpp", Line: 2},
+		{Severity: diagnostics.SeverityFatal, Message: "LibB.h: No such file or directory", File: "/libs/LibA/src/LibA.cpp", Line: 3},
+	}
+	err := b.strictIncludesError(lib, compileErr)
+	require.ErrorContains(t, err, "LibA includes LibB.h (/libs/LibA/src/LibA.cpp:3)")
+
+	// Diagnostics of other libraries are ignored
+	other := &libraries.Library{Name: "LibC", InstallDir: paths.New("/libs/LibC")}
+	require.Equal(t, compileErr, b.strictIncludesError(other, compileErr))
+}
@@ -0,0 +1,5 @@
+﻿void setup() {
+}
+
+void loop() {
+}
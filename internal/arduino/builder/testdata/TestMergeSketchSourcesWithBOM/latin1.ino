@@ -0,0 +1,3 @@
+// Caf
+void helper() {
+}
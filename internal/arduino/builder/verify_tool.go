@@ -0,0 +1,46 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"fmt"
+)
+
+// verifyToolRecipe is the recipe, among the properties of a platform tool,
+// that is run to verify the artifacts of the build
+const verifyToolRecipe = "verify.pattern"
+
+// RunVerifyTool runs the verification recipe of the given platform tool,
+// defined as tools.<tool>.verify.pattern, after the build. The properties of
+// the tool are added to the build properties, so the recipe can refer to
+// the artifacts with {build.path}/{build.project_name}.
+// An error is returned if the tool can't be found or if it fails.
+func (b *Builder) RunVerifyTool(tool string) error {
+	toolProperties := b.buildProperties.SubTree("tools." + tool)
+	if toolProperties.Size() == 0 {
+		return fmt.Errorf(tr("tool %s is not defined by the platform"), tool)
+	}
+	if toolProperties.Get(verifyToolRecipe) == "" {
+		return fmt.Errorf(tr("tool %[1]s doesn't define the %[2]s recipe"), tool, verifyToolRecipe)
+	}
+	properties := b.buildProperties.Clone()
+	properties.Merge(toolProperties)
+	command, err := b.prepareCommandForRecipe(properties, verifyToolRecipe, false)
+	if err != nil {
+		return err
+	}
+	return b.execCommand(command)
+}
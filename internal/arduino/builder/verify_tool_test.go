@@ -0,0 +1,47 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/arduino/arduino-cli/internal/arduino/builder/internal/logger"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunVerifyTool(t *testing.T) {
+	props := properties.NewMap()
+	props.Set("build.path", "/tmp/build")
+	props.Set("build.project_name", "Blink.ino")
+	props.Set("tools.avrdude.upload.pattern", "avrdude -U flash:w:{build.path}/{build.project_name}.hex:i")
+	props.Set("tools.checker.cmd", "this-command-does-not-exist")
+	props.Set("tools.checker.verify.pattern", "{cmd} {build.path}/{build.project_name}.elf")
+	b := &Builder{
+		ctx:             context.Background(),
+		logger:          logger.New(io.Discard, io.Discard, false, ""),
+		buildProperties: props,
+	}
+
+	require.ErrorContains(t, b.RunVerifyTool("missing"), "tool missing is not defined by the platform")
+	require.ErrorContains(t, b.RunVerifyTool("avrdude"), "tool avrdude doesn't define the verify.pattern recipe")
+
+	// The properties of the tool are used to expand the recipe
+	require.Error(t, b.RunVerifyTool("checker"))
+	require.Equal(t, []string{"this-command-does-not-exist", "/tmp/build/Blink.ino.elf"}, b.FailedCommand().Args)
+}
@@ -157,9 +157,7 @@ func (pm *Builder) loadPlatforms(targetPackage *cores.Package, packageDir *paths
 		if targetArchitecture == "tools" {
 			continue
 		}
-		if err := pm.loadPlatform(targetPackage, targetArchitecture, platformPath); err != nil {
-			merr = append(merr, err)
-		}
+		merr = append(merr, pm.loadPlatform(targetPackage, targetArchitecture, platformPath)...)
 	}
 
 	return merr
@@ -167,11 +165,14 @@ func (pm *Builder) loadPlatforms(targetPackage *cores.Package, packageDir *paths
 
 // loadPlatform loads a single platform and all its installed releases given a platformPath.
 // platformPath must be a directory.
-// Returns a gRPC Status error in case of failures.
-func (pm *Builder) loadPlatform(targetPackage *cores.Package, architecture string, platformPath *paths.Path) error {
+// A broken release doesn't prevent loading the other releases of the platform,
+// an error naming the offending platform and file is returned for each one.
+func (pm *Builder) loadPlatform(targetPackage *cores.Package, architecture string, platformPath *paths.Path) []error {
+	platformID := targetPackage.Name + ":" + architecture
+
 	// This is not a platform
 	if platformPath.IsNotDir() {
-		return errors.New(tr("path is not a platform directory: %s", platformPath))
+		return []error{errors.New(tr("path is not a platform directory: %s", platformPath))}
 	}
 
 	// There are two possible platform directory structures:
@@ -180,27 +181,27 @@ func (pm *Builder) loadPlatform(targetPackage *cores.Package, architecture strin
 	// We identify them by checking where is the bords.txt file
 	possibleBoardTxtPath := platformPath.Join("boards.txt")
 	if exist, err := possibleBoardTxtPath.ExistCheck(); err != nil {
-		return fmt.Errorf("%s: %w", tr("looking for boards.txt in %s", possibleBoardTxtPath), err)
+		return []error{fmt.Errorf("%s: %w", tr("looking for boards.txt in %s", possibleBoardTxtPath), err)}
 	} else if exist {
 		// case: ARCHITECTURE/boards.txt
 
 		platformTxtPath := platformPath.Join("platform.txt")
 		platformProperties, err := properties.SafeLoad(platformTxtPath.String())
 		if err != nil {
-			return fmt.Errorf("%s: %w", tr("loading platform.txt"), err)
+			return []error{fmt.Errorf("%s: %w", tr("loading platform %[1]s from %[2]s", platformID, platformTxtPath), err)}
 		}
 
 		versionString := platformProperties.ExpandPropsInString(platformProperties.Get("version"))
 		version, err := semver.Parse(versionString)
 		if err != nil {
-			return &cmderrors.InvalidVersionError{Cause: fmt.Errorf("%s: %s", platformTxtPath, err)}
+			return []error{&cmderrors.InvalidVersionError{Cause: fmt.Errorf("%s: %s", platformTxtPath, err)}}
 		}
 
 		platform := targetPackage.GetOrCreatePlatform(architecture)
 		platform.ManuallyInstalled = true
 		release := platform.GetOrCreateRelease(version)
 		if err := pm.loadPlatformRelease(release, platformPath); err != nil {
-			return fmt.Errorf("%s: %w", tr("loading platform release %s", release), err)
+			return []error{fmt.Errorf("%s: %w", tr("loading platform release %s", release), err)}
 		}
 		pm.log.WithField("platform", release).Infof("Loaded platform")
 
@@ -210,28 +211,33 @@ func (pm *Builder) loadPlatform(targetPackage *cores.Package, architecture strin
 
 		versionDirs, err := platformPath.ReadDir()
 		if err != nil {
-			return fmt.Errorf("%s: %w", tr("reading directory %s", platformPath), err)
+			return []error{fmt.Errorf("%s: %w", tr("reading directory %s", platformPath), err)}
 		}
 		versionDirs.FilterDirs()
 		versionDirs.FilterOutHiddenFiles()
+		var merr []error
 		for _, versionDir := range versionDirs {
 			if exist, err := versionDir.Join("boards.txt").ExistCheck(); err != nil {
-				return fmt.Errorf("%s: %w", tr("opening boards.txt"), err)
+				merr = append(merr, fmt.Errorf("%s: %w", tr("opening boards.txt of platform %[1]s in %[2]s", platformID, versionDir), err))
+				continue
 			} else if !exist {
 				continue
 			}
 
 			version, err := semver.Parse(versionDir.Base())
 			if err != nil {
-				return fmt.Errorf("%s: %w", tr("invalid version directory %s", versionDir), err)
+				merr = append(merr, fmt.Errorf("%s: %w", tr("invalid version directory %s", versionDir), err))
+				continue
 			}
 			platform := targetPackage.GetOrCreatePlatform(architecture)
 			release := platform.GetOrCreateRelease(version)
 			if err := pm.loadPlatformRelease(release, versionDir); err != nil {
-				return fmt.Errorf("%s: %w", tr("loading platform release %s", release), err)
+				merr = append(merr, fmt.Errorf("%s: %w", tr("loading platform release %s", release), err))
+				continue
 			}
 			pm.log.WithField("platform", release).Infof("Loaded platform")
 		}
+		return merr
 	}
 
 	return nil
@@ -297,7 +303,7 @@ func (pm *Builder) loadPlatformRelease(platform *cores.PlatformRelease, path *pa
 			platform.Programmers[programmerID].PlatformRelease = platform
 		}
 	} else {
-		return err
+		return fmt.Errorf(tr("loading %[1]s: %[2]s"), programmersTxtPath, err)
 	}
 
 	if err := pm.loadBoards(platform); err != nil {
@@ -413,14 +419,14 @@ func (pm *Builder) loadBoards(platform *cores.PlatformRelease) error {
 	boardsTxtPath := platform.InstallDir.Join("boards.txt")
 	allBoardsProperties, err := properties.LoadFromPath(boardsTxtPath)
 	if err != nil {
-		return err
+		return fmt.Errorf(tr("loading %[1]s: %[2]s"), boardsTxtPath, err)
 	}
 
 	boardsLocalTxtPath := platform.InstallDir.Join("boards.local.txt")
 	if boardsLocalProperties, err := properties.SafeLoadFromPath(boardsLocalTxtPath); err == nil {
 		allBoardsProperties.Merge(boardsLocalProperties)
 	} else {
-		return err
+		return fmt.Errorf(tr("loading %[1]s: %[2]s"), boardsLocalTxtPath, err)
 	}
 
 	platform.Menus = allBoardsProperties.SubTree("menu")
@@ -407,3 +407,35 @@ menu.upload_method.dfuMethod.upload.tool.default=stm32CubeProg
 	require.NoError(t, err)
 	require.Equal(t, expectedProps.AsMap(), props.AsMap())
 }
+
+func TestLoadHardwareWithBrokenPlatform(t *testing.T) {
+	hardwareDir, err := paths.MkTempDir("", "test_broken_platform")
+	require.NoError(t, err)
+	defer hardwareDir.RemoveAll()
+
+	writePlatform := func(packager, architecture, version, boardsTxt string) *paths.Path {
+		dir := hardwareDir.Join(packager, "hardware", architecture, version)
+		require.NoError(t, dir.MkdirAll())
+		require.NoError(t, dir.Join("platform.txt").WriteFile([]byte("name=Test\nversion="+version+"\n")))
+		require.NoError(t, dir.Join("boards.txt").WriteFile([]byte(boardsTxt)))
+		return dir
+	}
+	writePlatform("good", "avr", "1.0.0", "uno.name=Uno\n")
+	writePlatform("broken", "avr", "1.0.0", "uno.name=Uno\n")
+	brokenDir := writePlatform("broken", "avr", "1.1.0", "uno.name=Uno\nthis line is malformed\n")
+
+	pmb := NewBuilder(hardwareDir, hardwareDir, hardwareDir, hardwareDir, "test")
+	errs := pmb.LoadHardwareFromDirectory(hardwareDir)
+	require.Len(t, errs, 1)
+	require.Contains(t, errs[0].Error(), "broken:avr@1.1.0")
+	require.Contains(t, errs[0].Error(), brokenDir.Join("boards.txt").String())
+	require.Contains(t, errs[0].Error(), "invalid line format")
+
+	pm := pmb.Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+	_, err = pme.FindBoardWithFQBN("good:avr:uno")
+	require.NoError(t, err)
+	// The valid releases of the broken platform are still loaded
+	require.NotNil(t, pme.GetPackages()["broken"].Platforms["avr"].Releases["1.0.0"])
+}
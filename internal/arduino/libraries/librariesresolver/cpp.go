@@ -30,7 +30,8 @@ import (
 
 // Cpp finds libraries made for the C++ language
 type Cpp struct {
-	headers map[string]libraries.List
+	headers           map[string]libraries.List
+	locationsPriority map[libraries.LibraryLocation]int
 }
 
 var tr = i18n.Tr
@@ -50,6 +51,58 @@ func NewCppResolver(allLibs []*libraries.Library, targetPlatform, actualPlatform
 	return resolver
 }
 
+// SetLocationsOrder sets the precedence of the library locations used to choose
+// between libraries that are otherwise equally suitable: libraries installed in
+// a location listed first take precedence over the ones installed in a location
+// listed afterwards. Unmanaged libraries always have the highest precedence.
+func (resolver *Cpp) SetLocationsOrder(order []libraries.LibraryLocation) {
+	resolver.locationsPriority = map[libraries.LibraryLocation]int{}
+	for i, location := range order {
+		// The priority replaces the default location priority, so it must stay
+		// below the bonus given to the architecture-optimized libraries
+		resolver.locationsPriority[location] = len(order) - i
+	}
+}
+
+// ParseLocationsOrder converts a list of location names into a list of
+// LibraryLocation suitable for SetLocationsOrder. Allowed names are the ones
+// accepted by ParseLocation.
+func ParseLocationsOrder(order []string) ([]libraries.LibraryLocation, error) {
+	res := []libraries.LibraryLocation{}
+	seen := map[string]bool{}
+	for _, name := range order {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if seen[name] {
+			return nil, fmt.Errorf(tr("library location %s specified more than once"), name)
+		}
+		seen[name] = true
+		locations, ok := ParseLocation(name)
+		if !ok {
+			return nil, fmt.Errorf(tr("invalid library location: %s"), name)
+		}
+		res = append(res, locations...)
+	}
+	return res, nil
+}
+
+// ParseLocation converts a location name into the LibraryLocations it stands
+// for, the name is case insensitive. Allowed names are: "sketchbook" for user
+// installed libraries, "bundled" for libraries bundled with the IDE and "core"
+// for libraries bundled with the platform. If the name is not valid false is
+// returned.
+func ParseLocation(name string) ([]libraries.LibraryLocation, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "sketchbook":
+		return []libraries.LibraryLocation{libraries.User}, true
+	case "bundled":
+		return []libraries.LibraryLocation{libraries.IDEBuiltIn}, true
+	case "core":
+		return []libraries.LibraryLocation{libraries.PlatformBuiltIn, libraries.ReferencedPlatformBuiltIn}, true
+	default:
+		return nil, false
+	}
+}
+
 // ScanIDEBuiltinLibraries reads ide-builtin librariers loaded in the LibrariesManager to find
 // and cache all C++ headers for later retrieval.
 func (resolver *Cpp) ScanIDEBuiltinLibraries(allLibs []*libraries.Library) {
@@ -111,6 +164,9 @@ func (resolver *Cpp) ResolveFor(header, architecture string) *libraries.Library
 	var foundPriority int
 	for _, lib := range resolver.headers[header] {
 		libPriority := ComputePriority(lib, header, architecture)
+		if locPriority, ok := resolver.locationsPriority[lib.Location]; ok {
+			libPriority += locPriority - locationPriority(lib.Location)
+		}
 		msg := "  discarded"
 		if found == nil || foundPriority < libPriority {
 			found = libraries.List{}
@@ -191,22 +247,27 @@ func ComputePriority(lib *libraries.Library, header, arch string) int {
 		priority += 100
 	}
 
-	switch lib.Location {
+	priority += locationPriority(lib.Location)
+	return priority
+}
+
+// locationPriority returns the default priority of the given library location
+func locationPriority(location libraries.LibraryLocation) int {
+	switch location {
 	case libraries.IDEBuiltIn:
-		priority += 0
+		return 0
 	case libraries.ReferencedPlatformBuiltIn:
-		priority++
+		return 1
 	case libraries.PlatformBuiltIn:
-		priority += 2
+		return 2
 	case libraries.User:
-		priority += 3
+		return 3
 	case libraries.Unmanaged:
 		// Bonus for libraries specified via --libraries flags, those libraries gets the highest priority
-		priority += 10000
+		return 10000
 	default:
-		panic(fmt.Sprintf("Invalid library location: %d", lib.Location))
+		panic(fmt.Sprintf("Invalid library location: %d", location))
 	}
-	return priority
 }
 
 func findLibraryWithNameBestDistance(name string, libs libraries.List) *libraries.Library {
@@ -162,3 +162,35 @@ func TestCppHeaderResolverWithLibrariesInStrangeDirectoryNames(t *testing.T) {
 	resolver.headers["OneWire.h"] = librarylist2
 	require.Equal(t, "OneWire", resolver.ResolveFor("OneWire.h", "avr").DirName)
 }
+
+func TestCppHeaderResolverWithLocationsOrder(t *testing.T) {
+	userServo := &libraries.Library{Name: "Servo", Location: libraries.User, Architectures: []string{"avr", "sam", "samd"}}
+	libraryList := libraries.List{}
+	libraryList.Add(bundleServo, userServo)
+	resolver := &Cpp{headers: map[string]libraries.List{"Servo.h": libraryList}}
+
+	// By default the user library wins
+	require.Equal(t, userServo, resolver.ResolveFor("Servo.h", "avr"))
+
+	order, err := ParseLocationsOrder([]string{"bundled", "sketchbook", "core"})
+	require.NoError(t, err)
+	require.Equal(t, []libraries.LibraryLocation{
+		libraries.IDEBuiltIn, libraries.User, libraries.PlatformBuiltIn, libraries.ReferencedPlatformBuiltIn,
+	}, order)
+	resolver.SetLocationsOrder(order)
+	require.Equal(t, bundleServo, resolver.ResolveFor("Servo.h", "avr"))
+
+	// The locations order doesn't override the architecture optimization
+	avrServo := &libraries.Library{Name: "Servo", Location: libraries.User, Architectures: []string{"avr"}}
+	vanillaServo := &libraries.Library{Name: "Servo", Location: libraries.IDEBuiltIn, Architectures: []string{"*"}}
+	libraryList = libraries.List{}
+	libraryList.Add(vanillaServo, avrServo)
+	resolver = &Cpp{headers: map[string]libraries.List{"Servo.h": libraryList}}
+	resolver.SetLocationsOrder(order)
+	require.Equal(t, avrServo, resolver.ResolveFor("Servo.h", "avr"))
+
+	_, err = ParseLocationsOrder([]string{"sketchbook", "invalid"})
+	require.Error(t, err)
+	_, err = ParseLocationsOrder([]string{"sketchbook", "sketchbook"})
+	require.Error(t, err)
+}
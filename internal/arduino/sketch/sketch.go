@@ -20,6 +20,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"sort"
 	"strings"
 
@@ -47,8 +48,42 @@ func New(path *paths.Path) (*Sketch, error) {
 	if path == nil {
 		return nil, fmt.Errorf(tr("sketch path is not valid"))
 	}
+	return load(path.Canonical(), "")
+}
+
+// NewWithoutFollowingSymlinks creates an Sketch instance like New, but the
+// symlinks in the sketch path are kept instead of being resolved.
+func NewWithoutFollowingSymlinks(path *paths.Path) (*Sketch, error) {
+	if path == nil {
+		return nil, fmt.Errorf(tr("sketch path is not valid"))
+	}
+	absPath, err := path.Abs()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", tr("sketch path is not valid"), err)
+	}
+	return load(absPath, "")
+}
+
+// NewWithMainFile creates an Sketch instance like New, but mainFile is used as
+// the main sketch file instead of the one named after the sketch folder.
+// mainFile may be a file name or a path, in both cases the file must be in the
+// sketch folder. If followSymlinks is false the symlinks in the sketch path
+// are kept like in NewWithoutFollowingSymlinks.
+func NewWithMainFile(path *paths.Path, mainFile string, followSymlinks bool) (*Sketch, error) {
+	if path == nil {
+		return nil, fmt.Errorf(tr("sketch path is not valid"))
+	}
+	if followSymlinks {
+		return load(path.Canonical(), mainFile)
+	}
+	absPath, err := path.Abs()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", tr("sketch path is not valid"), err)
+	}
+	return load(absPath, mainFile)
+}
 
-	path = path.Canonical()
+func load(path *paths.Path, mainFileName string) (*Sketch, error) {
 	if exist, err := path.ExistCheck(); err != nil {
 		return nil, fmt.Errorf("%s: %s", tr("sketch path is not valid"), err)
 	} else if !exist {
@@ -59,21 +94,32 @@ func New(path *paths.Path) (*Sketch, error) {
 	}
 
 	var mainFile *paths.Path
-	for ext := range globals.MainFileValidExtensions {
-		candidateSketchMainFile := path.Join(path.Base() + ext)
-		if candidateSketchMainFile.Exist() {
-			if mainFile == nil {
-				mainFile = candidateSketchMainFile
-			} else {
-				return nil, errors.New(tr("multiple main sketch files found (%[1]v, %[2]v)",
-					mainFile,
-					candidateSketchMainFile,
-				))
+	if mainFileName != "" {
+		f, err := findExplicitMainFile(path, mainFileName)
+		if err != nil {
+			return nil, err
+		}
+		mainFile = f
+	} else {
+		for ext := range globals.MainFileValidExtensions {
+			candidateSketchMainFile := path.Join(path.Base() + ext)
+			if candidateSketchMainFile.Exist() {
+				if mainFile == nil {
+					mainFile = candidateSketchMainFile
+				} else {
+					return nil, errors.New(tr("multiple main sketch files found (%[1]v, %[2]v)",
+						mainFile,
+						candidateSketchMainFile,
+					))
+				}
 			}
 		}
 	}
 	if mainFile == nil {
-		return nil, fmt.Errorf(tr("main file missing from sketch: %s", path.Join(path.Base()+globals.MainFileValidExtension)))
+		return nil, &MainFileMissingError{
+			MainFile:   path.Join(path.Base() + globals.MainFileValidExtension),
+			Candidates: findMainFileCandidates(path),
+		}
 	}
 
 	sketch := &Sketch{
@@ -94,12 +140,15 @@ func New(path *paths.Path) (*Sketch, error) {
 		sketch.Project = prj
 	}
 
-	err := sketch.checkSketchCasing()
-	if e, ok := err.(*InvalidSketchFolderNameError); ok {
-		return nil, e
-	}
-	if err != nil {
-		return nil, err
+	// An explicitly selected main file is not required to match the folder name
+	if mainFileName == "" {
+		err := sketch.checkSketchCasing()
+		if e, ok := err.(*InvalidSketchFolderNameError); ok {
+			return nil, e
+		}
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if mainFile == nil {
@@ -155,6 +204,57 @@ func New(path *paths.Path) (*Sketch, error) {
 	return sketch, nil
 }
 
+// findExplicitMainFile returns the main file selected by the user, checking
+// that it's a sketch file placed in the sketch folder.
+func findExplicitMainFile(sketchFolder *paths.Path, mainFileName string) (*paths.Path, error) {
+	mainFile := sketchFolder.Join(filepath.Base(mainFileName))
+	if filepath.Base(mainFileName) != mainFileName {
+		// A path has been given instead of a file name
+		abs, err := paths.New(mainFileName).Abs()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", tr("main file is not valid"), err)
+		}
+		if !abs.Parent().EquivalentTo(sketchFolder) {
+			return nil, errors.New(tr("main file %[1]s is not in the sketch folder %[2]s", mainFileName, sketchFolder))
+		}
+	}
+	if !globals.MainFileValidExtensions[mainFile.Ext()] {
+		return nil, errors.New(tr("main file %[1]s is not a sketch file, the valid extensions are: %[2]s", mainFileName, strings.Join(validMainFileExtensions(), ", ")))
+	}
+	if !mainFile.IsNotDir() {
+		return nil, fmt.Errorf("%s: %s", tr("no such file or directory"), mainFile)
+	}
+	return mainFile, nil
+}
+
+// findMainFileCandidates returns the files in the sketch folder that could be
+// selected as main file.
+func findMainFileCandidates(sketchFolder *paths.Path) paths.PathList {
+	files, err := sketchFolder.ReadDir()
+	if err != nil {
+		return nil
+	}
+	files.FilterOutDirs()
+	files.FilterOutPrefix(".")
+	candidates := paths.PathList{}
+	for _, file := range files {
+		if globals.MainFileValidExtensions[file.Ext()] {
+			candidates.Add(file)
+		}
+	}
+	candidates.Sort()
+	return candidates
+}
+
+func validMainFileExtensions() []string {
+	res := []string{}
+	for ext := range globals.MainFileValidExtensions {
+		res = append(res, ext)
+	}
+	sort.Strings(res)
+	return res
+}
+
 // supportedFiles reads all files recursively contained in Sketch and
 // filter out unneded or unsupported ones and returns them
 func (s *Sketch) supportedFiles() (*paths.PathList, error) {
@@ -278,6 +378,25 @@ func (e *InvalidSketchFolderNameError) Error() string {
 	return tr("no valid sketch found in %[1]s: missing %[2]s", e.SketchFolder, e.SketchFile)
 }
 
+// MainFileMissingError is returned when the sketch folder doesn't contain a
+// main file named after the folder
+type MainFileMissingError struct {
+	MainFile   *paths.Path
+	Candidates paths.PathList
+}
+
+func (e *MainFileMissingError) Error() string {
+	msg := tr("main file missing from sketch: %s", e.MainFile)
+	if len(e.Candidates) == 0 {
+		return msg
+	}
+	names := []string{}
+	for _, candidate := range e.Candidates {
+		names = append(names, candidate.Base())
+	}
+	return msg + "; " + tr("candidate main files found: %s", strings.Join(names, ", "))
+}
+
 // CheckForPdeFiles returns all files ending with .pde extension
 // in sketch, this is mainly used to warn the user that these files
 // must be changed to .ino extension.
@@ -295,6 +414,56 @@ func CheckForPdeFiles(sketch *paths.Path) []*paths.Path {
 	return files
 }
 
+// ExcludeFiles removes the given files from the files compiled with the
+// Sketch. The files may be given as absolute paths or as paths relative to
+// the Sketch folder, they must be part of the Sketch and the main file can't
+// be excluded. The excluded files are returned.
+func (s *Sketch) ExcludeFiles(files ...string) (paths.PathList, error) {
+	excluded := paths.PathList{}
+	for _, file := range files {
+		p := paths.New(file)
+		if p == nil {
+			return nil, errors.New(tr("empty file name"))
+		}
+		if !p.IsAbs() {
+			p = s.FullPath.JoinPath(p)
+		}
+		if p.EquivalentTo(s.MainFile) {
+			return nil, errors.New(tr("the main sketch file %s can't be excluded", file))
+		}
+		if !p.Exist() {
+			return nil, errors.New(tr("the file %s doesn't exist in the sketch folder", file))
+		}
+		found := false
+		for _, sketchFile := range append(s.OtherSketchFiles.Clone(), s.AdditionalFiles...) {
+			if sketchFile.EquivalentTo(p) {
+				excluded.AddIfMissing(sketchFile)
+				found = true
+			}
+		}
+		if !found {
+			return nil, errors.New(tr("the file %s is not compiled with the sketch", file))
+		}
+	}
+	for _, p := range excluded {
+		s.OtherSketchFiles = removePath(s.OtherSketchFiles, p)
+		s.AdditionalFiles = removePath(s.AdditionalFiles, p)
+		s.RootFolderFiles = removePath(s.RootFolderFiles, p)
+	}
+	return excluded, nil
+}
+
+// removePath returns the list without the given path
+func removePath(list paths.PathList, p *paths.Path) paths.PathList {
+	res := paths.PathList{}
+	for _, item := range list {
+		if !item.EqualsTo(p) {
+			res.Add(item)
+		}
+	}
+	return res
+}
+
 // DefaultBuildPath generates the default build directory for a given sketch.
 // The build path is in a temporary directory and is unique for each sketch.
 func (s *Sketch) DefaultBuildPath() *paths.Path {
@@ -112,6 +112,37 @@ func TestNewSketchWrongMain(t *testing.T) {
 	require.Contains(t, err.Error(), expectedError)
 }
 
+func TestNewSketchWithMainFile(t *testing.T) {
+	sketchFolderPath := paths.New("testdata", "SketchWithWrongMain")
+	mainFilePath := sketchFolderPath.Join("main.ino")
+
+	for _, mainFile := range []string{"main.ino", mainFilePath.String()} {
+		sketch, err := NewWithMainFile(sketchFolderPath, mainFile, true)
+		require.NoError(t, err)
+		require.True(t, mainFilePath.EquivalentTo(sketch.MainFile))
+		require.Equal(t, "SketchWithWrongMain", sketch.Name)
+		require.Empty(t, sketch.OtherSketchFiles)
+	}
+
+	sketch, err := NewWithMainFile(sketchFolderPath, "other.ino", true)
+	require.Nil(t, sketch)
+	require.ErrorContains(t, err, "no such file or directory")
+
+	sketch, err = NewWithMainFile(sketchFolderPath, "main.cpp", true)
+	require.Nil(t, sketch)
+	require.ErrorContains(t, err, "is not a sketch file")
+
+	sketch, err = NewWithMainFile(sketchFolderPath, paths.New("testdata", "SketchSimple", "SketchSimple.ino").String(), true)
+	require.Nil(t, sketch)
+	require.ErrorContains(t, err, "is not in the sketch folder")
+
+	// Without an explicit main file the candidates are listed
+	_, err = New(sketchFolderPath)
+	var missingErr *MainFileMissingError
+	require.ErrorAs(t, err, &missingErr)
+	require.Equal(t, "main.ino", missingErr.Candidates[0].Base())
+}
+
 func TestNewSketchCasingWrong(t *testing.T) {
 	{
 		sketchPath := paths.New("testdata", "SketchWithWrongMain")
@@ -121,7 +152,7 @@ func TestNewSketchCasingWrong(t *testing.T) {
 		_, ok := err.(*InvalidSketchFolderNameError)
 		assert.False(t, ok)
 		sketchPath, _ = sketchPath.Abs()
-		expectedError := fmt.Sprintf("main file missing from sketch: %s", sketchPath.Join(sketchPath.Base()+".ino"))
+		expectedError := fmt.Sprintf("main file missing from sketch: %s; candidate main files found: main.ino", sketchPath.Join(sketchPath.Base()+".ino"))
 		assert.EqualError(t, err, expectedError)
 	}
 	{
@@ -132,7 +163,7 @@ func TestNewSketchCasingWrong(t *testing.T) {
 		_, ok := err.(*InvalidSketchFolderNameError)
 		assert.False(t, ok)
 		sketchPath, _ = sketchPath.Parent().Abs()
-		expectedError := fmt.Sprintf("main file missing from sketch: %s", sketchPath.Join(sketchPath.Base()+".ino"))
+		expectedError := fmt.Sprintf("main file missing from sketch: %s; candidate main files found: main.ino", sketchPath.Join(sketchPath.Base()+".ino"))
 		assert.EqualError(t, err, expectedError)
 	}
 	{
@@ -422,3 +453,36 @@ func TestSketchWithMultipleSymlinkLoops(t *testing.T) {
 	require.Error(t, err)
 	require.Nil(t, sketch)
 }
+
+func TestExcludeFiles(t *testing.T) {
+	sketchPath := paths.New(t.TempDir()).Join("Exclude")
+	require.NoError(t, sketchPath.Join("src").MkdirAll())
+	require.NoError(t, sketchPath.Join("Exclude.ino").WriteFile([]byte("void setup() {}\nvoid loop() {}\n")))
+	require.NoError(t, sketchPath.Join("wip.ino").WriteFile([]byte("void wip() {}\n")))
+	require.NoError(t, sketchPath.Join("other.ino").WriteFile([]byte("void other() {}\n")))
+	require.NoError(t, sketchPath.Join("src", "helper.cpp").WriteFile([]byte("void helper() {}\n")))
+	require.NoError(t, sketchPath.Join("README.txt").WriteFile([]byte("readme\n")))
+
+	sketch, err := New(sketchPath)
+	require.NoError(t, err)
+	require.Len(t, sketch.OtherSketchFiles, 2)
+	require.Len(t, sketch.AdditionalFiles, 1)
+
+	excluded, err := sketch.ExcludeFiles("wip.ino", sketchPath.Join("src", "helper.cpp").String(), "wip.ino")
+	require.NoError(t, err)
+	require.Equal(t, paths.PathList{sketchPath.Join("wip.ino"), sketchPath.Join("src", "helper.cpp")}, excluded)
+	require.Equal(t, paths.PathList{sketchPath.Join("other.ino")}, sketch.OtherSketchFiles)
+	require.Empty(t, sketch.AdditionalFiles)
+	require.False(t, sketch.RootFolderFiles.Contains(sketchPath.Join("wip.ino")))
+
+	excluded, err = sketch.ExcludeFiles()
+	require.NoError(t, err)
+	require.Empty(t, excluded)
+
+	_, err = sketch.ExcludeFiles("Exclude.ino")
+	require.ErrorContains(t, err, "the main sketch file Exclude.ino can't be excluded")
+	_, err = sketch.ExcludeFiles("missing.ino")
+	require.ErrorContains(t, err, "the file missing.ino doesn't exist in the sketch folder")
+	_, err = sketch.ExcludeFiles("README.txt")
+	require.ErrorContains(t, err, "the file README.txt is not compiled with the sketch")
+}
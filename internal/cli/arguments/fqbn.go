@@ -64,7 +64,7 @@ func (f *Fqbn) Set(fqbn string) {
 // - the value of the FQBN flag if explicitly specified, otherwise
 // - the default FQBN value in sketch.yaml (`default_fqbn` key) if available, otherwise
 // - it tries to autodetect the board connected to the given port flags
-// If all above methods fails, it returns the empty string.
+// If all above methods fails, it prints an error and terminates the execution.
 // The Port metadata are always returned except if:
 //   - the port is not found, in this case nil is returned
 //   - the FQBN autodetection fail, in this case the function prints an error and
@@ -80,7 +80,7 @@ func CalculateFQBNAndPort(portArgs *Port, fqbnArg *Fqbn, instance *rpc.Instance,
 		}
 		fqbn, port := portArgs.DetectFQBN(instance)
 		if fqbn == "" {
-			feedback.FatalError(&cmderrors.MissingFQBNError{}, feedback.ErrGeneric)
+			feedback.Fatal(tr("Please specify an FQBN. No board found on port %[1]s", portArgs.address), feedback.ErrGeneric)
 		}
 		return fqbn, port
 	}
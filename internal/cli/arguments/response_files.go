@@ -0,0 +1,55 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package arguments
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/arduino/go-paths-helper"
+)
+
+// ExpandResponseFiles replaces each "@file" argument with the arguments read
+// from the given file, one per line, allowing to pass argument lists that
+// exceed the command line length limits. Empty lines and lines starting with
+// "#" are ignored. The arguments read from the file are inserted in place of
+// "@file". A leading "@@" is replaced by a literal "@" to pass arguments
+// starting with "@".
+func ExpandResponseFiles(args []string) ([]string, error) {
+	res := []string{}
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "@@") {
+			res = append(res, arg[1:])
+			continue
+		}
+		if !strings.HasPrefix(arg, "@") || len(arg) == 1 {
+			res = append(res, arg)
+			continue
+		}
+		lines, err := paths.New(arg[1:]).ReadFileAsLines()
+		if err != nil {
+			return nil, fmt.Errorf(tr("reading arguments file %[1]s: %[2]w"), arg[1:], err)
+		}
+		for _, line := range lines {
+			line = strings.TrimRight(line, "\r")
+			if trimmed := strings.TrimSpace(line); trimmed == "" || strings.HasPrefix(trimmed, "#") {
+				continue
+			}
+			res = append(res, line)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,41 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package arguments
+
+import (
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandResponseFiles(t *testing.T) {
+	argsFile, err := paths.WriteToTempFile([]byte("# build flags\r\n--build-property\r\nbuild.extra_flags=-DA=1 -DB=2\r\n\r\n-D\r\nDEBUG\r\n"), nil, "")
+	require.NoError(t, err)
+	defer argsFile.Remove()
+
+	args, err := ExpandResponseFiles([]string{"compile", "-b", "arduino:avr:uno", "@" + argsFile.String(), "@@sketch", "@"})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"compile", "-b", "arduino:avr:uno",
+		"--build-property", "build.extra_flags=-DA=1 -DB=2",
+		"-D", "DEBUG",
+		"@sketch", "@",
+	}, args)
+
+	_, err = ExpandResponseFiles([]string{"@" + argsFile.Parent().Join("not-existing").String()})
+	require.Error(t, err)
+}
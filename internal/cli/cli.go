@@ -21,6 +21,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/arduino/arduino-cli/internal/cli/arguments"
 	"github.com/arduino/arduino-cli/internal/cli/board"
 	"github.com/arduino/arduino-cli/internal/cli/burnbootloader"
 	"github.com/arduino/arduino-cli/internal/cli/cache"
@@ -81,6 +82,18 @@ func NewCommand() *cobra.Command {
 	return arduinoCli
 }
 
+// ExpandResponseFiles replaces the "@file" arguments of the compile command
+// with the arguments read from the files, leaving the arguments of the other
+// commands untouched. The arguments are expanded in place before the flags
+// are parsed, so a flag given both on the command line and in a file takes
+// the value of the one that comes last.
+func ExpandResponseFiles(cmd *cobra.Command, args []string) ([]string, error) {
+	if subCmd, _, err := cmd.Find(args); err != nil || subCmd.Name() != "compile" {
+		return args, nil
+	}
+	return arguments.ExpandResponseFiles(args)
+}
+
 // this is here only for testing
 func createCliCommandTree(cmd *cobra.Command) {
 	cmd.AddCommand(board.NewCommand())
@@ -125,6 +138,7 @@ func createCliCommandTree(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringVar(&configFile, "config-file", "", tr("The custom config file (if not specified the default will be used)."))
 	cmd.PersistentFlags().StringSlice("additional-urls", []string{}, tr("Comma-separated list of additional URLs for the Boards Manager."))
 	cmd.PersistentFlags().Bool("no-color", false, "Disable colored output.")
+	cmd.PersistentFlags().Bool("force-tty", false, tr("Format the output as if printed to a terminal (colors and progress bars) even if it's redirected."))
 	configuration.BindFlags(cmd, configuration.Settings)
 }
 
@@ -147,6 +161,11 @@ func toLogLevel(s string) (t logrus.Level, found bool) {
 func preRun(cmd *cobra.Command, args []string) {
 	configFile := configuration.Settings.ConfigFileUsed()
 
+	// The data directory is required to store the inventory and the platforms
+	if err := configuration.CheckDataDir(configuration.Settings); err != nil {
+		feedback.Fatal(fmt.Sprintf("Error: %v", err), feedback.ErrInitializingInventory)
+	}
+
 	// initialize inventory
 	err := inventory.Init(configuration.DataDir(configuration.Settings).String())
 	if err != nil {
@@ -154,7 +173,10 @@ func preRun(cmd *cobra.Command, args []string) {
 	}
 
 	// https://no-color.org/
-	color.NoColor = configuration.Settings.GetBool("output.no_color") || os.Getenv("NO_COLOR") != ""
+	forceTTY := configuration.Settings.GetBool("output.force_tty")
+	feedback.SetForceTTY(forceTTY)
+	color.NoColor = configuration.Settings.GetBool("output.no_color") || os.Getenv("NO_COLOR") != "" ||
+		(!forceTTY && !feedback.HasConsole())
 
 	// Set default feedback output to colorable
 	feedback.SetOut(colorable.NewColorableStdout())
@@ -0,0 +1,72 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandResponseFiles(t *testing.T) {
+	argsFile, err := paths.WriteToTempFile([]byte("--fqbn\narduino:avr:mega\n-D\nFROM_FILE\n"), nil, "")
+	require.NoError(t, err)
+	defer argsFile.Remove()
+
+	var fqbn string
+	var defines, sketchArgs []string
+	root := &cobra.Command{Use: "arduino-cli"}
+	compileCmd := &cobra.Command{
+		Use:  "compile",
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			sketchArgs = args
+		},
+	}
+	compileCmd.Flags().StringVarP(&fqbn, "fqbn", "b", "", "")
+	compileCmd.Flags().StringArrayVarP(&defines, "define", "D", nil, "")
+	root.AddCommand(compileCmd, &cobra.Command{Use: "upload", Run: func(*cobra.Command, []string) {}})
+
+	run := func(args ...string) {
+		fqbn, defines, sketchArgs = "", nil, nil
+		expanded, err := ExpandResponseFiles(root, args)
+		require.NoError(t, err)
+		root.SetArgs(expanded)
+		require.NoError(t, root.Execute())
+	}
+
+	// The flag that comes last wins, wherever it's given
+	run("compile", "-b", "arduino:avr:uno", "-D", "FROM_CLI", "@"+argsFile.String(), "MySketch")
+	require.Equal(t, "arduino:avr:mega", fqbn)
+	require.Equal(t, []string{"FROM_CLI", "FROM_FILE"}, defines)
+	require.Equal(t, []string{"MySketch"}, sketchArgs)
+
+	run("compile", "@"+argsFile.String(), "-b", "arduino:avr:uno", "-D", "FROM_CLI", "MySketch")
+	require.Equal(t, "arduino:avr:uno", fqbn)
+	require.Equal(t, []string{"FROM_FILE", "FROM_CLI"}, defines)
+	require.Equal(t, []string{"MySketch"}, sketchArgs)
+
+	// The arguments of the other commands are not expanded
+	args := []string{"upload", "@" + argsFile.String()}
+	expanded, err := ExpandResponseFiles(root, args)
+	require.NoError(t, err)
+	require.Equal(t, args, expanded)
+
+	_, err = ExpandResponseFiles(root, []string{"compile", "@" + argsFile.Parent().Join("not-existing").String()})
+	require.Error(t, err)
+}
@@ -0,0 +1,95 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/arduino/arduino-cli/commands/board"
+	"github.com/arduino/arduino-cli/commands/cmderrors"
+	"github.com/arduino/arduino-cli/internal/cli/feedback"
+	"github.com/arduino/arduino-cli/internal/cli/feedback/result"
+	"github.com/arduino/arduino-cli/internal/cli/feedback/table"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/fatih/color"
+)
+
+// runListBoardOptions prints the menus available for the given board, with
+// their valid values, as defined in the platform's boards.txt.
+func runListBoardOptions(inst *rpc.Instance, fqbn string) {
+	if fqbn == "" {
+		feedback.FatalError(&cmderrors.MissingFQBNError{}, feedback.ErrBadArgument)
+	}
+	// Only the first three segments are needed to identify the board, any
+	// already selected option is ignored.
+	if split := strings.Split(fqbn, ":"); len(split) > 3 {
+		fqbn = strings.Join(split[:3], ":")
+	}
+
+	details, err := board.Details(context.Background(), &rpc.BoardDetailsRequest{
+		Instance: inst,
+		Fqbn:     fqbn,
+	})
+	if err != nil {
+		feedback.Fatal(tr("Error getting board details: %v", err), feedback.ErrGeneric)
+	}
+
+	feedback.PrintResult(&boardOptionsResult{
+		Fqbn:    details.GetFqbn(),
+		Options: result.NewConfigOptions(details.GetConfigOptions()),
+	})
+}
+
+type boardOptionsResult struct {
+	Fqbn    string                 `json:"fqbn"`
+	Options []*result.ConfigOption `json:"options"`
+}
+
+func (r *boardOptionsResult) Data() interface{} {
+	return r
+}
+
+func (r *boardOptionsResult) String() string {
+	if len(r.Options) == 0 {
+		return tr("Board %s has no configuration options.", r.Fqbn)
+	}
+
+	titleColor := color.New(color.FgHiGreen)
+	defaultColor := color.New(color.FgGreen)
+
+	t := table.New()
+	t.SetHeader(
+		table.NewCell(tr("Option"), titleColor),
+		table.NewCell(tr("Value"), titleColor),
+		table.NewCell(tr("Description"), titleColor),
+		"")
+	for _, option := range r.Options {
+		t.AddRow(option.Option, "", option.OptionLabel, "")
+		for _, value := range option.Values {
+			if value.Selected {
+				t.AddRow("",
+					table.NewCell(value.Value, defaultColor),
+					table.NewCell(value.ValueLabel, defaultColor),
+					table.NewCell("✔ ("+tr("default")+")", defaultColor))
+			} else {
+				t.AddRow("", value.Value, value.ValueLabel, "")
+			}
+		}
+	}
+	return fmt.Sprintln(tr("FQBN: %s", r.Fqbn)) + t.Render()
+}
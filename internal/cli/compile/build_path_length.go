@@ -0,0 +1,73 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"runtime"
+
+	arduinosketch "github.com/arduino/arduino-cli/internal/arduino/sketch"
+	"github.com/arduino/arduino-cli/internal/cli/feedback"
+	"github.com/arduino/go-paths-helper"
+)
+
+// defaultMaxBuildPathLength returns the default of the --max-build-path-length
+// flag. The files created by the build may add about 140 characters to the
+// build path, so a longer build path risks to exceed the 260 characters
+// allowed on Windows. On the other systems the check is disabled.
+func defaultMaxBuildPathLength() int {
+	if runtime.GOOS == "windows" {
+		return 120
+	}
+	return 0
+}
+
+// resolvedBuildPath returns the build path given with the --build-path flag
+// or, if not set, the default build path of the sketch.
+func resolvedBuildPath(buildPath string, sketchPath *paths.Path) *paths.Path {
+	if buildPath != "" {
+		if abs, err := paths.New(buildPath).Abs(); err == nil {
+			return abs
+		}
+		return paths.New(buildPath)
+	}
+	sk := &arduinosketch.Sketch{FullPath: sketchPath}
+	return sk.DefaultBuildPath()
+}
+
+// buildPathLengthWarning returns the message explaining that the build path
+// is longer than maxLength characters, or an empty string if it's not or if
+// maxLength is 0.
+func buildPathLengthWarning(buildPath *paths.Path, maxLength int) string {
+	length := len(buildPath.String())
+	if maxLength <= 0 || length <= maxLength {
+		return ""
+	}
+	return tr("The build path %[1]s is %[2]d characters long, more than %[3]d: the paths of the files created by the build may exceed the maximum length allowed by the system and make the build fail. Use %[4]s to build in a shorter path.",
+		buildPath, length, maxLength, "--build-path")
+}
+
+// checkBuildPathLength warns the user, or fails in strict mode, if the build
+// path is longer than maxLength characters.
+func checkBuildPathLength(buildPath *paths.Path, maxLength int) {
+	msg := buildPathLengthWarning(buildPath, maxLength)
+	if msg == "" {
+		return
+	}
+	if strict {
+		feedback.Fatal(msg, feedback.ErrBadArgument)
+	}
+	feedback.Warning(msg)
+}
@@ -0,0 +1,45 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildPathLengthWarning(t *testing.T) {
+	buildPath := paths.New("/tmp/" + strings.Repeat("a", 95))
+	require.Empty(t, buildPathLengthWarning(buildPath, 100))
+	require.Empty(t, buildPathLengthWarning(buildPath, 0))
+	require.Equal(t,
+		"The build path "+buildPath.String()+" is 100 characters long, more than 99: the paths of the files created by the build may exceed the maximum length allowed by the system and make the build fail. Use --build-path to build in a shorter path.",
+		buildPathLengthWarning(buildPath, 99))
+}
+
+func TestResolvedBuildPath(t *testing.T) {
+	sketchPath := paths.New("/sketches/Blink")
+	// The default build path is named after the hash of the sketch path
+	defaultBuildPath := resolvedBuildPath("", sketchPath)
+	require.Equal(t, paths.TempDir().Join("arduino", "sketches"), defaultBuildPath.Parent())
+	require.Len(t, defaultBuildPath.Base(), 32)
+
+	abs, err := paths.New("build").Abs()
+	require.NoError(t, err)
+	require.Equal(t, abs, resolvedBuildPath("build", sketchPath))
+}
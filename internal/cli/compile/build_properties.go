@@ -0,0 +1,60 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"fmt"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+)
+
+// layerBuildProperties composes the custom build properties from, in order
+// of increasing precedence:
+//   - the base properties file;
+//   - the overlay properties files, in the order they are given;
+//   - the inline properties given on the command line.
+//
+// Each layer overrides the properties set by the previous ones. The files
+// use the same format of platform.txt, so the OS specific properties (like
+// "key.linux") are applied. The base file may be nil.
+func layerBuildProperties(base *paths.Path, overlays paths.PathList, inline []string) ([]string, error) {
+	files := paths.PathList{}
+	if base != nil {
+		files.Add(base)
+	}
+	files.AddAll(overlays)
+	if len(files) == 0 {
+		return inline, nil
+	}
+
+	layered := properties.NewMap()
+	for _, file := range files {
+		props, err := properties.LoadFromPath(file)
+		if err != nil {
+			return nil, fmt.Errorf(tr("loading build properties from %[1]s: %[2]s"), file, err)
+		}
+		layered.Merge(props)
+	}
+
+	// The inline properties are appended after the ones from the files,
+	// since the last value given for a property wins
+	res := []string{}
+	for _, key := range layered.Keys() {
+		res = append(res, key+"="+layered.Get(key))
+	}
+	return append(res, inline...), nil
+}
@@ -0,0 +1,65 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLayerBuildProperties(t *testing.T) {
+	dir := paths.New(t.TempDir())
+	base := dir.Join("base.txt")
+	require.NoError(t, base.WriteFile([]byte("# base\nbuild.extra_flags=-DBASE\ncompiler.c.extra_flags=-Os\nbuild.custom=base\n")))
+	env := dir.Join("env.txt")
+	require.NoError(t, env.WriteFile([]byte("build.extra_flags=-DENV\nbuild.env=env\n")))
+	local := dir.Join("local.txt")
+	require.NoError(t, local.WriteFile([]byte("build.env=local\n")))
+
+	// No files, the inline properties are left untouched
+	res, err := layerBuildProperties(nil, nil, []string{"a=1", "a=2"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a=1", "a=2"}, res)
+
+	res, err = layerBuildProperties(base, paths.NewPathList(env.String(), local.String()), []string{"build.custom=inline"})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"compiler.c.extra_flags=-Os",
+		"build.custom=base",
+		"build.extra_flags=-DENV",
+		"build.env=local",
+		"build.custom=inline",
+	}, res)
+
+	// The last value wins, as done by the builder
+	props, err := properties.LoadFromSlice(res)
+	require.NoError(t, err)
+	require.Equal(t, "-DENV", props.Get("build.extra_flags"))
+	require.Equal(t, "-Os", props.Get("compiler.c.extra_flags"))
+	require.Equal(t, "local", props.Get("build.env"))
+	require.Equal(t, "inline", props.Get("build.custom"))
+
+	// The overlays can be used without a base file
+	res, err = layerBuildProperties(nil, paths.NewPathList(local.String(), env.String()), nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"build.extra_flags=-DENV", "build.env=env"}, res)
+
+	_, err = layerBuildProperties(dir.Join("missing.txt"), nil, nil)
+	require.ErrorContains(t, err, "loading build properties from "+dir.Join("missing.txt").String())
+}
@@ -0,0 +1,72 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+)
+
+// checkIncludeSketchName is the name of the sketch synthesized by --check-include
+const checkIncludeSketchName = "CheckInclude"
+
+// createCheckIncludeSketch creates, in a temporary folder, a minimal sketch
+// that includes only the given header. Compiling it checks that the header is
+// self-contained.
+func createCheckIncludeSketch(header string) (*paths.Path, error) {
+	header = strings.TrimSpace(header)
+	header = strings.TrimSuffix(strings.TrimPrefix(header, "<"), ">")
+	if header == "" || strings.ContainsAny(header, "<>\"\n") {
+		return nil, errors.New(tr("invalid header name: %s", header))
+	}
+	tmp, err := paths.MkTempDir("", "arduino-check-include-")
+	if err != nil {
+		return nil, err
+	}
+	sketchPath := tmp.Join(checkIncludeSketchName)
+	if err := sketchPath.MkdirAll(); err != nil {
+		tmp.RemoveAll()
+		return nil, err
+	}
+	source := fmt.Sprintf("#include <%s>\n\nvoid setup() {}\n\nvoid loop() {}\n", header)
+	if err := sketchPath.Join(checkIncludeSketchName + ".ino").WriteFile([]byte(source)); err != nil {
+		tmp.RemoveAll()
+		return nil, err
+	}
+	return sketchPath, nil
+}
+
+// checkIncludeFailure describes the first error found in the diagnostics of
+// the build of a sketch synthesized by --check-include: a missing include or
+// an undeclared symbol, together with its location. It returns nil if the
+// diagnostics contain no errors.
+func checkIncludeFailure(header string, diagnostics []*rpc.CompileDiagnostic) error {
+	for _, d := range diagnostics {
+		if d.GetSeverity() != "ERROR" && d.GetSeverity() != "FATAL" {
+			continue
+		}
+		location := paths.New(d.GetFile()).Base()
+		if d.GetLine() > 0 {
+			location = fmt.Sprintf("%s:%d", location, d.GetLine())
+		}
+		return errors.New(tr("header %[1]s is not self-contained, %[2]s: %[3]s", header, location, d.GetMessage()))
+	}
+	return nil
+}
@@ -22,13 +22,19 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/arduino/arduino-cli/commands/cmderrors"
 	"github.com/arduino/arduino-cli/commands/compile"
 	"github.com/arduino/arduino-cli/commands/core"
 	"github.com/arduino/arduino-cli/commands/sketch"
 	"github.com/arduino/arduino-cli/commands/upload"
+	arduinosketch "github.com/arduino/arduino-cli/internal/arduino/sketch"
 	"github.com/arduino/arduino-cli/internal/cli/arguments"
 	"github.com/arduino/arduino-cli/internal/cli/configuration"
 	"github.com/arduino/arduino-cli/internal/cli/feedback"
@@ -51,11 +57,16 @@ var (
 	preprocess              bool                     // Print preprocessed code to stdout.
 	buildCachePath          string                   // Builds of 'core.a' are saved into this path to be cached and reused.
 	buildPath               string                   // Path where to save compiled files.
-	buildProperties         []string                 // List of custom build properties separated by commas. Or can be used multiple times for multiple properties.
+	buildProperties         []string                 // Can be used multiple times for multiple properties.
+	legacyBuildProperties   []string                 // List of custom build properties separated by commas, a comma may be escaped with a backslash.
+	buildPropertiesFile     string                   // File with the base custom build properties, overridden by the overlays and the inline properties.
+	buildPropertiesOverlays []string                 // Files with custom build properties layered over the base file, in order.
 	keysKeychain            string                   // The path of the dir where to search for the custom keys to sign and encrypt a binary. Used only by the platforms that supports it
 	signKey                 string                   // The name of the custom signing key to use to sign a binary during the compile process. Used only by the platforms that supports it
 	encryptKey              string                   // The name of the custom encryption key to use to encrypt a binary during the compile process. Used only by the platforms that supports it
 	warnings                string                   // Used to tell gcc which warning level to use.
+	maxWarnings             int                      // Fail the build if the compiler emits more warnings than this, disabled if negative.
+	verifySize              bool                     // Fail the build if the program or data size exceeds the board maximums.
 	verbose                 bool                     // Turns on verbose mode.
 	quiet                   bool                     // Suppresses almost every output.
 	uploadAfterCompile      bool                     // Upload the binary after the compilation.
@@ -66,14 +77,86 @@ var (
 	programmer              arguments.Programmer     // Use the specified programmer to upload
 	clean                   bool                     // Cleanup the build folder and do not use any cached build
 	compilationDatabaseOnly bool                     // Only create compilation database without actually compiling
+	compilationDatabasePath string                   // Path of the compilation database file
+	validateCompilationDb   bool                     // Check that the files and directories referenced by the compilation database exist
 	sourceOverrides         string                   // Path to a .json file that contains a set of replacements of the sketch source code.
 	dumpProfile             bool                     // Create and print a profile configuration from the build
+	showInfo                bool                     // Print the sketch compile requirements instead of compiling.
+	listBoardOptions        bool                     // Print the menus available for the board instead of compiling.
+	reproducible            bool                     // Strip absolute paths and timestamps from the build output.
+	noBuildTime             bool                     // Use a fixed time instead of the time of the build in the extra.time.* properties.
+	libraryPathOrder        []string                 // Precedence of the libraries locations used to choose between duplicated libraries.
+	allowedLibrarySources   []string                 // Sources the libraries used by the build are allowed to come from.
+	extraFlags              []string                 // Extra flags to append to the compiler command line, optionally prefixed by a scope.
+	defines                 []string                 // Preprocessor macros to define, in the form NAME or NAME=VALUE.
+	listOutputs             bool                     // Print the list of the files produced by the build.
+	listLibraries           bool                     // Print the location of the libraries used by the build.
+	checkInclude            string                   // Compile a minimal sketch including only the given header.
+	keepObjects             bool                     // Copy the object files in the output directory.
+	exportPreprocessed      bool                     // Copy the Arduino-preprocessed sketch in the output directory.
+	noOverwrite             bool                     // Fail if an exported artifact would overwrite an existing file.
+	backup                  bool                     // Rename the existing files overwritten by the exported artifacts.
+	noFollowSymlinks        bool                     // Keep the symlinks in the sketch and build paths.
+	reportFile              string                   // Path of the file where the complete build report is written.
+	warningsFile            string                   // Path of the file where the compiler warnings are written.
+	onlyNewDiagnostics      bool                     // Report only the diagnostics of the files recompiled by this build.
+	copyTo                  []string                 // Directories where the final artifacts are copied.
+	archive                 string                   // Path of the zip archive where the artifacts are collected.
+	outputSketchName        string                   // Name used for the artifacts in place of the sketch name.
+	verifyTool              string                   // Platform tool whose verify recipe is run after the build.
+	exportDepGraph          string                   // Path of the file where the libraries dependency graph is written.
+	compilerPath            string                   // Directory of the compiler executables to use instead of the platform toolchain.
+	builderPath             string                   // Path of an external arduino-builder binary running the build, for debugging.
+	ideVersion              string                   // IDE version to build for, sets runtime.ide.version and the ARDUINO macro.
+	fileFlags               []string                 // Extra compiler flags of single source files, as path=flags.
+	sketchEncoding          string                   // Encoding of the sketch source files, detected if empty.
+	compareTo               string                   // Reference artifact compared byte by byte with the one produced by the build.
+	saveContext             string                   // File where the compile request is saved to replay the build.
+	loadContext             string                   // File containing a compile request saved with --save-context to replay.
+	excludeFiles            []string                 // Sketch files that are not compiled.
+	cppStd                  string                   // The C++ standard to compile with.
+	cStd                    string                   // The C standard to compile with.
+	analyzeMap              bool                     // Print a summary of the largest sections and symbols found in the linker map file.
+	summary                 bool                     // Print a summary of the compile commands run by the build.
+	fqbnSeparator           string                   // Separator used in place of ':' in the name of the default export directory.
+	verboseOnFailure        bool                     // Report the failed command and its full output if the build fails.
+	outputFormats           []string                 // Output formats that must be produced by the build.
+	progressStreamTarget    string                   // Where the progress events are emitted as newline-delimited JSON: stderr or a file path.
+	toolOverrides           []string                 // Tools to use in place of the ones selected by the platform, as [packager:]name@version.
+	strictFqbn              bool                     // Fail immediately if the FQBN doesn't name exactly an installed board.
+	configInline            string                   // Build specification given as a JSON or YAML document.
+	writeChecksums          bool                     // Write the SHA-256 checksum of each produced file in a .sha256 sidecar file.
+	md5Checksums            bool                     // Compute also the MD5 checksum of each produced file.
+	lto                     bool                     // Enable the link time optimization.
+	checkFile               string                   // Compile only the given sketch file to report its diagnostics.
+	noLTO                   bool                     // Disable the link time optimization.
+	mainFile                string                   // The main sketch file to use when it's not named after the sketch folder.
+	exportDeps              bool                     // Merge the dependency files generated by the compiler in a manifest in the output directory.
+	boardDefine             string                   // Override the build.board property used to compose the ARDUINO_<board> define.
+	bootloader              string                   // The bootloader to build for, among the options of the bootloader menu of the board.
+	strict                  bool                     // Fail instead of warning when the board differs from the sketch default one or the build path is too long.
+	maxBuildPathLength      int                      // Warn if the build path is longer than this number of characters, 0 disables the check.
+	printCacheKey           bool                     // Print the core cache key instead of compiling.
+	explainProperty         string                   // Build property to explain instead of compiling.
+	explainExitCodes        bool                     // Print the exit codes of the command and their meaning.
+	showLinkCommand         bool                     // Print the fully expanded command used to link the sketch.
+	trace                   bool                     // Print the paths resolved for the build before running it.
+	idePreferences          string                   // IDE preferences file used to find the libraries bundled with the IDE, or "none".
+	dumpIncludePaths        bool                     // Print the include paths used by the build instead of compiling.
+	dumpDefines             bool                     // Print the preprocessor macros passed to the compiler instead of compiling.
+	matrix                  []string                 // List of FQBNs to check the sketch compatibility with.
+	matrixFile              string                   // Path to a file containing the list of FQBNs to check the sketch compatibility with.
+	watch                   bool                     // Recompile the sketch every time one of its files changes.
 	// library and libraries sound similar but they're actually different.
 	// library expects a path to the root folder of one single library.
 	// libraries expects a path to a directory containing multiple libraries, similarly to the <directories.user>/libraries path.
 	library                []string // List of paths to libraries root folders. Can be used multiple times for different libraries
 	libraries              []string // List of custom libraries dir paths separated by commas. Or can be used multiple times for multiple libraries paths.
 	skipLibrariesDiscovery bool
+	onlyExplicitLibraries  bool   // Use only the libraries given with --library and --libraries
+	strictIncludes         bool   // Compile each library with only its own include paths
+	coreFromGit            string // Git URL, in the form url#ref, of the platform to use for the build
+	keepBuildPath          bool   // Keep the build path of a build made with --core-from-git
 	tr                     = i18n.Tr
 )
 
@@ -82,12 +165,16 @@ func NewCommand() *cobra.Command {
 	compileCommand := &cobra.Command{
 		Use:   "compile",
 		Short: tr("Compiles Arduino sketches."),
-		Long:  tr("Compiles Arduino sketches."),
+		Long:  tr("Compiles Arduino sketches. A sketch name that isn't the path of an existing folder is searched in the sketchbook."),
 		Example: "" +
 			"  " + os.Args[0] + " compile -b arduino:avr:uno /home/user/Arduino/MySketch\n" +
 			"  " + os.Args[0] + ` compile -b arduino:avr:uno --build-property "build.extra_flags=\"-DMY_DEFINE=\"hello world\"\"" /home/user/Arduino/MySketch` + "\n" +
 			"  " + os.Args[0] + ` compile -b arduino:avr:uno --build-property "build.extra_flags=-DPIN=2 \"-DMY_DEFINE=\"hello world\"\"" /home/user/Arduino/MySketch` + "\n" +
-			"  " + os.Args[0] + ` compile -b arduino:avr:uno --build-property build.extra_flags=-DPIN=2 --build-property "compiler.cpp.extra_flags=\"-DSSID=\"hello world\"\"" /home/user/Arduino/MySketch` + "\n",
+			"  " + os.Args[0] + ` compile -b arduino:avr:uno --build-property build.extra_flags=-DPIN=2 --build-property "compiler.cpp.extra_flags=\"-DSSID=\"hello world\"\"" /home/user/Arduino/MySketch` + "\n" +
+			"  " + os.Args[0] + ` compile -b arduino:avr:uno --extra-flags -DDEBUG --extra-flags cpp:-fno-exceptions /home/user/Arduino/MySketch` + "\n" +
+			"  " + os.Args[0] + ` compile -b arduino:avr:uno -D DEBUG -D "MY_DEFINE=\"hello world\"" /home/user/Arduino/MySketch` + "\n" +
+			"  " + os.Args[0] + ` compile -b arduino:avr:uno @build-args.txt /home/user/Arduino/MySketch` + "\n" +
+			"  " + os.Args[0] + ` compile -b arduino:avr:uno MySketch` + "\n",
 		Args: cobra.MaximumNArgs(1),
 		Run:  runCompileCommand,
 	}
@@ -96,15 +183,130 @@ func NewCommand() *cobra.Command {
 	profileArg.AddToCommand(compileCommand)
 	compileCommand.Flags().BoolVar(&dumpProfile, "dump-profile", false, tr("Create and print a profile configuration from the build."))
 	showPropertiesArg.AddToCommand(compileCommand)
-	compileCommand.Flags().BoolVar(&preprocess, "preprocess", false, tr("Print preprocessed code to stdout instead of compiling."))
+	compileCommand.Flags().BoolVar(&strict, "strict", false, tr("Fail, instead of printing a warning, if the board differs from the default board of the sketch or if the build path is longer than the value of %s.", "--max-build-path-length"))
+	compileCommand.Flags().IntVar(&maxBuildPathLength, "max-build-path-length", defaultMaxBuildPathLength(),
+		tr("Warn if the build path is longer than the given number of characters, since the paths of the files created by the build may exceed the maximum length allowed on Windows. Set it to 0 to disable the check."))
+	compileCommand.Flags().BoolVar(&dumpIncludePaths, "dump-include-paths", false,
+		tr("Print the include paths used by the build, one per line, instead of compiling. The libraries are resolved but the sources are not compiled."))
+	compileCommand.Flags().BoolVar(&dumpDefines, "dump-defines", false,
+		tr("Print the preprocessor macros passed to the compiler (by the core, the board options and the user flags), sorted, instead of compiling. The sources are not compiled."))
+	compileCommand.Flags().BoolVar(&printCacheKey, "print-cache-key", false, tr("Print the key identifying the cached core used by the build, instead of compiling. The key covers the FQBN with the board options, the platform and the optimization flags, but not the other build properties or the toolchain version: comparing the keys of two builds shows if they share the cached core."))
+	compileCommand.Flags().StringVar(&explainProperty, "explain-property", "",
+		tr("Print the default value of the given build property, the file where it's defined and the value it takes after applying the overrides, instead of compiling."))
+	compileCommand.Flags().BoolVar(&showLinkCommand, "show-link-command", false,
+		tr("Print the fully expanded command used to link the sketch, ready to be copied and run, also if the link fails. The verbose output is not needed."))
+	compileCommand.Flags().BoolVar(&trace, "trace", false,
+		tr("Print each path resolved for the build (sketch, build path, caches, platforms, tools and libraries folders) before running it, to diagnose a wrong setup of the folders."))
+	compileCommand.Flags().BoolVar(&showInfo, "info", false, tr("Show the board, core and libraries required by the sketch instead of compiling."))
+	compileCommand.Flags().BoolVar(&listBoardOptions, "list-board-options", false, tr("Print the configuration options available for the board, with their valid values, instead of compiling."))
+	compileCommand.Flags().BoolVar(&preprocess, "preprocess", false, tr("Print preprocessed code to stdout instead of compiling. With the JSON output format the preprocessed code of each sketch file is returned separately."))
+	compileCommand.Flags().StringArrayVar(&matrix, "matrix", []string{},
+		tr("Compile the sketch for each one of the given FQBNs and print a compatibility matrix. Can be used multiple times."))
+	compileCommand.Flags().StringVar(&matrixFile, "matrix-file", "",
+		tr("Path to a file containing the list of FQBNs, one per line, to compile the sketch for and print a compatibility matrix."))
+	compileCommand.Flags().BoolVar(&watch, "watch", false,
+		tr("After the first build, watch the sketch and the libraries given with --library for changes and recompile them automatically. Press Ctrl-C to exit."))
 	compileCommand.Flags().StringVar(&buildCachePath, "build-cache-path", "", tr("Builds of 'core.a' are saved into this path to be cached and reused."))
 	compileCommand.Flags().StringVarP(&exportDir, "output-dir", "", "", tr("Save build artifacts in this directory."))
+	compileCommand.Flags().BoolVar(&keepObjects, "keep-objects", false,
+		tr("Copy the compiled object files and the core archive in the 'objects' folder of the output directory, with a manifest mapping each source file to its object file."))
+	compileCommand.Flags().BoolVar(&noOverwrite, "no-overwrite", false,
+		tr("Fail if a build artifact exported in the output directory would overwrite an existing file."))
+	compileCommand.Flags().BoolVar(&backup, "backup", false,
+		tr("Rename the existing files overwritten by the build artifacts exported in the output directory, adding the '.bak' suffix."))
+	compileCommand.Flags().StringVar(&cppStd, "cpp-std", "",
+		tr("The C++ standard to compile with, for example c++17 or gnu++20. It overrides the default standard of the board."))
+	compileCommand.Flags().StringVar(&cStd, "c-std", "",
+		tr("The C standard to compile with, for example c11 or gnu17. It overrides the default standard of the board."))
+	compileCommand.Flags().BoolVar(&strictFqbn, "strict-fqbn", false,
+		tr("Fail immediately, before starting the build, if the FQBN doesn't name exactly a board of an installed platform with valid configuration options."))
+	compileCommand.Flags().StringVar(&configInline, "config-inline", "",
+		tr("Build specification given as a JSON or YAML document, with the fields: %s. The flags given on the command line take precedence.", "fqbn, libraries, library, build_properties, extra_flags, defines, warnings, optimize_for_debug, build_path, output_dir, output_formats"))
+	compileCommand.Flags().StringArrayVar(&toolOverrides, "tool", []string{},
+		tr("Use the given installed tool version in place of the one selected by the platform, in the form [packager:]name@version. Can be repeated for multiple tools."))
+	compileCommand.Flags().StringVar(&progressStreamTarget, "progress-stream", "",
+		tr("Emit the progress events of the build (phases, compiled files, warnings, errors and the final result) as newline-delimited JSON to stderr or the given file."))
+	compileCommand.Flags().StringSliceVar(&outputFormats, "output-format", []string{},
+		tr("Comma-separated list of output formats to produce and export in a single build, for example hex,bin,uf2."))
+	compileCommand.Flags().BoolVar(&verboseOnFailure, "verbose-on-failure", false,
+		tr("If the build fails, print the command that failed together with its full output, without making the whole build verbose."))
+	compileCommand.Flags().StringVar(&fqbnSeparator, "fqbn-separator", ".",
+		tr("Separator used in place of ':' when the FQBN is used as the name of the directory where the binaries are exported, for example '_' exports to 'build/arduino_avr_uno'."))
+	compileCommand.Flags().BoolVar(&summary, "summary", false,
+		tr("Print a summary of the compile commands run by the build: their number and the number of source files compiled, grouped by extension."))
+	compileCommand.Flags().BoolVar(&analyzeMap, "analyze-map", false,
+		tr("Print the largest sections and symbols found in the linker map file generated by the build."))
+	compileCommand.Flags().StringVar(&reportFile, "report-file", "",
+		tr("Write a complete report of the build (board, platforms, tools, libraries, sizes, artifacts and warnings) to the given file, in YAML format if the file has a .yaml or .yml extension, in JSON format otherwise."))
+	compileCommand.Flags().StringVar(&warningsFile, "warnings-file", "",
+		tr("Write the warnings emitted by the compiler to the given file, one per line in the form %s. The compiler output is still printed as usual.", "file:line:column: message"))
+	compileCommand.Flags().BoolVar(&onlyNewDiagnostics, "only-new-diagnostics", false,
+		tr("Report only the diagnostics emitted while compiling the source files recompiled by this build, including the ones in the headers they include."))
+	compileCommand.Flags().StringArrayVar(&copyTo, "copy-to", []string{},
+		tr("Copy the final artifacts (.hex, .bin and .elf files) to the given directory, that must exist. Can be used multiple times for multiple destinations."))
+	compileCommand.Flags().StringVar(&archive, "archive", "",
+		tr("Collect the artifacts produced by the build, together with a manifest describing them, in a zip archive at the given path."))
+	compileCommand.Flags().StringVar(&outputSketchName, "sketch-name", "",
+		tr("Name the artifacts after the given name instead of the sketch name, for example %[1]s produces %[2]s.", "firmware", "firmware.ino.hex"))
+	compileCommand.Flags().StringVar(&verifyTool, "verify-tool", "",
+		tr("Run the %[1]s recipe of the given platform tool after the build, the build fails if the tool returns an error.", "tools.<tool>.verify.pattern"))
+	compileCommand.Flags().StringVar(&exportDepGraph, "export-dep-graph", "",
+		tr("Write the graph of the libraries included by the sketch and by each library to the given file, in DOT format if the file has a .dot extension or in JSON format if it has a .json extension."))
+	compileCommand.Flags().StringVar(&compilerPath, "compiler-path", "",
+		tr("Run the compiler executables from the given directory, for example a toolchain installed in the system, instead of using the one installed with the platform. The %s build property is set to this directory.", "compiler.path"))
+	compileCommand.Flags().StringVar(&builderPath, "builder-path", "",
+		tr("Advanced, for debugging only: run the build with the given arduino-builder binary, using the hardware, libraries and build properties resolved by the CLI. The artifacts are not exported."))
+	compileCommand.Flags().StringVar(&ideVersion, "ide-version", "",
+		tr("The IDE version to build for, as a number like 10819 or as a version like 1.8.19. It sets the %[1]s build property and the %[2]s macro, for the libraries that depend on them.", "runtime.ide.version", "ARDUINO"))
+	compileCommand.Flags().StringArrayVar(&fileFlags, "file-flags", []string{},
+		tr("Extra compiler flags for a single source file, in the form path=flags, for example src/driver.cpp=-O0. The path is relative to the sketch folder, use an absolute path for the files of a library. The files of the core can't be targeted. Can be used multiple times for multiple files."))
+	compileCommand.Flags().StringVar(&sketchEncoding, "sketch-encoding", "",
+		tr("The encoding of the sketch source files, for example utf8, latin1 or windows-1252. The files are converted to UTF-8 before being compiled. If not set the UTF-8 BOM is removed and the UTF-16 files are converted, the other files are compiled as they are."))
+	compileCommand.Flags().StringVar(&compareTo, "compare-to", "",
+		tr("Compare byte by byte the artifact produced by the build with the given reference file, the artifact is selected by the extension of the file (for example .hex or .bin). The command fails if they differ. Use it together with --reproducible to detect the changes in the output."))
+	compileCommand.Flags().StringVar(&saveContext, "save-context", "",
+		tr("Save the context of the build, that is the board, the paths and all the other options, together with the hardware and libraries directories and the build properties resolved by the build, to the given JSON file. The file can be attached to a bug report to replay the build with %s.", "--load-context"))
+	compileCommand.Flags().StringVar(&loadContext, "load-context", "",
+		tr("Replay the build saved with %s in the given file. The sketch path and the other options are taken from the file, no other option can be given. A warning is printed if the resolved directories or build properties differ from the saved ones.", "--save-context"))
+	compileCommand.Flags().StringArrayVar(&excludeFiles, "exclude-file", []string{},
+		tr("Don't compile the given sketch file, relative to the sketch folder, for example a work in progress .ino tab. The main sketch file can't be excluded. Can be used multiple times for multiple files."))
+	compileCommand.Flags().BoolVar(&noFollowSymlinks, "no-follow-symlinks", false,
+		tr("Keep the symlinks in the sketch and build paths instead of resolving them to the real paths."))
+	compileCommand.Flags().BoolVar(&exportPreprocessed, "export-preprocessed-sketch", false,
+		tr("Copy the sketch source code produced by the Arduino preprocessing (with the generated function prototypes) in the output directory."))
+	compileCommand.Flags().BoolVar(&listOutputs, "list-outputs", false, tr("Print the list of the files produced by the build in the output directory, with their SHA-256 checksum."))
+	compileCommand.Flags().StringVar(&checkFile, "check-file", "",
+		tr("Compile only the given sketch file, with the full build context, to report its errors and warnings. The core and the libraries are not compiled and nothing is linked."))
+	compileCommand.Flags().StringVar(&mainFile, "main-file", "",
+		tr("The main sketch file to use when the sketch folder contains several .ino files and none of them is named after the folder."))
+	compileCommand.Flags().BoolVar(&exportDeps, "export-deps", false,
+		tr("Generate the dependency files of the compiled sources and merge them in a Makefile-compatible %s file in the output directory, for the use of external build systems.", "dependencies.d"))
+	compileCommand.Flags().StringVar(&boardDefine, "board-define", "",
+		tr("Override the %[1]s property of the board, used by the platform recipes to define the %[2]s macro. The value may be given with or without the %[3]s prefix. If the platform recipes don't derive the define from %[1]s, it's added to the compiler flags.", "build.board", "ARDUINO_<board>", "ARDUINO_"))
+	compileCommand.Flags().StringVar(&bootloader, "bootloader", "",
+		tr("Build for the given bootloader, among the options of the %s menu of the board, using the related build properties (memory size, fuses, etc.).", "bootloader"))
+	compileCommand.Flags().BoolVar(&lto, "lto", false, tr("Enable the link time optimization, adding the %s flags to the compiler and linker flags of the platform.", "-flto"))
+	compileCommand.Flags().BoolVar(&noLTO, "no-lto", false, tr("Disable the link time optimization, removing the %s flags from the compiler and linker flags of the platform.", "-flto"))
+	compileCommand.Flags().BoolVar(&writeChecksums, "write-checksums", false, tr("Write the SHA-256 checksum of each file produced by the build in a sidecar file with the .sha256 suffix, and print the list of the produced files."))
+	compileCommand.Flags().BoolVar(&md5Checksums, "md5", false, tr("Compute also the MD5 checksum of each file produced by the build, reported in the list of the produced files."))
+	compileCommand.Flags().StringVar(&checkInclude, "check-include", "",
+		tr("Compile a minimal sketch including only the given header, to check that it's self-contained. The sketch path must not be given."))
+	compileCommand.Flags().BoolVar(&listLibraries, "list-libraries", false, tr("Print the libraries used by the build together with their location (sketchbook, IDE, platform)."))
 	compileCommand.Flags().StringVar(&buildPath, "build-path", "",
 		tr("Path where to save compiled files. If omitted, a directory will be created in the default temporary path of your OS."))
-	compileCommand.Flags().StringSliceVar(&buildProperties, "build-properties", []string{},
-		tr("List of custom build properties separated by commas. Or can be used multiple times for multiple properties."))
+	compileCommand.Flags().StringSliceVar(&legacyBuildProperties, "build-properties", []string{},
+		tr("List of custom build properties separated by commas, use '\\,' to insert a literal comma. Or can be used multiple times for multiple properties."))
 	compileCommand.Flags().StringArrayVar(&buildProperties, "build-property", []string{},
 		tr("Override a build property with a custom value. Can be used multiple times for multiple properties."))
+	compileCommand.Flags().StringVar(&buildPropertiesFile, "build-properties-file", "",
+		tr("File with the base custom build properties, in the same format of platform.txt. The properties are overridden by the overlay files and by the build properties given on the command line."))
+	compileCommand.Flags().StringArrayVar(&buildPropertiesOverlays, "build-properties-overlay", []string{},
+		tr("File with custom build properties layered over the base properties file, in the same format of platform.txt. Can be used multiple times, each file overrides the previous ones. The build properties given on the command line override all the files."))
+	compileCommand.Flags().StringArrayVarP(&defines, "define", "D", []string{},
+		tr("Define a preprocessor macro, in the form NAME or NAME=VALUE. The definition is appended to the compiler flags. Can be used multiple times for multiple macros."))
+	compileCommand.Flags().StringArrayVar(&extraFlags, "extra-flags", []string{},
+		tr("Append flags to the compiler command line. The flags may be prefixed by a scope: %s. Can be used multiple times.",
+			"all: (default), c:, cpp:, S:, ld:"))
 	compileCommand.Flags().StringVar(&keysKeychain, "keys-keychain", "",
 		tr("The path of the dir to search for the custom keys to sign and encrypt a binary. Used only by the platforms that support it."))
 	compileCommand.Flags().StringVar(&signKey, "sign-key", "",
@@ -113,6 +315,10 @@ func NewCommand() *cobra.Command {
 		tr("The name of the custom encryption key to use to encrypt a binary during the compile process. Used only by the platforms that support it."))
 	compileCommand.Flags().StringVar(&warnings, "warnings", "none",
 		tr(`Optional, can be: %s. Used to tell gcc which warning level to use (-W flag).`, "none, default, more, all"))
+	compileCommand.Flags().IntVar(&maxWarnings, "max-warnings", -1,
+		tr("Fail the build if the compiler emits more than the given number of warnings. The --warnings flag must be set to a level that enables them."))
+	compileCommand.Flags().BoolVar(&verifySize, "verify-size", false,
+		tr("Fail the build if the program or data size exceeds the maximum declared by the board, also when the size tool of the platform reports it only as a warning."))
 	compileCommand.Flags().BoolVarP(&verbose, "verbose", "v", false, tr("Optional, turns on verbose mode."))
 	compileCommand.Flags().BoolVar(&quiet, "quiet", false, tr("Optional, suppresses almost every output."))
 	compileCommand.Flags().BoolVarP(&uploadAfterCompile, "upload", "u", false, tr("Upload the binary after the compilation."))
@@ -122,9 +328,28 @@ func NewCommand() *cobra.Command {
 		tr("Path to a single library’s root folder. Can be used multiple times or entries can be comma separated."))
 	compileCommand.Flags().StringSliceVar(&libraries, "libraries", []string{},
 		tr("Path to a collection of libraries. Can be used multiple times or entries can be comma separated."))
+	compileCommand.Flags().BoolVar(&reproducible, "reproducible", false, tr("Optional, strip absolute paths and timestamps from the compiled binaries to make the build reproducible."))
+	compileCommand.Flags().BoolVar(&noBuildTime, "no-build-time", false,
+		tr("Set the %[1]s build properties to the time in %[2]s (or to 0 if it's not set) instead of the time of the build.", "extra.time.*", "SOURCE_DATE_EPOCH"))
+	compileCommand.Flags().StringSliceVar(&libraryPathOrder, "library-path-order", []string{},
+		tr("Precedence of the libraries locations used to choose between duplicated libraries, for example: %s. Allowed locations are: %s.", "sketchbook,bundled,core", "sketchbook, bundled, core"))
+	compileCommand.Flags().StringSliceVar(&allowedLibrarySources, "allowed-library-sources", []string{},
+		tr("Fail the build if a library used by the sketch doesn't come from one of the given sources, for example: %s. Allowed sources are: %s.", "sketchbook", "sketchbook, bundled, core, unmanaged"))
+	compileCommand.Flags().BoolVar(&onlyExplicitLibraries, "only-explicit-libraries", false,
+		tr("Use only the libraries specified with %[1]s and %[2]s, ignoring the installed ones. The build fails if the sketch includes a library that is not specified.", "--library", "--libraries"))
+	compileCommand.Flags().StringVar(&coreFromGit, "core-from-git", "",
+		tr("Use the platform hosted in the given git repository, in the form url#ref, for the build. The platform is installed using the vendor and architecture of the FQBN, that should not match an already installed platform. The clone is cached and updated at each build, use --clean to clone it again."))
+	compileCommand.Flags().BoolVar(&keepBuildPath, "keep-build-path", false,
+		tr("Keep the build path of a build made with %s, that is otherwise removed when the command ends. A build path given with %s is always kept.", "--core-from-git", "--build-path"))
+	compileCommand.Flags().BoolVar(&strictIncludes, "strict-includes", false,
+		tr("Compile each library using only its own include path, the core include paths and the ones of the libraries listed in its 'depends' field, followed transitively. Useful to detect libraries relying on headers of other libraries."))
 	compileCommand.Flags().BoolVar(&optimizeForDebug, "optimize-for-debug", false, tr("Optional, optimize compile output for debugging, rather than for release."))
 	programmer.AddToCommand(compileCommand)
 	compileCommand.Flags().BoolVar(&compilationDatabaseOnly, "only-compilation-database", false, tr("Just produce the compilation database, without actually compiling. All build commands are skipped except pre* hooks."))
+	compileCommand.Flags().StringVar(&compilationDatabasePath, "compilation-database-path", "",
+		tr("Path of the compilation database file, defaults to 'compile_commands.json' in the build path."))
+	compileCommand.Flags().BoolVar(&validateCompilationDb, "validate-compilation-database", false,
+		tr("Check that the files and directories referenced by the compilation database exist, the build fails if any of them is missing."))
 	compileCommand.Flags().BoolVar(&clean, "clean", false, tr("Optional, cleanup the build folder and do not use any cached build."))
 	// We must use the following syntax for this flag since it's also bound to settings.
 	// This must be done because the value is set when the binding is accessed from viper. Accessing from cobra would only
@@ -134,7 +359,17 @@ func NewCommand() *cobra.Command {
 	compileCommand.Flag("source-override").Hidden = true
 	compileCommand.Flags().BoolVar(&skipLibrariesDiscovery, "skip-libraries-discovery", false, "Skip libraries discovery. This flag is provided only for use in language server and other, very specific, use cases. Do not use for normal compiles")
 	compileCommand.Flag("skip-libraries-discovery").Hidden = true
+	compileCommand.Flags().BoolVar(&explainExitCodes, "explain-exit-codes", false, tr("Print the exit codes that may be returned by the command and their meaning."))
+	compileCommand.Flag("explain-exit-codes").Hidden = true
 	configuration.Settings.BindPFlag("sketch.always_export_binaries", compileCommand.Flags().Lookup("export-binaries"))
+	compileCommand.Flags().String("builtin-libraries-dir", "", tr("Path to the folder of the libraries bundled with the IDE, overrides the %s setting.", "directories.builtin.libraries"))
+	compileCommand.Flags().StringVar(&idePreferences, "ide-preferences", "",
+		tr("Path to the %[1]s file of an Arduino IDE installation, used to find the libraries bundled with the most recently run IDE. Use %[2]s to disable the libraries bundled with the IDE.", "preferences.txt", "none"))
+	configuration.Settings.BindPFlag("directories.builtin.libraries", compileCommand.Flags().Lookup("builtin-libraries-dir"))
+	compileCommand.Flags().String("data-dir", "", tr("Path to the data folder where platforms and tools are installed, overrides the %s setting.", "directories.data"))
+	configuration.Settings.BindPFlag("directories.data", compileCommand.Flags().Lookup("data-dir"))
+	compileCommand.Flags().String("sketchbook-dir", "", tr("Path to the sketchbook folder where user libraries and hardware are installed, overrides the %s setting.", "directories.user"))
+	configuration.Settings.BindPFlag("directories.user", compileCommand.Flags().Lookup("sketchbook-dir"))
 
 	compileCommand.Flags().MarkDeprecated("build-properties", tr("please use --build-property instead."))
 
@@ -144,25 +379,61 @@ func NewCommand() *cobra.Command {
 func runCompileCommand(cmd *cobra.Command, args []string) {
 	logrus.Info("Executing `arduino-cli compile`")
 
-	if profileArg.Get() != "" {
-		if len(libraries) > 0 {
-			feedback.Fatal(tr("You cannot use the %s flag while compiling with a profile.", "--libraries"), feedback.ErrBadArgument)
+	if explainExitCodes {
+		runExplainExitCodes()
+		return
+	}
+
+	if loadContext != "" {
+		if len(args) > 0 {
+			feedback.Fatal(tr("You cannot pass a sketch path together with the %s flag.", "--load-context"), feedback.ErrBadArgument)
 		}
-		if len(library) > 0 {
-			feedback.Fatal(tr("You cannot use the %s flag while compiling with a profile.", "--library"), feedback.ErrBadArgument)
+		runLoadedContext(cmd, paths.New(loadContext))
+		return
+	}
+
+	applyConfigurationFlags(cmd)
+
+	if listBoardOptions {
+		// The sketch is not needed to list the board options
+		inst := instance.CreateAndInit()
+		runListBoardOptions(inst, fqbnArg.String())
+		return
+	}
+
+	checkCompileFlagsConflicts(cmd)
+
+	// The temporary folders are removed also if the command fails, the fatal
+	// errors exit the process without running the deferred calls
+	var tmpDirs paths.PathList
+	removeTmpDirs := func() {
+		for _, dir := range tmpDirs {
+			dir.RemoveAll()
 		}
 	}
+	defer removeTmpDirs()
+	feedback.OnExit(removeTmpDirs)
 
 	path := ""
 	if len(args) > 0 {
 		path = args[0]
 	}
+	if checkInclude != "" {
+		if path != "" {
+			feedback.Fatal(tr("You cannot pass a sketch path together with the %s flag.", "--check-include"), feedback.ErrBadArgument)
+		}
+		tmpSketch, err := createCheckIncludeSketch(checkInclude)
+		if err != nil {
+			feedback.Fatal(tr("Error creating the sketch to check the include: %v", err), feedback.ErrGeneric)
+		}
+		tmpDirs.Add(tmpSketch.Parent())
+		path = tmpSketch.String()
+	}
 
-	sketchPath := arguments.InitSketchPath(path, true)
+	sk, sketchPath, mainFileArg := loadCompiledSketch(path)
 
-	sk, err := sketch.LoadSketch(context.Background(), &rpc.LoadSketchRequest{SketchPath: sketchPath.String()})
-	if err != nil {
-		feedback.FatalError(err, feedback.ErrGeneric)
+	if coreFromGit != "" {
+		addCoreFromGit(sk)
 	}
 
 	var inst *rpc.Instance
@@ -174,12 +445,172 @@ func runCompileCommand(cmd *cobra.Command, args []string) {
 		inst, profile = instance.CreateAndInitWithProfile(profileArg.Get(), sketchPath)
 	}
 
+	if fqbnArg.String() != "" && profile == nil {
+		checkDefaultFQBNMismatch(fqbnArg.String(), sk.GetDefaultFqbn())
+	}
 	if fqbnArg.String() == "" {
 		fqbnArg.Set(profile.GetFqbn())
 	}
+	checkBuildPathLength(resolvedBuildPath(buildPath, paths.New(sk.GetLocationPath())), maxBuildPathLength)
+
+	if showInfo {
+		fqbn := fqbnArg.String()
+		if fqbn == "" {
+			fqbn = sk.GetDefaultFqbn()
+		}
+		runInfo(inst, sk, fqbn)
+		return
+	}
+
+	if matrixFQBNs := getMatrixFQBNs(); len(matrixFQBNs) > 0 {
+		runMatrix(newCompileRequest(cmd, inst, "", sketchPath, mainFileArg), matrixFQBNs)
+		return
+	}
 
 	fqbn, port := arguments.CalculateFQBNAndPort(&portArgs, &fqbnArg, inst, sk.GetDefaultFqbn(), sk.GetDefaultPort(), sk.GetDefaultProtocol())
+	compileRequest := newCompileRequest(cmd, inst, fqbn, sketchPath, mainFileArg)
+
+	if watch {
+		runWatch(compileRequest, append(paths.PathList{sketchPath}, paths.NewPathList(compileRequest.GetLibrary()...)...))
+		return
+	}
+
+	runSingleCompile(compileRequest, inst, profile, sk, port)
+}
+
+// applyConfigurationFlags applies the flags changing the configuration of the
+// command: the inline configuration and the IDE preferences. It also checks
+// the flags that can't be used with a profile.
+func applyConfigurationFlags(cmd *cobra.Command) {
+	if configInline != "" {
+		config, err := parseInlineConfig(configInline)
+		if err != nil {
+			feedback.Fatal(tr("Invalid %[1]s configuration: %[2]v", "--config-inline", err), feedback.ErrBadArgument)
+		}
+		config.apply(cmd)
+	}
+
+	if profileArg.Get() != "" {
+		if len(libraries) > 0 {
+			feedback.Fatal(tr("You cannot use the %s flag while compiling with a profile.", "--libraries"), feedback.ErrBadArgument)
+		}
+		if len(library) > 0 {
+			feedback.Fatal(tr("You cannot use the %s flag while compiling with a profile.", "--library"), feedback.ErrBadArgument)
+		}
+	}
+
+	if idePreferences != "" {
+		arguments.CheckFlagsConflicts(cmd, "ide-preferences", "builtin-libraries-dir")
+		builtinLibrariesDir := ""
+		if idePreferences != "none" {
+			dir, err := ideBuiltinLibrariesDir(paths.New(idePreferences))
+			if err != nil {
+				feedback.Fatal(tr("Invalid %[1]s file: %[2]v", "--ide-preferences", err), feedback.ErrBadArgument)
+			}
+			builtinLibrariesDir = dir.String()
+		}
+		configuration.Settings.Set("directories.builtin.libraries", builtinLibrariesDir)
+	}
+}
+
+// checkCompileFlagsConflicts fails if flags that can't be used together are
+// given to the command.
+func checkCompileFlagsConflicts(cmd *cobra.Command) {
+	arguments.CheckFlagsConflicts(cmd, "matrix", "upload")
+	arguments.CheckFlagsConflicts(cmd, "matrix-file", "upload")
+	arguments.CheckFlagsConflicts(cmd, "check-include", "upload")
+	arguments.CheckFlagsConflicts(cmd, "dump-include-paths", "upload")
+	arguments.CheckFlagsConflicts(cmd, "dump-defines", "upload")
+	arguments.CheckFlagsConflicts(cmd, "dump-defines", "dump-include-paths")
+	arguments.CheckFlagsConflicts(cmd, "no-overwrite", "backup")
+	arguments.CheckFlagsConflicts(cmd, "lto", "no-lto")
+	arguments.CheckFlagsConflicts(cmd, "check-file", "upload")
+	arguments.CheckFlagsConflicts(cmd, "show-link-command", "print-cache-key")
+	arguments.CheckFlagsConflicts(cmd, "show-link-command", "dump-include-paths")
+	arguments.CheckFlagsConflicts(cmd, "show-link-command", "only-compilation-database")
+	arguments.CheckFlagsConflicts(cmd, "sketch-name", "upload")
+	arguments.CheckFlagsConflicts(cmd, "explain-property", "upload")
+	arguments.CheckFlagsConflicts(cmd, "explain-property", "show-properties")
+	arguments.CheckFlagsConflicts(cmd, "explain-property", "print-cache-key")
+	arguments.CheckFlagsConflicts(cmd, "explain-property", "dump-include-paths")
+	for _, flag := range []string{"upload", "matrix", "matrix-file", "check-include", "preprocess", "show-properties",
+		"print-cache-key", "explain-property", "dump-include-paths", "dump-defines", "only-compilation-database"} {
+		arguments.CheckFlagsConflicts(cmd, "watch", flag)
+	}
+	for _, flag := range []string{"check-include", "matrix", "matrix-file", "watch"} {
+		arguments.CheckFlagsConflicts(cmd, "save-context", flag)
+	}
+	for _, flag := range []string{"matrix", "matrix-file", "preprocess", "show-properties", "print-cache-key",
+		"explain-property", "dump-include-paths", "dump-defines", "only-compilation-database", "export-binaries", "output-dir"} {
+		arguments.CheckFlagsConflicts(cmd, "builder-path", flag)
+	}
+}
+
+// loadCompiledSketch loads the sketch to compile from the given path, or from
+// the sketchbook if the path is the name of a sketch. It returns the sketch,
+// its path and the main file to pass to the compile request.
+func loadCompiledSketch(path string) (*rpc.LoadSketchResponse, *paths.Path, string) {
+	// A sketch name that isn't an existing path is looked up in the sketchbook
+	if path != "" && filepath.Base(path) == path && !paths.New(path).Exist() {
+		path = resolveSketchbookSketch(path)
+	}
+	sketchPath := arguments.InitSketchPath(path, true)
+
+	// A main file given as a path is relative to the current directory,
+	// otherwise it's the name of a file in the sketch folder
+	mainFileArg := mainFile
+	if mainFile != "" && filepath.Base(mainFile) != mainFile {
+		p, err := paths.New(mainFile).Abs()
+		if err != nil {
+			feedback.Fatal(tr("Error converting path to absolute: %v", err), feedback.ErrGeneric)
+		}
+		mainFileArg = p.String()
+	}
+
+	sk, err := sketch.LoadSketch(context.Background(), &rpc.LoadSketchRequest{SketchPath: sketchPath.String(), MainFile: mainFileArg})
+	if err != nil {
+		var missingMainFile *arduinosketch.MainFileMissingError
+		if errors.As(err, &missingMainFile) && len(missingMainFile.Candidates) > 0 {
+			feedback.Fatal(err.Error()+"\n"+tr("Use the %s flag to select the main sketch file.", "--main-file"), feedback.ErrBadArgument)
+		}
+		feedback.FatalError(err, feedback.ErrGeneric)
+	}
+	return sk, sketchPath, mainFileArg
+}
+
+// addCoreFromGit clones the platform given with the --core-from-git flag and
+// adds it to the hardware folders searched by the build.
+func addCoreFromGit(sk *rpc.LoadSketchResponse) {
+	if profileArg.Get() != "" {
+		feedback.Fatal(tr("You cannot use the %s flag while compiling with a profile.", "--core-from-git"), feedback.ErrBadArgument)
+	}
+	fqbn := fqbnArg.String()
+	if fqbn == "" {
+		fqbn = sk.GetDefaultFqbn()
+	}
+	if fqbn == "" {
+		feedback.FatalError(&cmderrors.MissingFQBNError{}, feedback.ErrBadArgument)
+	}
+	hardwareDir, err := prepareCoreFromGit(coreFromGit, fqbn, clean)
+	if err != nil {
+		feedback.Fatal(tr("Error cloning platform from %[1]s: %[2]v", coreFromGit, err), feedback.ErrNetwork)
+	}
+	extraHardware := configuration.Settings.GetStringSlice("directories.extra_hardware")
+	configuration.Settings.Set("directories.extra_hardware", append(extraHardware, hardwareDir.String()))
+}
+
+// showPropertiesMode returns the mode selected with the --show-properties flag.
+func showPropertiesMode() arguments.ShowPropertiesMode {
+	showProperties, err := showPropertiesArg.Get()
+	if err != nil {
+		feedback.Fatal(tr("Error parsing --show-properties flag: %v", err), feedback.ErrBadArgument)
+	}
+	return showProperties
+}
 
+// newCompileRequest returns the request to compile the given sketch for the
+// given board with the options given by the flags of the command.
+func newCompileRequest(cmd *cobra.Command, inst *rpc.Instance, fqbn string, sketchPath *paths.Path, mainFileArg string) *rpc.CompileRequest {
 	if keysKeychain != "" || signKey != "" || encryptKey != "" {
 		arguments.CheckFlagsMandatory(cmd, "keys-keychain", "sign-key", "encrypt-key")
 	}
@@ -188,162 +619,212 @@ func runCompileCommand(cmd *cobra.Command, args []string) {
 	if sourceOverrides != "" {
 		data, err := paths.New(sourceOverrides).ReadFile()
 		if err != nil {
-			feedback.Fatal(tr("Error opening source code overrides data file: %v", err), feedback.ErrGeneric)
+			feedback.Fatal(tr("Error opening source code overrides data file: %v", err), feedback.ErrBadArgument)
 		}
 		var o struct {
 			Overrides map[string]string `json:"overrides"`
 		}
 		if err := json.Unmarshal(data, &o); err != nil {
-			feedback.Fatal(tr("Error: invalid source code overrides data file: %v", err), feedback.ErrGeneric)
+			feedback.Fatal(tr("Error: invalid source code overrides data file: %v", err), feedback.ErrBadArgument)
 		}
 		overrides = o.Overrides
 	}
 
-	showProperties, err := showPropertiesArg.Get()
-	if err != nil {
-		feedback.Fatal(tr("Error parsing --show-properties flag: %v", err), feedback.ErrGeneric)
-	}
-
-	var stdOut, stdErr io.Writer
-	var stdIORes func() *feedback.OutputStreamsResult
-	if showProperties != arguments.ShowPropertiesDisabled {
-		stdOut, stdErr, stdIORes = feedback.NewBufferedStreams()
-	} else {
-		stdOut, stdErr, stdIORes = feedback.OutputStreams()
-	}
+	showProperties := showPropertiesMode()
 
 	var libraryAbs []string
 	for _, libPath := range paths.NewPathList(library...) {
-		if libPath, err = libPath.Abs(); err != nil {
+		libPath, err := libPath.Abs()
+		if err != nil {
 			feedback.Fatal(tr("Error converting path to absolute: %v", err), feedback.ErrGeneric)
 		}
 		libraryAbs = append(libraryAbs, libPath.String())
 	}
 
-	compileRequest := &rpc.CompileRequest{
+	compareToAbs := ""
+	if compareTo != "" {
+		p, err := paths.New(compareTo).Abs()
+		if err != nil {
+			feedback.Fatal(tr("Error converting path to absolute: %v", err), feedback.ErrGeneric)
+		}
+		compareToAbs = p.String()
+	}
+
+	checkFileAbs := ""
+	if checkFile != "" {
+		p, err := paths.New(checkFile).Abs()
+		if err != nil {
+			feedback.Fatal(tr("Error converting path to absolute: %v", err), feedback.ErrGeneric)
+		}
+		checkFileAbs = p.String()
+	}
+
+	customBuildProperties, err := layerBuildProperties(
+		paths.New(buildPropertiesFile),
+		paths.NewPathList(buildPropertiesOverlays...),
+		append(joinEscapedBuildProperties(legacyBuildProperties), buildProperties...))
+	if err != nil {
+		feedback.Fatal(tr("Error reading the build properties files: %v", err), feedback.ErrBadArgument)
+	}
+
+	linkTimeOptimization := rpc.LinkTimeOptimization_LINK_TIME_OPTIMIZATION_DEFAULT
+	if lto {
+		linkTimeOptimization = rpc.LinkTimeOptimization_LINK_TIME_OPTIMIZATION_ENABLED
+	} else if noLTO {
+		linkTimeOptimization = rpc.LinkTimeOptimization_LINK_TIME_OPTIMIZATION_DISABLED
+	}
+
+	return &rpc.CompileRequest{
 		Instance:                      inst,
 		Fqbn:                          fqbn,
 		SketchPath:                    sketchPath.String(),
-		ShowProperties:                showProperties != arguments.ShowPropertiesDisabled,
+		ShowProperties:                showProperties != arguments.ShowPropertiesDisabled || printCacheKey,
 		Preprocess:                    preprocess,
 		BuildCachePath:                buildCachePath,
 		BuildPath:                     buildPath,
-		BuildProperties:               buildProperties,
+		BuildProperties:               customBuildProperties,
 		Warnings:                      warnings,
 		Verbose:                       verbose,
 		Quiet:                         quiet,
 		ExportDir:                     exportDir,
 		Libraries:                     libraries,
 		OptimizeForDebug:              optimizeForDebug,
+		Reproducible:                  reproducible,
+		NoBuildTime:                   noBuildTime,
+		OnlyNewDiagnostics:            onlyNewDiagnostics,
+		CopyTo:                        copyTo,
+		Archive:                       archive,
+		SketchName:                    outputSketchName,
+		VerifyTool:                    verifyTool,
+		ExportDepGraph:                exportDepGraph,
+		CompilerPath:                  compilerPath,
+		BuilderPath:                   builderPath,
+		IdeVersion:                    ideVersion,
+		FileFlags:                     fileFlags,
+		ExplainProperty:               explainProperty,
+		SketchEncoding:                sketchEncoding,
+		CompareTo:                     compareToAbs,
+		ExcludeFiles:                  excludeFiles,
+		LibraryPathOrder:              libraryPathOrder,
+		AllowedLibrarySources:         allowedLibrarySources,
+		ValidateCompilationDatabase:   validateCompilationDb,
+		ExtraFlags:                    extraFlags,
+		Defines:                       defines,
+		CppStd:                        cppStd,
+		CStd:                          cStd,
+		FqbnSeparator:                 fqbnSeparator,
+		VerboseOnFailure:              verboseOnFailure,
+		OutputFormats:                 outputFormats,
+		ToolOverrides:                 toolOverrides,
+		StrictFqbn:                    strictFqbn,
+		WriteChecksums:                writeChecksums,
+		Md5Checksums:                  md5Checksums,
+		LinkTimeOptimization:          linkTimeOptimization,
+		CheckFile:                     checkFileAbs,
+		MainFile:                      mainFileArg,
+		ExportDeps:                    exportDeps,
+		BoardDefine:                   boardDefine,
+		Trace:                         trace,
+		Bootloader:                    bootloader,
+		OnlyExplicitLibraries:         onlyExplicitLibraries,
 		Clean:                         clean,
-		CreateCompilationDatabaseOnly: compilationDatabaseOnly,
+		CreateCompilationDatabaseOnly: compilationDatabaseOnly || dumpIncludePaths || dumpDefines,
+		DumpDefines:                   dumpDefines,
+		Summary:                       summary,
+		CompilationDatabasePath:       compilationDatabasePath,
 		SourceOverride:                overrides,
 		Library:                       libraryAbs,
 		KeysKeychain:                  keysKeychain,
 		SignKey:                       signKey,
 		EncryptKey:                    encryptKey,
 		SkipLibrariesDiscovery:        skipLibrariesDiscovery,
+		StrictIncludes:                strictIncludes,
+		VerifySize:                    verifySize,
+		KeepObjects:                   keepObjects,
+		ExportPreprocessedSketch:      exportPreprocessed,
+		NoOverwrite:                   noOverwrite,
+		Backup:                        backup,
+		NoFollowSymlinks:              noFollowSymlinks,
 		DoNotExpandBuildProperties:    showProperties == arguments.ShowPropertiesUnexpanded,
 	}
-	builderRes, compileError := compile.Compile(context.Background(), compileRequest, stdOut, stdErr, nil)
+}
 
-	var uploadRes *rpc.UploadResult
-	if compileError == nil && uploadAfterCompile {
-		userFieldRes, err := upload.SupportedUserFields(context.Background(), &rpc.SupportedUserFieldsRequest{
-			Instance: inst,
-			Fqbn:     fqbn,
-			Protocol: port.GetProtocol(),
-		})
+// runSingleCompile runs the given compile request, uploads the result if
+// requested and prints the outcome of the build.
+func runSingleCompile(compileRequest *rpc.CompileRequest, inst *rpc.Instance, profile *rpc.Profile, sk *rpc.LoadSketchResponse, port *rpc.Port) {
+	showProperties := showPropertiesMode()
+
+	// The cache key is computed together with the build properties, both
+	// are returned without running the build. The include paths are resolved
+	// without compiling, producing only the compilation database.
+	var stdOut, stdErr io.Writer
+	var stdIORes func() *feedback.OutputStreamsResult
+	if showProperties != arguments.ShowPropertiesDisabled || printCacheKey || dumpIncludePaths || dumpDefines || explainProperty != "" {
+		stdOut, stdErr, stdIORes = feedback.NewBufferedStreams()
+	} else {
+		stdOut, stdErr, stdIORes = feedback.OutputStreams()
+	}
+
+	var progressCB rpc.TaskProgressCB
+	var observer compile.Observer
+	if progressStreamTarget != "" {
+		progress, err := openProgressStream(progressStreamTarget)
 		if err != nil {
-			feedback.Fatal(tr("Error during Upload: %v", err), feedback.ErrGeneric)
+			feedback.Fatal(tr("Error opening the progress stream: %v", err), feedback.ErrGeneric)
 		}
+		defer progress.close()
+		progressCB = progress.taskProgressCB
+		observer = progress
+		compileRequest.DetailedProgress = true
+	}
 
-		fields := map[string]string{}
-		if len(userFieldRes.GetUserFields()) > 0 {
-			feedback.Print(tr("Uploading to specified board using %s protocol requires the following info:", port.GetProtocol()))
-			if f, err := arguments.AskForUserFields(userFieldRes.GetUserFields()); err != nil {
-				feedback.FatalError(err, feedback.ErrBadArgument)
-			} else {
-				fields = f
-			}
+	// On SIGINT or SIGTERM the build is interrupted gracefully, so the files
+	// being exported are cleaned up instead of being left half-written. The
+	// default handling of the signals is restored after the build.
+	ctx, stopSignalHandler := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	startedAt := time.Now()
+	builderRes, compileError := compile.Compile(ctx, compileRequest, stdOut, stdErr, progressCB, observer)
+	stopSignalHandler()
+	if saveContext != "" {
+		// The context is saved after the build to record the resolved
+		// environment, even if the build failed
+		ctx, err := newCompileContext(compileRequest, profile.GetName(), builderRes)
+		if err == nil {
+			err = saveCompileContext(paths.New(saveContext), ctx)
 		}
-
-		uploadRequest := &rpc.UploadRequest{
-			Instance:   inst,
-			Fqbn:       fqbn,
-			SketchPath: sketchPath.String(),
-			Port:       port,
-			Verbose:    verbose,
-			Verify:     verify,
-			ImportDir:  buildPath,
-			Programmer: programmer.String(inst, fqbn),
-			UserFields: fields,
+		if err != nil {
+			feedback.Fatal(tr("Error saving the compile context: %v", err), feedback.ErrGeneric)
 		}
-
-		if res, err := upload.Upload(context.Background(), uploadRequest, stdOut, stdErr); err != nil {
-			errcode := feedback.ErrGeneric
-			if errors.Is(err, &cmderrors.ProgrammerRequiredForUploadError{}) {
-				errcode = feedback.ErrMissingProgrammer
-			}
-			if errors.Is(err, &cmderrors.MissingProgrammerError{}) {
-				errcode = feedback.ErrMissingProgrammer
-			}
-			feedback.Fatal(tr("Error during Upload: %v", err), errcode)
-		} else {
-			uploadRes = res
+	}
+	if checkInclude != "" {
+		if err := checkIncludeFailure(checkInclude, builderRes.GetDiagnostics()); compileError != nil && err != nil {
+			compileError = err
+		}
+	}
+	if compileError == nil && maxWarnings >= 0 {
+		if count := countWarnings(builderRes.GetDiagnostics()); count > maxWarnings {
+			compileError = errors.New(tr("the compiler emitted %[1]d warnings, the maximum allowed is %[2]d", count, maxWarnings))
 		}
 	}
 
-	profileOut := ""
-	if dumpProfile && compileError == nil {
-		// Output profile
+	var uploadRes *rpc.UploadResult
+	if compileError == nil && uploadAfterCompile {
+		uploadRes = uploadCompiledSketch(compileRequest, inst, port, stdOut, stdErr)
+	}
 
-		libs := ""
-		hasVendoredLibs := false
-		for _, lib := range builderRes.GetUsedLibraries() {
-			if lib.GetLocation() != rpc.LibraryLocation_LIBRARY_LOCATION_USER && lib.GetLocation() != rpc.LibraryLocation_LIBRARY_LOCATION_UNMANAGED {
-				continue
-			}
-			if lib.GetVersion() == "" {
-				hasVendoredLibs = true
-				continue
+	// The objects built with a core cloned from git are not reusable by
+	// the builds made with the installed platforms
+	if coreFromGit != "" && !keepBuildPath && buildPath == "" {
+		if buildDir := paths.New(builderRes.GetBuildPath()); buildDir != nil {
+			if err := buildDir.RemoveAll(); err != nil {
+				feedback.Warning(tr("Error removing the build path %[1]s: %[2]v", buildDir, err))
 			}
-			libs += fmt.Sprintln("      - " + lib.GetName() + " (" + lib.GetVersion() + ")")
-		}
-		if hasVendoredLibs {
-			msg := "\n"
-			msg += tr("WARNING: The sketch is compiled using one or more custom libraries.") + "\n"
-			msg += tr("Currently, Build Profiles only support libraries available through Arduino Library Manager.")
-			feedback.Warning(msg)
-		}
-
-		newProfileName := "my_profile_name"
-		if split := strings.Split(compileRequest.GetFqbn(), ":"); len(split) > 2 {
-			newProfileName = split[2]
-		}
-		profileOut = fmt.Sprintln("profiles:")
-		profileOut += fmt.Sprintln("  " + newProfileName + ":")
-		profileOut += fmt.Sprintln("    fqbn: " + compileRequest.GetFqbn())
-		profileOut += fmt.Sprintln("    platforms:")
-		boardPlatform := builderRes.GetBoardPlatform()
-		profileOut += fmt.Sprintln("      - platform: " + boardPlatform.GetId() + " (" + boardPlatform.GetVersion() + ")")
-		if url := boardPlatform.GetPackageUrl(); url != "" {
-			profileOut += fmt.Sprintln("        platform_index_url: " + url)
 		}
+	}
 
-		if buildPlatform := builderRes.GetBuildPlatform(); buildPlatform != nil &&
-			buildPlatform.GetId() != boardPlatform.GetId() &&
-			buildPlatform.GetVersion() != boardPlatform.GetVersion() {
-			profileOut += fmt.Sprintln("      - platform: " + buildPlatform.GetId() + " (" + buildPlatform.GetVersion() + ")")
-			if url := buildPlatform.GetPackageUrl(); url != "" {
-				profileOut += fmt.Sprintln("        platform_index_url: " + url)
-			}
-		}
-		if len(libs) > 0 {
-			profileOut += fmt.Sprintln("    libraries:")
-			profileOut += fmt.Sprint(libs)
-		}
-		profileOut += fmt.Sprintln()
+	profileOut := ""
+	if dumpProfile && compileError == nil {
+		profileOut = buildProfileOutput(compileRequest, builderRes)
 	}
 
 	stdIO := stdIORes()
@@ -358,42 +839,267 @@ func runCompileCommand(cmd *cobra.Command, args []string) {
 		Diagnostics:        result.NewCompileDiagnostics(builderRes.GetDiagnostics()),
 		Success:            compileError == nil,
 		showPropertiesMode: showProperties,
+		printCacheKey:      printCacheKey,
+		explainProperty:    explainProperty != "",
+		showLinkCommand:    showLinkCommand,
+		dumpIncludePaths:   dumpIncludePaths,
+		dumpDefines:        dumpDefines,
 		hideStats:          preprocess,
+		listOutputs:        listOutputs || writeChecksums,
+		listLibraries:      listLibraries,
+	}
+
+	if reportFile != "" {
+		report := newBuildReport(compileRequest.GetFqbn(), compileRequest.GetSketchPath(), res.BuilderResult, startedAt, compileError)
+		if err := report.save(paths.New(reportFile)); err != nil {
+			feedback.Fatal(tr("Error writing the build report: %v", err), feedback.ErrGeneric)
+		}
+	}
+
+	if warningsFile != "" {
+		if err := writeWarningsFile(paths.New(warningsFile), res.Diagnostics); err != nil {
+			feedback.Fatal(tr("Error writing the warnings file: %v", err), feedback.ErrGeneric)
+		}
 	}
 
 	if compileError != nil {
-		res.Error = tr("Error during build: %v", compileError)
-
-		// Check the error type to give the user better feedback on how
-		// to resolve it
-		var platformErr *cmderrors.PlatformNotFoundError
-		if errors.As(compileError, &platformErr) {
-			split := strings.Split(platformErr.Platform, ":")
-			if len(split) < 2 {
-				panic(tr("Platform ID is not correct"))
-			}
+		res.Error = compileErrorMessage(compileError, inst, profile, sk)
+		feedback.FatalResult(res, compileErrorExitCode(compileError))
+	}
 
-			if profileArg.String() == "" {
-				res.Error += fmt.Sprintln()
-
-				if platform, err := core.PlatformSearch(&rpc.PlatformSearchRequest{
-					Instance:   inst,
-					SearchArgs: platformErr.Platform,
-				}); err != nil {
-					res.Error += err.Error()
-				} else if len(platform.GetSearchOutput()) > 0 {
-					suggestion := fmt.Sprintf("`%s core install %s`", version.VersionInfo.Application, platformErr.Platform)
-					res.Error += tr("Try running %s", suggestion)
-				} else {
-					res.Error += tr("Platform %s is not found in any known index\nMaybe you need to add a 3rd party URL?", platformErr.Platform)
-				}
-			}
+	if analyzeMap && res.BuilderResult != nil {
+		mapFile := findMapFile(res.BuilderResult)
+		if mapFile == nil {
+			feedback.Fatal(tr("The linker map file has not been generated by the build. Add %s to the extra flags to generate it.", `"ld:-Wl,-Map,{build.path}/{build.project_name}.map"`), feedback.ErrGeneric)
+		}
+		analysis, err := analyzeMapFile(mapFile)
+		if err != nil {
+			feedback.Fatal(tr("Error analyzing the linker map file %[1]s: %[2]v", mapFile, err), feedback.ErrGeneric)
 		}
-		feedback.FatalResult(res, feedback.ErrGeneric)
+		res.MapAnalysis = analysis
 	}
 	feedback.PrintResult(res)
 }
 
+// uploadCompiledSketch uploads the sketch built by the given compile request,
+// asking the user for the fields required by the upload protocol.
+func uploadCompiledSketch(compileRequest *rpc.CompileRequest, inst *rpc.Instance, port *rpc.Port, stdOut, stdErr io.Writer) *rpc.UploadResult {
+	userFieldRes, err := upload.SupportedUserFields(context.Background(), &rpc.SupportedUserFieldsRequest{
+		Instance: inst,
+		Fqbn:     compileRequest.GetFqbn(),
+		Protocol: port.GetProtocol(),
+	})
+	if err != nil {
+		feedback.Fatal(tr("Error during Upload: %v", err), feedback.ErrGeneric)
+	}
+
+	fields := map[string]string{}
+	if len(userFieldRes.GetUserFields()) > 0 {
+		feedback.Print(tr("Uploading to specified board using %s protocol requires the following info:", port.GetProtocol()))
+		if f, err := arguments.AskForUserFields(userFieldRes.GetUserFields()); err != nil {
+			feedback.FatalError(err, feedback.ErrBadArgument)
+		} else {
+			fields = f
+		}
+	}
+
+	uploadRequest := &rpc.UploadRequest{
+		Instance:   inst,
+		Fqbn:       compileRequest.GetFqbn(),
+		SketchPath: compileRequest.GetSketchPath(),
+		Port:       port,
+		Verbose:    verbose,
+		Verify:     verify,
+		ImportDir:  buildPath,
+		Programmer: programmer.String(inst, compileRequest.GetFqbn()),
+		UserFields: fields,
+	}
+
+	res, err := upload.Upload(context.Background(), uploadRequest, stdOut, stdErr)
+	if err != nil {
+		errcode := feedback.ErrGeneric
+		if errors.Is(err, &cmderrors.ProgrammerRequiredForUploadError{}) {
+			errcode = feedback.ErrMissingProgrammer
+		}
+		if errors.Is(err, &cmderrors.MissingProgrammerError{}) {
+			errcode = feedback.ErrMissingProgrammer
+		}
+		feedback.Fatal(tr("Error during Upload: %v", err), errcode)
+	}
+	return res
+}
+
+// buildProfileOutput returns the profile reproducing the build of the given
+// compile request, to be added to the sketch project file.
+func buildProfileOutput(compileRequest *rpc.CompileRequest, builderRes *rpc.BuilderResult) string {
+	libs := ""
+	hasVendoredLibs := false
+	for _, lib := range builderRes.GetUsedLibraries() {
+		if lib.GetLocation() != rpc.LibraryLocation_LIBRARY_LOCATION_USER && lib.GetLocation() != rpc.LibraryLocation_LIBRARY_LOCATION_UNMANAGED {
+			continue
+		}
+		if lib.GetVersion() == "" {
+			hasVendoredLibs = true
+			continue
+		}
+		libs += fmt.Sprintln("      - " + lib.GetName() + " (" + lib.GetVersion() + ")")
+	}
+	if hasVendoredLibs {
+		msg := "\n"
+		msg += tr("WARNING: The sketch is compiled using one or more custom libraries.") + "\n"
+		msg += tr("Currently, Build Profiles only support libraries available through Arduino Library Manager.")
+		feedback.Warning(msg)
+	}
+
+	newProfileName := "my_profile_name"
+	if split := strings.Split(compileRequest.GetFqbn(), ":"); len(split) > 2 {
+		newProfileName = split[2]
+	}
+	profileOut := fmt.Sprintln("profiles:")
+	profileOut += fmt.Sprintln("  " + newProfileName + ":")
+	profileOut += fmt.Sprintln("    fqbn: " + compileRequest.GetFqbn())
+	profileOut += fmt.Sprintln("    platforms:")
+	boardPlatform := builderRes.GetBoardPlatform()
+	profileOut += fmt.Sprintln("      - platform: " + boardPlatform.GetId() + " (" + boardPlatform.GetVersion() + ")")
+	if url := boardPlatform.GetPackageUrl(); url != "" {
+		profileOut += fmt.Sprintln("        platform_index_url: " + url)
+	}
+
+	if buildPlatform := builderRes.GetBuildPlatform(); buildPlatform != nil &&
+		buildPlatform.GetId() != boardPlatform.GetId() &&
+		buildPlatform.GetVersion() != boardPlatform.GetVersion() {
+		profileOut += fmt.Sprintln("      - platform: " + buildPlatform.GetId() + " (" + buildPlatform.GetVersion() + ")")
+		if url := buildPlatform.GetPackageUrl(); url != "" {
+			profileOut += fmt.Sprintln("        platform_index_url: " + url)
+		}
+	}
+	if len(libs) > 0 {
+		profileOut += fmt.Sprintln("    libraries:")
+		profileOut += fmt.Sprint(libs)
+	}
+	profileOut += fmt.Sprintln()
+	return profileOut
+}
+
+// compileErrorMessage returns the message reporting the given build error,
+// with the hints on how to resolve it.
+func compileErrorMessage(compileError error, inst *rpc.Instance, profile *rpc.Profile, sk *rpc.LoadSketchResponse) string {
+	msg := tr("Error during build: %v", compileError)
+
+	// Check the error type to give the user better feedback on how
+	// to resolve it
+	var platformErr *cmderrors.PlatformNotFoundError
+	if errors.As(compileError, &platformErr) {
+		split := strings.Split(platformErr.Platform, ":")
+		if len(split) < 2 {
+			panic(tr("Platform ID is not correct"))
+		}
+
+		if profileArg.String() == "" {
+			msg += fmt.Sprintln()
+
+			if platform, err := core.PlatformSearch(&rpc.PlatformSearchRequest{
+				Instance:   inst,
+				SearchArgs: platformErr.Platform,
+			}); err != nil {
+				msg += err.Error()
+			} else if len(platform.GetSearchOutput()) > 0 {
+				suggestion := fmt.Sprintf("`%s core install %s`", version.VersionInfo.Application, platformErr.Platform)
+				msg += tr("Try running %s", suggestion)
+			} else {
+				msg += tr("Platform %s is not found in any known index\nMaybe you need to add a 3rd party URL?", platformErr.Platform)
+			}
+		}
+	}
+
+	// The libraries pinned by a profile must be installed at the exact version
+	var libraryErr *cmderrors.LibraryNotFoundError
+	if errors.As(compileError, &libraryErr) && profile != nil {
+		suggestion := fmt.Sprintf("`%s lib install %s`", version.VersionInfo.Application, libraryErr.Library)
+		msg += fmt.Sprintln()
+		msg += tr("Try running %s", suggestion)
+	}
+
+	// Legacy .pde sketches often fail because they were written for very old
+	// versions of the Arduino core: give a hint to the user
+	if mainFile := paths.New(sk.GetMainFile()); mainFile.Ext() == ".pde" {
+		inoFile := strings.TrimSuffix(mainFile.Base(), ".pde") + ".ino"
+		msg += fmt.Sprintln()
+		msg += tr("Note: the sketch main file %[1]s uses the legacy .pde extension. Sketches written for old versions of the Arduino IDE may need to be updated, please rename it to %[2]s.", mainFile.Base(), inoFile)
+	}
+	return msg
+}
+
+// compileErrorExitCode returns the exit code of the command failed with the
+// given build error.
+func compileErrorExitCode(compileError error) feedback.ExitCode {
+	exitCode := feedback.ErrGeneric
+	var interruptedErr *cmderrors.CompileInterruptedError
+	var invalidArgumentErr *cmderrors.InvalidArgumentError
+	var invalidFQBNErr *cmderrors.InvalidFQBNError
+	var missingFQBNErr *cmderrors.MissingFQBNError
+	if errors.As(compileError, &interruptedErr) {
+		exitCode = feedback.ErrInterrupted
+	} else if errors.As(compileError, &invalidArgumentErr) || errors.As(compileError, &invalidFQBNErr) || errors.As(compileError, &missingFQBNErr) {
+		exitCode = feedback.ErrBadArgument
+	}
+	return exitCode
+}
+
+// checkDefaultFQBNMismatch warns the user, or fails in strict mode, if the
+// board given with the --fqbn flag differs from the default board of the
+// sketch. The board configuration options are not compared.
+func checkDefaultFQBNMismatch(fqbn, defaultFQBN string) {
+	if defaultFQBN == "" {
+		return
+	}
+	boardID := func(fqbn string) string {
+		split := strings.Split(fqbn, ":")
+		if len(split) > 3 {
+			split = split[:3]
+		}
+		return strings.Join(split, ":")
+	}
+	if boardID(fqbn) == boardID(defaultFQBN) {
+		return
+	}
+	msg := tr("The board %[1]s differs from the default board %[2]s of the sketch.", fqbn, defaultFQBN)
+	if strict {
+		feedback.Fatal(msg, feedback.ErrBadArgument)
+	}
+	feedback.Warning(msg + " " + tr("Compiling for %s.", fqbn))
+}
+
+// joinEscapedBuildProperties joins back the values of the deprecated --build-properties
+// flag that were split on a comma escaped with a backslash.
+func joinEscapedBuildProperties(values []string) []string {
+	res := []string{}
+	escaped := false
+	for _, value := range values {
+		if escaped {
+			res[len(res)-1] += value
+		} else {
+			res = append(res, value)
+		}
+		escaped = strings.HasSuffix(value, "\\")
+		if escaped {
+			res[len(res)-1] = strings.TrimSuffix(res[len(res)-1], "\\") + ","
+		}
+	}
+	return res
+}
+
+// countWarnings returns the number of warnings in the given diagnostics
+func countWarnings(diagnostics []*rpc.CompileDiagnostic) int {
+	count := 0
+	for _, d := range diagnostics {
+		if d.GetSeverity() == "WARNING" {
+			count++
+		}
+	}
+	return count
+}
+
 type updatedUploadPortResult struct {
 	UpdatedUploadPort *result.Port `json:"updated_upload_port,omitempty"`
 }
@@ -407,8 +1113,16 @@ type compileResult struct {
 	ProfileOut         string                      `json:"profile_out,omitempty"`
 	Error              string                      `json:"error,omitempty"`
 	Diagnostics        []*result.CompileDiagnostic `json:"diagnostics,omitempty"`
+	MapAnalysis        *mapAnalysis                `json:"map_analysis,omitempty"`
 	showPropertiesMode arguments.ShowPropertiesMode
+	printCacheKey      bool
+	explainProperty    bool
+	showLinkCommand    bool
+	dumpIncludePaths   bool
+	dumpDefines        bool
 	hideStats          bool
+	listOutputs        bool
+	listLibraries      bool
 }
 
 func (r *compileResult) Data() interface{} {
@@ -416,6 +1130,21 @@ func (r *compileResult) Data() interface{} {
 }
 
 func (r *compileResult) String() string {
+	if r.BuilderResult != nil && r.printCacheKey {
+		return r.BuilderResult.CoreCacheKey
+	}
+	if r.BuilderResult != nil && r.explainProperty {
+		return formatPropertyExplanation(r.BuilderResult.PropertyExplanation)
+	}
+	if r.BuilderResult != nil && r.showLinkCommand {
+		return r.BuilderResult.LinkCommand
+	}
+	if r.BuilderResult != nil && r.dumpIncludePaths {
+		return strings.Join(r.BuilderResult.IncludePaths, fmt.Sprintln())
+	}
+	if r.BuilderResult != nil && r.dumpDefines {
+		return strings.Join(r.BuilderResult.Defines, fmt.Sprintln())
+	}
 	if r.BuilderResult != nil && r.showPropertiesMode != arguments.ShowPropertiesDisabled {
 		return strings.Join(r.BuilderResult.BuildProperties, fmt.Sprintln())
 	}
@@ -435,11 +1164,27 @@ func (r *compileResult) String() string {
 	}
 	if build != nil && len(build.UsedLibraries) > 0 {
 		libraries := table.New()
-		libraries.SetHeader(
-			table.NewCell(tr("Used library"), titleColor),
-			table.NewCell(tr("Version"), titleColor),
-			table.NewCell(tr("Path"), pathColor))
+		if r.listLibraries {
+			libraries.SetHeader(
+				table.NewCell(tr("Used library"), titleColor),
+				table.NewCell(tr("Version"), titleColor),
+				table.NewCell(tr("Location"), titleColor),
+				table.NewCell(tr("Path"), pathColor))
+		} else {
+			libraries.SetHeader(
+				table.NewCell(tr("Used library"), titleColor),
+				table.NewCell(tr("Version"), titleColor),
+				table.NewCell(tr("Path"), pathColor))
+		}
 		for _, l := range build.UsedLibraries {
+			if r.listLibraries {
+				libraries.AddRow(
+					table.NewCell(l.Name, nameColor),
+					l.Version,
+					libraryLocationLabel(l),
+					table.NewCell(l.InstallDir, pathColor))
+				continue
+			}
 			libraries.AddRow(
 				table.NewCell(l.Name, nameColor),
 				l.Version,
@@ -468,12 +1213,129 @@ func (r *compileResult) String() string {
 		}
 		res += fmt.Sprintln(platforms.Render())
 	}
+	if build != nil && r.listOutputs && len(build.Artifacts) > 0 {
+		artifacts := table.New()
+		withMD5 := build.Artifacts[0].Md5 != ""
+		header := []interface{}{
+			table.NewCell(tr("Output file"), titleColor),
+			table.NewCell(tr("Type"), titleColor),
+			table.NewCell(tr("Size"), titleColor),
+			table.NewCell(tr("SHA-256"), titleColor),
+		}
+		if withMD5 {
+			header = append(header, table.NewCell(tr("MD5"), titleColor))
+		}
+		artifacts.SetHeader(header...)
+		for _, a := range build.Artifacts {
+			row := []interface{}{
+				table.NewCell(a.Path, pathColor),
+				a.Type,
+				tr("%d bytes", a.Size),
+				a.Sha256,
+			}
+			if withMD5 {
+				row = append(row, a.Md5)
+			}
+			artifacts.AddRow(row...)
+		}
+		res += fmt.Sprintln(artifacts.Render())
+	}
+	if r.MapAnalysis != nil {
+		sections := table.New()
+		sections.SetHeader(
+			table.NewCell(tr("Section"), titleColor),
+			table.NewCell(tr("Size"), titleColor))
+		for _, s := range r.MapAnalysis.Sections {
+			sections.AddRow(table.NewCell(s.Name, nameColor), tr("%d bytes", s.Size))
+		}
+		res += fmt.Sprintln(sections.Render())
+
+		symbols := table.New()
+		symbols.SetHeader(
+			table.NewCell(tr("Largest symbol"), titleColor),
+			table.NewCell(tr("Section"), titleColor),
+			table.NewCell(tr("Size"), titleColor),
+			table.NewCell(tr("Object file"), pathColor))
+		for _, s := range r.MapAnalysis.LargestSymbols {
+			symbols.AddRow(
+				table.NewCell(s.Name, nameColor),
+				s.Section,
+				tr("%d bytes", s.Size),
+				table.NewCell(s.Object, pathColor))
+		}
+		res += fmt.Sprintln(symbols.Render())
+	}
+	if build != nil && build.Summary != nil {
+		summary := table.New()
+		summary.SetHeader(
+			table.NewCell(tr("Compiled files"), titleColor),
+			table.NewCell(tr("Count"), titleColor))
+		extensions := []string{}
+		for ext := range build.Summary.FilesByExtension {
+			extensions = append(extensions, ext)
+		}
+		sort.Strings(extensions)
+		for _, ext := range extensions {
+			summary.AddRow(table.NewCell(ext, nameColor), fmt.Sprint(build.Summary.FilesByExtension[ext]))
+		}
+		summary.AddRow(tr("Total"), fmt.Sprint(build.Summary.UniqueFiles))
+		summary.AddRow(tr("Compile commands"), fmt.Sprint(build.Summary.TotalCommands))
+		res += fmt.Sprintln(summary.Render())
+	}
 	if r.ProfileOut != "" {
 		res += fmt.Sprintln(r.ProfileOut)
 	}
 	return strings.TrimRight(res, fmt.Sprintln())
 }
 
+// libraryLocationLabel returns a description of where the given library has
+// been installed, including the platform providing it if any.
+func libraryLocationLabel(l *result.Library) string {
+	switch l.Location {
+	case result.LibraryLocationUser:
+		return tr("sketchbook")
+	case result.LibraryLocationIDEBuiltin:
+		return tr("bundled with the IDE")
+	case result.LibraryLocationPlatformBuiltin, result.LibraryLocationReferencedPlatformBuiltin:
+		if l.ContainerPlatform != "" {
+			return tr("platform %s", l.ContainerPlatform)
+		}
+		return tr("platform")
+	case result.LibraryLocationUnmanged:
+		return tr("unmanaged")
+	}
+	return string(l.Location)
+}
+
+// formatPropertyExplanation returns the explanation of a build property
+// in a human readable form
+func formatPropertyExplanation(e *result.PropertyExplanation) string {
+	if e == nil {
+		return ""
+	}
+	t := table.New()
+	t.AddRow(tr("Property:"), e.Key)
+	if e.Source == "" {
+		t.AddRow(tr("Default value:"), tr("not defined by the platform or the board"))
+	} else {
+		t.AddRow(tr("Default value:"), e.DefaultValue)
+		t.AddRow(tr("Defined in:"), e.Source)
+	}
+	if !e.Defined {
+		t.AddRow(tr("Value:"), tr("not defined"))
+		return t.Render()
+	}
+	if e.Value != e.DefaultValue || e.Source == "" {
+		t.AddRow(tr("Value:"), e.Value)
+	} else {
+		t.AddRow(tr("Value:"), tr("unchanged"))
+	}
+	if e.ExpandedValue != e.Value {
+		t.AddRow(tr("Expanded value:"), e.ExpandedValue)
+	}
+	return t.Render()
+}
+
 func (r *compileResult) ErrorString() string {
 	return r.Error
 }
@@ -0,0 +1,184 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/arduino/arduino-cli/commands/compile"
+	"github.com/arduino/arduino-cli/internal/cli/arguments"
+	"github.com/arduino/arduino-cli/internal/cli/configuration"
+	"github.com/arduino/arduino-cli/internal/cli/feedback"
+	"github.com/arduino/arduino-cli/internal/cli/feedback/result"
+	"github.com/arduino/arduino-cli/internal/cli/instance"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/arduino-cli/version"
+	"github.com/arduino/go-paths-helper"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// compileContext is the content of the files written with --save-context:
+// the compile request, with all the flags already applied, the profile used
+// to initialize the instance and the environment resolved by the build.
+type compileContext struct {
+	Version string          `json:"version"`
+	Profile string          `json:"profile,omitempty"`
+	Request json.RawMessage `json:"request"`
+	// The hardware and libraries directories used by the build, including
+	// the libraries given explicitly
+	HardwareDirs  []string `json:"hardware_dirs,omitempty"`
+	LibrariesDirs []string `json:"libraries_dirs,omitempty"`
+	// The build properties resolved by the build
+	BuildProperties []string `json:"build_properties,omitempty"`
+}
+
+// newCompileContext returns the context of the build made with the given
+// request and profile, builderRes is the result of the build.
+func newCompileContext(req *rpc.CompileRequest, profile string, builderRes *rpc.BuilderResult) (*compileContext, error) {
+	req = proto.Clone(req).(*rpc.CompileRequest)
+	req.Instance = nil
+	request, err := protojson.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	librariesDirs := paths.NewPathList(append(req.GetLibraries(), req.GetLibrary()...)...)
+	librariesDirs.Add(configuration.LibrariesDir(configuration.Settings))
+	if dir := configuration.IDEBuiltinLibrariesDir(configuration.Settings); dir != nil {
+		librariesDirs.Add(dir)
+	}
+	hardwareDirs := configuration.HardwareDirectories(configuration.Settings)
+	return &compileContext{
+		Version:         version.VersionInfo.VersionString,
+		Profile:         profile,
+		Request:         request,
+		HardwareDirs:    hardwareDirs.AsStrings(),
+		LibrariesDirs:   librariesDirs.AsStrings(),
+		BuildProperties: builderRes.GetBuildProperties(),
+	}, nil
+}
+
+// saveCompileContext writes the context of the build to the file, so that
+// the build can be replayed with --load-context.
+func saveCompileContext(file *paths.Path, ctx *compileContext) error {
+	data, err := json.MarshalIndent(ctx, "", "  ")
+	if err != nil {
+		return err
+	}
+	return file.WriteFile(data)
+}
+
+// loadCompileContext reads the context of a build from a file written with
+// --save-context, and returns it together with its compile request.
+func loadCompileContext(file *paths.Path) (*compileContext, *rpc.CompileRequest, error) {
+	data, err := file.ReadFile()
+	if err != nil {
+		return nil, nil, err
+	}
+	var ctx compileContext
+	if err := json.Unmarshal(data, &ctx); err != nil {
+		return nil, nil, err
+	}
+	if len(ctx.Request) == 0 {
+		return nil, nil, errors.New(tr("missing compile request"))
+	}
+	req := &rpc.CompileRequest{}
+	if err := protojson.Unmarshal(ctx.Request, req); err != nil {
+		return nil, nil, err
+	}
+	return &ctx, req, nil
+}
+
+// diffStrings returns the elements of a that are not in b
+func diffStrings(a, b []string) []string {
+	inB := map[string]bool{}
+	for _, s := range b {
+		inB[s] = true
+	}
+	res := []string{}
+	for _, s := range a {
+		if !inB[s] {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+// runLoadedContext replays the build saved in the given file. The other flags
+// of the compile command can't be used, since they would be ignored.
+func runLoadedContext(cmd *cobra.Command, file *paths.Path) {
+	cmd.LocalFlags().Visit(func(flag *pflag.Flag) {
+		if flag.Name != "load-context" {
+			arguments.CheckFlagsConflicts(cmd, "load-context", flag.Name)
+		}
+	})
+
+	savedCtx, compileRequest, err := loadCompileContext(file)
+	if err != nil {
+		feedback.Fatal(tr("Error reading the compile context %[1]s: %[2]v", file, err), feedback.ErrBadArgument)
+	}
+	inst, _ := instance.CreateAndInitWithProfile(savedCtx.Profile, paths.New(compileRequest.GetSketchPath()))
+	compileRequest.Instance = inst
+
+	stdOut, stdErr, stdIORes := feedback.OutputStreams()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	builderRes, compileError := compile.Compile(ctx, compileRequest, stdOut, stdErr, nil, nil)
+	stop()
+
+	// The build is not reproduced exactly if the environment has changed
+	if replayCtx, err := newCompileContext(compileRequest, savedCtx.Profile, builderRes); err == nil {
+		if dirs := diffStrings(savedCtx.HardwareDirs, replayCtx.HardwareDirs); len(dirs) > 0 {
+			feedback.Warning(tr("The following hardware directories of the saved context are not used: %s", strings.Join(dirs, ", ")))
+		}
+		if dirs := diffStrings(savedCtx.LibrariesDirs, replayCtx.LibrariesDirs); len(dirs) > 0 {
+			feedback.Warning(tr("The following libraries directories of the saved context are not used: %s", strings.Join(dirs, ", ")))
+		}
+		if len(savedCtx.BuildProperties) > 0 {
+			props := []string{}
+			for _, prop := range diffStrings(savedCtx.BuildProperties, replayCtx.BuildProperties) {
+				// The build time changes at each build
+				if !strings.HasPrefix(prop, "extra.time.") {
+					props = append(props, prop)
+				}
+			}
+			if len(props) > 0 {
+				feedback.Warning(tr("The following build properties differ from the saved context: %s", strings.Join(props, ", ")))
+			}
+		}
+	}
+
+	stdIO := stdIORes()
+	res := &compileResult{
+		CompilerOut:   stdIO.Stdout,
+		CompilerErr:   stdIO.Stderr,
+		BuilderResult: result.NewBuilderResult(builderRes),
+		Diagnostics:   result.NewCompileDiagnostics(builderRes.GetDiagnostics()),
+		Success:       compileError == nil,
+	}
+	if compileError != nil {
+		res.Error = tr("Error during build: %v", compileError)
+		feedback.FatalResult(res, feedback.ErrGeneric)
+	}
+	feedback.PrintResult(res)
+}
@@ -0,0 +1,72 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/internal/cli/configuration"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSaveAndLoadCompileContext(t *testing.T) {
+	configuration.Settings = configuration.Init("")
+	file := paths.New(t.TempDir()).Join("context.json")
+	req := &rpc.CompileRequest{
+		Instance:        &rpc.Instance{Id: 1},
+		Fqbn:            "arduino:avr:uno",
+		SketchPath:      "/sketches/Blink",
+		BuildPath:       "/tmp/build",
+		BuildProperties: []string{"build.extra_flags=-DDEBUG"},
+		Warnings:        "all",
+		Library:         []string{"/libraries/Servo"},
+		Verbose:         true,
+	}
+	builderRes := &rpc.BuilderResult{BuildProperties: []string{"build.board=AVR_UNO", "build.extra_flags=-DDEBUG"}}
+	ctx, err := newCompileContext(req, "uno", builderRes)
+	require.NoError(t, err)
+	require.Equal(t, builderRes.GetBuildProperties(), ctx.BuildProperties)
+	require.Contains(t, ctx.LibrariesDirs, "/libraries/Servo")
+	require.NoError(t, saveCompileContext(file, ctx))
+
+	loadedCtx, loaded, err := loadCompileContext(file)
+	require.NoError(t, err)
+	require.Equal(t, "uno", loadedCtx.Profile)
+	require.Equal(t, ctx.HardwareDirs, loadedCtx.HardwareDirs)
+	require.Equal(t, ctx.LibrariesDirs, loadedCtx.LibrariesDirs)
+	require.Equal(t, ctx.BuildProperties, loadedCtx.BuildProperties)
+	// The instance is not saved, a new one is created to replay the build
+	expected := proto.Clone(req).(*rpc.CompileRequest)
+	expected.Instance = nil
+	require.True(t, proto.Equal(expected, loaded))
+	require.NotNil(t, req.GetInstance())
+
+	require.NoError(t, file.WriteFile([]byte(`{"version": "1.0.0"}`)))
+	_, _, err = loadCompileContext(file)
+	require.EqualError(t, err, "missing compile request")
+
+	require.NoError(t, file.WriteFile([]byte(`{"request": {"unknown_field": true}}`)))
+	_, _, err = loadCompileContext(file)
+	require.Error(t, err)
+}
+
+func TestDiffStrings(t *testing.T) {
+	require.Equal(t, []string{"b"}, diffStrings([]string{"a", "b"}, []string{"a", "c"}))
+	require.Empty(t, diffStrings([]string{"a"}, []string{"a", "c"}))
+}
@@ -0,0 +1,161 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/arduino/arduino-cli/internal/arduino/cores"
+	"github.com/arduino/arduino-cli/internal/buildcache"
+	"github.com/arduino/go-paths-helper"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/sirupsen/logrus"
+)
+
+// parseCoreGitURL splits a git URL in the form "url#ref" into the
+// repository URL and the reference (branch, tag or commit) to checkout.
+func parseCoreGitURL(gitURL string) (string, plumbing.Revision, error) {
+	if strings.HasPrefix(gitURL, "git@") || paths.New(gitURL).Exist() {
+		if i := strings.LastIndex(gitURL, "#"); i != -1 {
+			return gitURL[:i], plumbing.Revision(gitURL[i+1:]), nil
+		}
+		return gitURL, "", nil
+	}
+	parsed, err := url.Parse(gitURL)
+	if err != nil || parsed.Host == "" {
+		return "", "", errors.New(tr("invalid git url"))
+	}
+	ref := plumbing.Revision(parsed.Fragment)
+	parsed.Fragment = ""
+	return parsed.String(), ref, nil
+}
+
+// prepareCoreFromGit clones the platform hosted in the given git repository
+// into a hardware folder, using the vendor and architecture of the given
+// FQBN, and returns the path to the hardware folder. Clones are cached by
+// URL and reference in the build cache, so they are purged together with
+// the other cached builds when unused. A cached clone is updated from the
+// repository before being reused, unless forceClone is true in which case
+// it's cloned again.
+func prepareCoreFromGit(gitURL string, fqbnIn string, forceClone bool) (*paths.Path, error) {
+	fqbn, err := cores.ParseFQBN(fqbnIn)
+	if err != nil {
+		return nil, err
+	}
+	repoURL, ref, err := parseCoreGitURL(gitURL)
+	if err != nil {
+		return nil, err
+	}
+
+	md5SumBytes := md5.Sum([]byte(gitURL))
+	key := strings.ToUpper(hex.EncodeToString(md5SumBytes[:]))
+	cacheDir := paths.TempDir().Join("arduino", "cores-from-git")
+	if forceClone {
+		if err := cacheDir.Join(key).RemoveAll(); err != nil {
+			return nil, err
+		}
+	}
+	hardwareDir, err := buildcache.New(cacheDir).GetOrCreate(key)
+	if err != nil {
+		return nil, err
+	}
+	platformDir := hardwareDir.Join(fqbn.Package, fqbn.PlatformArch)
+	if platformDir.IsDir() {
+		logrus.WithField("path", platformDir).Info("Using cached platform clone")
+		if err := updateCoreRepository(platformDir, ref); err != nil {
+			// The cached clone is still usable, for example when offline
+			logrus.WithError(err).Warn("Error updating the cached platform clone")
+		}
+		return hardwareDir, nil
+	}
+
+	if err := platformDir.Parent().MkdirAll(); err != nil {
+		return nil, err
+	}
+	if err := cloneCoreRepository(platformDir, repoURL, ref); err != nil {
+		platformDir.RemoveAll()
+		return nil, err
+	}
+	return hardwareDir, nil
+}
+
+// cloneCoreRepository clones the repository in the given directory and
+// checkouts the given reference, if not empty.
+func cloneCoreRepository(dir *paths.Path, repoURL string, ref plumbing.Revision) error {
+	depth := 1
+	if ref != "" {
+		depth = 0
+	}
+	repo, err := git.PlainClone(dir.String(), false, &git.CloneOptions{
+		URL:   repoURL,
+		Depth: depth,
+	})
+	if err != nil {
+		return err
+	}
+	if ref == "" {
+		return nil
+	}
+	h, err := repo.ResolveRevision(ref)
+	if err != nil {
+		return err
+	}
+	w, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	return w.Checkout(&git.CheckoutOptions{Hash: plumbing.NewHash(h.String())})
+}
+
+// updateCoreRepository fetches the repository cloned in the given directory
+// and checkouts the latest version of the given reference, or of the default
+// branch if the reference is empty.
+func updateCoreRepository(dir *paths.Path, ref plumbing.Revision) error {
+	repo, err := git.PlainOpen(dir.String())
+	if err != nil {
+		return err
+	}
+	w, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	if ref == "" {
+		// The shallow clones report an empty request when already up to date
+		if err := w.Pull(&git.PullOptions{Force: true}); err != nil &&
+			!errors.Is(err, git.NoErrAlreadyUpToDate) && !errors.Is(err, transport.ErrEmptyUploadPackRequest) {
+			return err
+		}
+		return nil
+	}
+	if err := repo.Fetch(&git.FetchOptions{Tags: git.AllTags, Force: true}); err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return err
+	}
+	// A branch must be resolved to the fetched remote branch, since the
+	// local one is not updated by the fetch
+	h, err := repo.ResolveRevision("refs/remotes/origin/" + ref)
+	if err != nil {
+		if h, err = repo.ResolveRevision(ref); err != nil {
+			return err
+		}
+	}
+	return w.Checkout(&git.CheckoutOptions{Hash: *h, Force: true})
+}
@@ -0,0 +1,65 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+)
+
+// initTestRepository creates a git repository in the given directory and
+// returns a function committing a new version of its platform.txt
+func initTestRepository(t *testing.T, dir *paths.Path) func(content string) {
+	repo, err := git.PlainInit(dir.String(), false)
+	require.NoError(t, err)
+	w, err := repo.Worktree()
+	require.NoError(t, err)
+	return func(content string) {
+		require.NoError(t, dir.Join("platform.txt").WriteFile([]byte(content)))
+		_, err := w.Add("platform.txt")
+		require.NoError(t, err)
+		_, err = w.Commit(content, &git.CommitOptions{Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}})
+		require.NoError(t, err)
+	}
+}
+
+func TestUpdateCoreRepository(t *testing.T) {
+	for name, ref := range map[string]plumbing.Revision{"DefaultBranch": "", "Branch": "master"} {
+		t.Run(name, func(t *testing.T) {
+			tmp := paths.New(t.TempDir())
+			origin := tmp.Join("origin")
+			commit := initTestRepository(t, origin)
+			commit("version=1.0.0\n")
+
+			clone := tmp.Join("clone")
+			require.NoError(t, cloneCoreRepository(clone, origin.String(), ref))
+			require.NoError(t, updateCoreRepository(clone, ref))
+
+			// The cached clone follows the changes of the repository
+			commit("version=1.0.1\n")
+			require.NoError(t, updateCoreRepository(clone, ref))
+			data, err := clone.Join("platform.txt").ReadFile()
+			require.NoError(t, err)
+			require.Equal(t, "version=1.0.1\n", string(data))
+		})
+	}
+}
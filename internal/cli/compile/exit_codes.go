@@ -0,0 +1,62 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"strconv"
+
+	"github.com/arduino/arduino-cli/internal/cli/feedback"
+	"github.com/arduino/arduino-cli/internal/cli/feedback/table"
+	"github.com/fatih/color"
+)
+
+// runExplainExitCodes prints the exit codes that may be returned by the
+// command with their meaning.
+func runExplainExitCodes() {
+	res := &exitCodesResult{ExitCodes: []*exitCodeDescription{}}
+	for _, code := range feedback.ExitCodes() {
+		res.ExitCodes = append(res.ExitCodes, &exitCodeDescription{
+			Code:        int(code),
+			Description: code.Description(),
+		})
+	}
+	feedback.PrintResult(res)
+}
+
+type exitCodeDescription struct {
+	Code        int    `json:"code"`
+	Description string `json:"description"`
+}
+
+type exitCodesResult struct {
+	ExitCodes []*exitCodeDescription `json:"exit_codes"`
+}
+
+func (r *exitCodesResult) Data() interface{} {
+	return r
+}
+
+func (r *exitCodesResult) String() string {
+	titleColor := color.New(color.FgHiGreen)
+	t := table.New()
+	t.SetHeader(
+		table.NewCell(tr("Exit code"), titleColor),
+		table.NewCell(tr("Description"), titleColor))
+	for _, c := range r.ExitCodes {
+		t.AddRow(strconv.Itoa(c.Code), c.Description)
+	}
+	return t.Render()
+}
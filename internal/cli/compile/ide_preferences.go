@@ -0,0 +1,71 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+)
+
+// ideBuiltinLibrariesDir returns the folder of the libraries bundled with
+// the Arduino IDE installation recorded in the given IDE preferences file.
+// The IDE records each installation that has been run with the keys
+// "last.ide.<version>.hardwarepath" and "last.ide.<version>.daterun": the
+// most recently run installation is used. Missing or malformed keys are
+// ignored.
+func ideBuiltinLibrariesDir(preferencesFile *paths.Path) (*paths.Path, error) {
+	preferences, err := properties.LoadFromPath(preferencesFile)
+	if err != nil {
+		return nil, fmt.Errorf(tr("reading IDE preferences: %v"), err)
+	}
+
+	var hardwareDir *paths.Path
+	var lastRun int64 = -1
+	for _, key := range preferences.Keys() {
+		// The version contains dots, so the keys are matched as a whole
+		if !strings.HasPrefix(key, "last.ide.") || !strings.HasSuffix(key, ".hardwarepath") {
+			continue
+		}
+		hardwarePath := strings.TrimSpace(preferences.Get(key))
+		if hardwarePath == "" {
+			continue
+		}
+		// A missing or invalid date is older than any valid one
+		dateRunKey := strings.TrimSuffix(key, ".hardwarepath") + ".daterun"
+		dateRun, err := strconv.ParseInt(strings.TrimSpace(preferences.Get(dateRunKey)), 10, 64)
+		if err != nil {
+			dateRun = 0
+		}
+		if dateRun > lastRun {
+			hardwareDir = paths.New(hardwarePath)
+			lastRun = dateRun
+		}
+	}
+	if hardwareDir == nil {
+		return nil, errors.New(tr("no IDE installation found in the IDE preferences"))
+	}
+
+	librariesDir := hardwareDir.Parent().Join("libraries")
+	if !librariesDir.IsDir() {
+		return nil, fmt.Errorf(tr("the libraries folder %s of the IDE installation doesn't exist"), librariesDir)
+	}
+	return librariesDir, nil
+}
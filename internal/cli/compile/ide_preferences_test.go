@@ -0,0 +1,59 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIDEBuiltinLibrariesDir(t *testing.T) {
+	tmp := paths.New(t.TempDir())
+	oldIDE := tmp.Join("arduino-1.8.13")
+	newIDE := tmp.Join("arduino-1.8.19")
+	require.NoError(t, oldIDE.Join("libraries").MkdirAll())
+	require.NoError(t, newIDE.Join("libraries").MkdirAll())
+	preferences := tmp.Join("preferences.txt")
+
+	// The most recently run IDE is used, the malformed entries are ignored
+	require.NoError(t, preferences.WriteFile([]byte(
+		"sketchbook.path=/home/user/Arduino\n"+
+			"last.ide.1.8.13.hardwarepath="+oldIDE.Join("hardware").String()+"\n"+
+			"last.ide.1.8.13.daterun=1600000000000\n"+
+			"last.ide.1.8.19.hardwarepath="+newIDE.Join("hardware").String()+"\n"+
+			"last.ide.1.8.19.daterun=1650000000000\n"+
+			"last.ide.1.6.0.daterun=1700000000000\n"+
+			"last.ide.1.8.5.hardwarepath="+tmp.Join("missing", "hardware").String()+"\n"+
+			"last.ide.1.8.5.daterun=invalid\n")))
+	dir, err := ideBuiltinLibrariesDir(preferences)
+	require.NoError(t, err)
+	require.Equal(t, newIDE.Join("libraries").String(), dir.String())
+
+	// The IDE folder must exist
+	require.NoError(t, preferences.WriteFile([]byte(
+		"last.ide.1.8.5.hardwarepath="+tmp.Join("missing", "hardware").String()+"\n")))
+	_, err = ideBuiltinLibrariesDir(preferences)
+	require.ErrorContains(t, err, "doesn't exist")
+
+	require.NoError(t, preferences.WriteFile([]byte("sketchbook.path=/home/user/Arduino\n")))
+	_, err = ideBuiltinLibrariesDir(preferences)
+	require.ErrorContains(t, err, "no IDE installation found")
+
+	_, err = ideBuiltinLibrariesDir(tmp.Join("missing.txt"))
+	require.Error(t, err)
+}
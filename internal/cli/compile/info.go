@@ -0,0 +1,297 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/arduino/arduino-cli/commands/compile"
+	"github.com/arduino/arduino-cli/commands/core"
+	"github.com/arduino/arduino-cli/commands/lib"
+	"github.com/arduino/arduino-cli/internal/cli/feedback"
+	"github.com/arduino/arduino-cli/internal/cli/feedback/table"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/fatih/color"
+)
+
+var includeRegexp = regexp.MustCompile(`^\s*#[ \t]*include\s*[<"](\S+)[">]`)
+var conditionalRegexp = regexp.MustCompile(`^\s*#[ \t]*(if|ifdef|ifndef|elif|else|endif)\b(.*)$`)
+
+// runInfo prints the requirements needed to compile the sketch (target board,
+// board core and libraries) without running the build.
+func runInfo(inst *rpc.Instance, sk *rpc.LoadSketchResponse, fqbn string) {
+	sketchFiles := []string{sk.GetMainFile()}
+	sketchFiles = append(sketchFiles, sk.GetOtherSketchFiles()...)
+	sketchFiles = append(sketchFiles, sk.GetAdditionalFiles()...)
+
+	// Headers provided by the sketch itself are not library dependencies
+	localHeaders := map[string]bool{}
+	for _, f := range sketchFiles {
+		localHeaders[paths.New(f).Base()] = true
+	}
+
+	includes := []string{}
+	seen := map[string]bool{}
+	for _, f := range sketchFiles {
+		data, err := paths.New(f).ReadFile()
+		if err != nil {
+			feedback.Fatal(tr("Error reading sketch files: %v", err), feedback.ErrGeneric)
+		}
+		for _, include := range sourceIncludes(string(data)) {
+			if seen[include] || localHeaders[paths.New(include).Base()] {
+				continue
+			}
+			seen[include] = true
+			includes = append(includes, include)
+		}
+	}
+	sort.Strings(includes)
+
+	res := &sketchInfoResult{
+		Fqbn:      fqbn,
+		Libraries: []*sketchInfoLibrary{},
+	}
+	// The libraries are resolved as the build would do, this is possible only
+	// if the board platform is installed: otherwise the installed libraries
+	// providing the includes are listed.
+	var resolved map[string]*rpc.Library
+	if fqbn != "" && len(includes) > 0 {
+		var err error
+		resolved, err = compile.ResolveIncludes(context.Background(), &rpc.CompileRequest{
+			Instance:   inst,
+			Fqbn:       fqbn,
+			SketchPath: sk.GetLocationPath(),
+		}, includes)
+		if err != nil {
+			resolved = nil
+		}
+	}
+	if resolved != nil {
+		for _, include := range includes {
+			l, ok := resolved[include]
+			if !ok {
+				// Provided by the core, the variant or the toolchain
+				continue
+			}
+			dep := &sketchInfoLibrary{Include: include}
+			if l != nil {
+				dep.Library = l.GetName()
+				dep.Version = l.GetVersion()
+				dep.Installed = true
+			}
+			res.Libraries = append(res.Libraries, dep)
+		}
+	} else {
+		libs, err := lib.LibraryList(context.Background(), &rpc.LibraryListRequest{
+			Instance: inst,
+			Fqbn:     fqbn,
+			All:      true,
+		})
+		if err != nil {
+			feedback.Fatal(tr("Error listing libraries: %v", err), feedback.ErrGeneric)
+		}
+		for _, include := range includes {
+			dep := &sketchInfoLibrary{Include: include}
+			for _, installed := range libs.GetInstalledLibraries() {
+				l := installed.GetLibrary()
+				if slices.Contains(l.GetProvidesIncludes(), include) {
+					dep.Library = l.GetName()
+					dep.Version = l.GetVersion()
+					dep.Installed = true
+					break
+				}
+			}
+			res.Libraries = append(res.Libraries, dep)
+		}
+	}
+
+	if split := strings.Split(fqbn, ":"); len(split) > 2 {
+		res.Core = split[0] + ":" + split[1]
+		platforms, err := core.PlatformSearch(&rpc.PlatformSearchRequest{
+			Instance:   inst,
+			SearchArgs: res.Core,
+		})
+		if err != nil {
+			feedback.Fatal(tr("Error searching for platforms: %v", err), feedback.ErrGeneric)
+		}
+		for _, platform := range platforms.GetSearchOutput() {
+			if platform.GetMetadata().GetId() == res.Core && platform.GetInstalledVersion() != "" {
+				res.CoreInstalled = true
+				res.CoreVersion = platform.GetInstalledVersion()
+			}
+		}
+	}
+
+	feedback.PrintResult(res)
+}
+
+// sourceIncludes returns the files included by the given source code, in
+// order. The includes in comments and in the sections disabled by an `#if 0`
+// are skipped.
+func sourceIncludes(source string) []string {
+	includes := []string{}
+	// Each entry tells if the corresponding conditional section is disabled
+	// and if it has been disabled by an `#if 0`
+	type section struct{ disabled, ifZero bool }
+	sections := []section{}
+	disabled := func() bool {
+		return len(sections) > 0 && sections[len(sections)-1].disabled
+	}
+	for _, line := range strings.Split(stripComments(source), "\n") {
+		if match := conditionalRegexp.FindStringSubmatch(line); match != nil {
+			switch match[1] {
+			case "if", "ifdef", "ifndef":
+				ifZero := match[1] == "if" && strings.TrimSpace(match[2]) == "0"
+				sections = append(sections, section{disabled: disabled() || ifZero, ifZero: ifZero})
+			case "elif", "else":
+				// Only the alternatives of an `#if 0` are known to be enabled
+				if n := len(sections); n > 0 && sections[n-1].ifZero {
+					parentDisabled := n > 1 && sections[n-2].disabled
+					sections[n-1] = section{disabled: parentDisabled}
+				}
+			case "endif":
+				if n := len(sections); n > 0 {
+					sections = sections[:n-1]
+				}
+			}
+			continue
+		}
+		if disabled() {
+			continue
+		}
+		if match := includeRegexp.FindStringSubmatch(line); match != nil {
+			includes = append(includes, match[1])
+		}
+	}
+	return includes
+}
+
+// stripComments returns the given source code with the comments replaced by
+// spaces. The line breaks are preserved, so the lines are not merged.
+func stripComments(source string) string {
+	var res strings.Builder
+	inLineComment, inBlockComment, inString := false, false, byte(0)
+	for i := 0; i < len(source); i++ {
+		c := source[i]
+		next := byte(0)
+		if i+1 < len(source) {
+			next = source[i+1]
+		}
+		switch {
+		case inLineComment:
+			if c == '\n' {
+				inLineComment = false
+				res.WriteByte(c)
+			}
+		case inBlockComment:
+			if c == '*' && next == '/' {
+				inBlockComment = false
+				res.WriteByte(' ')
+				i++
+			} else if c == '\n' {
+				res.WriteByte(c)
+			}
+		case inString != 0:
+			res.WriteByte(c)
+			if c == '\\' && next != 0 {
+				res.WriteByte(next)
+				i++
+			} else if c == inString || c == '\n' {
+				inString = 0
+			}
+		case c == '/' && next == '/':
+			inLineComment = true
+			i++
+		case c == '/' && next == '*':
+			inBlockComment = true
+			res.WriteByte(' ')
+			i++
+		default:
+			if c == '"' || c == '\'' {
+				inString = c
+			}
+			res.WriteByte(c)
+		}
+	}
+	return res.String()
+}
+
+type sketchInfoLibrary struct {
+	Include   string `json:"include"`
+	Library   string `json:"library,omitempty"`
+	Version   string `json:"version,omitempty"`
+	Installed bool   `json:"installed"`
+}
+
+type sketchInfoResult struct {
+	Fqbn          string               `json:"fqbn,omitempty"`
+	Core          string               `json:"core,omitempty"`
+	CoreVersion   string               `json:"core_version,omitempty"`
+	CoreInstalled bool                 `json:"core_installed"`
+	Libraries     []*sketchInfoLibrary `json:"libraries"`
+}
+
+func (r *sketchInfoResult) Data() interface{} {
+	return r
+}
+
+func (r *sketchInfoResult) String() string {
+	titleColor := color.New(color.FgHiGreen)
+	nameColor := color.New(color.FgHiYellow)
+	missingColor := color.New(color.FgHiRed)
+
+	res := ""
+	if r.Fqbn == "" {
+		res += fmt.Sprintln(tr("FQBN: %s", tr("not specified")))
+	} else {
+		res += fmt.Sprintln(tr("FQBN: %s", r.Fqbn))
+	}
+	if r.Core != "" {
+		if r.CoreInstalled {
+			res += fmt.Sprintln(tr("Core: %[1]s (%[2]s installed)", r.Core, r.CoreVersion))
+		} else {
+			res += fmt.Sprintln(tr("Core: %s (not installed)", r.Core))
+		}
+	}
+
+	if len(r.Libraries) == 0 {
+		res += fmt.Sprintln()
+		res += tr("No library dependencies detected.")
+		return res
+	}
+
+	libraries := table.New()
+	libraries.SetHeader(
+		table.NewCell(tr("Include"), titleColor),
+		table.NewCell(tr("Library"), titleColor),
+		table.NewCell(tr("Version"), titleColor))
+	for _, l := range r.Libraries {
+		if l.Installed {
+			libraries.AddRow(l.Include, table.NewCell(l.Library, nameColor), l.Version)
+		} else {
+			libraries.AddRow(l.Include, table.NewCell(tr("not found"), missingColor), "")
+		}
+	}
+	res += fmt.Sprintln()
+	res += libraries.Render()
+	return res
+}
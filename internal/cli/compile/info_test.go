@@ -0,0 +1,45 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceIncludes(t *testing.T) {
+	source := `#include <Arduino.h>
+#include "Servo.h"
+// #include <Commented.h>
+/* #include <BlockCommented.h>
+#include <StillCommented.h> */
+const char *s = "// not a comment"; #include <NotAnInclude.h>
+#if 0
+#include <Disabled.h>
+#if defined(ARDUINO)
+#include <NestedDisabled.h>
+#endif
+#else
+#include <Enabled.h>
+#endif
+#ifdef ARDUINO_ARCH_AVR
+#include <avr/io.h>
+#endif
+  #  include <Wire.h> // trailing comment
+`
+	require.Equal(t, []string{"Arduino.h", "Servo.h", "Enabled.h", "avr/io.h", "Wire.h"}, sourceIncludes(source))
+}
@@ -0,0 +1,123 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+// inlineConfig is the build specification given with the --config-inline flag
+type inlineConfig struct {
+	Fqbn             string   `json:"fqbn"`
+	Libraries        []string `json:"libraries"`
+	Library          []string `json:"library"`
+	BuildProperties  []string `json:"build_properties"`
+	ExtraFlags       []string `json:"extra_flags"`
+	Defines          []string `json:"defines"`
+	Warnings         string   `json:"warnings"`
+	OptimizeForDebug *bool    `json:"optimize_for_debug"`
+	BuildPath        string   `json:"build_path"`
+	OutputDir        string   `json:"output_dir"`
+	OutputFormats    []string `json:"output_formats"`
+}
+
+// parseInlineConfig parses and validates a build specification given as a
+// JSON or YAML document. The returned error names the invalid field.
+func parseInlineConfig(data string) (*inlineConfig, error) {
+	jsonData, err := yaml.YAMLToJSON([]byte(data))
+	if err != nil {
+		return nil, fmt.Errorf(tr("invalid syntax: %v"), err)
+	}
+	config := &inlineConfig{}
+	if string(jsonData) == "null" {
+		return config, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(config); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			if typeErr.Field == "" {
+				return nil, errors.New(tr("the configuration must be an object"))
+			}
+			return nil, fmt.Errorf(tr("invalid field '%[1]s': expected %[2]s, got %[3]s"), typeErr.Field, inlineConfigTypeName(typeErr.Type), typeErr.Value)
+		}
+		if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			return nil, fmt.Errorf(tr("unknown field %[1]s"), field)
+		}
+		return nil, err
+	}
+
+	if config.Warnings != "" && !slices.Contains([]string{"none", "default", "more", "all"}, config.Warnings) {
+		return nil, fmt.Errorf(tr("invalid field '%[1]s': '%[2]s' is not one of %[3]s"), "warnings", config.Warnings, "none, default, more, all")
+	}
+	for _, prop := range config.BuildProperties {
+		if !strings.Contains(prop, "=") {
+			return nil, fmt.Errorf(tr("invalid field '%[1]s': '%[2]s' is not in the form key=value"), "build_properties", prop)
+		}
+	}
+	return config, nil
+}
+
+// inlineConfigTypeName returns a description of the expected type of a field
+func inlineConfigTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return tr("a string")
+	case reflect.Bool:
+		return tr("a boolean")
+	case reflect.Slice:
+		return tr("a list of strings")
+	}
+	return t.String()
+}
+
+// apply sets the compile flags from the configuration. The flags given
+// explicitly on the command line take precedence over the configuration, the
+// configuration lists are prepended to the ones given on the command line.
+func (c *inlineConfig) apply(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	if c.Fqbn != "" && !flags.Changed("fqbn") {
+		fqbnArg.Set(c.Fqbn)
+	}
+	if c.Warnings != "" && !flags.Changed("warnings") {
+		warnings = c.Warnings
+	}
+	if c.OptimizeForDebug != nil && !flags.Changed("optimize-for-debug") {
+		optimizeForDebug = *c.OptimizeForDebug
+	}
+	if c.BuildPath != "" && !flags.Changed("build-path") {
+		buildPath = c.BuildPath
+	}
+	if c.OutputDir != "" && !flags.Changed("output-dir") {
+		exportDir = c.OutputDir
+	}
+	libraries = append(c.Libraries, libraries...)
+	library = append(c.Library, library...)
+	buildProperties = append(c.BuildProperties, buildProperties...)
+	extraFlags = append(c.ExtraFlags, extraFlags...)
+	defines = append(c.Defines, defines...)
+	outputFormats = append(c.OutputFormats, outputFormats...)
+}
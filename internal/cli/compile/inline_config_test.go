@@ -0,0 +1,58 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInlineConfig(t *testing.T) {
+	config, err := parseInlineConfig(`{"fqbn": "arduino:avr:uno", "libraries": ["/tmp/libs"], "optimize_for_debug": true}`)
+	require.NoError(t, err)
+	require.Equal(t, "arduino:avr:uno", config.Fqbn)
+	require.Equal(t, []string{"/tmp/libs"}, config.Libraries)
+	require.True(t, *config.OptimizeForDebug)
+
+	config, err = parseInlineConfig("fqbn: arduino:avr:uno\nbuild_properties:\n  - build.extra_flags=-DFOO\nwarnings: all\n")
+	require.NoError(t, err)
+	require.Equal(t, "arduino:avr:uno", config.Fqbn)
+	require.Equal(t, []string{"build.extra_flags=-DFOO"}, config.BuildProperties)
+	require.Equal(t, "all", config.Warnings)
+	require.Nil(t, config.OptimizeForDebug)
+
+	_, err = parseInlineConfig(`{"fqbn": 3}`)
+	require.EqualError(t, err, "invalid field 'fqbn': expected a string, got number")
+
+	_, err = parseInlineConfig(`{"libraries": "/tmp/libs"}`)
+	require.EqualError(t, err, "invalid field 'libraries': expected a list of strings, got string")
+
+	_, err = parseInlineConfig(`{"fqnb": "arduino:avr:uno"}`)
+	require.EqualError(t, err, `unknown field "fqnb"`)
+
+	_, err = parseInlineConfig(`["arduino:avr:uno"]`)
+	require.EqualError(t, err, "the configuration must be an object")
+
+	_, err = parseInlineConfig(`{"warnings": "lots"}`)
+	require.EqualError(t, err, "invalid field 'warnings': 'lots' is not one of none, default, more, all")
+
+	_, err = parseInlineConfig(`{"build_properties": ["build.extra_flags"]}`)
+	require.EqualError(t, err, "invalid field 'build_properties': 'build.extra_flags' is not in the form key=value")
+
+	_, err = parseInlineConfig("fqbn: [")
+	require.ErrorContains(t, err, "invalid syntax")
+}
@@ -0,0 +1,165 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/arduino/arduino-cli/internal/cli/feedback/result"
+	"github.com/arduino/go-paths-helper"
+)
+
+// mapAnalysisMaxSymbols is the number of symbols reported by the map analysis.
+const mapAnalysisMaxSymbols = 20
+
+// mapAnalysis is the summary of the linker map file produced by a build.
+type mapAnalysis struct {
+	MapFile        string        `json:"map_file"`
+	Sections       []*mapSection `json:"sections"`
+	LargestSymbols []*mapSymbol  `json:"largest_symbols"`
+}
+
+// mapSection is an output section of the linked executable.
+type mapSection struct {
+	Name string `json:"name"`
+	Size uint64 `json:"size"`
+}
+
+// mapSymbol is an input section placed in the linked executable, named after
+// the first symbol it defines if any.
+type mapSymbol struct {
+	Name    string `json:"name"`
+	Section string `json:"section"`
+	Size    uint64 `json:"size"`
+	Object  string `json:"object,omitempty"`
+}
+
+var (
+	// ".text           0x00000000      0x5a4" or ".text.loop      0x000000a8        0x2 loop.o"
+	mapSectionRegexp = regexp.MustCompile(`^(\s?)(\.\S+)(?:\s+0x([0-9a-fA-F]+)\s+0x([0-9a-fA-F]+)(?:\s+(.+))?)?\s*$`)
+	// "                0x000000a8        0x2 loop.o", continuing a section name on the previous line
+	mapSectionContinuationRegexp = regexp.MustCompile(`^\s+0x([0-9a-fA-F]+)\s+0x([0-9a-fA-F]+)(?:\s+(.+))?\s*$`)
+	// "                0x000000a8                loop"
+	mapSymbolRegexp = regexp.MustCompile(`^\s+0x([0-9a-fA-F]+)\s+([A-Za-z_.$][^\s=]*)\s*$`)
+)
+
+// analyzeMapFile parses the given GNU ld map file and returns the size of
+// the output sections and the largest input sections placed in them.
+// The debugging sections are not reported since they're not loaded on the board.
+func analyzeMapFile(mapFile *paths.Path) (*mapAnalysis, error) {
+	data, err := mapFile.ReadFile()
+	if err != nil {
+		return nil, err
+	}
+	analysis := &mapAnalysis{
+		MapFile:        mapFile.String(),
+		Sections:       []*mapSection{},
+		LargestSymbols: []*mapSymbol{},
+	}
+
+	var outputSection string
+	var pendingName, pendingIndent string
+	var lastSymbol *mapSymbol
+	addSection := func(indent, name, size, object string) {
+		sizeValue, _ := strconv.ParseUint(size, 16, 64)
+		lastSymbol = nil
+		if indent == "" {
+			outputSection = name
+			if sizeValue > 0 && !isDebugSection(name) {
+				analysis.Sections = append(analysis.Sections, &mapSection{Name: name, Size: sizeValue})
+			}
+			return
+		}
+		if sizeValue == 0 || outputSection == "" || isDebugSection(outputSection) {
+			return
+		}
+		lastSymbol = &mapSymbol{Name: name, Section: outputSection, Size: sizeValue, Object: strings.TrimSpace(object)}
+		analysis.LargestSymbols = append(analysis.LargestSymbols, lastSymbol)
+	}
+
+	inMemoryMap := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if !inMemoryMap {
+			inMemoryMap = strings.HasPrefix(line, "Linker script and memory map")
+			continue
+		}
+		if pendingName != "" {
+			name, indent := pendingName, pendingIndent
+			pendingName = ""
+			if m := mapSectionContinuationRegexp.FindStringSubmatch(line); m != nil {
+				addSection(indent, name, m[2], m[3])
+				continue
+			}
+		}
+		if m := mapSectionRegexp.FindStringSubmatch(line); m != nil {
+			if m[4] == "" {
+				// The section name is too long, the address and size follow on the next line
+				pendingName, pendingIndent = m[2], m[1]
+				continue
+			}
+			addSection(m[1], m[2], m[4], m[5])
+			continue
+		}
+		if m := mapSymbolRegexp.FindStringSubmatch(line); m != nil && lastSymbol != nil {
+			// Name the input section after the first symbol defined in it
+			if strings.HasPrefix(lastSymbol.Name, ".") {
+				lastSymbol.Name = m[2]
+			}
+			continue
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(analysis.LargestSymbols, func(i, j int) bool {
+		return analysis.LargestSymbols[i].Size > analysis.LargestSymbols[j].Size
+	})
+	if len(analysis.LargestSymbols) > mapAnalysisMaxSymbols {
+		analysis.LargestSymbols = analysis.LargestSymbols[:mapAnalysisMaxSymbols]
+	}
+	return analysis, nil
+}
+
+// isDebugSection returns true if the given output section only contains
+// debugging information or comments.
+func isDebugSection(name string) bool {
+	for _, prefix := range []string{".debug", ".stab", ".comment", ".note", ".ARM.attributes"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// findMapFile returns the linker map file among the artifacts of the given
+// build, or nil if the build didn't generate it.
+func findMapFile(build *result.BuilderResult) *paths.Path {
+	for _, artifact := range build.Artifacts {
+		if artifact.Type == "map" {
+			return paths.New(artifact.Path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+const testMapFile = `Archive member included to satisfy reference by file (symbol)
+
+Memory Configuration
+
+Name             Origin             Length             Attributes
+text             0x00000000         0x00020000         xr
+
+Linker script and memory map
+
+LOAD /tmp/build/sketch/Blink.ino.cpp.o
+
+.text           0x00000000      0x5a4
+ *(.vectors)
+ .vectors       0x00000000       0x68 /tools/avr/lib/crtatmega328p.o
+                0x00000000                __vectors
+ *fill*         0x00000068        0x2 
+ .text.setup    0x0000006a       0x20 /tmp/build/sketch/Blink.ino.cpp.o
+                0x0000006a                setup
+ .text.a_very_long_function_name
+                0x0000008a      0x400 /tmp/build/sketch/Blink.ino.cpp.o
+                0x0000008a                a_very_long_function_name
+                0x0000048a                . = ALIGN (0x2)
+
+.data           0x00800100       0x12 load address 0x000005a4
+                0x00800100                PROVIDE (__data_start, .)
+ .data          0x00800100       0x12 /tmp/build/core/core.a(HardwareSerial0.cpp.o)
+
+.bss            0x00800112        0x0
+
+.debug_info     0x00000000     0x1000
+ .debug_info    0x00000000     0x1000 /tmp/build/sketch/Blink.ino.cpp.o
+`
+
+func TestAnalyzeMapFile(t *testing.T) {
+	mapFile := paths.New(t.TempDir()).Join("Blink.ino.map")
+	require.NoError(t, mapFile.WriteFile([]byte(testMapFile)))
+
+	analysis, err := analyzeMapFile(mapFile)
+	require.NoError(t, err)
+	require.Equal(t, mapFile.String(), analysis.MapFile)
+	require.Equal(t, []*mapSection{
+		{Name: ".text", Size: 0x5a4},
+		{Name: ".data", Size: 0x12},
+	}, analysis.Sections)
+	require.Equal(t, []*mapSymbol{
+		{Name: "a_very_long_function_name", Section: ".text", Size: 0x400, Object: "/tmp/build/sketch/Blink.ino.cpp.o"},
+		{Name: "__vectors", Section: ".text", Size: 0x68, Object: "/tools/avr/lib/crtatmega328p.o"},
+		{Name: "setup", Section: ".text", Size: 0x20, Object: "/tmp/build/sketch/Blink.ino.cpp.o"},
+		{Name: ".data", Section: ".data", Size: 0x12, Object: "/tmp/build/core/core.a(HardwareSerial0.cpp.o)"},
+	}, analysis.LargestSymbols)
+
+	_, err = analyzeMapFile(mapFile.Parent().Join("missing.map"))
+	require.Error(t, err)
+}
@@ -0,0 +1,140 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/arduino/arduino-cli/commands/compile"
+	"github.com/arduino/arduino-cli/internal/cli/feedback"
+	"github.com/arduino/arduino-cli/internal/cli/feedback/table"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/fatih/color"
+	"google.golang.org/protobuf/proto"
+)
+
+// getMatrixFQBNs returns the list of FQBNs given with the --matrix and
+// --matrix-file flags.
+func getMatrixFQBNs() []string {
+	fqbns := append([]string{}, matrix...)
+	if matrixFile != "" {
+		data, err := paths.New(matrixFile).ReadFile()
+		if err != nil {
+			feedback.Fatal(tr("Error reading FQBNs list file: %v", err), feedback.ErrBadArgument)
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			fqbns = append(fqbns, line)
+		}
+	}
+	return fqbns
+}
+
+// runMatrix compiles the sketch for each one of the given FQBNs and prints
+// a summary of the results.
+func runMatrix(compileRequest *rpc.CompileRequest, fqbns []string) {
+	res := &matrixResult{Results: []*matrixEntry{}}
+	for _, fqbn := range fqbns {
+		req := proto.Clone(compileRequest).(*rpc.CompileRequest)
+		req.Fqbn = fqbn
+		stdOut, stdErr := &bytes.Buffer{}, &bytes.Buffer{}
+		builderRes, err := compile.Compile(context.Background(), req, stdOut, stdErr, nil, nil)
+		entry := &matrixEntry{Fqbn: fqbn, Success: err == nil}
+		if err != nil {
+			entry.Error = firstCompileError(builderRes, stdErr.String(), err)
+		}
+		res.Results = append(res.Results, entry)
+	}
+
+	for _, entry := range res.Results {
+		if !entry.Success {
+			feedback.FatalResult(res, feedback.ErrGeneric)
+		}
+	}
+	feedback.PrintResult(res)
+}
+
+// firstCompileError returns the first line describing why the build failed.
+func firstCompileError(builderRes *rpc.BuilderResult, compilerErr string, err error) string {
+	for _, diag := range builderRes.GetDiagnostics() {
+		if diag.GetSeverity() == "ERROR" || diag.GetSeverity() == "FATAL" {
+			if diag.GetFile() == "" {
+				return diag.GetMessage()
+			}
+			return fmt.Sprintf("%s:%d:%d: %s", paths.New(diag.GetFile()).Base(), diag.GetLine(), diag.GetColumn(), diag.GetMessage())
+		}
+	}
+	for _, line := range strings.Split(compilerErr, "\n") {
+		if strings.Contains(strings.ToLower(line), "error") {
+			return strings.TrimSpace(line)
+		}
+	}
+	return strings.SplitN(err.Error(), "\n", 2)[0]
+}
+
+type matrixEntry struct {
+	Fqbn    string `json:"fqbn"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+type matrixResult struct {
+	Results []*matrixEntry `json:"results"`
+}
+
+func (r *matrixResult) Data() interface{} {
+	return r
+}
+
+func (r *matrixResult) String() string {
+	titleColor := color.New(color.FgHiGreen)
+	passColor := color.New(color.FgHiGreen)
+	failColor := color.New(color.FgHiRed)
+
+	t := table.New()
+	t.SetHeader(
+		table.NewCell(tr("FQBN"), titleColor),
+		table.NewCell(tr("Result"), titleColor),
+		table.NewCell(tr("Error"), titleColor))
+	for _, entry := range r.Results {
+		if entry.Success {
+			t.AddRow(entry.Fqbn, table.NewCell(tr("pass"), passColor), "")
+		} else {
+			t.AddRow(entry.Fqbn, table.NewCell(tr("fail"), failColor), entry.Error)
+		}
+	}
+	return t.Render()
+}
+
+func (r *matrixResult) ErrorString() string {
+	failed := 0
+	for _, entry := range r.Results {
+		if !entry.Success {
+			failed++
+		}
+	}
+	if failed == 0 {
+		return ""
+	}
+	return tr("Compilation failed for %[1]d of %[2]d boards", failed, len(r.Results))
+}
@@ -0,0 +1,158 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/arduino/arduino-cli/internal/cli/feedback/result"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+)
+
+// progressEvent is the common part of all the events emitted on the progress
+// stream, the type identifies the kind of event.
+type progressEvent struct {
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+}
+
+func newProgressEvent(eventType string) progressEvent {
+	return progressEvent{Type: eventType, Time: time.Now()}
+}
+
+// phaseEvent is emitted when a new phase of the build starts.
+type phaseEvent struct {
+	progressEvent
+	Phase string `json:"phase"`
+}
+
+// commandEvent is emitted before running each command of the build.
+type commandEvent struct {
+	progressEvent
+	Command []string `json:"command"`
+}
+
+// percentEvent is emitted when the overall progress of the build changes.
+type percentEvent struct {
+	progressEvent
+	Percent   float32 `json:"percent"`
+	Completed bool    `json:"completed"`
+}
+
+// fileCompiledEvent is emitted when a source file has been compiled.
+type fileCompiledEvent struct {
+	progressEvent
+	Phase string `json:"phase,omitempty"`
+	File  string `json:"file"`
+}
+
+// diagnosticEvent is emitted for each warning or error reported by the compiler.
+type diagnosticEvent struct {
+	progressEvent
+	*result.CompileDiagnostic
+}
+
+// doneEvent is emitted when the build is finished.
+type doneEvent struct {
+	progressEvent
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// progressStream emits the progress events of the build as newline-delimited
+// JSON, one event per line. It observes the build as a compile.Observer.
+type progressStream struct {
+	lock  sync.Mutex
+	out   io.Writer
+	close func() error
+}
+
+// openProgressStream opens the progress stream on stderr or on the file with
+// the given path. The stream is never written on stdout, where it would be
+// mixed with the output of the command.
+func openProgressStream(target string) (*progressStream, error) {
+	if target == "stderr" {
+		return &progressStream{out: os.Stderr, close: func() error { return nil }}, nil
+	}
+	file, err := os.Create(target)
+	if err != nil {
+		return nil, err
+	}
+	return &progressStream{out: file, close: file.Close}, nil
+}
+
+func (s *progressStream) emit(event interface{}) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.out.Write(append(data, '\n'))
+}
+
+// taskProgressCB converts the progress of the build into events, the phases
+// are notified to the observer.
+func (s *progressStream) taskProgressCB(progress *rpc.TaskProgress) {
+	switch {
+	case progress.GetMessage() != "":
+		s.emit(&fileCompiledEvent{
+			progressEvent: newProgressEvent("file_compiled"),
+			Phase:         progress.GetName(),
+			File:          progress.GetMessage(),
+		})
+	case progress.GetName() == "":
+		s.emit(&percentEvent{
+			progressEvent: newProgressEvent("progress"),
+			Percent:       progress.GetPercent(),
+			Completed:     progress.GetCompleted(),
+		})
+	}
+}
+
+// OnPhase implements compile.Observer
+func (s *progressStream) OnPhase(phase string) {
+	s.emit(&phaseEvent{progressEvent: newProgressEvent("phase"), Phase: phase})
+}
+
+// OnCommand implements compile.Observer
+func (s *progressStream) OnCommand(commandArgs []string) {
+	s.emit(&commandEvent{progressEvent: newProgressEvent("command"), Command: commandArgs})
+}
+
+// OnDiagnostic implements compile.Observer
+func (s *progressStream) OnDiagnostic(diagnostic *rpc.CompileDiagnostic) {
+	d := result.NewCompileDiagnostic(diagnostic)
+	switch d.Severity {
+	case "WARNING":
+		s.emit(&diagnosticEvent{progressEvent: newProgressEvent("warning"), CompileDiagnostic: d})
+	case "ERROR", "FATAL":
+		s.emit(&diagnosticEvent{progressEvent: newProgressEvent("error"), CompileDiagnostic: d})
+	}
+}
+
+// OnComplete implements compile.Observer
+func (s *progressStream) OnComplete(_ *rpc.BuilderResult, buildErr error) {
+	event := &doneEvent{progressEvent: newProgressEvent("done"), Success: buildErr == nil}
+	if buildErr != nil {
+		event.Error = buildErr.Error()
+	}
+	s.emit(event)
+}
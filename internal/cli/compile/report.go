@@ -0,0 +1,113 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/arduino/arduino-cli/internal/cli/feedback/result"
+	"github.com/arduino/go-paths-helper"
+	"sigs.k8s.io/yaml"
+)
+
+// buildReport is the complete report of a build written with --report-file
+type buildReport struct {
+	Fqbn                   string                             `json:"fqbn"`
+	Sketch                 string                             `json:"sketch"`
+	Success                bool                               `json:"success"`
+	Error                  string                             `json:"error,omitempty"`
+	StartedAt              time.Time                          `json:"started_at"`
+	DurationSeconds        float64                            `json:"duration_seconds"`
+	BuildPath              string                             `json:"build_path,omitempty"`
+	BoardPlatform          *result.InstalledPlatformReference `json:"board_platform,omitempty"`
+	BuildPlatform          *result.InstalledPlatformReference `json:"build_platform,omitempty"`
+	Tools                  []string                           `json:"tools"`
+	Libraries              []*buildReportLibrary              `json:"libraries"`
+	ExecutableSectionsSize []*result.ExecutableSectionSize    `json:"executable_sections_size"`
+	Artifacts              []*result.BuildArtifact            `json:"artifacts"`
+	Warnings               []*result.CompileDiagnostic        `json:"warnings"`
+}
+
+type buildReportLibrary struct {
+	Name       string                 `json:"name"`
+	Version    string                 `json:"version,omitempty"`
+	Location   result.LibraryLocation `json:"location,omitempty"`
+	InstallDir string                 `json:"install_dir,omitempty"`
+}
+
+// newBuildReport creates the report of the build of the given sketch
+func newBuildReport(fqbn, sketchPath string, build *result.BuilderResult, startedAt time.Time, buildErr error) *buildReport {
+	report := &buildReport{
+		Fqbn:                   fqbn,
+		Sketch:                 sketchPath,
+		Success:                buildErr == nil,
+		StartedAt:              startedAt,
+		DurationSeconds:        time.Since(startedAt).Seconds(),
+		Tools:                  []string{},
+		Libraries:              []*buildReportLibrary{},
+		ExecutableSectionsSize: []*result.ExecutableSectionSize{},
+		Artifacts:              []*result.BuildArtifact{},
+		Warnings:               []*result.CompileDiagnostic{},
+	}
+	if buildErr != nil {
+		report.Error = buildErr.Error()
+	}
+	if build == nil {
+		return report
+	}
+	report.BuildPath = build.BuildPath
+	report.BoardPlatform = build.BoardPlatform
+	report.BuildPlatform = build.BuildPlatform
+	report.Tools = append(report.Tools, build.UsedTools...)
+	for _, lib := range build.UsedLibraries {
+		report.Libraries = append(report.Libraries, &buildReportLibrary{
+			Name:       lib.Name,
+			Version:    lib.Version,
+			Location:   lib.Location,
+			InstallDir: lib.InstallDir,
+		})
+	}
+	report.ExecutableSectionsSize = append(report.ExecutableSectionsSize, build.ExecutableSectionsSize...)
+	report.Artifacts = append(report.Artifacts, build.Artifacts...)
+	for _, d := range build.Diagnostics {
+		if d.Severity == "WARNING" {
+			report.Warnings = append(report.Warnings, d)
+		}
+	}
+	return report
+}
+
+// save writes the report to the given file, in YAML format if the file has
+// a .yaml or .yml extension, in JSON format otherwise.
+func (r *buildReport) save(file *paths.Path) error {
+	var data []byte
+	var err error
+	switch strings.ToLower(file.Ext()) {
+	case ".yaml", ".yml":
+		data, err = yaml.Marshal(r)
+	default:
+		data, err = json.MarshalIndent(r, "", "  ")
+	}
+	if err != nil {
+		return err
+	}
+	if err := file.Parent().MkdirAll(); err != nil {
+		return err
+	}
+	return file.WriteFile(data)
+}
@@ -0,0 +1,90 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/arduino/arduino-cli/internal/cli/configuration"
+	"github.com/arduino/arduino-cli/internal/cli/feedback"
+	"github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
+)
+
+// sketchbookMaxDepth limits the depth of the sketchbook folders searched for
+// a sketch, it also prevents following symlink loops
+const sketchbookMaxDepth = 5
+
+// findSketchbookSketches returns the folders of the sketches with the given
+// name in the sketchbook, like the sketch list of the IDE does. The sketches
+// may be organized in subfolders of the sketchbook, while the hidden folders
+// and the libraries and hardware folders of the sketchbook are skipped.
+func findSketchbookSketches(sketchbook *paths.Path, name string) (paths.PathList, error) {
+	if sketchbook == nil || !sketchbook.IsDir() {
+		return paths.NewPathList(), nil
+	}
+	depth := func(dir *paths.Path) int {
+		rel, err := sketchbook.RelTo(dir)
+		if err != nil {
+			return sketchbookMaxDepth
+		}
+		return strings.Count(filepath.ToSlash(rel.String()), "/") + 1
+	}
+	recursionFilter := func(dir *paths.Path) bool {
+		if strings.HasPrefix(dir.Base(), ".") || depth(dir) >= sketchbookMaxDepth {
+			return false
+		}
+		if dir.Parent().EqualsTo(sketchbook) && (dir.Base() == "libraries" || dir.Base() == "hardware") {
+			return false
+		}
+		return true
+	}
+	isSketch := func(dir *paths.Path) bool {
+		return dir.Base() == name && (dir.Join(name+".ino").Exist() || dir.Join(name+".pde").Exist())
+	}
+	res, err := sketchbook.ReadDirRecursiveFiltered(recursionFilter, paths.FilterDirectories(), isSketch)
+	if err != nil {
+		return nil, err
+	}
+	res.Sort()
+	return res, nil
+}
+
+// resolveSketchbookSketch returns the path of the sketch with the given name
+// in the sketchbook. If no sketch is found the name is returned unchanged,
+// to be used as a path, while if several sketches are found the user must
+// choose one of them giving its path.
+func resolveSketchbookSketch(name string) string {
+	sketches, err := findSketchbookSketches(configuration.UserDir(configuration.Settings), name)
+	if err != nil {
+		logrus.WithError(err).Warn("Error searching the sketch in the sketchbook")
+		return name
+	}
+	switch len(sketches) {
+	case 0:
+		return name
+	case 1:
+		logrus.WithField("path", sketches[0]).Info("Sketch found in the sketchbook")
+		return sketches[0].String()
+	}
+	msg := tr("Multiple sketches named %s found in the sketchbook, please give the path of the sketch to compile:", name)
+	for _, sketch := range sketches {
+		msg += "\n  " + sketch.String()
+	}
+	feedback.Fatal(msg, feedback.ErrBadArgument)
+	return ""
+}
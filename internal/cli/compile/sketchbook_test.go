@@ -0,0 +1,54 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindSketchbookSketches(t *testing.T) {
+	sketchbook := paths.New(t.TempDir())
+	createSketch := func(dir *paths.Path, mainFile string) {
+		require.NoError(t, dir.MkdirAll())
+		require.NoError(t, dir.Join(mainFile).WriteFile([]byte{}))
+	}
+	createSketch(sketchbook.Join("Blink"), "Blink.ino")
+	createSketch(sketchbook.Join("projects", "Blink"), "Blink.ino")
+	createSketch(sketchbook.Join("old", "Fade"), "Fade.pde")
+	createSketch(sketchbook.Join("NotASketch"), "main.ino")
+	// Hidden folders and the libraries of the sketchbook are skipped
+	createSketch(sketchbook.Join(".git", "Fade"), "Fade.ino")
+	createSketch(sketchbook.Join("libraries", "Lib", "examples", "Fade"), "Fade.ino")
+
+	sketches, err := findSketchbookSketches(sketchbook, "Blink")
+	require.NoError(t, err)
+	require.Equal(t, paths.PathList{sketchbook.Join("Blink"), sketchbook.Join("projects", "Blink")}, sketches)
+
+	sketches, err = findSketchbookSketches(sketchbook, "Fade")
+	require.NoError(t, err)
+	require.Equal(t, paths.PathList{sketchbook.Join("old", "Fade")}, sketches)
+
+	sketches, err = findSketchbookSketches(sketchbook, "NotASketch")
+	require.NoError(t, err)
+	require.Empty(t, sketches)
+
+	sketches, err = findSketchbookSketches(nil, "Blink")
+	require.NoError(t, err)
+	require.Empty(t, sketches)
+}
@@ -0,0 +1,55 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/arduino/arduino-cli/internal/cli/feedback/result"
+	"github.com/arduino/go-paths-helper"
+)
+
+// formatWarnings returns the warnings found in the given diagnostics, one
+// per line, in the form file:line:column: message. Only the first line of
+// multi-line messages is kept.
+func formatWarnings(diagnostics []*result.CompileDiagnostic) string {
+	var res strings.Builder
+	for _, d := range diagnostics {
+		if d.Severity != "WARNING" {
+			continue
+		}
+		message, _, _ := strings.Cut(strings.TrimSpace(d.Message), "\n")
+		location := d.File
+		if d.Line > 0 {
+			location += fmt.Sprintf(":%d", d.Line)
+			if d.Column > 0 {
+				location += fmt.Sprintf(":%d", d.Column)
+			}
+		}
+		fmt.Fprintf(&res, "%s: %s\n", location, message)
+	}
+	return res.String()
+}
+
+// writeWarningsFile writes the warnings found in the given diagnostics to
+// file, the file is written (empty) even if there are no warnings.
+func writeWarningsFile(file *paths.Path, diagnostics []*result.CompileDiagnostic) error {
+	if err := file.Parent().MkdirAll(); err != nil {
+		return err
+	}
+	return file.WriteFile([]byte(formatWarnings(diagnostics)))
+}
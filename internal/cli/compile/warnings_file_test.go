@@ -0,0 +1,50 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/internal/cli/feedback/result"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatWarnings(t *testing.T) {
+	diagnostics := []*result.CompileDiagnostic{
+		{Severity: "WARNING", Message: "unused variable 'a' [-Wunused-variable]", File: "/sketch/Sketch.ino", Line: 3, Column: 7},
+		{Severity: "ERROR", Message: "'b' was not declared in this scope", File: "/sketch/Sketch.ino", Line: 4, Column: 3},
+		{Severity: "WARNING", Message: "#warning \"deprecated\"\n   #warning \"deprecated\"\n    ^~~~~~~", File: "/libs/Foo/Foo.h", Line: 2},
+		{Severity: "WARNING", Message: "some linker warning", File: "/build/Sketch.ino.elf"},
+	}
+	require.Equal(t,
+		"/sketch/Sketch.ino:3:7: unused variable 'a' [-Wunused-variable]\n"+
+			"/libs/Foo/Foo.h:2: #warning \"deprecated\"\n"+
+			"/build/Sketch.ino.elf: some linker warning\n",
+		formatWarnings(diagnostics))
+	require.Empty(t, formatWarnings(nil))
+}
+
+func TestWriteWarningsFile(t *testing.T) {
+	file := paths.New(t.TempDir()).Join("reports", "warnings.txt")
+	diagnostics := []*result.CompileDiagnostic{
+		{Severity: "WARNING", Message: "unused variable 'a'", File: "Sketch.ino", Line: 3, Column: 7},
+	}
+	require.NoError(t, writeWarningsFile(file, diagnostics))
+	data, err := file.ReadFile()
+	require.NoError(t, err)
+	require.Equal(t, "Sketch.ino:3:7: unused variable 'a'\n", string(data))
+}
@@ -0,0 +1,182 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/arduino/arduino-cli/commands/compile"
+	"github.com/arduino/arduino-cli/internal/cli/feedback"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+)
+
+const (
+	// watchPollInterval is how often the watched folders are checked for changes.
+	watchPollInterval = 250 * time.Millisecond
+	// watchDebounce is how long the files must stay untouched before starting
+	// a new build, so that a burst of saves triggers a single build.
+	watchDebounce = 500 * time.Millisecond
+)
+
+// runWatch compiles the sketch and then compiles it again every time a file
+// in the watched folders changes, until the command is interrupted.
+func runWatch(compileRequest *rpc.CompileRequest, watched paths.PathList) {
+	stdOut, stdErr, err := feedback.DirectStreams()
+	if err != nil {
+		feedback.Fatal(tr("The %[1]s flag is %[2]v", "--watch", err), feedback.ErrBadArgument)
+	}
+
+	// The build folders may be inside the sketch, the files written there
+	// by the build must not trigger another build.
+	excluded := paths.PathList{paths.New(compileRequest.GetSketchPath()).Join("build")}
+	if buildPath := compileRequest.GetBuildPath(); buildPath != "" {
+		excluded.Add(paths.New(buildPath))
+	}
+	if exportDir := compileRequest.GetExportDir(); exportDir != "" {
+		excluded.Add(paths.New(exportDir))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	snapshot := newWatchSnapshot(watched, excluded)
+	for {
+		builderRes, err := compile.Compile(ctx, compileRequest, stdOut, stdErr, nil, nil)
+		if ctx.Err() != nil {
+			break
+		}
+		if err != nil {
+			feedback.Warning(tr("Error during build: %v", err))
+		}
+		feedback.Print(tr("Build finished with %[1]d errors and %[2]d warnings, watching for changes (press Ctrl-C to exit)...",
+			countErrors(builderRes.GetDiagnostics()), countWarnings(builderRes.GetDiagnostics())))
+
+		changed, next := waitForChanges(ctx, snapshot, watched, excluded)
+		if ctx.Err() != nil {
+			break
+		}
+		feedback.Print(tr("Changed: %s", strings.Join(changed, ", ")))
+		snapshot = next
+	}
+	feedback.Print(tr("Stopped watching for changes."))
+}
+
+// waitForChanges polls the watched folders until they differ from the given
+// snapshot and then stay unchanged for watchDebounce. It returns the files
+// that changed and the new snapshot, or nothing if the context is canceled.
+func waitForChanges(ctx context.Context, snapshot watchSnapshot, watched, excluded paths.PathList) ([]string, watchSnapshot) {
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	last := snapshot
+	var lastChange time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, nil
+		case <-ticker.C:
+		}
+		current := newWatchSnapshot(watched, excluded)
+		if len(last.changes(current)) > 0 {
+			last = current
+			lastChange = time.Now()
+			continue
+		}
+		if !lastChange.IsZero() && time.Since(lastChange) >= watchDebounce {
+			if changed := snapshot.changes(last); len(changed) > 0 {
+				return changed, last
+			}
+			// The files have been restored as they were before
+			lastChange = time.Time{}
+		}
+	}
+}
+
+// watchSnapshot records the modification time and the size of the files
+// in the watched folders.
+type watchSnapshot map[string]watchedFile
+
+type watchedFile struct {
+	modTime time.Time
+	size    int64
+}
+
+// newWatchSnapshot collects the files in the given folders. Hidden files and
+// folders, like the ones created by editors and version control systems, and
+// the excluded folders are ignored. Unreadable folders are skipped.
+func newWatchSnapshot(watched, excluded paths.PathList) watchSnapshot {
+	notHidden := func(p *paths.Path) bool { return !strings.HasPrefix(p.Base(), ".") }
+	notExcluded := func(p *paths.Path) bool {
+		for _, excl := range excluded {
+			if p.EquivalentTo(excl) {
+				return false
+			}
+		}
+		return true
+	}
+
+	snapshot := watchSnapshot{}
+	for _, dir := range watched {
+		files, err := dir.ReadDirRecursiveFiltered(
+			paths.AndFilter(notHidden, notExcluded),
+			paths.FilterOutDirectories(), notHidden)
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			if info, err := file.Stat(); err == nil {
+				snapshot[file.String()] = watchedFile{modTime: info.ModTime(), size: info.Size()}
+			}
+		}
+	}
+	return snapshot
+}
+
+// changes returns the sorted list of files added, removed or modified in
+// the other snapshot.
+func (s watchSnapshot) changes(other watchSnapshot) []string {
+	changed := []string{}
+	for file, info := range s {
+		if otherInfo, ok := other[file]; !ok || !otherInfo.modTime.Equal(info.modTime) || otherInfo.size != info.size {
+			changed = append(changed, file)
+		}
+	}
+	for file := range other {
+		if _, ok := s[file]; !ok {
+			changed = append(changed, file)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// countErrors returns the number of errors in the given diagnostics.
+func countErrors(diagnostics []*rpc.CompileDiagnostic) int {
+	count := 0
+	for _, d := range diagnostics {
+		if d.GetSeverity() == "ERROR" || d.GetSeverity() == "FATAL" {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,63 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchSnapshot(t *testing.T) {
+	sketch := paths.New(t.TempDir())
+	library := paths.New(t.TempDir())
+	write := func(file *paths.Path, data string) {
+		require.NoError(t, file.Parent().MkdirAll())
+		require.NoError(t, file.WriteFile([]byte(data)))
+	}
+	write(sketch.Join("sketch.ino"), "void setup() {}")
+	write(sketch.Join("src", "helper.cpp"), "")
+	write(library.Join("src", "Lib.h"), "")
+	watched := paths.PathList{sketch, library}
+	excluded := paths.PathList{sketch.Join("build")}
+
+	snapshot := newWatchSnapshot(watched, excluded)
+	require.Len(t, snapshot, 3)
+	require.Empty(t, snapshot.changes(newWatchSnapshot(watched, excluded)))
+
+	// Hidden files and the files written in the excluded folders are ignored
+	write(sketch.Join(".sketch.ino.swp"), "")
+	write(sketch.Join(".git", "index"), "")
+	write(sketch.Join("build", "sketch.ino.hex"), "")
+	require.Empty(t, snapshot.changes(newWatchSnapshot(watched, excluded)))
+
+	// Added, modified and removed files are reported
+	write(sketch.Join("sketch.ino"), "void setup() { init(); }")
+	write(library.Join("src", "Lib.cpp"), "")
+	require.NoError(t, sketch.Join("src", "helper.cpp").Remove())
+	require.Equal(t, []string{
+		sketch.Join("sketch.ino").String(),
+		sketch.Join("src", "helper.cpp").String(),
+		library.Join("src", "Lib.cpp").String(),
+	}, snapshot.changes(newWatchSnapshot(watched, excluded)))
+
+	// A new modification time is a change even if the size is the same
+	snapshot = newWatchSnapshot(watched, excluded)
+	require.NoError(t, library.Join("src", "Lib.h").Chtimes(time.Now(), time.Now().Add(time.Hour)))
+	require.Equal(t, []string{library.Join("src", "Lib.h").String()}, snapshot.changes(newWatchSnapshot(watched, excluded)))
+}
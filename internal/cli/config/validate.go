@@ -41,6 +41,7 @@ var validMap = map[string]reflect.Kind{
 	"network.proxy":                 reflect.String,
 	"network.user_agent_ext":        reflect.String,
 	"output.no_color":               reflect.Bool,
+	"output.force_tty":              reflect.Bool,
 	"updater.enable_notification":   reflect.Bool,
 }
 
@@ -54,7 +54,10 @@ func Init(configFile string) *viper.Viper {
 		}
 
 		settings.SetConfigName("arduino-cli")
-		settings.AddConfigPath(configDir)
+		// Without a data directory there is no default config file
+		if configDir != "" {
+			settings.AddConfigPath(configDir)
+		}
 	}
 
 	// Attempt to read config file
@@ -76,14 +79,16 @@ func BindFlags(cmd *cobra.Command, settings *viper.Viper) {
 	settings.BindPFlag("logging.format", cmd.Flag("log-format"))
 	settings.BindPFlag("board_manager.additional_urls", cmd.Flag("additional-urls"))
 	settings.BindPFlag("output.no_color", cmd.Flag("no-color"))
+	settings.BindPFlag("output.force_tty", cmd.Flag("force-tty"))
 }
 
-// getDefaultArduinoDataDir returns the full path to the default arduino folder
+// getDefaultArduinoDataDir returns the full path to the default arduino folder,
+// or the empty string if the user home directory is not available (for
+// example in a minimal container where HOME is not set).
 func getDefaultArduinoDataDir() string {
 	userHomeDir, err := os.UserHomeDir()
 	if err != nil {
-		feedback.Warning(tr("Unable to get user home dir: %v", err))
-		return "."
+		return ""
 	}
 
 	switch runtime.GOOS {
@@ -95,7 +100,7 @@ func getDefaultArduinoDataDir() string {
 		localAppDataPath, err := win32.GetLocalAppDataFolder()
 		if err != nil {
 			feedback.Warning(tr("Unable to get Local App Data Folder: %v", err))
-			return "."
+			return ""
 		}
 		return filepath.Join(localAppDataPath, "Arduino15")
 	default:
@@ -103,12 +108,12 @@ func getDefaultArduinoDataDir() string {
 	}
 }
 
-// getDefaultUserDir returns the full path to the default user folder
+// getDefaultUserDir returns the full path to the default user folder, or the
+// empty string if the user home directory is not available.
 func getDefaultUserDir() string {
 	userHomeDir, err := os.UserHomeDir()
 	if err != nil {
-		feedback.Warning(tr("Unable to get user home dir: %v", err))
-		return "."
+		return ""
 	}
 
 	switch runtime.GOOS {
@@ -120,7 +125,7 @@ func getDefaultUserDir() string {
 		documentsPath, err := win32.GetDocumentsFolder()
 		if err != nil {
 			feedback.Warning(tr("Unable to get Documents Folder: %v", err))
-			return "."
+			return ""
 		}
 		return filepath.Join(documentsPath, "Arduino")
 	default:
@@ -130,7 +135,11 @@ func getDefaultUserDir() string {
 
 // GetDefaultBuiltinLibrariesDir returns the full path to the default builtin libraries dir
 func GetDefaultBuiltinLibrariesDir() string {
-	return filepath.Join(getDefaultArduinoDataDir(), "libraries")
+	dataDir := getDefaultArduinoDataDir()
+	if dataDir == "" {
+		return ""
+	}
+	return filepath.Join(dataDir, "libraries")
 }
 
 // FindConfigFileInArgs returns the config file path using the
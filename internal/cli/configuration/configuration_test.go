@@ -19,8 +19,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
 )
 
@@ -59,6 +61,40 @@ func TestInit(t *testing.T) {
 	require.Equal(t, ":9090", settings.GetString("metrics.addr"))
 }
 
+func TestDirectoriesWithoutHome(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the user home directory is not based on HOME on Windows")
+	}
+	t.Setenv("HOME", "")
+	for _, env := range []string{"ARDUINO_DATA_DIR", "ARDUINO_DOWNLOADS_DIR", "ARDUINO_SKETCHBOOK_DIR"} {
+		t.Setenv(env, "")
+	}
+
+	settings := viper.New()
+	SetDefaults(settings)
+	require.Nil(t, DataDir(settings))
+	require.Nil(t, DownloadsDir(settings))
+	require.Nil(t, PackagesDir(settings))
+	require.Nil(t, LibrariesDir(settings))
+	require.Empty(t, HardwareDirectories(settings))
+	require.EqualError(t, CheckDataDir(settings), "The directories.data directory is not set and the user home directory is not available: set ARDUINO_DATA_DIR or HOME")
+	require.EqualError(t, CheckUserDir(settings), "The directories.user directory is not set and the user home directory is not available: set ARDUINO_SKETCHBOOK_DIR or HOME")
+
+	// The directories set explicitly are used
+	tmp := tmpDirOrDie()
+	defer os.RemoveAll(tmp)
+	t.Setenv("ARDUINO_DATA_DIR", tmp)
+	require.NoError(t, CheckDataDir(settings))
+	require.Equal(t, tmp, DataDir(settings).String())
+	require.Equal(t, filepath.Join(tmp, "staging"), DownloadsDir(settings).String())
+	require.Equal(t, filepath.Join(tmp, "packages"), PackagesDir(settings).String())
+	require.Error(t, CheckUserDir(settings))
+
+	t.Setenv("ARDUINO_SKETCHBOOK_DIR", tmp)
+	require.NoError(t, CheckUserDir(settings))
+	require.Equal(t, filepath.Join(tmp, "libraries"), LibrariesDir(settings).String())
+}
+
 func TestFindConfigFile(t *testing.T) {
 	configFile := FindConfigFileInArgs([]string{"--config-file"})
 	require.Equal(t, "", configFile)
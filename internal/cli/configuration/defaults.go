@@ -35,10 +35,16 @@ func SetDefaults(settings *viper.Viper) {
 	// Boards Manager
 	settings.SetDefault("board_manager.additional_urls", []string{})
 
-	// arduino directories
-	settings.SetDefault("directories.Data", getDefaultArduinoDataDir())
-	settings.SetDefault("directories.Downloads", filepath.Join(getDefaultArduinoDataDir(), "staging"))
-	settings.SetDefault("directories.User", getDefaultUserDir())
+	// arduino directories, the defaults are based on the user home directory
+	// and are not set if it's not available: in that case the directories
+	// must be set explicitly, see DataDir, DownloadsDir and UserDir.
+	if dataDir := getDefaultArduinoDataDir(); dataDir != "" {
+		settings.SetDefault("directories.Data", dataDir)
+		settings.SetDefault("directories.Downloads", filepath.Join(dataDir, "staging"))
+	}
+	if userDir := getDefaultUserDir(); userDir != "" {
+		settings.SetDefault("directories.User", userDir)
+	}
 
 	// Sketch compilation
 	settings.SetDefault("sketch.always_export_binaries", false)
@@ -54,6 +60,7 @@ func SetDefaults(settings *viper.Viper) {
 
 	// output settings
 	settings.SetDefault("output.no_color", false)
+	settings.SetDefault("output.force_tty", false)
 
 	// updater settings
 	settings.SetDefault("updater.enable_notification", true)
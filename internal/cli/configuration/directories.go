@@ -20,25 +20,61 @@ import (
 	"github.com/spf13/viper"
 )
 
+// MissingDirectoryError is returned when a directory is not set and its
+// default location can't be determined because the user home directory is
+// not available.
+type MissingDirectoryError struct {
+	// Setting is the name of the missing directory setting
+	Setting string
+	// EnvVar is the environment variable that can be used to set it
+	EnvVar string
+}
+
+func (e *MissingDirectoryError) Error() string {
+	return tr("The %[1]s directory is not set and the user home directory is not available: set %[2]s or HOME", e.Setting, e.EnvVar)
+}
+
+// CheckDataDir returns a MissingDirectoryError if the data directory is not
+// available.
+func CheckDataDir(settings *viper.Viper) error {
+	if DataDir(settings) == nil {
+		return &MissingDirectoryError{Setting: "directories.data", EnvVar: "ARDUINO_DATA_DIR"}
+	}
+	return nil
+}
+
+// CheckUserDir returns a MissingDirectoryError if the user (sketchbook)
+// directory is not available.
+func CheckUserDir(settings *viper.Viper) error {
+	if UserDir(settings) == nil {
+		return &MissingDirectoryError{Setting: "directories.user", EnvVar: "ARDUINO_SKETCHBOOK_DIR"}
+	}
+	return nil
+}
+
 // HardwareDirectories returns all paths that may contains hardware packages.
 func HardwareDirectories(settings *viper.Viper) paths.PathList {
 	res := paths.PathList{}
 
-	if settings.IsSet("directories.Data") {
-		packagesDir := PackagesDir(Settings)
-		if packagesDir.IsDir() {
-			res.Add(packagesDir)
-		}
+	if packagesDir := PackagesDir(settings); packagesDir != nil && packagesDir.IsDir() {
+		res.Add(packagesDir)
 	}
 
-	if settings.IsSet("directories.User") {
-		skDir := paths.New(settings.GetString("directories.User"))
+	if skDir := UserDir(settings); skDir != nil {
 		hwDir := skDir.Join("hardware")
 		if hwDir.IsDir() {
 			res.Add(hwDir)
 		}
 	}
 
+	// Additional hardware directories set at runtime, for example by the
+	// compile command to use a platform cloned from a git repository.
+	for _, dir := range settings.GetStringSlice("directories.extra_hardware") {
+		if hwDir := paths.New(dir); hwDir.IsDir() {
+			res.Add(hwDir)
+		}
+	}
+
 	return res
 }
 
@@ -49,29 +85,58 @@ func IDEBuiltinLibrariesDir(settings *viper.Viper) *paths.Path {
 }
 
 // LibrariesDir returns the full path to the user directory containing
-// custom libraries
+// custom libraries, or nil if the user directory is not available.
 func LibrariesDir(settings *viper.Viper) *paths.Path {
-	return paths.New(settings.GetString("directories.User")).Join("libraries")
+	userDir := UserDir(settings)
+	if userDir == nil {
+		return nil
+	}
+	return userDir.Join("libraries")
 }
 
-// PackagesDir returns the full path to the packages folder
+// PackagesDir returns the full path to the packages folder, or nil if the
+// data directory is not available.
 func PackagesDir(settings *viper.Viper) *paths.Path {
-	return DataDir(settings).Join("packages")
+	dataDir := DataDir(settings)
+	if dataDir == nil {
+		return nil
+	}
+	return dataDir.Join("packages")
 }
 
 // ProfilesCacheDir returns the full path to the profiles cache directory
 // (it contains all the platforms and libraries used to compile a sketch
-// using profiles)
+// using profiles), or nil if the data directory is not available.
 func ProfilesCacheDir(settings *viper.Viper) *paths.Path {
-	return DataDir(settings).Join("internal")
+	dataDir := DataDir(settings)
+	if dataDir == nil {
+		return nil
+	}
+	return dataDir.Join("internal")
 }
 
-// DataDir returns the full path to the data directory
+// DataDir returns the full path to the data directory, or nil if it's not
+// set and the user home directory is not available.
 func DataDir(settings *viper.Viper) *paths.Path {
 	return paths.New(settings.GetString("directories.Data"))
 }
 
-// DownloadsDir returns the full path to the download cache directory
+// UserDir returns the full path to the user (sketchbook) directory, or nil if
+// it's not set and the user home directory is not available.
+func UserDir(settings *viper.Viper) *paths.Path {
+	return paths.New(settings.GetString("directories.User"))
+}
+
+// DownloadsDir returns the full path to the download cache directory. If it's
+// not set, the "staging" folder inside the data directory is used; nil is
+// returned if the data directory is not available either.
 func DownloadsDir(settings *viper.Viper) *paths.Path {
-	return paths.New(settings.GetString("directories.Downloads"))
+	if downloadsDir := paths.New(settings.GetString("directories.Downloads")); downloadsDir != nil {
+		return downloadsDir
+	}
+	dataDir := DataDir(settings)
+	if dataDir == nil {
+		return nil
+	}
+	return dataDir.Join("staging")
 }
@@ -56,4 +56,54 @@ const (
 
 	// ErrMissingProgrammer is returned when the programmer argument is missing (11)
 	ErrMissingProgrammer
+
+	// ErrInterrupted is returned when the command is interrupted by a
+	// SIGINT or SIGTERM signal (12)
+	ErrInterrupted
 )
+
+// ExitCodes returns all the exit codes that may be returned by the CLI
+func ExitCodes() []ExitCode {
+	return []ExitCode{
+		Success,
+		ErrGeneric,
+		ErrNoConfigFile,
+		ErrNetwork,
+		ErrCoreConfig,
+		ErrBadArgument,
+		ErrFailedToListenToTCPPort,
+		ErrBadTCPPortArgument,
+		ErrInitializingInventory,
+		ErrMissingProgrammer,
+		ErrInterrupted,
+	}
+}
+
+// Description returns a human readable description of the exit code
+func (e ExitCode) Description() string {
+	switch e {
+	case Success:
+		return tr("The command completed successfully.")
+	case ErrGeneric:
+		return tr("Generic error, for example a failed build or upload.")
+	case ErrNoConfigFile:
+		return tr("The configuration file was not found.")
+	case ErrNetwork:
+		return tr("A network error occurred, for example while downloading or cloning.")
+	case ErrCoreConfig:
+		return tr("The CLI configuration is not valid, or a directory vital for the CLI to work can't be created or accessed.")
+	case ErrBadArgument:
+		return tr("The arguments or the flags are not valid, for example a missing FQBN or an invalid file given to a flag.")
+	case ErrFailedToListenToTCPPort:
+		return tr("The daemon failed to listen on the TCP port.")
+	case ErrBadTCPPortArgument:
+		return tr("The TCP port argument of the daemon is not valid.")
+	case ErrInitializingInventory:
+		return tr("The inventory can't be initialized, usually because of a wrong configuration of the data directory.")
+	case ErrMissingProgrammer:
+		return tr("The upload requires a programmer that has not been selected.")
+	case ErrInterrupted:
+		return tr("The command was interrupted by a SIGINT or SIGTERM signal.")
+	}
+	return ""
+}
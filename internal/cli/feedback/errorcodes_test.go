@@ -0,0 +1,35 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2024 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package feedback
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExitCodes(t *testing.T) {
+	codes := ExitCodes()
+	require.Equal(t, Success, codes[0])
+	require.Contains(t, codes, ErrBadArgument)
+	require.Contains(t, codes, ErrInterrupted)
+	for _, code := range codes {
+		require.NotEmpty(t, code.Description(), "exit code %d", code)
+	}
+	require.Equal(t, ExitCode(7), ErrBadArgument)
+	require.Equal(t, ExitCode(12), ErrInterrupted)
+	require.Empty(t, ExitCode(2).Description())
+}
@@ -76,6 +76,7 @@ var (
 	bufferWarnings []string
 	format         OutputFormat
 	formatSelected bool
+	exitHooks      []func()
 )
 
 func init() {
@@ -93,6 +94,7 @@ func reset() {
 	bufferWarnings = nil
 	format = Text
 	formatSelected = false
+	exitHooks = nil
 }
 
 // Result is anything more complex than a sentence that needs to be printed
@@ -178,14 +180,14 @@ func FatalError(err error, exitCode ExitCode) {
 // FatalResult outputs the result and exits with status exitCode.
 func FatalResult(res ErrorResult, exitCode ExitCode) {
 	PrintResult(res)
-	os.Exit(int(exitCode))
+	exit(exitCode)
 }
 
 // Fatal outputs the errorMsg and exits with status exitCode.
 func Fatal(errorMsg string, exitCode ExitCode) {
 	if format == Text {
 		fmt.Fprintln(stdErr, errorMsg)
-		os.Exit(int(exitCode))
+		exit(exitCode)
 	}
 
 	type FatalError struct {
@@ -210,6 +212,27 @@ func Fatal(errorMsg string, exitCode ExitCode) {
 		panic("unknown output format")
 	}
 	fmt.Fprintln(stdErr, string(d))
+	exit(exitCode)
+}
+
+// OnExit registers a function to run before Fatal, FatalError or FatalResult
+// exit the process, since the exit skips the deferred calls. The functions run
+// in the reverse order of registration.
+func OnExit(f func()) {
+	exitHooks = append(exitHooks, f)
+}
+
+// runExitHooks runs and unregisters the functions registered with OnExit.
+func runExitHooks() {
+	hooks := exitHooks
+	exitHooks = nil
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hooks[i]()
+	}
+}
+
+func exit(exitCode ExitCode) {
+	runExitHooks()
 	os.Exit(int(exitCode))
 }
 
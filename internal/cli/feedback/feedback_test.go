@@ -20,7 +20,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	"github.com/stretchr/testify/require"
 )
 
@@ -121,3 +123,39 @@ func (r *testResult) String() string {
 	}
 	return "Failure"
 }
+
+func TestDownloadProgressLines(t *testing.T) {
+	reset()
+
+	myOut := new(bytes.Buffer)
+	SetOut(myOut)
+	SetFormat(Text)
+
+	progress := NewDownloadProgressLinesCB(0)
+	progress(&rpc.DownloadProgress{Message: &rpc.DownloadProgress_Start{Start: &rpc.DownloadProgressStart{Label: "tool.zip"}}})
+	progress(&rpc.DownloadProgress{Message: &rpc.DownloadProgress_Update{Update: &rpc.DownloadProgressUpdate{Downloaded: 50, TotalSize: 200}}})
+	progress(&rpc.DownloadProgress{Message: &rpc.DownloadProgress_End{End: &rpc.DownloadProgressEnd{Success: true}}})
+	require.Equal(t, "tool.zip 25% (50 of 200 bytes)\ntool.zip downloaded\n", myOut.String())
+
+	// Updates within the interval are not printed
+	myOut.Reset()
+	progress = NewDownloadProgressLinesCB(time.Hour)
+	progress(&rpc.DownloadProgress{Message: &rpc.DownloadProgress_Start{Start: &rpc.DownloadProgressStart{Label: "tool.zip"}}})
+	progress(&rpc.DownloadProgress{Message: &rpc.DownloadProgress_Update{Update: &rpc.DownloadProgressUpdate{Downloaded: 50, TotalSize: 200}}})
+	progress(&rpc.DownloadProgress{Message: &rpc.DownloadProgress_End{End: &rpc.DownloadProgressEnd{Success: true}}})
+	require.Equal(t, "tool.zip downloaded\n", myOut.String())
+}
+
+func TestExitHooks(t *testing.T) {
+	reset()
+
+	calls := []string{}
+	OnExit(func() { calls = append(calls, "first") })
+	OnExit(func() { calls = append(calls, "second") })
+	runExitHooks()
+	require.Equal(t, []string{"second", "first"}, calls)
+
+	// The hooks run only once
+	runExitHooks()
+	require.Equal(t, []string{"second", "first"}, calls)
+}
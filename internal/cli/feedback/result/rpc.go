@@ -917,6 +917,15 @@ type BuilderResult struct {
 	BuildPlatform          *InstalledPlatformReference `json:"build_platform,omitempty"`
 	BuildProperties        []string                    `json:"build_properties,omitempty"`
 	Diagnostics            []*CompileDiagnostic        `json:"diagnostics,omitempty"`
+	Artifacts              []*BuildArtifact            `json:"artifacts,omitempty"`
+	CoreCacheKey           string                      `json:"core_cache_key,omitempty"`
+	IncludePaths           []string                    `json:"include_paths,omitempty"`
+	UsedTools              []string                    `json:"used_tools,omitempty"`
+	PreprocessedFiles      []*PreprocessedFile         `json:"preprocessed_files,omitempty"`
+	LinkCommand            string                      `json:"link_command,omitempty"`
+	PropertyExplanation    *PropertyExplanation        `json:"property_explanation,omitempty"`
+	Defines                []string                    `json:"defines,omitempty"`
+	Summary                *CompilationSummary         `json:"summary,omitempty"`
 }
 
 func NewBuilderResult(c *rpc.BuilderResult) *BuilderResult {
@@ -931,6 +940,14 @@ func NewBuilderResult(c *rpc.BuilderResult) *BuilderResult {
 	for i, v := range c.GetExecutableSectionsSize() {
 		executableSectionsSizes[i] = NewExecutableSectionSize(v)
 	}
+	artifacts := make([]*BuildArtifact, len(c.GetArtifacts()))
+	for i, v := range c.GetArtifacts() {
+		artifacts[i] = NewBuildArtifact(v)
+	}
+	preprocessedFiles := make([]*PreprocessedFile, len(c.GetPreprocessedFiles()))
+	for i, v := range c.GetPreprocessedFiles() {
+		preprocessedFiles[i] = NewPreprocessedFile(v)
+	}
 
 	return &BuilderResult{
 		BuildPath:              c.GetBuildPath(),
@@ -940,6 +957,93 @@ func NewBuilderResult(c *rpc.BuilderResult) *BuilderResult {
 		BuildPlatform:          NewInstalledPlatformReference(c.GetBuildPlatform()),
 		BuildProperties:        c.GetBuildProperties(),
 		Diagnostics:            NewCompileDiagnostics(c.GetDiagnostics()),
+		Artifacts:              artifacts,
+		CoreCacheKey:           c.GetCoreCacheKey(),
+		IncludePaths:           c.GetIncludePaths(),
+		UsedTools:              c.GetUsedTools(),
+		PreprocessedFiles:      preprocessedFiles,
+		LinkCommand:            c.GetLinkCommand(),
+		PropertyExplanation:    NewPropertyExplanation(c.GetPropertyExplanation()),
+		Defines:                c.GetDefines(),
+		Summary:                NewCompilationSummary(c.GetSummary()),
+	}
+}
+
+type CompilationSummary struct {
+	TotalCommands    int32            `json:"total_commands,omitempty"`
+	UniqueFiles      int32            `json:"unique_files,omitempty"`
+	FilesByExtension map[string]int32 `json:"files_by_extension,omitempty"`
+}
+
+func NewCompilationSummary(s *rpc.CompilationSummary) *CompilationSummary {
+	if s == nil {
+		return nil
+	}
+	return &CompilationSummary{
+		TotalCommands:    s.GetTotalCommands(),
+		UniqueFiles:      s.GetUniqueFiles(),
+		FilesByExtension: s.GetFilesByExtension(),
+	}
+}
+
+type PropertyExplanation struct {
+	Key           string `json:"key,omitempty"`
+	DefaultValue  string `json:"default_value,omitempty"`
+	Source        string `json:"source,omitempty"`
+	Value         string `json:"value,omitempty"`
+	ExpandedValue string `json:"expanded_value,omitempty"`
+	Defined       bool   `json:"defined"`
+}
+
+func NewPropertyExplanation(e *rpc.PropertyExplanation) *PropertyExplanation {
+	if e == nil {
+		return nil
+	}
+	return &PropertyExplanation{
+		Key:           e.GetKey(),
+		DefaultValue:  e.GetDefaultValue(),
+		Source:        e.GetSource(),
+		Value:         e.GetValue(),
+		ExpandedValue: e.GetExpandedValue(),
+		Defined:       e.GetDefined(),
+	}
+}
+
+type BuildArtifact struct {
+	Path   string `json:"path,omitempty"`
+	Size   int64  `json:"size,omitempty"`
+	Type   string `json:"type,omitempty"`
+	Sha256 string `json:"sha256,omitempty"`
+	Md5    string `json:"md5,omitempty"`
+}
+
+func NewBuildArtifact(a *rpc.BuildArtifact) *BuildArtifact {
+	if a == nil {
+		return nil
+	}
+	return &BuildArtifact{
+		Path:   a.GetPath(),
+		Size:   a.GetSize(),
+		Type:   a.GetType(),
+		Sha256: a.GetSha256(),
+		Md5:    a.GetMd5(),
+	}
+}
+
+type PreprocessedFile struct {
+	SourcePath string `json:"source_path,omitempty"`
+	Content    string `json:"content,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+func NewPreprocessedFile(f *rpc.PreprocessedFile) *PreprocessedFile {
+	if f == nil {
+		return nil
+	}
+	return &PreprocessedFile{
+		SourcePath: f.GetSourcePath(),
+		Content:    f.GetContent(),
+		Error:      f.GetError(),
 	}
 }
 
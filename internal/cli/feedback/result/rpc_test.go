@@ -194,10 +194,26 @@ func TestAllFieldAreMapped(t *testing.T) {
 	builderResultResult := result.NewBuilderResult(builderResultRpc)
 	mustContainsAllPropertyOfRpcStruct(t, builderResultRpc, builderResultResult)
 
+	compilationSummaryRpc := &rpc.CompilationSummary{}
+	compilationSummaryResult := result.NewCompilationSummary(compilationSummaryRpc)
+	mustContainsAllPropertyOfRpcStruct(t, compilationSummaryRpc, compilationSummaryResult)
+
+	propertyExplanationRpc := &rpc.PropertyExplanation{}
+	propertyExplanationResult := result.NewPropertyExplanation(propertyExplanationRpc)
+	mustContainsAllPropertyOfRpcStruct(t, propertyExplanationRpc, propertyExplanationResult)
+
+	preprocessedFileRpc := &rpc.PreprocessedFile{}
+	preprocessedFileResult := result.NewPreprocessedFile(preprocessedFileRpc)
+	mustContainsAllPropertyOfRpcStruct(t, preprocessedFileRpc, preprocessedFileResult)
+
 	executableSectionSizeRpc := &rpc.ExecutableSectionSize{}
 	executableSectionSizeResult := result.NewExecutableSectionSize(executableSectionSizeRpc)
 	mustContainsAllPropertyOfRpcStruct(t, executableSectionSizeRpc, executableSectionSizeResult)
 
+	buildArtifactRpc := &rpc.BuildArtifact{}
+	buildArtifactResult := result.NewBuildArtifact(buildArtifactRpc)
+	mustContainsAllPropertyOfRpcStruct(t, buildArtifactRpc, buildArtifactResult)
+
 	installedPlatformReferenceRpc := &rpc.InstalledPlatformReference{}
 	installedPlatformReferenceResult := result.NewInstalledPlatformReference(installedPlatformReferenceRpc)
 	mustContainsAllPropertyOfRpcStruct(t, installedPlatformReferenceRpc, installedPlatformReferenceResult)
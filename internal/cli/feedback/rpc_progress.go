@@ -17,16 +17,21 @@ package feedback
 
 import (
 	"sync"
+	"time"
 
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	"github.com/cmaglie/pb"
 )
 
-// ProgressBar returns a DownloadProgressCB that prints a progress bar.
+// ProgressBar returns a DownloadProgressCB that prints a progress bar. If the
+// output is not a terminal the progress is printed as periodic text lines.
 func ProgressBar() rpc.DownloadProgressCB {
-	if format == Text {
+	if format == Text && (forceTTY || HasConsole()) {
 		return NewDownloadProgressBarCB()
 	}
+	if format == Text {
+		return NewDownloadProgressLinesCB(progressLinesInterval)
+	}
 	return func(curr *rpc.DownloadProgress) {
 		// Non interactive output, no progress bar
 	}
@@ -82,6 +87,45 @@ func NewDownloadProgressBarCB() func(*rpc.DownloadProgress) {
 	}
 }
 
+// progressLinesInterval is the minimum interval between two progress lines
+var progressLinesInterval = 5 * time.Second
+
+// NewDownloadProgressLinesCB creates a progress callback that prints the
+// download progress as text lines, at most one every interval, suitable for
+// non-interactive outputs like CI logs.
+func NewDownloadProgressLinesCB(interval time.Duration) func(*rpc.DownloadProgress) {
+	var mux sync.Mutex
+	var label string
+	var lastPrint time.Time
+	return func(curr *rpc.DownloadProgress) {
+		mux.Lock()
+		defer mux.Unlock()
+
+		if start := curr.GetStart(); start != nil {
+			label = start.GetLabel()
+			lastPrint = time.Now()
+		}
+		if update := curr.GetUpdate(); update != nil {
+			if time.Since(lastPrint) < interval {
+				return
+			}
+			lastPrint = time.Now()
+			if total := update.GetTotalSize(); total > 0 {
+				Print(tr("%[1]s %[2]d%% (%[3]d of %[4]d bytes)", label, update.GetDownloaded()*100/total, update.GetDownloaded(), total))
+			} else {
+				Print(tr("%[1]s %[2]d bytes", label, update.GetDownloaded()))
+			}
+		}
+		if end := curr.GetEnd(); end != nil {
+			msg := end.GetMessage()
+			if end.GetSuccess() && msg == "" {
+				msg = tr("downloaded")
+			}
+			Print(label + " " + msg)
+		}
+	}
+}
+
 // NewTaskProgressCB returns a commands.TaskProgressCB progress listener
 // that outputs to terminal
 func NewTaskProgressCB() func(curr *rpc.TaskProgress) {
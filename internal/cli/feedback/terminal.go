@@ -74,6 +74,14 @@ func IsInteractive() bool {
 	return isatty.IsTerminal(os.Stdin.Fd()) || isatty.IsCygwinTerminal(os.Stdin.Fd())
 }
 
+var forceTTY bool
+
+// SetForceTTY forces the output to be formatted as if printed to a terminal,
+// even if it's redirected.
+func SetForceTTY(force bool) {
+	forceTTY = force
+}
+
 // HasConsole returns true if the CLI outputs to a terminal/console
 func HasConsole() bool {
 	return isatty.IsTerminal(os.Stdout.Fd()) || isatty.IsCygwinTerminal(os.Stdout.Fd())
@@ -16,12 +16,15 @@
 package compile_test
 
 import (
+	"archive/zip"
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
 	"regexp"
+	"runtime"
 	"sort"
 	"strings"
 	"testing"
@@ -76,6 +79,80 @@ func TestCompile(t *testing.T) {
 		{"PreprocessFlagDoNotMessUpWithOutput", preprocessFlagDoNotMessUpWithOutput},
 		{"WithCustomBuildPath", buildWithCustomBuildPath},
 		{"WithCustomBuildPathAndOUtputDirFlag", buildWithCustomBuildPathAndOUtputDirFlag},
+		{"InfoFlag", compileInfoFlag},
+		{"ListOutputsFlag", compileListOutputsFlag},
+		{"FailingPdeSketchHint", compileFailingPdeSketchHint},
+		{"MatrixFlag", compileMatrixFlag},
+		{"OnlyExplicitLibrariesFlag", compileOnlyExplicitLibrariesFlag},
+		{"ListBoardOptionsFlag", compileListBoardOptionsFlag},
+		{"MaxWarningsFlag", compileMaxWarningsFlag},
+		{"WithPortAndNoBoard", compileWithPortAndNoBoard},
+		{"StrictIncludesFlag", compileStrictIncludesFlag},
+		{"BuiltinLibrariesDirFlag", compileBuiltinLibrariesDirFlag},
+		{"KeepObjectsFlag", compileKeepObjectsFlag},
+		{"DefaultFQBNMismatch", compileDefaultFQBNMismatch},
+		{"PrintCacheKeyFlag", compilePrintCacheKeyFlag},
+		{"DefineFlag", compileDefineFlag},
+		{"DataDirAndSketchbookDirFlags", compileDataDirAndSketchbookDirFlags},
+		{"VerifySizeFlag", compileVerifySizeFlag},
+		{"ExportPreprocessedSketchFlag", compileExportPreprocessedSketchFlag},
+		{"ListLibrariesFlag", compileListLibrariesFlag},
+		{"CheckIncludeFlag", compileCheckIncludeFlag},
+		{"CompilationDatabasePathFlag", compileCompilationDatabasePathFlag},
+		{"ArgumentsFromFile", compileArgumentsFromFile},
+		{"DumpIncludePathsFlag", compileDumpIncludePathsFlag},
+		{"NoOverwriteAndBackupFlags", compileNoOverwriteAndBackupFlags},
+		{"WithSymlinkedSketchFolder", compileWithSymlinkedSketchFolder},
+		{"ReportFileFlag", compileReportFileFlag},
+		{"LanguageStandardFlags", compileLanguageStandardFlags},
+		{"AnalyzeMapFlag", compileAnalyzeMapFlag},
+		{"FqbnSeparatorFlag", compileFqbnSeparatorFlag},
+		{"VerboseOnFailureFlag", compileVerboseOnFailureFlag},
+		{"OutputFormatFlag", compileOutputFormatFlag},
+		{"ProgressStreamFlag", compileProgressStreamFlag},
+		{"ToolOverrideFlag", compileToolOverrideFlag},
+		{"StrictFqbnFlag", compileStrictFqbnFlag},
+		{"SketchWithBOM", compileSketchWithBOM},
+		{"PreprocessJSON", compilePreprocessJSON},
+		{"ConfigInlineFlag", compileConfigInlineFlag},
+		{"WriteChecksumsFlag", compileWriteChecksumsFlag},
+		{"LTOFlags", compileLTOFlags},
+		{"CheckFileFlag", compileCheckFileFlag},
+		{"MainFileFlag", compileMainFileFlag},
+		{"ExportDepsFlag", compileExportDepsFlag},
+		{"BoardDefineFlag", compileBoardDefineFlag},
+		{"BootloaderFlag", compileBootloaderFlag},
+		{"ShowLinkCommandFlag", compileShowLinkCommandFlag},
+		{"IDEPreferencesFlag", compileIDEPreferencesFlag},
+		{"TraceFlag", compileTraceFlag},
+		{"SketchFromSketchbook", compileSketchFromSketchbook},
+		{"AllowedLibrarySourcesFlag", compileAllowedLibrarySourcesFlag},
+		{"ValidateCompilationDatabaseFlag", compileValidateCompilationDatabaseFlag},
+		{"NoBuildTimeFlag", compileNoBuildTimeFlag},
+		{"WarningsFileFlag", compileWarningsFileFlag},
+		{"OnlyNewDiagnosticsFlag", compileOnlyNewDiagnosticsFlag},
+		{"CopyToFlag", compileCopyToFlag},
+		{"ArchiveFlag", compileArchiveFlag},
+		{"CyclicBuildProperties", compileCyclicBuildProperties},
+		{"SketchNameFlag", compileSketchNameFlag},
+		{"VerifyToolFlag", compileVerifyToolFlag},
+		{"ExportDepGraphFlag", compileExportDepGraphFlag},
+		{"CompilerPathFlag", compileCompilerPathFlag},
+		{"ExplainPropertyFlag", compileExplainPropertyFlag},
+		{"RecipeOverrides", compileRecipeOverrides},
+		{"SketchEncodingFlag", compileSketchEncodingFlag},
+		{"ExplainExitCodesFlag", compileExplainExitCodesFlag},
+		{"BuildPropertiesLayers", compileBuildPropertiesLayers},
+		{"CompareToFlag", compileCompareToFlag},
+		{"ExcludeFileFlag", compileExcludeFileFlag},
+		{"DumpDefinesFlag", compileDumpDefinesFlag},
+		{"WatchFlag", compileWatchFlag},
+		{"BuilderPathFlag", compileBuilderPathFlag},
+		{"MaxBuildPathLengthFlag", compileMaxBuildPathLengthFlag},
+		{"IDEVersionFlag", compileIDEVersionFlag},
+		{"SaveAndLoadContext", compileSaveAndLoadContext},
+		{"FileFlags", compileFileFlags},
+		{"SummaryFlag", compileSummaryFlag},
 	}.Run(t, env, cli)
 }
 
@@ -320,6 +397,14 @@ func compileWithBuildPropertiesFlag(t *testing.T, env *integrationtest.Environme
 		require.Contains(t, string(stderr), "Flag --build-properties has been deprecated, please use --build-property instead.")
 		require.Contains(t, string(stdout), "-DFIRST_PIN=1")
 		require.Contains(t, string(stdout), "-DSECOND_PIN=2")
+
+		// A comma escaped with a backslash is part of the value
+		stdout, _, err = cli.Run("compile", "-b", fqbn,
+			"--build-properties", `build.extra_flags=-DFIRST_PIN=1\,-DSECOND_PIN=2,compiler.cpp.extra_flags=-DTHIRD_PIN=3`,
+			"--show-properties", sketchPath.String())
+		require.NoError(t, err)
+		require.Contains(t, string(stdout), "build.extra_flags=-DFIRST_PIN=1,-DSECOND_PIN=2\n")
+		require.Contains(t, string(stdout), "compiler.cpp.extra_flags=-DTHIRD_PIN=3\n")
 	}
 }
 
@@ -907,6 +992,47 @@ func TestCompileWithFullyPrecompiledLibrary(t *testing.T) {
 	require.Contains(t, string(stdout), "Skipping dependencies detection for precompiled library Arduino_TensorFlowLite")
 }
 
+func TestCompileWithoutHardwareDirectories(t *testing.T) {
+	env, cli := integrationtest.CreateArduinoCLIWithEnvironment(t)
+	defer env.CleanUp()
+
+	sketchName := "CompileWithoutHardwareDirectories"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	// No platform has been installed yet
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "No hardware directory found: install a platform with 'core install'")
+}
+
+func TestCompileWithoutHomeDirectory(t *testing.T) {
+	env, cli := integrationtest.CreateArduinoCLIWithEnvironment(t)
+	defer env.CleanUp()
+
+	sketchPath := cli.SketchbookDir().Join("CompileWithoutHomeDirectory")
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	// The CLI runs without HOME, so the data directory must be set explicitly
+	customEnv := cli.GetDefaultEnv()
+	customEnv["HOME"] = ""
+	delete(customEnv, "ARDUINO_DATA_DIR")
+	_, stderr, err := cli.RunWithCustomEnv(customEnv, "compile", "-b", "arduino:avr:uno", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "set ARDUINO_DATA_DIR or HOME")
+
+	// The sketchbook directory is optional
+	customEnv = cli.GetDefaultEnv()
+	customEnv["HOME"] = ""
+	delete(customEnv, "ARDUINO_SKETCHBOOK_DIR")
+	_, stderr, err = cli.RunWithCustomEnv(customEnv, "compile", "-b", "arduino:avr:uno", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "No hardware directory found")
+	require.NotContains(t, string(stderr), "ARDUINO_SKETCHBOOK_DIR")
+}
+
 func TestCompileManuallyInstalledPlatform(t *testing.T) {
 	env, cli := integrationtest.CreateArduinoCLIWithEnvironment(t)
 	defer env.CleanUp()
@@ -1225,40 +1351,24 @@ func buildWithCustomBuildPath(t *testing.T, env *integrationtest.Environment, cl
 	t.Run("InsideSketch", func(t *testing.T) {
 		buildPath := sketchPath.Join("build")
 
-		// Run build
-		_, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--build-path", buildPath.String(), sketchPath.String())
-		require.NoError(t, err)
-		// Run build twice, to verify the build still works when the build directory is present at the start
-		_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--build-path", buildPath.String(), sketchPath.String())
-		require.NoError(t, err)
-
-		// Run again a couple of times with a different build path, to verify that old build
-		// path is not copied back in the sketch build recursively.
-		// https://github.com/arduino/arduino-cli/issues/2266
-		secondBuildPath := sketchPath.Join("build2")
-		_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--build-path", secondBuildPath.String(), sketchPath.String())
-		require.NoError(t, err)
-		_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--build-path", buildPath.String(), sketchPath.String())
-		require.NoError(t, err)
-		_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--build-path", secondBuildPath.String(), sketchPath.String())
-		require.NoError(t, err)
-		_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--build-path", buildPath.String(), sketchPath.String())
-		require.NoError(t, err)
-
-		// Print build path content for debugging purposes
-		bp, _ := buildPath.ReadDirRecursive()
-		fmt.Println("Build path content:")
-		for _, file := range bp {
-			fmt.Println("> ", file.String())
-		}
-
-		require.False(t, buildPath.Join("sketch", "build2", "sketch").Exist())
+		// The intermediate files could be picked up as sketch sources
+		_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--build-path", buildPath.String(), sketchPath.String())
+		require.Error(t, err)
+		require.Contains(t, string(stderr), "The build path can't be inside the sketch folder")
+		require.NoDirExists(t, buildPath.String())
 	})
 
 	t.Run("SameAsSektch", func(t *testing.T) {
 		// Run build
-		_, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--build-path", sketchPath.String(), sketchPath.String())
+		_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--build-path", sketchPath.String(), sketchPath.String())
 		require.Error(t, err)
+		require.Contains(t, string(stderr), "The build path can't be the sketch folder")
+	})
+
+	t.Run("BuildCacheSameAsSketch", func(t *testing.T) {
+		_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--build-cache-path", sketchPath.String(), sketchPath.String())
+		require.Error(t, err)
+		require.Contains(t, string(stderr), "The build cache path can't be the sketch folder")
 	})
 }
 
@@ -1292,3 +1402,1917 @@ func buildWithCustomBuildPathAndOUtputDirFlag(t *testing.T, env *integrationtest
 		require.NotEmpty(t, content)
 	}
 }
+
+func compileInfoFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileInfoFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	buildPath := cli.DataDir().Join("test_dir", "info_build_dir")
+	defer buildPath.RemoveAll()
+
+	// Create a test sketch including a header from the sketch and one from a missing library
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+	require.NoError(t, sketchPath.Join("local.h").WriteFile([]byte{}))
+	require.NoError(t, sketchPath.Join(sketchName+".ino").WriteFile([]byte(
+		"#include \"local.h\"\n#include <NotExistingLibrary.h>\nvoid setup() {}\nvoid loop() {}\n")))
+
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--info", "--build-path", buildPath.String(), sketchPath.String(), "--format", "json")
+	require.NoError(t, err)
+	requirejson.Query(t, stdout, ".fqbn", `"arduino:avr:uno"`)
+	requirejson.Query(t, stdout, ".core", `"arduino:avr"`)
+	requirejson.Query(t, stdout, ".core_installed", "true")
+	requirejson.Query(t, stdout, ".libraries", `[{"include":"NotExistingLibrary.h","installed":false}]`)
+
+	// The sketch must not be compiled
+	require.NoDirExists(t, buildPath.String())
+}
+
+func compileListOutputsFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileListOutputsFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	buildPath := cli.DataDir().Join("test_dir", "list_outputs_build_dir")
+	defer buildPath.RemoveAll()
+
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--build-path", buildPath.String(), sketchPath.String(), "--format", "json")
+	require.NoError(t, err)
+	hexFile := buildPath.Join(sketchName + ".ino.hex")
+	hexInfo, err := hexFile.Stat()
+	require.NoError(t, err)
+	requirejson.Contains(t, stdout, fmt.Sprintf(`{"builder_result":{"artifacts":[{"path":%q,"type":"hex","size":%d}]}}`, hexFile.String(), hexInfo.Size()))
+	requirejson.Contains(t, stdout, fmt.Sprintf(`{"builder_result":{"artifacts":[{"path":%q,"type":"elf"}]}}`, buildPath.Join(sketchName+".ino.elf").String()))
+
+	stdout, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--build-path", buildPath.String(), sketchPath.String(), "--list-outputs")
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), hexFile.String())
+}
+
+func compileFailingPdeSketchHint(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileFailingPdeSketch"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+
+	// Create a broken sketch with the legacy .pde extension
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+	require.NoError(t, sketchPath.Join(sketchName+".ino").Remove())
+	require.NoError(t, sketchPath.Join(sketchName+".pde").WriteFile([]byte("void setup() { undefinedFunction(); }\nvoid loop() {}\n")))
+
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "uses the legacy .pde extension")
+	require.Contains(t, string(stderr), sketchName+".ino")
+}
+
+func compileMatrixFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileMatrixFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	stdout, _, err := cli.Run("compile", "--matrix", "arduino:avr:uno", "--matrix", "arduino:avr:nano", sketchPath.String(), "--format", "json")
+	require.NoError(t, err)
+	requirejson.Query(t, stdout, ".results | map(.success)", "[true, true]")
+
+	// Use a FQBNs list file with a not installed platform
+	matrixFile := sketchPath.Join("fqbns.txt")
+	require.NoError(t, matrixFile.WriteFile([]byte("# boards to test\narduino:avr:uno\narduino:samd:mkr1000\n")))
+	stdout, _, err = cli.Run("compile", "--matrix-file", matrixFile.String(), sketchPath.String(), "--format", "json")
+	require.Error(t, err)
+	requirejson.Query(t, stdout, ".results | map(.fqbn)", `["arduino:avr:uno", "arduino:samd:mkr1000"]`)
+	requirejson.Query(t, stdout, ".results | map(.success)", "[true, false]")
+	requirejson.Query(t, stdout, ".results[1].error | length > 0", "true")
+}
+
+func compileOnlyExplicitLibrariesFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileOnlyExplicitLibraries"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	librariesPath := cli.DataDir().Join("test_dir", "explicit_libraries")
+	defer librariesPath.RemoveAll()
+
+	// Create a library in a custom libraries folder
+	myLibPath := librariesPath.Join("MyLib")
+	require.NoError(t, myLibPath.MkdirAll())
+	require.NoError(t, myLibPath.Join("MyLib.h").WriteFile([]byte("#define MY_LIB 1\n")))
+
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+	require.NoError(t, sketchPath.Join(sketchName+".ino").WriteFile([]byte("#include <MyLib.h>\nvoid setup() {}\nvoid loop() {}\n")))
+
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--only-explicit-libraries", "--libraries", librariesPath.String(), sketchPath.String(), "--format", "json")
+	require.NoError(t, err)
+	requirejson.Query(t, stdout, ".builder_result.used_libraries | map(.name)", `["MyLib"]`)
+
+	// A platform bundled library is not available
+	require.NoError(t, sketchPath.Join(sketchName+".ino").WriteFile([]byte("#include <MyLib.h>\n#include <EEPROM.h>\nvoid setup() {}\nvoid loop() {}\n")))
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--only-explicit-libraries", "--libraries", librariesPath.String(), sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "EEPROM.h is not provided by any of the explicitly specified libraries")
+}
+
+func compileListBoardOptionsFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:nano", "--list-board-options", "--format", "json")
+	require.NoError(t, err)
+	requirejson.Query(t, stdout, ".fqbn", `"arduino:avr:nano"`)
+	requirejson.Query(t, stdout, `.options | map(.option)`, `["cpu"]`)
+	requirejson.Contains(t, stdout, `{"options":[{"option":"cpu","values":[{"value":"atmega328","selected":true},{"value":"atmega328old"}]}]}`)
+
+	// Already selected options are ignored
+	stdout, _, err = cli.Run("compile", "-b", "arduino:avr:nano:cpu=atmega168", "--list-board-options", "--format", "json")
+	require.NoError(t, err)
+	requirejson.Query(t, stdout, ".fqbn", `"arduino:avr:nano"`)
+
+	// A board without menus
+	stdout, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--list-board-options", "--format", "json")
+	require.NoError(t, err)
+	requirejson.Query(t, stdout, ".options", "null")
+
+	_, _, err = cli.Run("compile", "--list-board-options")
+	require.Error(t, err)
+}
+
+func compileMaxWarningsFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileMaxWarningsFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+	require.NoError(t, sketchPath.Join(sketchName+".ino").WriteFile([]byte(
+		"void setup() {\n  int unused1;\n  int unused2;\n}\nvoid loop() {}\n")))
+
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--warnings", "all", "--max-warnings", "1", sketchPath.String(), "--format", "json")
+	require.Error(t, err)
+	requirejson.Query(t, stdout, ".success", "false")
+	requirejson.Query(t, stdout, ".error", `"Error during build: the compiler emitted 2 warnings, the maximum allowed is 1"`)
+
+	stdout, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--warnings", "all", "--max-warnings", "2", sketchPath.String(), "--format", "json")
+	require.NoError(t, err)
+	requirejson.Query(t, stdout, ".success", "true")
+}
+
+func compileWithPortAndNoBoard(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileWithPortAndNoBoard"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	// The FQBN can't be detected from a port without a connected board
+	_, stderr, err := cli.Run("compile", "-p", "/dev/not-existing-port", "--discovery-timeout", "1s", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "Please specify an FQBN. No board found on port /dev/not-existing-port")
+}
+
+func compileStrictIncludesFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileStrictIncludesFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	libsPath := cli.SketchbookDir().Join("strict_includes_libraries")
+	defer libsPath.RemoveAll()
+
+	// LibA uses a header of LibB without declaring the dependency
+	libA := libsPath.Join("LibA")
+	require.NoError(t, libA.MkdirAll())
+	require.NoError(t, libA.Join("library.properties").WriteFile([]byte("name=LibA\nversion=1.0.0\n")))
+	require.NoError(t, libA.Join("LibA.h").WriteFile([]byte("void libA();\n")))
+	require.NoError(t, libA.Join("LibA.cpp").WriteFile([]byte("#include \"LibA.h\"\n#include <LibB.h>\nvoid libA() { libB(); }\n")))
+	libB := libsPath.Join("LibB")
+	require.NoError(t, libB.MkdirAll())
+	require.NoError(t, libB.Join("library.properties").WriteFile([]byte("name=LibB\nversion=1.0.0\n")))
+	require.NoError(t, libB.Join("LibB.h").WriteFile([]byte("void libB();\n")))
+	require.NoError(t, libB.Join("LibB.cpp").WriteFile([]byte("#include \"LibB.h\"\nvoid libB() {}\n")))
+
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+	require.NoError(t, sketchPath.Join(sketchName+".ino").WriteFile([]byte(
+		"#include <LibA.h>\n#include <LibB.h>\nvoid setup() { libA(); }\nvoid loop() {}\n")))
+
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--libraries", libsPath.String(), sketchPath.String())
+	require.NoError(t, err)
+
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--libraries", libsPath.String(), "--strict-includes", sketchPath.String(), "--format", "json")
+	require.Error(t, err)
+	requirejson.Query(t, stdout, ".success", "false")
+	require.Contains(t, requirejson.Parse(t, stdout).Query(".error").String(), "strict includes: LibA includes LibB.h")
+
+	// Declaring the dependency makes the build succeed
+	require.NoError(t, libA.Join("library.properties").WriteFile([]byte("name=LibA\nversion=1.0.0\ndepends=LibB\n")))
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--libraries", libsPath.String(), "--strict-includes", sketchPath.String())
+	require.NoError(t, err)
+}
+
+func TestCompileWithCoreFromGit(t *testing.T) {
+	env, cli := integrationtest.CreateArduinoCLIWithEnvironment(t)
+	defer env.CleanUp()
+
+	_, _, err := cli.Run("update")
+	require.NoError(t, err)
+
+	// Installs the core via CLI so all the necessary tools are installed
+	_, _, err = cli.Run("core", "install", "arduino:avr@1.8.3")
+	require.NoError(t, err)
+
+	sketchName := "CompileWithCoreFromGit"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	_, _, err = cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	gitURL := "https://github.com/arduino/ArduinoCore-avr.git#1.8.3"
+	fqbn := "arduino-core-from-git:avr:uno"
+	stdout, _, err := cli.Run("compile", "-b", fqbn, "--core-from-git", gitURL, sketchPath.String(), "--format", "json")
+	require.NoError(t, err)
+	requirejson.Query(t, stdout, ".builder_result.board_platform.id", `"arduino-core-from-git:avr"`)
+
+	// The clone is reused by the following builds
+	_, _, err = cli.Run("compile", "-b", fqbn, "--core-from-git", gitURL, sketchPath.String())
+	require.NoError(t, err)
+
+	// The platform is not available without the flag
+	_, _, err = cli.Run("compile", "-b", fqbn, sketchPath.String())
+	require.Error(t, err)
+
+	_, _, err = cli.Run("compile", "--core-from-git", gitURL, sketchPath.String())
+	require.Error(t, err)
+}
+
+func compileBuiltinLibrariesDirFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileBuiltinLibrariesDirFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	builtinLibsDir := cli.DataDir().Join("not-existing-builtin-libraries")
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "-v", "--builtin-libraries-dir", builtinLibsDir.String(), sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), "Using built-in libraries from folder: "+builtinLibsDir.String())
+	require.Contains(t, string(stdout), "Warning: the built-in libraries folder "+builtinLibsDir.String()+" does not exist")
+
+	stdout, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "-v", sketchPath.String())
+	require.NoError(t, err)
+	require.NotContains(t, string(stdout), "Using built-in libraries from folder")
+}
+
+func compileKeepObjectsFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileKeepObjectsFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	outputDir := cli.DataDir().Join("test_dir", "keep_objects_output_dir")
+	defer outputDir.RemoveAll()
+
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--keep-objects", "--output-dir", outputDir.String(), sketchPath.String())
+	require.NoError(t, err)
+	objectsDir := outputDir.Join("objects")
+	require.FileExists(t, objectsDir.Join("sketch", sketchName+".ino.cpp.o").String())
+
+	manifest, err := objectsDir.Join("manifest.json").ReadFile()
+	require.NoError(t, err)
+	requirejson.Query(t, manifest, `.objects | map(select(.object == "sketch/`+sketchName+`.ino.cpp.o")) | length`, "1")
+	requirejson.Query(t, manifest, `.core_archive | length > 0`, "true")
+}
+
+func compileDefaultFQBNMismatch(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileDefaultFQBNMismatch"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+	require.NoError(t, sketchPath.Join("sketch.yaml").WriteFile([]byte("default_fqbn: arduino:avr:uno\n")))
+
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:nano", "--info", sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stderr), "The board arduino:avr:nano differs from the default board arduino:avr:uno of the sketch.")
+
+	_, stderr, err = cli.Run("compile", "-b", "arduino:avr:nano", "--strict", "--info", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "The board arduino:avr:nano differs from the default board arduino:avr:uno of the sketch.")
+
+	// The board configuration options are not compared
+	require.NoError(t, sketchPath.Join("sketch.yaml").WriteFile([]byte("default_fqbn: arduino:avr:nano\n")))
+	_, stderr, err = cli.Run("compile", "-b", "arduino:avr:nano:cpu=atmega328old", "--strict", "--info", sketchPath.String())
+	require.NoError(t, err)
+	require.NotContains(t, string(stderr), "differs from the default board")
+}
+
+func compilePrintCacheKeyFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompilePrintCacheKeyFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	buildPath := cli.DataDir().Join("test_dir", "print_cache_key_build_dir")
+	defer buildPath.RemoveAll()
+
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--print-cache-key", "--build-path", buildPath.String(), sketchPath.String())
+	require.NoError(t, err)
+	key := strings.TrimSpace(string(stdout))
+	require.True(t, strings.HasPrefix(key, "arduino_avr_uno_"), key)
+	// The sketch must not be compiled
+	require.NoFileExists(t, buildPath.Join(sketchName+".ino.hex").String())
+
+	stdout, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--print-cache-key", "--optimize-for-debug", sketchPath.String())
+	require.NoError(t, err)
+	require.NotEqual(t, key, strings.TrimSpace(string(stdout)))
+
+	stdout, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--print-cache-key", sketchPath.String(), "--format", "json")
+	require.NoError(t, err)
+	requirejson.Query(t, stdout, ".builder_result.core_cache_key", fmt.Sprintf("%q", key))
+}
+
+func compileDefineFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileDefineFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+	require.NoError(t, sketchPath.Join(sketchName+".ino").WriteFile([]byte(`
+#if !defined(FOO) || LEVEL != 2 || !defined(BAR)
+#error "Missing defines"
+#endif
+const char *msg = MSG;
+void setup() {}
+void loop() {}
+`)))
+
+	// The defines are appended to the existing build.extra_flags
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--build-property", "build.extra_flags=-DBAR",
+		"-D", "FOO", "--define", "LEVEL=2", "--show-properties", sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), "build.extra_flags=-DBAR -DFOO -DLEVEL=2\n")
+
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--build-property", "build.extra_flags=-DBAR",
+		"-D", "FOO", "-D", "LEVEL=2", "-D", `MSG="hello world"`, sketchPath.String())
+	require.NoError(t, err)
+
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "-D", "FOO", sketchPath.String())
+	require.Error(t, err)
+
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "-D", "=1", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "Invalid define")
+}
+
+func compileDataDirAndSketchbookDirFlags(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileDataDirAndSketchbookDirFlags"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+	require.NoError(t, sketchPath.Join(sketchName+".ino").WriteFile([]byte("#include <IsolatedLib.h>\nvoid setup() {}\nvoid loop() {}\n")))
+
+	// A library available only in the alternative sketchbook
+	sketchbookDir, err := paths.MkTempDir("", "alt_sketchbook")
+	require.NoError(t, err)
+	defer sketchbookDir.RemoveAll()
+	libDir := sketchbookDir.Join("libraries", "IsolatedLib")
+	require.NoError(t, libDir.MkdirAll())
+	require.NoError(t, libDir.Join("IsolatedLib.h").WriteFile([]byte("#pragma once\n")))
+
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", sketchPath.String())
+	require.Error(t, err)
+
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "-v", "--sketchbook-dir", sketchbookDir.String(), sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), libDir.String())
+
+	// An empty data folder has no platforms installed
+	dataDir, err := paths.MkTempDir("", "alt_data")
+	require.NoError(t, err)
+	defer dataDir.RemoveAll()
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--data-dir", dataDir.String(), "--sketchbook-dir", sketchbookDir.String(), sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "Platform 'arduino:avr' not found")
+}
+
+func compileVerifySizeFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileVerifySizeFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--verify-size", sketchPath.String())
+	require.NoError(t, err)
+	require.NotContains(t, string(stderr), "size verification skipped")
+
+	// The overflow is reported in bytes
+	_, stderr, err = cli.Run("compile", "-b", "arduino:avr:uno", "--verify-size", "--build-property", "upload.maximum_size=100", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "Program storage space exceeded by")
+	require.Contains(t, string(stderr), "text section exceeds available space in board")
+
+	// Boards without maximums are skipped
+	_, stderr, err = cli.Run("compile", "-b", "arduino:avr:uno", "--verify-size", "--build-property", "upload.maximum_size=", sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stderr), "size verification skipped")
+}
+
+func compileExportPreprocessedSketchFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileExportPreprocessedSketchFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+	require.NoError(t, sketchPath.Join(sketchName+".ino").WriteFile([]byte("void setup() { helper(); }\nvoid loop() {}\nvoid helper() {}\n")))
+
+	outputDir := cli.SketchbookDir().Join("export-preprocessed")
+	defer outputDir.RemoveAll()
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--output-dir", outputDir.String(), "--export-preprocessed-sketch", sketchPath.String(), "--format", "json")
+	require.NoError(t, err)
+
+	// The exported source contains the generated prototype
+	preprocessed, err := outputDir.Join(sketchName + ".ino.cpp").ReadFile()
+	require.NoError(t, err)
+	require.Contains(t, string(preprocessed), "#include <Arduino.h>")
+	require.Contains(t, string(preprocessed), "void helper();")
+
+	// The exported source is not a build artifact
+	requirejson.Query(t, stdout, `.builder_result.artifacts | map(select(.path | endswith(".cpp"))) | length`, "0")
+}
+
+func compileListLibrariesFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileListLibrariesFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+	require.NoError(t, sketchPath.Join(sketchName+".ino").WriteFile([]byte("#include <SPI.h>\nvoid setup() {}\nvoid loop() {}\n")))
+
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", sketchPath.String())
+	require.NoError(t, err)
+	require.NotContains(t, string(stdout), "Location")
+
+	stdout, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--list-libraries", sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), "Location")
+	require.Contains(t, string(stdout), "platform arduino:avr")
+
+	stdout, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--format", "json", sketchPath.String())
+	require.NoError(t, err)
+	requirejson.Contains(t, stdout, `{"builder_result":{"used_libraries":[{"name":"SPI","location":"platform"}]}}`)
+}
+
+func compileCheckIncludeFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	_, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--check-include", "SPI.h")
+	require.NoError(t, err)
+
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--check-include", "NotExistingHeader.h")
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "header NotExistingHeader.h is not self-contained")
+	require.Contains(t, string(stderr), "NotExistingHeader.h: No such file or directory")
+
+	// A library header using a type it doesn't declare
+	libDir, err := paths.MkTempDir("", "NotSelfContained")
+	require.NoError(t, err)
+	defer libDir.RemoveAll()
+	require.NoError(t, libDir.Join("NotSelfContained.h").WriteFile([]byte("#pragma once\nvoid setSpeed(speed_t speed);\n")))
+
+	_, stderr, err = cli.Run("compile", "-b", "arduino:avr:uno", "--library", libDir.String(), "--check-include", "<NotSelfContained.h>")
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "header <NotSelfContained.h> is not self-contained, NotSelfContained.h:2:")
+	require.Contains(t, string(stderr), "speed_t")
+
+	// The sketch path can't be given together with the header
+	_, stderr, err = cli.Run("compile", "-b", "arduino:avr:uno", "--check-include", "SPI.h", cli.SketchbookDir().String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "You cannot pass a sketch path together with the --check-include flag.")
+}
+
+func compileCompilationDatabasePathFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileCompilationDatabasePathFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	buildPath := sketchPath.Join("build")
+
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	// The database is saved in the build path by default
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--only-compilation-database", "--build-path", buildPath.String(), sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), "Compilation database saved to: "+buildPath.Join("compile_commands.json").String())
+	require.FileExists(t, buildPath.Join("compile_commands.json").String())
+
+	// The folder of a custom path is created if needed
+	dbPath := sketchPath.Join("out", "db", "compile_commands.json")
+	stdout, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--only-compilation-database", "--compilation-database-path", dbPath.String(), sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), "Compilation database saved to: "+dbPath.String())
+	require.FileExists(t, dbPath.String())
+}
+
+func compileArgumentsFromFile(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileArgumentsFromFile"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	argsFile := sketchPath.Join("build-args.txt")
+	require.NoError(t, argsFile.WriteFile([]byte("# Build options\n-b\narduino:avr:uno\n--build-property\nbuild.extra_flags=-DPIN=2 -DDEBUG\n-D\nFROM_FILE\n")))
+
+	stdout, _, err := cli.Run("compile", "@"+argsFile.String(), "-D", "FROM_CLI", "--show-properties", sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), "build.extra_flags=-DPIN=2 -DDEBUG -DFROM_FILE -DFROM_CLI\n")
+
+	_, stderr, err := cli.Run("compile", "@"+sketchPath.Join("not-existing.txt").String(), sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "reading arguments file")
+}
+
+func compileDumpIncludePathsFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileDumpIncludePathsFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	buildPath := sketchPath.Join("build")
+
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+	require.NoError(t, sketchPath.Join(sketchName+".ino").WriteFile([]byte("#include <SPI.h>\nvoid setup() {}\nvoid loop() {}\n")))
+
+	platformDir := cli.DataDir().Join("packages", "arduino", "hardware", "avr", "1.8.5")
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--build-path", buildPath.String(), "--dump-include-paths", sketchPath.String())
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		platformDir.Join("cores", "arduino").String(),
+		platformDir.Join("variants", "standard").String(),
+		platformDir.Join("libraries", "SPI", "src").String(),
+	}, strings.Split(strings.TrimSpace(string(stdout)), "\n"))
+
+	// The sketch is not compiled
+	require.NoFileExists(t, buildPath.Join(sketchName+".ino.hex").String())
+
+	stdout, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--build-path", buildPath.String(), "--dump-include-paths", "--format", "json", sketchPath.String())
+	require.NoError(t, err)
+	requirejson.Query(t, stdout, ".builder_result.include_paths | length", "3")
+}
+
+func compileNoOverwriteAndBackupFlags(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileNoOverwriteAndBackupFlags"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	outputDir := sketchPath.Join("output")
+	hexFile := outputDir.Join(sketchName + ".ino.hex")
+
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--output-dir", outputDir.String(), "--no-overwrite", sketchPath.String())
+	require.NoError(t, err)
+	require.FileExists(t, hexFile.String())
+
+	// Nothing is exported if any of the artifacts already exists
+	elfFile := outputDir.Join(sketchName + ".ino.elf")
+	require.NoError(t, elfFile.Remove())
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--output-dir", outputDir.String(), "--no-overwrite", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "the file already exists")
+	require.NoFileExists(t, elfFile.String())
+
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--output-dir", outputDir.String(), "--backup", sketchPath.String())
+	require.NoError(t, err)
+	require.FileExists(t, hexFile.String())
+	require.FileExists(t, outputDir.Join(sketchName+".ino.hex.bak").String())
+
+	_, stderr, err = cli.Run("compile", "-b", "arduino:avr:uno", "--output-dir", outputDir.String(), "--backup", "--no-overwrite", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "Can't use the following flags together")
+}
+
+func compileWithSymlinkedSketchFolder(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Symlinks may require elevated privileges on Windows")
+	}
+	sketchName := "CompileWithSymlinkedSketchFolder"
+	realDir := cli.SketchbookDir().Join("real")
+	defer realDir.RemoveAll()
+	realSketchPath := realDir.Join(sketchName)
+	_, _, err := cli.Run("sketch", "new", realSketchPath.String())
+	require.NoError(t, err)
+
+	linkDir := cli.SketchbookDir().Join("link")
+	require.NoError(t, os.Symlink(realDir.String(), linkDir.String()))
+	defer linkDir.Remove()
+	linkSketchPath := linkDir.Join(sketchName)
+	linkBuildPath := linkSketchPath.Join("build")
+
+	// The build path inside the symlinked sketch is ignored when collecting
+	// the sketch files, even when built again
+	for i := 0; i < 2; i++ {
+		_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--build-path", linkBuildPath.String(), linkSketchPath.String())
+		require.NoError(t, err)
+	}
+	require.FileExists(t, realSketchPath.Join("build", sketchName+".ino.hex").String())
+
+	// The symlinks are resolved by default
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--build-path", linkBuildPath.String(), "--show-properties", linkSketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), "build.source.path="+realSketchPath.Canonical().String()+"\n")
+	require.Contains(t, string(stdout), "build.path="+realSketchPath.Canonical().Join("build").String()+"\n")
+
+	stdout, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--build-path", linkBuildPath.String(), "--no-follow-symlinks", "--show-properties", linkSketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), "build.source.path="+linkSketchPath.String()+"\n")
+	require.Contains(t, string(stdout), "build.path="+linkBuildPath.String()+"\n")
+
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--build-path", linkBuildPath.String(), "--no-follow-symlinks", linkSketchPath.String())
+	require.NoError(t, err)
+}
+
+func compileReportFileFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileReportFileFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	reportPath := cli.SketchbookDir().Join("reports", "report.json")
+	defer reportPath.Parent().RemoveAll()
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--report-file", reportPath.String(), sketchPath.String())
+	require.NoError(t, err)
+	report, err := reportPath.ReadFile()
+	require.NoError(t, err)
+	requirejson.Query(t, report, ".fqbn", `"arduino:avr:uno"`)
+	requirejson.Query(t, report, ".success", "true")
+	requirejson.Query(t, report, ".build_platform.id", `"arduino:avr"`)
+	requirejson.Query(t, report, ".build_platform.version", `"1.8.5"`)
+	requirejson.Query(t, report, `.tools | map(startswith("arduino:avr-gcc@")) | any`, "true")
+	requirejson.Query(t, report, ".executable_sections_size | length > 0", "true")
+	requirejson.Query(t, report, ".duration_seconds > 0", "true")
+
+	// The report is written in YAML format depending on the extension
+	yamlReportPath := reportPath.Parent().Join("report.yaml")
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--report-file", yamlReportPath.String(), sketchPath.String())
+	require.NoError(t, err)
+	yamlReport, err := yamlReportPath.ReadFile()
+	require.NoError(t, err)
+	require.Contains(t, string(yamlReport), "fqbn: arduino:avr:uno\n")
+
+	// The report is written also when the build fails
+	require.NoError(t, sketchPath.Join(sketchName+".ino").WriteFile([]byte("void setup() { syntax error }\nvoid loop() {}\n")))
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--report-file", reportPath.String(), sketchPath.String())
+	require.Error(t, err)
+	report, err = reportPath.ReadFile()
+	require.NoError(t, err)
+	requirejson.Query(t, report, ".success", "false")
+	requirejson.Query(t, report, ".error | length > 0", "true")
+}
+
+func compileLanguageStandardFlags(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileLanguageStandardFlags"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--cpp-std", "c++17", "--c-std", "gnu11", "--show-properties", sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), "compiler.cpp.extra_flags=-std=c++17\n")
+	require.Contains(t, string(stdout), "compiler.c.extra_flags=-std=gnu11\n")
+
+	stdout, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--cpp-std", "c++17", "-v", sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), "-std=c++17")
+
+	// The avr-gcc 7.3.0 toolchain is too old for C++23
+	_, stderr, _ := cli.Run("compile", "-b", "arduino:avr:uno", "--cpp-std", "c++23", sketchPath.String())
+	require.Contains(t, string(stderr), "likely doesn't support the c++23 standard")
+
+	_, stderr, err = cli.Run("compile", "-b", "arduino:avr:uno", "--cpp-std", "c++42", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "unknown language standard 'c++42'")
+}
+
+func compileAnalyzeMapFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileAnalyzeMapFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	// The avr platform doesn't generate the map file by default
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--analyze-map", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "The linker map file has not been generated by the build")
+
+	mapFlag := "ld:-Wl,-Map,{build.path}/{build.project_name}.map"
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--extra-flags", mapFlag, "--analyze-map", sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), "Largest symbol")
+	require.Contains(t, string(stdout), ".text")
+
+	stdout, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--extra-flags", mapFlag, "--analyze-map", "--json", sketchPath.String())
+	require.NoError(t, err)
+	requirejson.Query(t, stdout, `.map_analysis.map_file | endswith("`+sketchName+`.ino.map")`, "true")
+	requirejson.Query(t, stdout, `.map_analysis.sections | map(select(.name == ".text")) | length`, "1")
+	requirejson.Query(t, stdout, ".map_analysis.largest_symbols | length > 0", "true")
+}
+
+func compileFqbnSeparatorFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileFqbnSeparatorFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--export-binaries", sketchPath.String())
+	require.NoError(t, err)
+	require.FileExists(t, sketchPath.Join("build", "arduino.avr.uno", sketchName+".ino.hex").String())
+
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--export-binaries", "--fqbn-separator", "_", sketchPath.String())
+	require.NoError(t, err)
+	require.FileExists(t, sketchPath.Join("build", "arduino_avr_uno", sketchName+".ino.hex").String())
+
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--export-binaries", "--fqbn-separator", "/", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "invalid FQBN separator '/'")
+}
+
+func compileVerboseOnFailureFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileVerboseOnFailureFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	// A successful build doesn't report anything
+	stdout, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--verbose-on-failure", sketchPath.String())
+	require.NoError(t, err)
+	require.NotContains(t, string(stdout), "Failed command:")
+	require.NotContains(t, string(stderr), "Failed command:")
+
+	require.NoError(t, sketchPath.Join(sketchName+".ino").WriteFile([]byte("void setup() { syntax error }\nvoid loop() {}\n")))
+	stdout, stderr, err = cli.Run("compile", "-b", "arduino:avr:uno", "--verbose-on-failure", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "Failed command:")
+	require.Contains(t, string(stderr), "avr-g++")
+	require.Contains(t, string(stderr), sketchName+".ino.cpp.o")
+	// The rest of the build is not verbose
+	require.NotContains(t, string(stdout), "Using board 'uno'")
+
+	_, stderr, err = cli.Run("compile", "-b", "arduino:avr:uno", sketchPath.String())
+	require.Error(t, err)
+	require.NotContains(t, string(stderr), "Failed command:")
+}
+
+func compileOutputFormatFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileOutputFormatFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	outputDir := cli.SketchbookDir().Join("output-format")
+	defer outputDir.RemoveAll()
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--output-format", "hex,bin", "--output-dir", outputDir.String(), sketchPath.String())
+	require.NoError(t, err)
+	require.FileExists(t, outputDir.Join(sketchName+".ino.hex").String())
+	require.FileExists(t, outputDir.Join(sketchName+".ino.bin").String())
+	require.FileExists(t, outputDir.Join(sketchName+".ino.elf").String())
+
+	// The avr platform can't produce uf2 files
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--output-format", "hex,uf2", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "the platform can't produce the 'uf2' output format")
+}
+
+func compileProgressStreamFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileProgressStreamFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	readEvents := func(stream *paths.Path) []string {
+		data, err := stream.ReadFile()
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		for _, line := range lines {
+			requirejson.Query(t, []byte(line), ".type | length > 0", "true")
+		}
+		return lines
+	}
+
+	stream := cli.SketchbookDir().Join("progress.ndjson")
+	defer stream.Remove()
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--clean", "--progress-stream", stream.String(), sketchPath.String())
+	require.NoError(t, err)
+	events := readEvents(stream)
+	require.Contains(t, strings.Join(events, "\n"), `"type":"phase","time":`)
+	require.Contains(t, strings.Join(events, "\n"), `"phase":"Compiling sketch..."`)
+	require.Contains(t, strings.Join(events, "\n"), `"type":"file_compiled"`)
+	require.Contains(t, strings.Join(events, "\n"), `"type":"command"`)
+	requirejson.Query(t, []byte(events[len(events)-1]), ".type", `"done"`)
+	requirejson.Query(t, []byte(events[len(events)-1]), ".success", "true")
+
+	require.NoError(t, sketchPath.Join(sketchName+".ino").WriteFile([]byte("void setup() { syntax error }\nvoid loop() {}\n")))
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--progress-stream", stream.String(), sketchPath.String())
+	require.Error(t, err)
+	events = readEvents(stream)
+	require.Contains(t, strings.Join(events, "\n"), `"type":"error"`)
+	requirejson.Query(t, []byte(events[len(events)-1]), ".success", "false")
+}
+
+func compileToolOverrideFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileToolOverrideFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	gccPath := cli.DataDir().Join("packages", "arduino", "tools", "avr-gcc", "7.3.0-atmel3.6.1-arduino7")
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--tool", "arduino:avr-gcc@7.3.0-atmel3.6.1-arduino7", "--show-properties", sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), "runtime.tools.avr-gcc.path="+gccPath.String()+"\n")
+
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--tool", "avr-gcc@7.3.0-atmel3.6.1-arduino7", sketchPath.String())
+	require.NoError(t, err)
+
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--tool", "avr-gcc@1.0.0", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "Tool avr-gcc@1.0.0 is not installed")
+	require.Contains(t, string(stderr), "Installed versions: arduino:avr-gcc@7.3.0-atmel3.6.1-arduino7")
+}
+
+func compileStrictFqbnFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileStrictFqbnFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:nano:cpu=atmega328old", "--strict-fqbn", sketchPath.String())
+	require.NoError(t, err)
+
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:unoo", "--strict-fqbn", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "board unoo not found in platform arduino:avr@1.8.5, valid board IDs are:")
+	require.Contains(t, string(stderr), "uno")
+
+	_, stderr, err = cli.Run("compile", "-b", "arduino:avr:nano:cpu=atmega32", "--strict-fqbn", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "invalid value 'atmega32' for option 'cpu' of board arduino:avr:nano, valid values are: atmega328, atmega328old, atmega168")
+
+	_, stderr, err = cli.Run("compile", "-b", "arduino:avr:nano:clock=16", "--strict-fqbn", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "invalid option 'clock' for board arduino:avr:nano, valid options are: cpu")
+}
+
+func compileSketchWithBOM(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileSketchWithBOM"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	// Files saved by some Windows editors start with a BOM
+	mainFile := sketchPath.Join(sketchName + ".ino")
+	require.NoError(t, mainFile.WriteFile([]byte("\xEF\xBB\xBFvoid setup() {\n}\n\nvoid loop() {\n}\n")))
+	require.NoError(t, sketchPath.Join("Other.ino").WriteFile([]byte("// Caf\xE9\nvoid other() {\n}\n")))
+
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stderr), "Other.ino is not UTF-8 encoded")
+	require.NotContains(t, string(stderr), sketchName+".ino is")
+}
+
+func compilePreprocessJSON(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompilePreprocessJSON"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+	require.NoError(t, sketchPath.Join("helper.h").WriteFile([]byte("int helper();\n")))
+	mainFile := sketchPath.Join(sketchName + ".ino").String()
+	helperFile := sketchPath.Join("helper.h").String()
+
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--preprocess", "--format", "json", sketchPath.String())
+	require.NoError(t, err)
+	type preprocessedFile struct {
+		SourcePath string `json:"source_path"`
+		Content    string `json:"content"`
+		Error      string `json:"error"`
+	}
+	readPreprocessedFiles := func(stdout []byte) map[string]preprocessedFile {
+		var res struct {
+			BuilderResult struct {
+				PreprocessedFiles []preprocessedFile `json:"preprocessed_files"`
+			} `json:"builder_result"`
+		}
+		require.NoError(t, json.Unmarshal(stdout, &res))
+		files := map[string]preprocessedFile{}
+		for _, f := range res.BuilderResult.PreprocessedFiles {
+			files[f.SourcePath] = f
+		}
+		return files
+	}
+	files := readPreprocessedFiles(stdout)
+	require.Len(t, files, 2)
+	require.Contains(t, files[mainFile].Content, "#include <Arduino.h>")
+	require.Contains(t, files[mainFile].Content, "void setup();")
+	require.Contains(t, files[helperFile].Content, "int helper();")
+	require.Empty(t, files[helperFile].Error)
+
+	// The plain mode output is unchanged
+	stdout, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--preprocess", sketchPath.String())
+	require.NoError(t, err)
+	require.Equal(t, files[mainFile].Content, string(stdout))
+
+	// The preprocessing errors are reported for each file
+	require.NoError(t, sketchPath.Join(sketchName+".ino").WriteFile([]byte("#include <DoesNotExist.h>\nvoid setup() {}\nvoid loop() {}\n")))
+	stdout, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--preprocess", "--format", "json", sketchPath.String())
+	require.Error(t, err)
+	files = readPreprocessedFiles(stdout)
+	require.NotEmpty(t, files[mainFile].Error)
+	require.Empty(t, files[mainFile].Content)
+}
+
+func compileConfigInlineFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileConfigInlineFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	config := `{"fqbn": "arduino:avr:uno", "build_properties": ["build.extra_flags=-DFROM_CONFIG"]}`
+	stdout, _, err := cli.Run("compile", "--config-inline", config, "--show-properties", "--format", "json", sketchPath.String())
+	require.NoError(t, err)
+	requirejson.Query(t, stdout, `.builder_result.build_properties | map(select(. == "build.extra_flags=-DFROM_CONFIG")) | length`, "1")
+	requirejson.Query(t, stdout, `.builder_result.build_properties | map(select(. == "build.board=AVR_UNO")) | length`, "1")
+
+	// The command line flags take precedence
+	stdout, _, err = cli.Run("compile", "--config-inline", "fqbn: arduino:avr:uno", "-b", "arduino:avr:nano", "--show-properties", "--format", "json", sketchPath.String())
+	require.NoError(t, err)
+	requirejson.Query(t, stdout, `.builder_result.build_properties | map(select(. == "build.board=AVR_NANO")) | length`, "1")
+
+	_, stderr, err := cli.Run("compile", "--config-inline", `{"fqbn": "arduino:avr:uno", "warnings": 3}`, sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "Invalid --config-inline configuration: invalid field 'warnings': expected a string, got number")
+}
+
+func compileWriteChecksumsFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileWriteChecksumsFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	outputDir := cli.SketchbookDir().Join("CompileWriteChecksumsFlagOutput")
+	defer outputDir.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--output-dir", outputDir.String(), "--write-checksums", sketchPath.String(), "--format", "json")
+	require.NoError(t, err)
+
+	hexFile := outputDir.Join(sketchName + ".ino.hex")
+	hexData, err := hexFile.ReadFile()
+	require.NoError(t, err)
+	sha256Sum := fmt.Sprintf("%x", sha256.Sum256(hexData))
+	requirejson.Contains(t, stdout, fmt.Sprintf(`{"builder_result":{"artifacts":[{"path":%q,"sha256":%q}]}}`, hexFile.String(), sha256Sum))
+	// The MD5 checksum is computed only on request
+	requirejson.Query(t, stdout, `.builder_result.artifacts | map(select(.md5)) | length`, "0")
+
+	sidecar, err := outputDir.Join(sketchName + ".ino.hex.sha256").ReadFile()
+	require.NoError(t, err)
+	require.Equal(t, sha256Sum+"  "+sketchName+".ino.hex\n", string(sidecar))
+
+	// The checksum files are not listed as artifacts
+	requirejson.Query(t, stdout, `.builder_result.artifacts | map(select(.path | endswith(".sha256"))) | length`, "0")
+
+	stdout, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--output-dir", outputDir.String(), "--write-checksums", sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), sha256Sum)
+
+	stdout, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--output-dir", outputDir.String(), "--md5", sketchPath.String(), "--format", "json")
+	require.NoError(t, err)
+	md5Sum := fmt.Sprintf("%x", md5.Sum(hexData))
+	requirejson.Contains(t, stdout, fmt.Sprintf(`{"builder_result":{"artifacts":[{"path":%q,"sha256":%q,"md5":%q}]}}`, hexFile.String(), sha256Sum, md5Sum))
+}
+
+func compileLTOFlags(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileLTOFlags"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	buildPath := cli.SketchbookDir().Join("CompileLTOFlagsBuild")
+	defer buildPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	// The AVR platform enables the link time optimization by default
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--no-lto", "--show-properties", "--format", "json", sketchPath.String())
+	require.NoError(t, err)
+	requirejson.Query(t, stdout, `.builder_result.build_properties | map(select(startswith("compiler.c.elf.flags=") and contains("-flto"))) | length`, "0")
+
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--no-lto", "--build-path", buildPath.String(), sketchPath.String())
+	require.NoError(t, err)
+	stdout, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--lto", "--build-path", buildPath.String(), sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), "Size with link time optimization compared to the previous build without it: text")
+
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--lto", "--no-lto", sketchPath.String())
+	require.Error(t, err)
+}
+
+func compileCheckFileFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileCheckFileFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	buildPath := cli.SketchbookDir().Join("CompileCheckFileFlagBuild")
+	defer buildPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+	helper := sketchPath.Join("helper.cpp")
+	require.NoError(t, helper.WriteFile([]byte("int helper() {\n  return undeclared;\n}\n")))
+
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--build-path", buildPath.String(), "--check-file", helper.String(), sketchPath.String(), "--format", "json")
+	require.Error(t, err)
+	requirejson.Query(t, stdout, `.builder_result.diagnostics | map(select(.severity == "ERROR" and (.file | endswith("helper.cpp")))) | length > 0`, "true")
+
+	// Only the checked file is compiled
+	require.NoError(t, helper.WriteFile([]byte("int helper() {\n  return 0;\n}\n")))
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--build-path", buildPath.String(), "--check-file", helper.String(), sketchPath.String())
+	require.NoError(t, err)
+	require.True(t, buildPath.Join("sketch", "helper.cpp.o").Exist())
+	require.False(t, buildPath.Join("sketch", sketchName+".ino.cpp.o").Exist())
+	require.False(t, buildPath.Join(sketchName+".ino.hex").Exist())
+
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--check-file", sketchPath.Join("missing.cpp").String(), sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "is not a file of the sketch")
+}
+
+func compileMainFileFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchPath := cli.SketchbookDir().Join("CompileMainFileFlag")
+	defer sketchPath.RemoveAll()
+	require.NoError(t, sketchPath.MkdirAll())
+	require.NoError(t, sketchPath.Join("Main.ino").WriteFile([]byte("void setup() {}\nvoid loop() { helper(); }\n")))
+	require.NoError(t, sketchPath.Join("Helper.ino").WriteFile([]byte("void helper() {}\n")))
+
+	// The candidates are listed when no file is named after the folder
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "main file missing from sketch:")
+	require.Contains(t, string(stderr), "candidate main files found: Helper.ino, Main.ino")
+	require.Contains(t, string(stderr), "--main-file")
+
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--main-file", "Main.ino", sketchPath.String(), "--format", "json")
+	require.NoError(t, err)
+	requirejson.Query(t, stdout, `.builder_result.build_properties | map(select(. == "build.project_name=Main.ino")) | length`, "1")
+
+	// The main file can also be given as a path
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--main-file", sketchPath.Join("Main.ino").String(), sketchPath.String())
+	require.NoError(t, err)
+
+	_, stderr, err = cli.Run("compile", "-b", "arduino:avr:uno", "--main-file", "Missing.ino", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "no such file or directory")
+}
+
+func compileExportDepsFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileExportDepsFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	outputDir := cli.SketchbookDir().Join("CompileExportDepsFlagOutput")
+	defer outputDir.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--export-deps", "--output-dir", outputDir.String(), sketchPath.String())
+	require.NoError(t, err)
+	manifest := outputDir.Join("dependencies.d")
+	require.Contains(t, string(stdout), "Dependencies saved to: "+manifest.String())
+	deps, err := manifest.ReadFile()
+	require.NoError(t, err)
+	require.Contains(t, string(deps), sketchPath.Join(sketchName+".ino").String())
+	require.Contains(t, string(deps), sketchName+".ino.cpp.o:")
+	require.Contains(t, string(deps), "Arduino.h")
+}
+
+func compileBoardDefineFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchPath := cli.SketchbookDir().Join("CompileBoardDefineFlag")
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	// The AVR recipes derive the define from build.board, no extra flag is needed
+	stdout, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--board-define", "ARDUINO_MY_UNO", sketchPath.String(), "--format", "json")
+	require.NoError(t, err)
+	require.NotContains(t, string(stderr), "the define has been added to the compiler flags")
+	requirejson.Query(t, stdout, `.builder_result.build_properties | map(select(. == "build.board=MY_UNO")) | length`, "1")
+
+	// The prefix is optional
+	stdout, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--board-define", "MY_UNO", sketchPath.String(), "--format", "json")
+	require.NoError(t, err)
+	requirejson.Query(t, stdout, `.builder_result.build_properties | map(select(. == "build.board=MY_UNO")) | length`, "1")
+
+	_, stderr, err = cli.Run("compile", "-b", "arduino:avr:uno", "--board-define", "MY-UNO", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "Invalid board define")
+
+	_, stderr, err = cli.Run("compile", "-b", "arduino:avr:uno", "--board-define", "MY_UNO", "--build-property", "build.board=OTHER", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "build.board=OTHER")
+}
+
+func compileBootloaderFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchPath := cli.SketchbookDir().Join("CompileBootloaderFlag")
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	// The Uno doesn't have a bootloader menu
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--bootloader", "optiboot", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "Invalid bootloader")
+	require.Contains(t, string(stderr), "board arduino:avr:uno doesn't define any bootloader")
+}
+
+func compileShowLinkCommandFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileShowLinkCommandFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--show-link-command", sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), "avr-gcc")
+	require.Contains(t, string(stdout), sketchName+".ino.elf")
+	require.Contains(t, string(stdout), "core.a")
+	require.NotContains(t, string(stdout), "Used platform")
+
+	stdout, _, err = cli.Run("compile", "-b", "arduino:avr:uno", sketchPath.String(), "--format", "json")
+	require.NoError(t, err)
+	requirejson.Query(t, stdout, `.builder_result.link_command | contains("avr-gcc")`, "true")
+}
+
+func compileIDEPreferencesFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchPath := cli.SketchbookDir().Join("CompileIDEPreferencesFlag")
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	ideDir := cli.SketchbookDir().Join("arduino-1.8.19")
+	defer ideDir.RemoveAll()
+	require.NoError(t, ideDir.Join("libraries").MkdirAll())
+	preferences := ideDir.Join("preferences.txt")
+	require.NoError(t, preferences.WriteFile([]byte(
+		"last.ide.1.8.19.hardwarepath="+ideDir.Join("hardware").String()+"\n"+
+			"last.ide.1.8.19.daterun=1650000000000\n")))
+
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "-v", "--ide-preferences", preferences.String(), sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), "Using built-in libraries from folder: "+ideDir.Join("libraries").String())
+
+	// The libraries bundled with the IDE can be disabled
+	stdout, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "-v", "--ide-preferences", "none", sketchPath.String())
+	require.NoError(t, err)
+	require.NotContains(t, string(stdout), "Using built-in libraries from folder")
+
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--ide-preferences", sketchPath.Join("missing.txt").String(), sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "Invalid --ide-preferences file")
+}
+
+func compileTraceFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchPath := cli.SketchbookDir().Join("CompileTraceFlag")
+	defer sketchPath.RemoveAll()
+	buildPath := cli.SketchbookDir().Join("CompileTraceFlagBuild")
+	defer buildPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--trace", "--build-path", buildPath.String(), sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stderr), "Trace: sketch: "+sketchPath.String())
+	require.Contains(t, string(stderr), "Trace: build path: "+buildPath.String())
+	require.Contains(t, string(stderr), "Trace: board platform: "+cli.DataDir().Join("packages", "arduino", "hardware", "avr").String())
+	require.Contains(t, string(stderr), "Trace: tool arduino:avr-gcc@")
+	require.Contains(t, string(stderr), "Trace: libraries folder: "+cli.SketchbookDir().Join("libraries").String())
+
+	// Without the flag nothing is traced
+	_, stderr, err = cli.Run("compile", "-b", "arduino:avr:uno", "--build-path", buildPath.String(), sketchPath.String())
+	require.NoError(t, err)
+	require.NotContains(t, string(stderr), "Trace:")
+}
+
+func compileSketchFromSketchbook(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileSketchFromSketchbook"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	// The sketch is found in the sketchbook by name
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", sketchName, "--format", "json")
+	require.NoError(t, err)
+	requirejson.Query(t, stdout, `.builder_result.build_properties | map(select(. == "build.project_name=`+sketchName+`.ino")) | length`, "1")
+
+	// The user must choose among the sketches with the same name
+	otherSketchPath := cli.SketchbookDir().Join("projects", sketchName)
+	defer cli.SketchbookDir().Join("projects").RemoveAll()
+	_, _, err = cli.Run("sketch", "new", otherSketchPath.String())
+	require.NoError(t, err)
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", sketchName)
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "Multiple sketches named "+sketchName+" found in the sketchbook")
+	require.Contains(t, string(stderr), sketchPath.String())
+	require.Contains(t, string(stderr), otherSketchPath.String())
+
+	// Unknown names are still treated as paths
+	_, stderr, err = cli.Run("compile", "-b", "arduino:avr:uno", "MissingSketch")
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "MissingSketch")
+}
+
+func compileAllowedLibrarySourcesFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileAllowedLibrarySourcesFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+	require.NoError(t, sketchPath.Join(sketchName+".ino").WriteFile([]byte("#include <EEPROM.h>\nvoid setup() {}\nvoid loop() {}\n")))
+
+	// EEPROM is bundled with the platform
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--allowed-library-sources", "sketchbook", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "The following libraries come from a source that is not allowed: EEPROM")
+
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--allowed-library-sources", "sketchbook,core", sketchPath.String())
+	require.NoError(t, err)
+
+	_, stderr, err = cli.Run("compile", "-b", "arduino:avr:uno", "--allowed-library-sources", "everywhere", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "invalid library source 'everywhere'")
+}
+
+func compileValidateCompilationDatabaseFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileValidateCompilationDatabaseFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	buildPath := sketchPath.Join("build")
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--build-path", buildPath.String(),
+		"--only-compilation-database", "--validate-compilation-database", sketchPath.String())
+	require.NoError(t, err)
+	require.NotContains(t, string(stderr), "compilation database")
+	require.FileExists(t, buildPath.Join("compile_commands.json").String())
+}
+
+func compileNoBuildTimeFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileNoBuildTimeFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	// The time of the build is used by default
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--show-properties", sketchPath.String())
+	require.NoError(t, err)
+	require.NotContains(t, string(stdout), "extra.time.utc=0\n")
+
+	stdout, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--no-build-time", "--show-properties", sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), "extra.time.utc=0\n")
+	require.Contains(t, string(stdout), "extra.time.local=0\n")
+
+	customEnv := cli.GetDefaultEnv()
+	customEnv["SOURCE_DATE_EPOCH"] = "1600000000"
+	stdout, _, err = cli.RunWithCustomEnv(customEnv, "compile", "-b", "arduino:avr:uno", "--no-build-time", "--show-properties", sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), "extra.time.utc=1600000000\n")
+	require.Contains(t, string(stdout), "extra.time.zone=0\n")
+
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--no-build-time", sketchPath.String())
+	require.NoError(t, err)
+}
+
+func compileWarningsFileFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileWarningsFileFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+	sketchFile := sketchPath.Join(sketchName + ".ino")
+	require.NoError(t, sketchFile.WriteFile([]byte("void setup() {\n  int a;\n}\nvoid loop() {}\n")))
+
+	warningsFile := sketchPath.Join("reports", "warnings.txt")
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--warnings", "all", "--warnings-file", warningsFile.String(), sketchPath.String())
+	require.NoError(t, err)
+	// The compiler output is still printed
+	require.Contains(t, string(stderr), "unused variable 'a'")
+	data, err := warningsFile.ReadFile()
+	require.NoError(t, err)
+	require.Contains(t, string(data), sketchFile.String()+":2:7: unused variable 'a'")
+}
+
+func compileOnlyNewDiagnosticsFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileOnlyNewDiagnosticsFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+	require.NoError(t, sketchPath.Join("helper.h").WriteFile([]byte("inline void helper() {\n  int b;\n}\n")))
+	require.NoError(t, sketchPath.Join(sketchName+".ino").WriteFile([]byte("#include \"helper.h\"\nvoid setup() {\n  int a;\n}\nvoid loop() {}\n")))
+
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--warnings", "all", "--format", "json", sketchPath.String())
+	require.NoError(t, err)
+	requirejson.Query(t, stdout, `.builder_result.diagnostics | map(select(.file | endswith("helper.h"))) | length`, "1")
+	requirejson.Query(t, stdout, `.builder_result.diagnostics | map(select(.file | endswith(".ino"))) | length`, "1")
+
+	// The sketch is recompiled, the warning in the header it includes is kept
+	require.NoError(t, sketchPath.Join(sketchName+".ino").WriteFile([]byte("#include \"helper.h\"\nvoid setup() {\n  int a;\n}\nvoid loop() {\n}\n")))
+	stdout, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--warnings", "all", "--only-new-diagnostics", "--format", "json", sketchPath.String())
+	require.NoError(t, err)
+	requirejson.Query(t, stdout, `.builder_result.diagnostics | map(select(.file | endswith("helper.h"))) | length`, "1")
+	requirejson.Query(t, stdout, `.builder_result.diagnostics | map(select(.file | endswith(".ino"))) | length`, "1")
+}
+
+func compileCopyToFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileCopyToFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	release := cli.SketchbookDir().Join("release")
+	staging := cli.SketchbookDir().Join("staging")
+	defer release.RemoveAll()
+	defer staging.RemoveAll()
+	require.NoError(t, release.MkdirAll())
+	require.NoError(t, staging.MkdirAll())
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--copy-to", release.String(), "--copy-to", staging.String(), sketchPath.String())
+	require.NoError(t, err)
+	for _, dir := range []*paths.Path{release, staging} {
+		require.FileExists(t, dir.Join(sketchName+".ino.hex").String())
+		require.FileExists(t, dir.Join(sketchName+".ino.elf").String())
+	}
+
+	// The missing destination is reported, the other one is still copied
+	require.NoError(t, release.RemoveAll())
+	missing := cli.SketchbookDir().Join("missing")
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--copy-to", missing.String(), "--copy-to", release.String(), "--copy-to", staging.String(), sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "Error copying the artifacts to 2 of the 3 destinations")
+	require.Contains(t, string(stderr), missing.String())
+	require.Contains(t, string(stderr), release.String())
+	require.FileExists(t, staging.Join(sketchName+".ino.hex").String())
+}
+
+func compileArchiveFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileArchiveFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	archivePath := sketchPath.Join("release", "firmware")
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--archive", archivePath.String(), sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), "Artifacts archived to: "+archivePath.String()+".zip")
+
+	archive, err := zip.OpenReader(archivePath.String() + ".zip")
+	require.NoError(t, err)
+	defer archive.Close()
+	names := map[string]bool{}
+	for _, f := range archive.File {
+		names[f.Name] = true
+	}
+	folder := sketchName + "_arduino.avr.uno/"
+	require.True(t, names[folder+sketchName+".ino.hex"])
+	require.True(t, names[folder+sketchName+".ino.elf"])
+	require.True(t, names[folder+"manifest.json"])
+
+	// Only zip archives are supported
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--archive", sketchPath.Join("release", "firmware.tar.gz").String(), sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "Unsupported archive format .gz, only zip archives can be created")
+	require.NoFileExists(t, sketchPath.Join("release", "firmware.tar.gz").String())
+}
+
+func compileCyclicBuildProperties(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileCyclicBuildProperties"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--build-property", "build.extra_flags=-DFOO {build.extra_flags}", "--show-properties", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "the build property build.extra_flags references itself: build.extra_flags -> build.extra_flags")
+
+	_, stderr, err = cli.Run("compile", "-b", "arduino:avr:uno",
+		"--build-property", "build.extra_flags={compiler.foo}",
+		"--build-property", "compiler.foo={build.extra_flags}",
+		sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "build.extra_flags -> compiler.foo -> build.extra_flags")
+}
+
+func compileSketchNameFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileSketchNameFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	buildPath := sketchPath.Join("build-dir")
+	exportPath := sketchPath.Join("exported")
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--sketch-name", "firmware",
+		"--build-path", buildPath.String(), "--output-dir", exportPath.String(), sketchPath.String())
+	require.NoError(t, err)
+	require.FileExists(t, buildPath.Join("firmware.ino.hex").String())
+	require.FileExists(t, exportPath.Join("firmware.ino.hex").String())
+	require.FileExists(t, exportPath.Join("firmware.ino.elf").String())
+	require.NoFileExists(t, exportPath.Join(sketchName+".ino.hex").String())
+
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--sketch-name", "fw/1", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "invalid sketch name 'fw/1'")
+
+	_, stderr, err = cli.Run("compile", "-b", "arduino:avr:uno", "--sketch-name", "firmware", "--build-property", "build.project_name=other.ino", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "The sketch name conflicts with the build property build.project_name=other.ino")
+}
+
+func compileVerifyToolFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileVerifyToolFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	platformLocalTxt := cli.DataDir().Join("packages", "arduino", "hardware", "avr", "1.8.5", "platform.local.txt")
+	defer platformLocalTxt.Remove()
+	require.NoError(t, platformLocalTxt.WriteFile([]byte(
+		"tools.elfcheck.cmd={runtime.tools.avr-gcc.path}/bin/avr-size\n"+
+			"tools.elfcheck.verify.pattern=\"{cmd}\" \"{build.path}/{build.project_name}.elf\"\n"+
+			"tools.badcheck.cmd={runtime.tools.avr-gcc.path}/bin/avr-size\n"+
+			"tools.badcheck.verify.pattern=\"{cmd}\" \"{build.path}/missing.elf\"\n")))
+
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--verify-tool", "elfcheck", sketchPath.String())
+	require.NoError(t, err)
+
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--verify-tool", "badcheck", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "Verification with the tool badcheck failed")
+
+	_, stderr, err = cli.Run("compile", "-b", "arduino:avr:uno", "--verify-tool", "avrdude", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "tool avrdude doesn't define the verify.pattern recipe")
+}
+
+func compileExportDepGraphFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileExportDepGraphFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+	require.NoError(t, sketchPath.Join(sketchName+".ino").WriteFile([]byte("#include <LibA.h>\nvoid setup() {}\nvoid loop() {}\n")))
+
+	// The sources of LibA include LibB
+	libA := sketchPath.Join("libs", "LibA")
+	libB := sketchPath.Join("libs", "LibB")
+	require.NoError(t, libA.MkdirAll())
+	require.NoError(t, libB.MkdirAll())
+	require.NoError(t, libA.Join("LibA.h").WriteFile([]byte("#define LIB_A 1\n")))
+	require.NoError(t, libA.Join("LibA.cpp").WriteFile([]byte("#include \"LibA.h\"\n#include <LibB.h>\n")))
+	require.NoError(t, libB.Join("LibB.h").WriteFile([]byte("#define LIB_B 1\n")))
+
+	graphFile := sketchPath.Join("graph.json")
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--library", libA.String(), "--library", libB.String(),
+		"--export-dep-graph", graphFile.String(), sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), "Dependency graph saved to: "+graphFile.String())
+	data, err := graphFile.ReadFile()
+	require.NoError(t, err)
+	requirejson.Query(t, data, `.sketch`, `"`+sketchName+`"`)
+	requirejson.Query(t, data, `.nodes`, `["`+sketchName+`","LibA","LibB"]`)
+	requirejson.Query(t, data, `.edges`, `[{"from":"`+sketchName+`","to":"LibA"},{"from":"LibA","to":"LibB"}]`)
+
+	dotFile := sketchPath.Join("graph.dot")
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--library", libA.String(), "--library", libB.String(),
+		"--export-dep-graph", dotFile.String(), sketchPath.String())
+	require.NoError(t, err)
+	data, err = dotFile.ReadFile()
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"LibA" -> "LibB";`)
+
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--export-dep-graph", sketchPath.Join("graph.txt").String(), sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "unsupported dependency graph format '.txt'")
+}
+
+func compileCompilerPathFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileCompilerPathFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	// The toolchain installed with the platform is used as a system toolchain
+	toolchainDir := cli.DataDir().Join("packages", "arduino", "tools", "avr-gcc", "7.3.0-atmel3.6.1-arduino7", "bin")
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--compiler-path", toolchainDir.String(), "--show-properties", sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), "compiler.path="+toolchainDir.String()+string(os.PathSeparator)+"\n")
+
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--compiler-path", toolchainDir.String(), sketchPath.String())
+	require.NoError(t, err)
+
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--compiler-path", sketchPath.String(), sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "the following compiler executables are missing in "+sketchPath.String())
+}
+
+func compileExplainPropertyFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileExplainPropertyFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	platformDir := cli.DataDir().Join("packages", "arduino", "hardware", "avr", "1.8.5")
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--explain-property", "compiler.warning_flags",
+		"--build-property", "compiler.warning_flags=-Wall", sketchPath.String(), "--format", "json")
+	require.NoError(t, err)
+	requirejson.Query(t, stdout, `.builder_result.property_explanation.default_value`, `"-w"`)
+	requirejson.Query(t, stdout, `.builder_result.property_explanation.source`, `"`+strings.ReplaceAll(platformDir.Join("platform.txt").String(), `\`, `\\`)+`"`)
+	requirejson.Query(t, stdout, `.builder_result.property_explanation.value`, `"-Wall"`)
+
+	stdout, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--explain-property", "build.mcu", sketchPath.String(), "--format", "json")
+	require.NoError(t, err)
+	requirejson.Query(t, stdout, `.builder_result.property_explanation.value`, `"atmega328p"`)
+	requirejson.Query(t, stdout, `.builder_result.property_explanation.source`, `"`+strings.ReplaceAll(platformDir.Join("boards.txt").String(), `\`, `\\`)+`"`)
+
+	stdout, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--explain-property", "build.arch", sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), "set at runtime by arduino-cli")
+}
+
+func compileRecipeOverrides(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileRecipeOverrides"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	// A misspelled recipe is reported
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--show-properties",
+		"--build-property", "recipe.objcopy.hexx.pattern=avr-objcopy", sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stderr), "Warning: the build property recipe.objcopy.hexx.pattern overrides a recipe not defined by the platform")
+
+	// The hooks are not defined by the platform
+	_, stderr, err = cli.Run("compile", "-b", "arduino:avr:uno", "--show-properties",
+		"--build-property", "recipe.hooks.prebuild.1.pattern=echo prebuild", sketchPath.String())
+	require.NoError(t, err)
+	require.NotContains(t, string(stderr), "overrides a recipe not defined by the platform")
+
+	// The overridden recipes are shown in verbose mode
+	stdout, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "-v",
+		"--build-property", "recipe.output.save_file={build.project_name}.custom.hex", sketchPath.String())
+	require.NoError(t, err)
+	require.NotContains(t, string(stderr), "overrides a recipe not defined by the platform")
+	require.Contains(t, string(stdout), "Overriding recipe recipe.output.save_file\n")
+	require.Contains(t, string(stdout), "  before: {build.project_name}.{build.variant}.hex\n")
+	require.Contains(t, string(stdout), "  after: {build.project_name}.custom.hex\n")
+}
+
+func compileSketchEncodingFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileSketchEncodingFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+	mainFile := sketchPath.Join(sketchName + ".ino")
+	require.NoError(t, mainFile.WriteFile([]byte("const char *name = \"Caf\xE9\";\nvoid setup() {\n}\n\nvoid loop() {\n}\n")))
+
+	stdout, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--sketch-encoding", "latin1", "--preprocess", sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), "const char *name = \"Café\";")
+	require.NotContains(t, string(stderr), "is encoded as")
+
+	_, stderr, err = cli.Run("compile", "-b", "arduino:avr:uno", "--sketch-encoding", "latin1", sketchPath.String())
+	require.NoError(t, err)
+	require.NotContains(t, string(stderr), "is encoded as")
+	require.NotContains(t, string(stderr), "is not UTF-8 encoded")
+
+	_, stderr, err = cli.Run("compile", "-b", "arduino:avr:uno", "--sketch-encoding", "bogus", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "unknown sketch encoding 'bogus'")
+}
+
+func compileExplainExitCodesFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	// The sketch is not needed
+	stdout, _, err := cli.Run("compile", "--explain-exit-codes", "--format", "json")
+	require.NoError(t, err)
+	requirejson.Query(t, stdout, `.exit_codes | length`, `11`)
+	requirejson.Query(t, stdout, `.exit_codes | map(select(.code == 7)) | length`, `1`)
+
+	stdout, _, err = cli.Run("compile", "--explain-exit-codes")
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), "The command was interrupted by a SIGINT or SIGTERM signal.")
+
+	sketchName := "CompileExplainExitCodesFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err = cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	// An invalid argument is reported with the specific exit code
+	_, _, err = cli.Run("compile", sketchPath.String())
+	require.ErrorContains(t, err, "exit status 7")
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--sketch-encoding", "bogus", sketchPath.String())
+	require.ErrorContains(t, err, "exit status 7")
+}
+
+func compileBuildPropertiesLayers(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileBuildPropertiesLayers"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	base := sketchPath.Join("base.txt")
+	require.NoError(t, base.WriteFile([]byte("build.layer.base=base\nbuild.layer.env=base\nbuild.layer.inline=base\n")))
+	overlay := sketchPath.Join("env.txt")
+	require.NoError(t, overlay.WriteFile([]byte("build.layer.env=env\nbuild.layer.inline=env\n")))
+
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--show-properties",
+		"--build-properties-file", base.String(),
+		"--build-properties-overlay", overlay.String(),
+		"--build-property", "build.layer.inline=inline",
+		sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), "build.layer.base=base\n")
+	require.Contains(t, string(stdout), "build.layer.env=env\n")
+	require.Contains(t, string(stdout), "build.layer.inline=inline\n")
+
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno",
+		"--build-properties-file", sketchPath.Join("missing.txt").String(), sketchPath.String())
+	require.ErrorContains(t, err, "exit status 7")
+	require.Contains(t, string(stderr), "Error reading the build properties files")
+}
+
+func compileCompareToFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileCompareToFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+	buildPath := sketchPath.Join("build")
+	referencePath := sketchPath.Join("reference")
+	require.NoError(t, referencePath.MkdirAll())
+
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--reproducible", "--build-path", buildPath.String(), sketchPath.String())
+	require.NoError(t, err)
+	reference := referencePath.Join("reference.hex")
+	require.NoError(t, buildPath.Join(sketchName+".ino.hex").CopyTo(reference))
+
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--reproducible", "--build-path", buildPath.String(), "--compare-to", reference.String(), sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), "The artifact "+sketchName+".ino.hex matches the reference "+reference.String())
+
+	// A change in the sketch changes the output
+	require.NoError(t, sketchPath.Join(sketchName+".ino").WriteFile([]byte("void setup() {\n  pinMode(13, OUTPUT);\n}\n\nvoid loop() {\n}\n")))
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--reproducible", "--build-path", buildPath.String(), "--compare-to", reference.String(), sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "The artifact "+sketchName+".ino.hex differs from the reference "+reference.String()+" at byte")
+
+	_, stderr, err = cli.Run("compile", "-b", "arduino:avr:uno", "--compare-to", referencePath.Join("missing.hex").String(), sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "doesn't exist")
+}
+
+func compileExcludeFileFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileExcludeFileFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+	buildPath := sketchPath.Join("build")
+	// A work in progress tab that doesn't compile
+	require.NoError(t, sketchPath.Join("wip.ino").WriteFile([]byte("void wip() {\n  this does not compile\n}\n")))
+
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--build-path", buildPath.String(), sketchPath.String())
+	require.Error(t, err)
+
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--build-path", buildPath.String(), "--exclude-file", "wip.ino", sketchPath.String())
+	require.NoError(t, err)
+
+	// The copies left in the build path by a previous build are not compiled
+	require.NoError(t, sketchPath.Join("helper.cpp").WriteFile([]byte("this does not compile\n")))
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--build-path", buildPath.String(), "--exclude-file", "wip.ino", sketchPath.String())
+	require.Error(t, err)
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--build-path", buildPath.String(), "--exclude-file", "wip.ino", "--exclude-file", "helper.cpp", sketchPath.String())
+	require.NoError(t, err)
+
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--exclude-file", sketchName+".ino", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "the main sketch file "+sketchName+".ino can't be excluded")
+
+	_, stderr, err = cli.Run("compile", "-b", "arduino:avr:uno", "--exclude-file", "missing.ino", sketchPath.String())
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "the file missing.ino doesn't exist in the sketch folder")
+}
+
+func compileDumpDefinesFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileDumpDefinesFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--dump-defines", "-D", "MY_FEATURE", sketchPath.String())
+	require.NoError(t, err)
+	defines := strings.Split(strings.TrimSpace(string(stdout)), "\n")
+	require.Contains(t, defines, "MY_FEATURE")
+	require.Contains(t, defines, "F_CPU=16000000L")
+	require.Contains(t, defines, "ARDUINO_AVR_UNO")
+	require.True(t, sort.StringsAreSorted(defines))
+
+	stdout, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--dump-defines", sketchPath.String(), "--format", "json")
+	require.NoError(t, err)
+	requirejson.Query(t, stdout, `.builder_result.defines | map(select(. == "ARDUINO_ARCH_AVR")) | length`, `1`)
+	requirejson.Query(t, stdout, `.builder_result.defines | map(select(. == "MY_FEATURE")) | length`, `0`)
+}
+
+func compileWatchFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileWatchFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	// The watch mode streams the build output, so it's available only in text format
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--watch", sketchPath.String(), "--format", "json")
+	require.ErrorContains(t, err, "exit status 7")
+	require.Contains(t, string(stderr), "The --watch flag is available only in text format")
+
+	_, stderr, err = cli.Run("compile", "-b", "arduino:avr:uno", "--watch", "--upload", sketchPath.String())
+	require.ErrorContains(t, err, "exit status 7")
+	require.Contains(t, string(stderr), "Can't use the following flags together: --watch, --upload")
+}
+
+func compileBuilderPathFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileBuilderPathFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--builder-path", sketchPath.Join("missing").String(), sketchPath.String())
+	require.ErrorContains(t, err, "exit status 7")
+	require.Contains(t, string(stderr), "Invalid builder path")
+
+	_, stderr, err = cli.Run("compile", "-b", "arduino:avr:uno", "--builder-path", sketchPath.Join(sketchName+".ino").String(), "--dump-defines", sketchPath.String())
+	require.ErrorContains(t, err, "exit status 7")
+	require.Contains(t, string(stderr), "Can't use the following flags together: --builder-path, --dump-defines")
+}
+
+func compileMaxBuildPathLengthFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileMaxBuildPathLengthFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+	buildPath := sketchPath.Join("build")
+	warning := fmt.Sprintf("The build path %s is %d characters long, more than 10", buildPath, len(buildPath.String()))
+
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--build-path", buildPath.String(), "--max-build-path-length", "10", sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stderr), warning)
+
+	_, stderr, err = cli.Run("compile", "-b", "arduino:avr:uno", "--build-path", buildPath.String(), "--max-build-path-length", "0", sketchPath.String())
+	require.NoError(t, err)
+	require.NotContains(t, string(stderr), "The build path")
+
+	_, stderr, err = cli.Run("compile", "-b", "arduino:avr:uno", "--build-path", buildPath.String(), "--max-build-path-length", "10", "--strict", sketchPath.String())
+	require.ErrorContains(t, err, "exit status 7")
+	require.Contains(t, string(stderr), warning)
+}
+
+func compileIDEVersionFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileIDEVersionFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--ide-version", "1.8.19", "--show-properties", sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, strings.Split(string(stdout), "\n"), "runtime.ide.version=10819")
+
+	stdout, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--ide-version", "10819", "--dump-defines", sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, strings.Split(strings.TrimSpace(string(stdout)), "\n"), "ARDUINO=10819")
+
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--ide-version", "1.8", sketchPath.String())
+	require.ErrorContains(t, err, "exit status 7")
+	require.Contains(t, string(stderr), "invalid IDE version '1.8'")
+}
+
+func compileSaveAndLoadContext(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileSaveAndLoadContext"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+	contextFile := cli.SketchbookDir().Join("context.json")
+	defer contextFile.Remove()
+
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--build-property", "build.extra_flags=-DMY_FEATURE", "--save-context", contextFile.String(), sketchPath.String())
+	require.NoError(t, err)
+	data, err := contextFile.ReadFile()
+	require.NoError(t, err)
+	requirejson.Query(t, data, `.request.fqbn`, `"arduino:avr:uno"`)
+	requirejson.Query(t, data, `.request.sketchPath`, fmt.Sprintf("%q", sketchPath.String()))
+	requirejson.Query(t, data, `.request.buildProperties`, `["build.extra_flags=-DMY_FEATURE"]`)
+	// The resolved environment is saved too
+	requirejson.Query(t, data, `.hardware_dirs | length > 0`, `true`)
+	requirejson.Query(t, data, `.libraries_dirs | length > 0`, `true`)
+	requirejson.Query(t, data, `.build_properties | map(select(. == "build.extra_flags=-DMY_FEATURE")) | length`, `1`)
+
+	// The build is replayed with the saved board and build properties
+	stdout, stderr, err := cli.Run("compile", "--load-context", contextFile.String(), "--format", "json")
+	require.NoError(t, err)
+	requirejson.Query(t, stdout, `.success`, `true`)
+	requirejson.Query(t, stdout, `.builder_result.build_properties | map(select(. == "build.extra_flags=-DMY_FEATURE")) | length`, `1`)
+	require.NotContains(t, string(stderr), "differ from the saved context")
+
+	_, stderr, err = cli.Run("compile", "--load-context", contextFile.String(), sketchPath.String())
+	require.ErrorContains(t, err, "exit status 7")
+	require.Contains(t, string(stderr), "You cannot pass a sketch path together with the --load-context flag.")
+
+	// The other flags would be ignored
+	_, stderr, err = cli.Run("compile", "--load-context", contextFile.String(), "--upload")
+	require.ErrorContains(t, err, "exit status 7")
+	require.Contains(t, string(stderr), "Can't use the following flags together: --load-context, --upload")
+}
+
+func compileFileFlags(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileFileFlags"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+	require.NoError(t, sketchPath.Join("src").MkdirAll())
+	require.NoError(t, sketchPath.Join("src", "driver.cpp").WriteFile([]byte("int driver() { return 0; }\n")))
+	buildPath := sketchPath.Join("build")
+
+	_, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--build-path", buildPath.String(), "--file-flags", "src/driver.cpp=-O0", sketchPath.String())
+	require.NoError(t, err)
+	compilationDatabase, err := buildPath.Join("compile_commands.json").ReadFile()
+	require.NoError(t, err)
+	// Only the targeted file gets the extra flag, at the end of the command line
+	requirejson.Query(t, compilationDatabase, `map(select(.file | endswith("driver.cpp"))) | .[0].arguments | .[-1]`, `"-O0"`)
+	requirejson.Query(t, compilationDatabase, `map(select(.file | endswith(".ino.cpp"))) | .[0].arguments | any(. == "-O0")`, `false`)
+
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:uno", "--file-flags", "src/missing.cpp=-O0", sketchPath.String())
+	require.ErrorContains(t, err, "exit status 7")
+	require.Contains(t, string(stderr), "the file src/missing.cpp doesn't exist")
+}
+
+func compileSummaryFlag(t *testing.T, env *integrationtest.Environment, cli *integrationtest.ArduinoCLI) {
+	sketchName := "CompileSummaryFlag"
+	sketchPath := cli.SketchbookDir().Join(sketchName)
+	defer sketchPath.RemoveAll()
+	_, _, err := cli.Run("sketch", "new", sketchPath.String())
+	require.NoError(t, err)
+
+	stdout, _, err := cli.Run("compile", "-b", "arduino:avr:uno", "--summary", "--format", "json", sketchPath.String())
+	require.NoError(t, err)
+	requirejson.Query(t, stdout, `.builder_result.summary.files_by_extension | has(".cpp")`, "true")
+	requirejson.Query(t, stdout, `.builder_result.summary.unique_files > 1`, "true")
+	requirejson.Query(t, stdout, `.builder_result.summary.total_commands >= .builder_result.summary.unique_files`, "true")
+
+	stdout, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--summary", sketchPath.String())
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), "Compile commands")
+
+	// The summary is not returned if not requested
+	stdout, _, err = cli.Run("compile", "-b", "arduino:avr:uno", "--format", "json", sketchPath.String())
+	require.NoError(t, err)
+	requirejson.Query(t, stdout, `.builder_result.summary`, "null")
+}
@@ -90,13 +90,11 @@ func TestCompileBuildPathInsideSketch(t *testing.T) {
 	require.NoError(t, err)
 
 	cli.SetWorkingDir(cli.WorkingDir().Join(sketch))
-	// Compile the sketch creating the build directory inside the sketch directory
-	_, _, err = cli.Run("compile", "-b", "arduino:avr:mega", "--build-path", "build-mega")
-	require.NoError(t, err)
-
-	// Compile again using the same build path
-	_, _, err = cli.Run("compile", "-b", "arduino:avr:mega", "--build-path", "build-mega")
-	require.NoError(t, err)
+	// A build directory inside the sketch directory is rejected
+	_, stderr, err := cli.Run("compile", "-b", "arduino:avr:mega", "--build-path", "build-mega")
+	require.Error(t, err)
+	require.Contains(t, string(stderr), "The build path can't be inside the sketch folder")
+	require.False(t, cli.WorkingDir().Join("build-mega").Exist())
 }
 
 func TestCompilerErrOutput(t *testing.T) {
@@ -85,12 +85,19 @@ func TestLogOptions(t *testing.T) {
 	outLines := strings.Split(trimOut, "\n")
 	require.Len(t, outLines, 1)
 
-	// Plain text logs on stdout
+	// Plain text logs on stdout, colors are disabled since the output is not a terminal
 	stdout, _, err = cli.Run("version", "-v")
 	require.NoError(t, err)
 	trimOut = strings.TrimSpace(string(stdout))
 	outLines = strings.Split(trimOut, "\n")
 	require.Greater(t, len(outLines), 1)
+	require.Contains(t, outLines[0], "level=info")
+
+	stdout, _, err = cli.Run("version", "-v", "--force-tty")
+	require.NoError(t, err)
+	trimOut = strings.TrimSpace(string(stdout))
+	outLines = strings.Split(trimOut, "\n")
+	require.Greater(t, len(outLines), 1)
 	require.True(t, strings.HasPrefix(outLines[0], "\x1b[36mINFO\x1b[0m")) // account for the colors
 
 	// Plain text logs on file
@@ -28,6 +28,11 @@ func main() {
 	configuration.Settings = configuration.Init(configuration.FindConfigFileInArgs(os.Args))
 	i18n.Init(configuration.Settings.GetString("locale"))
 	arduinoCmd := cli.NewCommand()
+	args, err := cli.ExpandResponseFiles(arduinoCmd, os.Args[1:])
+	if err != nil {
+		feedback.FatalError(err, feedback.ErrBadArgument)
+	}
+	arduinoCmd.SetArgs(args)
 	if err := arduinoCmd.Execute(); err != nil {
 		feedback.FatalError(err, feedback.ErrGeneric)
 	}
@@ -36,6 +36,58 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type LinkTimeOptimization int32
+
+const (
+	// Use the link time optimization settings of the platform.
+	LinkTimeOptimization_LINK_TIME_OPTIMIZATION_DEFAULT LinkTimeOptimization = 0
+	// Enable the link time optimization.
+	LinkTimeOptimization_LINK_TIME_OPTIMIZATION_ENABLED LinkTimeOptimization = 1
+	// Disable the link time optimization.
+	LinkTimeOptimization_LINK_TIME_OPTIMIZATION_DISABLED LinkTimeOptimization = 2
+)
+
+// Enum value maps for LinkTimeOptimization.
+var (
+	LinkTimeOptimization_name = map[int32]string{
+		0: "LINK_TIME_OPTIMIZATION_DEFAULT",
+		1: "LINK_TIME_OPTIMIZATION_ENABLED",
+		2: "LINK_TIME_OPTIMIZATION_DISABLED",
+	}
+	LinkTimeOptimization_value = map[string]int32{
+		"LINK_TIME_OPTIMIZATION_DEFAULT":  0,
+		"LINK_TIME_OPTIMIZATION_ENABLED":  1,
+		"LINK_TIME_OPTIMIZATION_DISABLED": 2,
+	}
+)
+
+func (x LinkTimeOptimization) Enum() *LinkTimeOptimization {
+	p := new(LinkTimeOptimization)
+	*p = x
+	return p
+}
+
+func (x LinkTimeOptimization) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LinkTimeOptimization) Descriptor() protoreflect.EnumDescriptor {
+	return file_cc_arduino_cli_commands_v1_compile_proto_enumTypes[0].Descriptor()
+}
+
+func (LinkTimeOptimization) Type() protoreflect.EnumType {
+	return &file_cc_arduino_cli_commands_v1_compile_proto_enumTypes[0]
+}
+
+func (x LinkTimeOptimization) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LinkTimeOptimization.Descriptor instead.
+func (LinkTimeOptimization) EnumDescriptor() ([]byte, []int) {
+	return file_cc_arduino_cli_commands_v1_compile_proto_rawDescGZIP(), []int{0}
+}
+
 type CompileRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -111,6 +163,189 @@ type CompileRequest struct {
 	// If set to true the returned build properties will be left unexpanded, with
 	// the variables placeholders exactly as defined in the platform.
 	DoNotExpandBuildProperties bool `protobuf:"varint,29,opt,name=do_not_expand_build_properties,json=doNotExpandBuildProperties,proto3" json:"do_not_expand_build_properties,omitempty"`
+	// If set to true the build is made reproducible: absolute build and sketch
+	// paths are stripped from the compiled binaries and the build timestamps are
+	// normalized.
+	Reproducible bool `protobuf:"varint,30,opt,name=reproducible,proto3" json:"reproducible,omitempty"`
+	// The precedence of the libraries locations used to choose between
+	// duplicated libraries. Allowed values are "sketchbook", "bundled" and
+	// "core", the locations listed first take precedence.
+	LibraryPathOrder []string `protobuf:"bytes,31,rep,name=library_path_order,json=libraryPathOrder,proto3" json:"library_path_order,omitempty"`
+	// Extra flags to append to the compiler command line, in the form
+	// "[scope:]flags". Allowed scopes are "all" (the default), "c", "cpp", "S"
+	// and "ld".
+	ExtraFlags []string `protobuf:"bytes,32,rep,name=extra_flags,json=extraFlags,proto3" json:"extra_flags,omitempty"`
+	// If set to true only the libraries explicitly specified with `library` and
+	// `libraries` are used to resolve the sketch includes: the libraries
+	// installed in the sketchbook, bundled with the IDE or with the platform are
+	// ignored.
+	OnlyExplicitLibraries bool `protobuf:"varint,33,opt,name=only_explicit_libraries,json=onlyExplicitLibraries,proto3" json:"only_explicit_libraries,omitempty"`
+	// If set to true each library is compiled using only its own include path,
+	// the include paths of the core and of its declared dependencies, followed
+	// transitively. This allows to detect libraries that rely on headers of
+	// other libraries.
+	StrictIncludes bool `protobuf:"varint,34,opt,name=strict_includes,json=strictIncludes,proto3" json:"strict_includes,omitempty"`
+	// If set to true the compiled object files and the core archive are copied
+	// in the "objects" folder of the output directory, together with a
+	// "manifest.json" file mapping each source file to its object file.
+	KeepObjects bool `protobuf:"varint,35,opt,name=keep_objects,json=keepObjects,proto3" json:"keep_objects,omitempty"`
+	// Preprocessor macros to define, in the form "NAME" or "NAME=VALUE". They
+	// are appended to the "build.extra_flags" property.
+	Defines []string `protobuf:"bytes,36,rep,name=defines,proto3" json:"defines,omitempty"`
+	// If set to true the sketch source code produced by the Arduino
+	// preprocessing (merged .ino files with the generated function prototypes)
+	// is copied in the output directory.
+	ExportPreprocessedSketch bool `protobuf:"varint,37,opt,name=export_preprocessed_sketch,json=exportPreprocessedSketch,proto3" json:"export_preprocessed_sketch,omitempty"`
+	// Path of the compilation database file, defaults to
+	// "compile_commands.json" in the build path.
+	CompilationDatabasePath string `protobuf:"bytes,38,opt,name=compilation_database_path,json=compilationDatabasePath,proto3" json:"compilation_database_path,omitempty"`
+	// If set to true the build fails if an artifact exported in the export
+	// directory would overwrite an existing file.
+	NoOverwrite bool `protobuf:"varint,39,opt,name=no_overwrite,json=noOverwrite,proto3" json:"no_overwrite,omitempty"`
+	// If set to true the existing files overwritten by the exported artifacts
+	// are renamed with the ".bak" suffix.
+	Backup bool `protobuf:"varint,40,opt,name=backup,proto3" json:"backup,omitempty"`
+	// If set to true the symlinks in the sketch and build paths are kept,
+	// otherwise they are resolved to the real paths.
+	NoFollowSymlinks bool `protobuf:"varint,41,opt,name=no_follow_symlinks,json=noFollowSymlinks,proto3" json:"no_follow_symlinks,omitempty"`
+	// The C++ standard to compile the sketch with (for example `c++17`).
+	CppStd string `protobuf:"bytes,42,opt,name=cpp_std,json=cppStd,proto3" json:"cpp_std,omitempty"`
+	// The C standard to compile the sketch with (for example `gnu11`).
+	CStd string `protobuf:"bytes,43,opt,name=c_std,json=cStd,proto3" json:"c_std,omitempty"`
+	// The separator used in place of `:` when the FQBN is used as the name of
+	// the default export directory (defaults to `.`).
+	FqbnSeparator string `protobuf:"bytes,44,opt,name=fqbn_separator,json=fqbnSeparator,proto3" json:"fqbn_separator,omitempty"`
+	// If set to true, when the build fails the command that failed and its full
+	// output are reported, even if the build is not verbose.
+	VerboseOnFailure bool `protobuf:"varint,45,opt,name=verbose_on_failure,json=verboseOnFailure,proto3" json:"verbose_on_failure,omitempty"`
+	// The output formats (for example `hex`, `bin` or `uf2`) that must be
+	// produced by the build, in addition to the ones produced by default.
+	OutputFormats []string `protobuf:"bytes,46,rep,name=output_formats,json=outputFormats,proto3" json:"output_formats,omitempty"`
+	// Tools to use in place of the ones selected by the platform, in the form
+	// `[packager:]name@version` (for example `avr-gcc@7.3.0-atmel3.6.1-arduino7`).
+	ToolOverrides []string `protobuf:"bytes,47,rep,name=tool_overrides,json=toolOverrides,proto3" json:"tool_overrides,omitempty"`
+	// If set to true, the FQBN must name exactly a board of an installed
+	// platform, with valid configuration options, before starting the build.
+	StrictFqbn bool `protobuf:"varint,48,opt,name=strict_fqbn,json=strictFqbn,proto3" json:"strict_fqbn,omitempty"`
+	// If set to true, the SHA-256 checksum of each produced artifact is written
+	// in a sidecar file with the ".sha256" suffix.
+	WriteChecksums bool `protobuf:"varint,49,opt,name=write_checksums,json=writeChecksums,proto3" json:"write_checksums,omitempty"`
+	// Enable or disable the link time optimization, overriding the flags of the
+	// platform.
+	LinkTimeOptimization LinkTimeOptimization `protobuf:"varint,50,opt,name=link_time_optimization,json=linkTimeOptimization,proto3,enum=cc.arduino.cli.commands.v1.LinkTimeOptimization" json:"link_time_optimization,omitempty"`
+	// If set, only the given sketch file is compiled to report its errors and
+	// warnings: the core and the libraries are not compiled and nothing is
+	// linked.
+	CheckFile string `protobuf:"bytes,51,opt,name=check_file,json=checkFile,proto3" json:"check_file,omitempty"`
+	// The main sketch file to use, when it's not named after the sketch folder.
+	// It can be a file name or a path to a file in the sketch folder.
+	MainFile string `protobuf:"bytes,52,opt,name=main_file,json=mainFile,proto3" json:"main_file,omitempty"`
+	// If set to true, the generation of the dependency files is enabled and all
+	// of them are merged in a single Makefile-compatible manifest, named
+	// "dependencies.d", in the output directory.
+	ExportDeps bool `protobuf:"varint,53,opt,name=export_deps,json=exportDeps,proto3" json:"export_deps,omitempty"`
+	// Override the build.board property of the board, that is used by the
+	// platform recipes to compose the ARDUINO_{build.board} define. The value
+	// may be given with or without the ARDUINO_ prefix.
+	BoardDefine string `protobuf:"bytes,54,opt,name=board_define,json=boardDefine,proto3" json:"board_define,omitempty"`
+	// The bootloader to build for, among the options of the "bootloader" menu
+	// of the board. The build properties of the selected option are used.
+	Bootloader string `protobuf:"bytes,55,opt,name=bootloader,proto3" json:"bootloader,omitempty"`
+	// If set to true, the paths resolved for the build (sketch, build path,
+	// caches, platforms, tools and libraries folders) are written to the error
+	// stream before running the build.
+	Trace bool `protobuf:"varint,56,opt,name=trace,proto3" json:"trace,omitempty"`
+	// If set, the build fails if a library used by the sketch doesn't come
+	// from one of these sources: "sketchbook", "bundled" (libraries bundled
+	// with the IDE), "core" (libraries bundled with the platform) and
+	// "unmanaged" (libraries given with the library and libraries fields).
+	AllowedLibrarySources []string `protobuf:"bytes,57,rep,name=allowed_library_sources,json=allowedLibrarySources,proto3" json:"allowed_library_sources,omitempty"`
+	// If set to true, after the build the entries of the compilation database
+	// are checked against the filesystem, and the build fails if any of them
+	// references a file or a directory that doesn't exist.
+	ValidateCompilationDatabase bool `protobuf:"varint,58,opt,name=validate_compilation_database,json=validateCompilationDatabase,proto3" json:"validate_compilation_database,omitempty"`
+	// If set to true, the extra.time.* build properties are set to the time in
+	// the SOURCE_DATE_EPOCH environment variable (or to 0 if it's not set)
+	// instead of the time of the build. This is implied by reproducible.
+	NoBuildTime bool `protobuf:"varint,59,opt,name=no_build_time,json=noBuildTime,proto3" json:"no_build_time,omitempty"`
+	// If set to true, only the diagnostics emitted while compiling the source
+	// files recompiled by this build are returned, including the ones in the
+	// headers they include.
+	OnlyNewDiagnostics bool `protobuf:"varint,60,opt,name=only_new_diagnostics,json=onlyNewDiagnostics,proto3" json:"only_new_diagnostics,omitempty"`
+	// The directories where the final artifacts (.hex, .bin and .elf files) are
+	// copied after the build. Each directory must exist, if the copy to one of
+	// them fails the others are still copied but the build fails.
+	CopyTo []string `protobuf:"bytes,61,rep,name=copy_to,json=copyTo,proto3" json:"copy_to,omitempty"`
+	// If set, after the build the exported artifacts and a manifest describing
+	// them are collected in a zip archive at this path, inside a folder named
+	// after the sketch and the board. The ".zip" extension is added if the path
+	// has no extension.
+	Archive string `protobuf:"bytes,62,opt,name=archive,proto3" json:"archive,omitempty"`
+	// If set, the artifacts are named after this name instead of the name of
+	// the sketch, for example "firmware" produces "firmware.ino.hex". The
+	// sketch is still looked up with its actual name.
+	SketchName string `protobuf:"bytes,63,opt,name=sketch_name,json=sketchName,proto3" json:"sketch_name,omitempty"`
+	// If set, after the build the "verify.pattern" recipe of this platform tool
+	// (defined as "tools.<verify_tool>.verify.pattern") is run, and the build
+	// fails if it returns a non-zero exit code.
+	VerifyTool string `protobuf:"bytes,64,opt,name=verify_tool,json=verifyTool,proto3" json:"verify_tool,omitempty"`
+	// If set, after the build the graph of the libraries included by the
+	// sketch and by each library is written to this file, in DOT format if the
+	// file has the ".dot" extension or in JSON format if it has the ".json" one.
+	ExportDepGraph string `protobuf:"bytes,65,opt,name=export_dep_graph,json=exportDepGraph,proto3" json:"export_dep_graph,omitempty"`
+	// If set, the compiler executables are run from this directory instead of
+	// the one of the toolchain installed with the platform, by setting the
+	// "compiler.path" build property. The directory must contain all the
+	// executables listed in the "compiler.*.cmd" build properties.
+	CompilerPath string `protobuf:"bytes,66,opt,name=compiler_path,json=compilerPath,proto3" json:"compiler_path,omitempty"`
+	// If set, the build is not run and the explanation of the given build
+	// property is returned in the BuilderResult: its default value, the file
+	// where it's defined and the value after applying the overrides.
+	ExplainProperty string `protobuf:"bytes,67,opt,name=explain_property,json=explainProperty,proto3" json:"explain_property,omitempty"`
+	// The encoding of the sketch source files, for example "utf8" or "latin1",
+	// the files are converted to UTF-8 before being compiled. If empty or
+	// "auto" the UTF-8 BOM is removed and the UTF-16 files are converted, the
+	// other files are compiled as they are.
+	SketchEncoding string `protobuf:"bytes,68,opt,name=sketch_encoding,json=sketchEncoding,proto3" json:"sketch_encoding,omitempty"`
+	// If set, after the build the artifact with the same extension of this
+	// reference file is compared byte by byte with it, and the build fails if
+	// they differ. Use it together with reproducible to detect the changes in
+	// the output of the build.
+	CompareTo string `protobuf:"bytes,69,opt,name=compare_to,json=compareTo,proto3" json:"compare_to,omitempty"`
+	// The sketch files, relative to the sketch folder, that are not compiled.
+	// The main sketch file can't be excluded.
+	ExcludeFiles []string `protobuf:"bytes,70,rep,name=exclude_files,json=excludeFiles,proto3" json:"exclude_files,omitempty"`
+	// If set, the preprocessor macros defined with -D in the compiler command
+	// lines are returned in the BuilderResult. Use it together with
+	// create_compilation_database_only to get them without compiling.
+	DumpDefines bool `protobuf:"varint,71,opt,name=dump_defines,json=dumpDefines,proto3" json:"dump_defines,omitempty"`
+	// Path of an external arduino-builder binary. If set, the build is run by
+	// the given binary using the hardware, libraries and build properties
+	// resolved by the CLI. Meant only for debugging and testing builder
+	// versions: the artifacts are not exported and no result is computed.
+	BuilderPath string `protobuf:"bytes,72,opt,name=builder_path,json=builderPath,proto3" json:"builder_path,omitempty"`
+	// The IDE version to build for, in the numeric format (e.g. "10819") or in
+	// the dotted format (e.g. "1.8.19"). It sets the runtime.ide.version build
+	// property, used by the platforms to define the ARDUINO macro.
+	IdeVersion string `protobuf:"bytes,73,opt,name=ide_version,json=ideVersion,proto3" json:"ide_version,omitempty"`
+	// Extra compiler flags of single source files, in the form `path=flags`.
+	// The path is relative to the sketch folder, or absolute for the files of
+	// the libraries. The flags are added at the end of the compile command of
+	// the file. The files of the core and of the variant can't be targeted.
+	FileFlags []string `protobuf:"bytes,74,rep,name=file_flags,json=fileFlags,proto3" json:"file_flags,omitempty"`
+	// If set, a summary of the compile commands run by the build, taken from
+	// the compilation database, is returned in the BuilderResult.
+	Summary bool `protobuf:"varint,75,opt,name=summary,proto3" json:"summary,omitempty"`
+	// If set, the progress messages also report the phases of the build and
+	// the compiled files, in the `name` and `message` fields of TaskProgress.
+	DetailedProgress bool `protobuf:"varint,76,opt,name=detailed_progress,json=detailedProgress,proto3" json:"detailed_progress,omitempty"`
+	// If set to true, the MD5 checksum of each produced artifact is computed
+	// together with the SHA-256 one.
+	Md5Checksums bool `protobuf:"varint,77,opt,name=md5_checksums,json=md5Checksums,proto3" json:"md5_checksums,omitempty"`
+	// If set to true, the build fails if the size of any section of the
+	// executable exceeds the maximum declared by the board, even when the
+	// platform size recipe reports it only as a warning.
+	VerifySize bool `protobuf:"varint,78,opt,name=verify_size,json=verifySize,proto3" json:"verify_size,omitempty"`
 }
 
 func (x *CompileRequest) Reset() {
@@ -254,103 +489,915 @@ func (x *CompileRequest) GetClean() bool {
 	if x != nil {
 		return x.Clean
 	}
-	return false
+	return false
+}
+
+func (x *CompileRequest) GetCreateCompilationDatabaseOnly() bool {
+	if x != nil {
+		return x.CreateCompilationDatabaseOnly
+	}
+	return false
+}
+
+func (x *CompileRequest) GetSourceOverride() map[string]string {
+	if x != nil {
+		return x.SourceOverride
+	}
+	return nil
+}
+
+func (x *CompileRequest) GetExportBinaries() *wrapperspb.BoolValue {
+	if x != nil {
+		return x.ExportBinaries
+	}
+	return nil
+}
+
+func (x *CompileRequest) GetLibrary() []string {
+	if x != nil {
+		return x.Library
+	}
+	return nil
+}
+
+func (x *CompileRequest) GetKeysKeychain() string {
+	if x != nil {
+		return x.KeysKeychain
+	}
+	return ""
+}
+
+func (x *CompileRequest) GetSignKey() string {
+	if x != nil {
+		return x.SignKey
+	}
+	return ""
+}
+
+func (x *CompileRequest) GetEncryptKey() string {
+	if x != nil {
+		return x.EncryptKey
+	}
+	return ""
+}
+
+func (x *CompileRequest) GetSkipLibrariesDiscovery() bool {
+	if x != nil {
+		return x.SkipLibrariesDiscovery
+	}
+	return false
+}
+
+func (x *CompileRequest) GetDoNotExpandBuildProperties() bool {
+	if x != nil {
+		return x.DoNotExpandBuildProperties
+	}
+	return false
+}
+
+func (x *CompileRequest) GetReproducible() bool {
+	if x != nil {
+		return x.Reproducible
+	}
+	return false
+}
+
+func (x *CompileRequest) GetLibraryPathOrder() []string {
+	if x != nil {
+		return x.LibraryPathOrder
+	}
+	return nil
+}
+
+func (x *CompileRequest) GetExtraFlags() []string {
+	if x != nil {
+		return x.ExtraFlags
+	}
+	return nil
+}
+
+func (x *CompileRequest) GetOnlyExplicitLibraries() bool {
+	if x != nil {
+		return x.OnlyExplicitLibraries
+	}
+	return false
+}
+
+func (x *CompileRequest) GetStrictIncludes() bool {
+	if x != nil {
+		return x.StrictIncludes
+	}
+	return false
+}
+
+func (x *CompileRequest) GetKeepObjects() bool {
+	if x != nil {
+		return x.KeepObjects
+	}
+	return false
+}
+
+func (x *CompileRequest) GetDefines() []string {
+	if x != nil {
+		return x.Defines
+	}
+	return nil
+}
+
+func (x *CompileRequest) GetExportPreprocessedSketch() bool {
+	if x != nil {
+		return x.ExportPreprocessedSketch
+	}
+	return false
+}
+
+func (x *CompileRequest) GetCompilationDatabasePath() string {
+	if x != nil {
+		return x.CompilationDatabasePath
+	}
+	return ""
+}
+
+func (x *CompileRequest) GetNoOverwrite() bool {
+	if x != nil {
+		return x.NoOverwrite
+	}
+	return false
+}
+
+func (x *CompileRequest) GetBackup() bool {
+	if x != nil {
+		return x.Backup
+	}
+	return false
+}
+
+func (x *CompileRequest) GetNoFollowSymlinks() bool {
+	if x != nil {
+		return x.NoFollowSymlinks
+	}
+	return false
+}
+
+func (x *CompileRequest) GetCppStd() string {
+	if x != nil {
+		return x.CppStd
+	}
+	return ""
+}
+
+func (x *CompileRequest) GetCStd() string {
+	if x != nil {
+		return x.CStd
+	}
+	return ""
+}
+
+func (x *CompileRequest) GetFqbnSeparator() string {
+	if x != nil {
+		return x.FqbnSeparator
+	}
+	return ""
+}
+
+func (x *CompileRequest) GetVerboseOnFailure() bool {
+	if x != nil {
+		return x.VerboseOnFailure
+	}
+	return false
+}
+
+func (x *CompileRequest) GetOutputFormats() []string {
+	if x != nil {
+		return x.OutputFormats
+	}
+	return nil
+}
+
+func (x *CompileRequest) GetToolOverrides() []string {
+	if x != nil {
+		return x.ToolOverrides
+	}
+	return nil
+}
+
+func (x *CompileRequest) GetStrictFqbn() bool {
+	if x != nil {
+		return x.StrictFqbn
+	}
+	return false
+}
+
+func (x *CompileRequest) GetWriteChecksums() bool {
+	if x != nil {
+		return x.WriteChecksums
+	}
+	return false
+}
+
+func (x *CompileRequest) GetLinkTimeOptimization() LinkTimeOptimization {
+	if x != nil {
+		return x.LinkTimeOptimization
+	}
+	return LinkTimeOptimization_LINK_TIME_OPTIMIZATION_DEFAULT
+}
+
+func (x *CompileRequest) GetCheckFile() string {
+	if x != nil {
+		return x.CheckFile
+	}
+	return ""
+}
+
+func (x *CompileRequest) GetMainFile() string {
+	if x != nil {
+		return x.MainFile
+	}
+	return ""
+}
+
+func (x *CompileRequest) GetExportDeps() bool {
+	if x != nil {
+		return x.ExportDeps
+	}
+	return false
+}
+
+func (x *CompileRequest) GetBoardDefine() string {
+	if x != nil {
+		return x.BoardDefine
+	}
+	return ""
+}
+
+func (x *CompileRequest) GetBootloader() string {
+	if x != nil {
+		return x.Bootloader
+	}
+	return ""
+}
+
+func (x *CompileRequest) GetTrace() bool {
+	if x != nil {
+		return x.Trace
+	}
+	return false
+}
+
+func (x *CompileRequest) GetAllowedLibrarySources() []string {
+	if x != nil {
+		return x.AllowedLibrarySources
+	}
+	return nil
+}
+
+func (x *CompileRequest) GetValidateCompilationDatabase() bool {
+	if x != nil {
+		return x.ValidateCompilationDatabase
+	}
+	return false
+}
+
+func (x *CompileRequest) GetNoBuildTime() bool {
+	if x != nil {
+		return x.NoBuildTime
+	}
+	return false
+}
+
+func (x *CompileRequest) GetOnlyNewDiagnostics() bool {
+	if x != nil {
+		return x.OnlyNewDiagnostics
+	}
+	return false
+}
+
+func (x *CompileRequest) GetCopyTo() []string {
+	if x != nil {
+		return x.CopyTo
+	}
+	return nil
+}
+
+func (x *CompileRequest) GetArchive() string {
+	if x != nil {
+		return x.Archive
+	}
+	return ""
+}
+
+func (x *CompileRequest) GetSketchName() string {
+	if x != nil {
+		return x.SketchName
+	}
+	return ""
+}
+
+func (x *CompileRequest) GetVerifyTool() string {
+	if x != nil {
+		return x.VerifyTool
+	}
+	return ""
+}
+
+func (x *CompileRequest) GetExportDepGraph() string {
+	if x != nil {
+		return x.ExportDepGraph
+	}
+	return ""
+}
+
+func (x *CompileRequest) GetCompilerPath() string {
+	if x != nil {
+		return x.CompilerPath
+	}
+	return ""
+}
+
+func (x *CompileRequest) GetExplainProperty() string {
+	if x != nil {
+		return x.ExplainProperty
+	}
+	return ""
+}
+
+func (x *CompileRequest) GetSketchEncoding() string {
+	if x != nil {
+		return x.SketchEncoding
+	}
+	return ""
+}
+
+func (x *CompileRequest) GetCompareTo() string {
+	if x != nil {
+		return x.CompareTo
+	}
+	return ""
+}
+
+func (x *CompileRequest) GetExcludeFiles() []string {
+	if x != nil {
+		return x.ExcludeFiles
+	}
+	return nil
+}
+
+func (x *CompileRequest) GetDumpDefines() bool {
+	if x != nil {
+		return x.DumpDefines
+	}
+	return false
+}
+
+func (x *CompileRequest) GetBuilderPath() string {
+	if x != nil {
+		return x.BuilderPath
+	}
+	return ""
+}
+
+func (x *CompileRequest) GetIdeVersion() string {
+	if x != nil {
+		return x.IdeVersion
+	}
+	return ""
+}
+
+func (x *CompileRequest) GetFileFlags() []string {
+	if x != nil {
+		return x.FileFlags
+	}
+	return nil
+}
+
+func (x *CompileRequest) GetSummary() bool {
+	if x != nil {
+		return x.Summary
+	}
+	return false
+}
+
+func (x *CompileRequest) GetDetailedProgress() bool {
+	if x != nil {
+		return x.DetailedProgress
+	}
+	return false
+}
+
+func (x *CompileRequest) GetMd5Checksums() bool {
+	if x != nil {
+		return x.Md5Checksums
+	}
+	return false
+}
+
+func (x *CompileRequest) GetVerifySize() bool {
+	if x != nil {
+		return x.VerifySize
+	}
+	return false
+}
+
+type CompileResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Message:
+	//
+	//	*CompileResponse_OutStream
+	//	*CompileResponse_ErrStream
+	//	*CompileResponse_Progress
+	//	*CompileResponse_Result
+	Message isCompileResponse_Message `protobuf_oneof:"message"`
+}
+
+func (x *CompileResponse) Reset() {
+	*x = CompileResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CompileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompileResponse) ProtoMessage() {}
+
+func (x *CompileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompileResponse.ProtoReflect.Descriptor instead.
+func (*CompileResponse) Descriptor() ([]byte, []int) {
+	return file_cc_arduino_cli_commands_v1_compile_proto_rawDescGZIP(), []int{1}
+}
+
+func (m *CompileResponse) GetMessage() isCompileResponse_Message {
+	if m != nil {
+		return m.Message
+	}
+	return nil
+}
+
+func (x *CompileResponse) GetOutStream() []byte {
+	if x, ok := x.GetMessage().(*CompileResponse_OutStream); ok {
+		return x.OutStream
+	}
+	return nil
+}
+
+func (x *CompileResponse) GetErrStream() []byte {
+	if x, ok := x.GetMessage().(*CompileResponse_ErrStream); ok {
+		return x.ErrStream
+	}
+	return nil
+}
+
+func (x *CompileResponse) GetProgress() *TaskProgress {
+	if x, ok := x.GetMessage().(*CompileResponse_Progress); ok {
+		return x.Progress
+	}
+	return nil
+}
+
+func (x *CompileResponse) GetResult() *BuilderResult {
+	if x, ok := x.GetMessage().(*CompileResponse_Result); ok {
+		return x.Result
+	}
+	return nil
+}
+
+type isCompileResponse_Message interface {
+	isCompileResponse_Message()
+}
+
+type CompileResponse_OutStream struct {
+	// The output of the compilation process (stream)
+	OutStream []byte `protobuf:"bytes,1,opt,name=out_stream,json=outStream,proto3,oneof"`
+}
+
+type CompileResponse_ErrStream struct {
+	// The error output of the compilation process (stream)
+	ErrStream []byte `protobuf:"bytes,2,opt,name=err_stream,json=errStream,proto3,oneof"`
+}
+
+type CompileResponse_Progress struct {
+	// Completions reports of the compilation process (stream)
+	Progress *TaskProgress `protobuf:"bytes,3,opt,name=progress,proto3,oneof"`
+}
+
+type CompileResponse_Result struct {
+	// The compilation result
+	Result *BuilderResult `protobuf:"bytes,4,opt,name=result,proto3,oneof"`
+}
+
+func (*CompileResponse_OutStream) isCompileResponse_Message() {}
+
+func (*CompileResponse_ErrStream) isCompileResponse_Message() {}
+
+func (*CompileResponse_Progress) isCompileResponse_Message() {}
+
+func (*CompileResponse_Result) isCompileResponse_Message() {}
+
+type BuilderResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The compiler build path
+	BuildPath string `protobuf:"bytes,1,opt,name=build_path,json=buildPath,proto3" json:"build_path,omitempty"`
+	// The libraries used in the build
+	UsedLibraries []*Library `protobuf:"bytes,2,rep,name=used_libraries,json=usedLibraries,proto3" json:"used_libraries,omitempty"`
+	// The size of the executable split by sections
+	ExecutableSectionsSize []*ExecutableSectionSize `protobuf:"bytes,3,rep,name=executable_sections_size,json=executableSectionsSize,proto3" json:"executable_sections_size,omitempty"`
+	// The platform where the board is defined
+	BoardPlatform *InstalledPlatformReference `protobuf:"bytes,4,opt,name=board_platform,json=boardPlatform,proto3" json:"board_platform,omitempty"`
+	// The platform used for the build (if referenced from the board platform)
+	BuildPlatform *InstalledPlatformReference `protobuf:"bytes,5,opt,name=build_platform,json=buildPlatform,proto3" json:"build_platform,omitempty"`
+	// Build properties used for compiling
+	BuildProperties []string `protobuf:"bytes,7,rep,name=build_properties,json=buildProperties,proto3" json:"build_properties,omitempty"`
+	// Compiler errors and warnings
+	Diagnostics []*CompileDiagnostic `protobuf:"bytes,8,rep,name=diagnostics,proto3" json:"diagnostics,omitempty"`
+	// The files produced by the build in the output directory
+	Artifacts []*BuildArtifact `protobuf:"bytes,9,rep,name=artifacts,proto3" json:"artifacts,omitempty"`
+	// The key identifying the cached core archive used by the build, it
+	// changes when the FQBN (including the board options), the platform or the
+	// optimization flags change. The other build properties, like the extra
+	// flags or the defines, and the toolchain version are not covered.
+	CoreCacheKey string `protobuf:"bytes,10,opt,name=core_cache_key,json=coreCacheKey,proto3" json:"core_cache_key,omitempty"`
+	// The include paths used to compile the sketch, in the order they are
+	// passed to the compiler: core, variant and the libraries used
+	IncludePaths []string `protobuf:"bytes,11,rep,name=include_paths,json=includePaths,proto3" json:"include_paths,omitempty"`
+	// The tools required by the build, in the form "packager:name@version"
+	UsedTools []string `protobuf:"bytes,12,rep,name=used_tools,json=usedTools,proto3" json:"used_tools,omitempty"`
+	// The sketch files produced by the preprocessing, only set when the
+	// preprocessing is requested
+	PreprocessedFiles []*PreprocessedFile `protobuf:"bytes,13,rep,name=preprocessed_files,json=preprocessedFiles,proto3" json:"preprocessed_files,omitempty"`
+	// The fully expanded command line used to link the sketch, set as soon as
+	// the link step is reached, even if it fails
+	LinkCommand string `protobuf:"bytes,14,opt,name=link_command,json=linkCommand,proto3" json:"link_command,omitempty"`
+	// The explanation of the build property requested with explain_property
+	PropertyExplanation *PropertyExplanation `protobuf:"bytes,15,opt,name=property_explanation,json=propertyExplanation,proto3" json:"property_explanation,omitempty"`
+	// The preprocessor macros passed to the compiler, in the form NAME or
+	// NAME=VALUE, sorted. Set only if dump_defines is requested.
+	Defines []string `protobuf:"bytes,16,rep,name=defines,proto3" json:"defines,omitempty"`
+	// The summary of the compile commands run by the build. Set only if
+	// summary is requested.
+	Summary *CompilationSummary `protobuf:"bytes,17,opt,name=summary,proto3" json:"summary,omitempty"`
+}
+
+func (x *BuilderResult) Reset() {
+	*x = BuilderResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BuilderResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuilderResult) ProtoMessage() {}
+
+func (x *BuilderResult) ProtoReflect() protoreflect.Message {
+	mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuilderResult.ProtoReflect.Descriptor instead.
+func (*BuilderResult) Descriptor() ([]byte, []int) {
+	return file_cc_arduino_cli_commands_v1_compile_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BuilderResult) GetBuildPath() string {
+	if x != nil {
+		return x.BuildPath
+	}
+	return ""
+}
+
+func (x *BuilderResult) GetUsedLibraries() []*Library {
+	if x != nil {
+		return x.UsedLibraries
+	}
+	return nil
+}
+
+func (x *BuilderResult) GetExecutableSectionsSize() []*ExecutableSectionSize {
+	if x != nil {
+		return x.ExecutableSectionsSize
+	}
+	return nil
+}
+
+func (x *BuilderResult) GetBoardPlatform() *InstalledPlatformReference {
+	if x != nil {
+		return x.BoardPlatform
+	}
+	return nil
+}
+
+func (x *BuilderResult) GetBuildPlatform() *InstalledPlatformReference {
+	if x != nil {
+		return x.BuildPlatform
+	}
+	return nil
+}
+
+func (x *BuilderResult) GetBuildProperties() []string {
+	if x != nil {
+		return x.BuildProperties
+	}
+	return nil
+}
+
+func (x *BuilderResult) GetDiagnostics() []*CompileDiagnostic {
+	if x != nil {
+		return x.Diagnostics
+	}
+	return nil
+}
+
+func (x *BuilderResult) GetArtifacts() []*BuildArtifact {
+	if x != nil {
+		return x.Artifacts
+	}
+	return nil
+}
+
+func (x *BuilderResult) GetCoreCacheKey() string {
+	if x != nil {
+		return x.CoreCacheKey
+	}
+	return ""
+}
+
+func (x *BuilderResult) GetIncludePaths() []string {
+	if x != nil {
+		return x.IncludePaths
+	}
+	return nil
+}
+
+func (x *BuilderResult) GetUsedTools() []string {
+	if x != nil {
+		return x.UsedTools
+	}
+	return nil
+}
+
+func (x *BuilderResult) GetPreprocessedFiles() []*PreprocessedFile {
+	if x != nil {
+		return x.PreprocessedFiles
+	}
+	return nil
+}
+
+func (x *BuilderResult) GetLinkCommand() string {
+	if x != nil {
+		return x.LinkCommand
+	}
+	return ""
+}
+
+func (x *BuilderResult) GetPropertyExplanation() *PropertyExplanation {
+	if x != nil {
+		return x.PropertyExplanation
+	}
+	return nil
+}
+
+func (x *BuilderResult) GetDefines() []string {
+	if x != nil {
+		return x.Defines
+	}
+	return nil
+}
+
+func (x *BuilderResult) GetSummary() *CompilationSummary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+type CompilationSummary struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The number of compile commands run, or recorded, by the build
+	TotalCommands int32 `protobuf:"varint,1,opt,name=total_commands,json=totalCommands,proto3" json:"total_commands,omitempty"`
+	// The number of distinct source files compiled
+	UniqueFiles int32 `protobuf:"varint,2,opt,name=unique_files,json=uniqueFiles,proto3" json:"unique_files,omitempty"`
+	// The number of distinct source files compiled, grouped by file extension
+	// (for example ".cpp" or ".c")
+	FilesByExtension map[string]int32 `protobuf:"bytes,3,rep,name=files_by_extension,json=filesByExtension,proto3" json:"files_by_extension,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+}
+
+func (x *CompilationSummary) Reset() {
+	*x = CompilationSummary{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CompilationSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompilationSummary) ProtoMessage() {}
+
+func (x *CompilationSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompilationSummary.ProtoReflect.Descriptor instead.
+func (*CompilationSummary) Descriptor() ([]byte, []int) {
+	return file_cc_arduino_cli_commands_v1_compile_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CompilationSummary) GetTotalCommands() int32 {
+	if x != nil {
+		return x.TotalCommands
+	}
+	return 0
+}
+
+func (x *CompilationSummary) GetUniqueFiles() int32 {
+	if x != nil {
+		return x.UniqueFiles
+	}
+	return 0
+}
+
+func (x *CompilationSummary) GetFilesByExtension() map[string]int32 {
+	if x != nil {
+		return x.FilesByExtension
+	}
+	return nil
+}
+
+type PropertyExplanation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The build property key
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// The value of the property defined by the platform and the board, before
+	// applying the overrides
+	DefaultValue string `protobuf:"bytes,2,opt,name=default_value,json=defaultValue,proto3" json:"default_value,omitempty"`
+	// Where the default value is defined, the path of a platform or board
+	// file, or a description of the source if it's set at runtime. Empty if
+	// the property is not defined.
+	Source string `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	// The value of the property after applying the overrides from the build
+	// properties and the command line options
+	Value string `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
+	// The value after applying the overrides, with all the references to the
+	// other properties expanded
+	ExpandedValue string `protobuf:"bytes,5,opt,name=expanded_value,json=expandedValue,proto3" json:"expanded_value,omitempty"`
+	// True if the property is defined after applying the overrides
+	Defined bool `protobuf:"varint,6,opt,name=defined,proto3" json:"defined,omitempty"`
+}
+
+func (x *PropertyExplanation) Reset() {
+	*x = PropertyExplanation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-func (x *CompileRequest) GetCreateCompilationDatabaseOnly() bool {
-	if x != nil {
-		return x.CreateCompilationDatabaseOnly
-	}
-	return false
+func (x *PropertyExplanation) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *CompileRequest) GetSourceOverride() map[string]string {
-	if x != nil {
-		return x.SourceOverride
+func (*PropertyExplanation) ProtoMessage() {}
+
+func (x *PropertyExplanation) ProtoReflect() protoreflect.Message {
+	mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *CompileRequest) GetExportBinaries() *wrapperspb.BoolValue {
-	if x != nil {
-		return x.ExportBinaries
-	}
-	return nil
+// Deprecated: Use PropertyExplanation.ProtoReflect.Descriptor instead.
+func (*PropertyExplanation) Descriptor() ([]byte, []int) {
+	return file_cc_arduino_cli_commands_v1_compile_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *CompileRequest) GetLibrary() []string {
+func (x *PropertyExplanation) GetKey() string {
 	if x != nil {
-		return x.Library
+		return x.Key
 	}
-	return nil
+	return ""
 }
 
-func (x *CompileRequest) GetKeysKeychain() string {
+func (x *PropertyExplanation) GetDefaultValue() string {
 	if x != nil {
-		return x.KeysKeychain
+		return x.DefaultValue
 	}
 	return ""
 }
 
-func (x *CompileRequest) GetSignKey() string {
+func (x *PropertyExplanation) GetSource() string {
 	if x != nil {
-		return x.SignKey
+		return x.Source
 	}
 	return ""
 }
 
-func (x *CompileRequest) GetEncryptKey() string {
+func (x *PropertyExplanation) GetValue() string {
 	if x != nil {
-		return x.EncryptKey
+		return x.Value
 	}
 	return ""
 }
 
-func (x *CompileRequest) GetSkipLibrariesDiscovery() bool {
+func (x *PropertyExplanation) GetExpandedValue() string {
 	if x != nil {
-		return x.SkipLibrariesDiscovery
+		return x.ExpandedValue
 	}
-	return false
+	return ""
 }
 
-func (x *CompileRequest) GetDoNotExpandBuildProperties() bool {
+func (x *PropertyExplanation) GetDefined() bool {
 	if x != nil {
-		return x.DoNotExpandBuildProperties
+		return x.Defined
 	}
 	return false
 }
 
-type CompileResponse struct {
+type PreprocessedFile struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Types that are assignable to Message:
-	//
-	//	*CompileResponse_OutStream
-	//	*CompileResponse_ErrStream
-	//	*CompileResponse_Progress
-	//	*CompileResponse_Result
-	Message isCompileResponse_Message `protobuf_oneof:"message"`
+	// Absolute path of the sketch source file, the .ino files are merged
+	// together in the preprocessed main file
+	SourcePath string `protobuf:"bytes,1,opt,name=source_path,json=sourcePath,proto3" json:"source_path,omitempty"`
+	// The preprocessed source code
+	Content string `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	// The error occurred preprocessing the file, if any
+	Error string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
 }
 
-func (x *CompileResponse) Reset() {
-	*x = CompileResponse{}
+func (x *PreprocessedFile) Reset() {
+	*x = PreprocessedFile{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[1]
+		mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *CompileResponse) String() string {
+func (x *PreprocessedFile) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CompileResponse) ProtoMessage() {}
+func (*PreprocessedFile) ProtoMessage() {}
 
-func (x *CompileResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[1]
+func (x *PreprocessedFile) ProtoReflect() protoreflect.Message {
+	mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -361,116 +1408,67 @@ func (x *CompileResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CompileResponse.ProtoReflect.Descriptor instead.
-func (*CompileResponse) Descriptor() ([]byte, []int) {
-	return file_cc_arduino_cli_commands_v1_compile_proto_rawDescGZIP(), []int{1}
-}
-
-func (m *CompileResponse) GetMessage() isCompileResponse_Message {
-	if m != nil {
-		return m.Message
-	}
-	return nil
-}
-
-func (x *CompileResponse) GetOutStream() []byte {
-	if x, ok := x.GetMessage().(*CompileResponse_OutStream); ok {
-		return x.OutStream
-	}
-	return nil
+// Deprecated: Use PreprocessedFile.ProtoReflect.Descriptor instead.
+func (*PreprocessedFile) Descriptor() ([]byte, []int) {
+	return file_cc_arduino_cli_commands_v1_compile_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *CompileResponse) GetErrStream() []byte {
-	if x, ok := x.GetMessage().(*CompileResponse_ErrStream); ok {
-		return x.ErrStream
+func (x *PreprocessedFile) GetSourcePath() string {
+	if x != nil {
+		return x.SourcePath
 	}
-	return nil
+	return ""
 }
 
-func (x *CompileResponse) GetProgress() *TaskProgress {
-	if x, ok := x.GetMessage().(*CompileResponse_Progress); ok {
-		return x.Progress
+func (x *PreprocessedFile) GetContent() string {
+	if x != nil {
+		return x.Content
 	}
-	return nil
+	return ""
 }
 
-func (x *CompileResponse) GetResult() *BuilderResult {
-	if x, ok := x.GetMessage().(*CompileResponse_Result); ok {
-		return x.Result
+func (x *PreprocessedFile) GetError() string {
+	if x != nil {
+		return x.Error
 	}
-	return nil
-}
-
-type isCompileResponse_Message interface {
-	isCompileResponse_Message()
-}
-
-type CompileResponse_OutStream struct {
-	// The output of the compilation process (stream)
-	OutStream []byte `protobuf:"bytes,1,opt,name=out_stream,json=outStream,proto3,oneof"`
-}
-
-type CompileResponse_ErrStream struct {
-	// The error output of the compilation process (stream)
-	ErrStream []byte `protobuf:"bytes,2,opt,name=err_stream,json=errStream,proto3,oneof"`
-}
-
-type CompileResponse_Progress struct {
-	// Completions reports of the compilation process (stream)
-	Progress *TaskProgress `protobuf:"bytes,3,opt,name=progress,proto3,oneof"`
-}
-
-type CompileResponse_Result struct {
-	// The compilation result
-	Result *BuilderResult `protobuf:"bytes,4,opt,name=result,proto3,oneof"`
+	return ""
 }
 
-func (*CompileResponse_OutStream) isCompileResponse_Message() {}
-
-func (*CompileResponse_ErrStream) isCompileResponse_Message() {}
-
-func (*CompileResponse_Progress) isCompileResponse_Message() {}
-
-func (*CompileResponse_Result) isCompileResponse_Message() {}
-
-type BuilderResult struct {
+type BuildArtifact struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The compiler build path
-	BuildPath string `protobuf:"bytes,1,opt,name=build_path,json=buildPath,proto3" json:"build_path,omitempty"`
-	// The libraries used in the build
-	UsedLibraries []*Library `protobuf:"bytes,2,rep,name=used_libraries,json=usedLibraries,proto3" json:"used_libraries,omitempty"`
-	// The size of the executable split by sections
-	ExecutableSectionsSize []*ExecutableSectionSize `protobuf:"bytes,3,rep,name=executable_sections_size,json=executableSectionsSize,proto3" json:"executable_sections_size,omitempty"`
-	// The platform where the board is defined
-	BoardPlatform *InstalledPlatformReference `protobuf:"bytes,4,opt,name=board_platform,json=boardPlatform,proto3" json:"board_platform,omitempty"`
-	// The platform used for the build (if referenced from the board platform)
-	BuildPlatform *InstalledPlatformReference `protobuf:"bytes,5,opt,name=build_platform,json=buildPlatform,proto3" json:"build_platform,omitempty"`
-	// Build properties used for compiling
-	BuildProperties []string `protobuf:"bytes,7,rep,name=build_properties,json=buildProperties,proto3" json:"build_properties,omitempty"`
-	// Compiler errors and warnings
-	Diagnostics []*CompileDiagnostic `protobuf:"bytes,8,rep,name=diagnostics,proto3" json:"diagnostics,omitempty"`
-}
-
-func (x *BuilderResult) Reset() {
-	*x = BuilderResult{}
+	// Absolute path of the artifact
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// Size of the artifact in bytes
+	Size int64 `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	// Type of the artifact, for example "hex", "elf", "bin" or "map"
+	Type string `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	// Hex encoded SHA-256 checksum of the artifact
+	Sha256 string `protobuf:"bytes,4,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	// Hex encoded MD5 checksum of the artifact, set only if requested with
+	// CompileRequest.md5_checksums
+	Md5 string `protobuf:"bytes,5,opt,name=md5,proto3" json:"md5,omitempty"`
+}
+
+func (x *BuildArtifact) Reset() {
+	*x = BuildArtifact{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[2]
+		mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *BuilderResult) String() string {
+func (x *BuildArtifact) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BuilderResult) ProtoMessage() {}
+func (*BuildArtifact) ProtoMessage() {}
 
-func (x *BuilderResult) ProtoReflect() protoreflect.Message {
-	mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[2]
+func (x *BuildArtifact) ProtoReflect() protoreflect.Message {
+	mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -481,58 +1479,44 @@ func (x *BuilderResult) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BuilderResult.ProtoReflect.Descriptor instead.
-func (*BuilderResult) Descriptor() ([]byte, []int) {
-	return file_cc_arduino_cli_commands_v1_compile_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use BuildArtifact.ProtoReflect.Descriptor instead.
+func (*BuildArtifact) Descriptor() ([]byte, []int) {
+	return file_cc_arduino_cli_commands_v1_compile_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *BuilderResult) GetBuildPath() string {
+func (x *BuildArtifact) GetPath() string {
 	if x != nil {
-		return x.BuildPath
+		return x.Path
 	}
 	return ""
 }
 
-func (x *BuilderResult) GetUsedLibraries() []*Library {
-	if x != nil {
-		return x.UsedLibraries
-	}
-	return nil
-}
-
-func (x *BuilderResult) GetExecutableSectionsSize() []*ExecutableSectionSize {
+func (x *BuildArtifact) GetSize() int64 {
 	if x != nil {
-		return x.ExecutableSectionsSize
-	}
-	return nil
-}
-
-func (x *BuilderResult) GetBoardPlatform() *InstalledPlatformReference {
-	if x != nil {
-		return x.BoardPlatform
+		return x.Size
 	}
-	return nil
+	return 0
 }
 
-func (x *BuilderResult) GetBuildPlatform() *InstalledPlatformReference {
+func (x *BuildArtifact) GetType() string {
 	if x != nil {
-		return x.BuildPlatform
+		return x.Type
 	}
-	return nil
+	return ""
 }
 
-func (x *BuilderResult) GetBuildProperties() []string {
+func (x *BuildArtifact) GetSha256() string {
 	if x != nil {
-		return x.BuildProperties
+		return x.Sha256
 	}
-	return nil
+	return ""
 }
 
-func (x *BuilderResult) GetDiagnostics() []*CompileDiagnostic {
+func (x *BuildArtifact) GetMd5() string {
 	if x != nil {
-		return x.Diagnostics
+		return x.Md5
 	}
-	return nil
+	return ""
 }
 
 type ExecutableSectionSize struct {
@@ -548,7 +1532,7 @@ type ExecutableSectionSize struct {
 func (x *ExecutableSectionSize) Reset() {
 	*x = ExecutableSectionSize{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[3]
+		mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -561,7 +1545,7 @@ func (x *ExecutableSectionSize) String() string {
 func (*ExecutableSectionSize) ProtoMessage() {}
 
 func (x *ExecutableSectionSize) ProtoReflect() protoreflect.Message {
-	mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[3]
+	mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -574,7 +1558,7 @@ func (x *ExecutableSectionSize) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExecutableSectionSize.ProtoReflect.Descriptor instead.
 func (*ExecutableSectionSize) Descriptor() ([]byte, []int) {
-	return file_cc_arduino_cli_commands_v1_compile_proto_rawDescGZIP(), []int{3}
+	return file_cc_arduino_cli_commands_v1_compile_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *ExecutableSectionSize) GetName() string {
@@ -624,7 +1608,7 @@ type CompileDiagnostic struct {
 func (x *CompileDiagnostic) Reset() {
 	*x = CompileDiagnostic{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[4]
+		mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -637,7 +1621,7 @@ func (x *CompileDiagnostic) String() string {
 func (*CompileDiagnostic) ProtoMessage() {}
 
 func (x *CompileDiagnostic) ProtoReflect() protoreflect.Message {
-	mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[4]
+	mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -650,7 +1634,7 @@ func (x *CompileDiagnostic) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CompileDiagnostic.ProtoReflect.Descriptor instead.
 func (*CompileDiagnostic) Descriptor() ([]byte, []int) {
-	return file_cc_arduino_cli_commands_v1_compile_proto_rawDescGZIP(), []int{4}
+	return file_cc_arduino_cli_commands_v1_compile_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *CompileDiagnostic) GetSeverity() string {
@@ -720,7 +1704,7 @@ type CompileDiagnosticContext struct {
 func (x *CompileDiagnosticContext) Reset() {
 	*x = CompileDiagnosticContext{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[5]
+		mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -733,7 +1717,7 @@ func (x *CompileDiagnosticContext) String() string {
 func (*CompileDiagnosticContext) ProtoMessage() {}
 
 func (x *CompileDiagnosticContext) ProtoReflect() protoreflect.Message {
-	mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[5]
+	mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -746,7 +1730,7 @@ func (x *CompileDiagnosticContext) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CompileDiagnosticContext.ProtoReflect.Descriptor instead.
 func (*CompileDiagnosticContext) Descriptor() ([]byte, []int) {
-	return file_cc_arduino_cli_commands_v1_compile_proto_rawDescGZIP(), []int{5}
+	return file_cc_arduino_cli_commands_v1_compile_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *CompileDiagnosticContext) GetMessage() string {
@@ -795,7 +1779,7 @@ type CompileDiagnosticNote struct {
 func (x *CompileDiagnosticNote) Reset() {
 	*x = CompileDiagnosticNote{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[6]
+		mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -808,7 +1792,7 @@ func (x *CompileDiagnosticNote) String() string {
 func (*CompileDiagnosticNote) ProtoMessage() {}
 
 func (x *CompileDiagnosticNote) ProtoReflect() protoreflect.Message {
-	mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[6]
+	mi := &file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -821,7 +1805,7 @@ func (x *CompileDiagnosticNote) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CompileDiagnosticNote.ProtoReflect.Descriptor instead.
 func (*CompileDiagnosticNote) Descriptor() ([]byte, []int) {
-	return file_cc_arduino_cli_commands_v1_compile_proto_rawDescGZIP(), []int{6}
+	return file_cc_arduino_cli_commands_v1_compile_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *CompileDiagnosticNote) GetMessage() string {
@@ -866,7 +1850,7 @@ var file_cc_arduino_cli_commands_v1_compile_proto_rawDesc = []byte{
 	0x76, 0x31, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a,
 	0x24, 0x63, 0x63, 0x2f, 0x61, 0x72, 0x64, 0x75, 0x69, 0x6e, 0x6f, 0x2f, 0x63, 0x6c, 0x69, 0x2f,
 	0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x2f, 0x76, 0x31, 0x2f, 0x6c, 0x69, 0x62, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xd8, 0x08, 0x0a, 0x0e, 0x43, 0x6f, 0x6d, 0x70, 0x69, 0x6c,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xc6, 0x17, 0x0a, 0x0e, 0x43, 0x6f, 0x6d, 0x70, 0x69, 0x6c,
 	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x40, 0x0a, 0x08, 0x69, 0x6e, 0x73, 0x74,
 	0x61, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x63, 0x63, 0x2e,
 	0x61, 0x72, 0x64, 0x75, 0x69, 0x6e, 0x6f, 0x2e, 0x63, 0x6c, 0x69, 0x2e, 0x63, 0x6f, 0x6d, 0x6d,
@@ -931,104 +1915,307 @@ var file_cc_arduino_cli_commands_v1_compile_proto_rawDesc = []byte{
 	0x6e, 0x6f, 0x74, 0x5f, 0x65, 0x78, 0x70, 0x61, 0x6e, 0x64, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64,
 	0x5f, 0x70, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x69, 0x65, 0x73, 0x18, 0x1d, 0x20, 0x01, 0x28,
 	0x08, 0x52, 0x1a, 0x64, 0x6f, 0x4e, 0x6f, 0x74, 0x45, 0x78, 0x70, 0x61, 0x6e, 0x64, 0x42, 0x75,
-	0x69, 0x6c, 0x64, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x69, 0x65, 0x73, 0x1a, 0x41, 0x0a,
-	0x13, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x45,
-	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
-	0x22, 0xeb, 0x01, 0x0a, 0x0f, 0x43, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x0a, 0x6f, 0x75, 0x74, 0x5f, 0x73, 0x74, 0x72, 0x65,
-	0x61, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x09, 0x6f, 0x75, 0x74, 0x53,
-	0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x1f, 0x0a, 0x0a, 0x65, 0x72, 0x72, 0x5f, 0x73, 0x74, 0x72,
-	0x65, 0x61, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x09, 0x65, 0x72, 0x72,
-	0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x46, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65,
-	0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x63, 0x63, 0x2e, 0x61, 0x72,
-	0x64, 0x75, 0x69, 0x6e, 0x6f, 0x2e, 0x63, 0x6c, 0x69, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
-	0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65,
-	0x73, 0x73, 0x48, 0x00, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x43,
-	0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x29,
+	0x69, 0x6c, 0x64, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x69, 0x65, 0x73, 0x12, 0x22, 0x0a,
+	0x0c, 0x72, 0x65, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x69, 0x62, 0x6c, 0x65, 0x18, 0x1e, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0c, 0x72, 0x65, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x69, 0x62, 0x6c,
+	0x65, 0x12, 0x2c, 0x0a, 0x12, 0x6c, 0x69, 0x62, 0x72, 0x61, 0x72, 0x79, 0x5f, 0x70, 0x61, 0x74,
+	0x68, 0x5f, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x18, 0x1f, 0x20, 0x03, 0x28, 0x09, 0x52, 0x10, 0x6c,
+	0x69, 0x62, 0x72, 0x61, 0x72, 0x79, 0x50, 0x61, 0x74, 0x68, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x12,
+	0x1f, 0x0a, 0x0b, 0x65, 0x78, 0x74, 0x72, 0x61, 0x5f, 0x66, 0x6c, 0x61, 0x67, 0x73, 0x18, 0x20,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x65, 0x78, 0x74, 0x72, 0x61, 0x46, 0x6c, 0x61, 0x67, 0x73,
+	0x12, 0x36, 0x0a, 0x17, 0x6f, 0x6e, 0x6c, 0x79, 0x5f, 0x65, 0x78, 0x70, 0x6c, 0x69, 0x63, 0x69,
+	0x74, 0x5f, 0x6c, 0x69, 0x62, 0x72, 0x61, 0x72, 0x69, 0x65, 0x73, 0x18, 0x21, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x15, 0x6f, 0x6e, 0x6c, 0x79, 0x45, 0x78, 0x70, 0x6c, 0x69, 0x63, 0x69, 0x74, 0x4c,
+	0x69, 0x62, 0x72, 0x61, 0x72, 0x69, 0x65, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x73, 0x74, 0x72, 0x69,
+	0x63, 0x74, 0x5f, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x73, 0x18, 0x22, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0e, 0x73, 0x74, 0x72, 0x69, 0x63, 0x74, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65,
+	0x73, 0x12, 0x21, 0x0a, 0x0c, 0x6b, 0x65, 0x65, 0x70, 0x5f, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74,
+	0x73, 0x18, 0x23, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x6b, 0x65, 0x65, 0x70, 0x4f, 0x62, 0x6a,
+	0x65, 0x63, 0x74, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x66, 0x69, 0x6e, 0x65, 0x73, 0x18,
+	0x24, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x64, 0x65, 0x66, 0x69, 0x6e, 0x65, 0x73, 0x12, 0x3c,
+	0x0a, 0x1a, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x5f, 0x70, 0x72, 0x65, 0x70, 0x72, 0x6f, 0x63,
+	0x65, 0x73, 0x73, 0x65, 0x64, 0x5f, 0x73, 0x6b, 0x65, 0x74, 0x63, 0x68, 0x18, 0x25, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x18, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x50, 0x72, 0x65, 0x70, 0x72, 0x6f,
+	0x63, 0x65, 0x73, 0x73, 0x65, 0x64, 0x53, 0x6b, 0x65, 0x74, 0x63, 0x68, 0x12, 0x3a, 0x0a, 0x19,
+	0x63, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x64, 0x61, 0x74, 0x61,
+	0x62, 0x61, 0x73, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x26, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x17, 0x63, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61,
+	0x62, 0x61, 0x73, 0x65, 0x50, 0x61, 0x74, 0x68, 0x12, 0x21, 0x0a, 0x0c, 0x6e, 0x6f, 0x5f, 0x6f,
+	0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74, 0x65, 0x18, 0x27, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b,
+	0x6e, 0x6f, 0x4f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x62,
+	0x61, 0x63, 0x6b, 0x75, 0x70, 0x18, 0x28, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x62, 0x61, 0x63,
+	0x6b, 0x75, 0x70, 0x12, 0x2c, 0x0a, 0x12, 0x6e, 0x6f, 0x5f, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77,
+	0x5f, 0x73, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x18, 0x29, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x10, 0x6e, 0x6f, 0x46, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x53, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b,
+	0x73, 0x12, 0x17, 0x0a, 0x07, 0x63, 0x70, 0x70, 0x5f, 0x73, 0x74, 0x64, 0x18, 0x2a, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x63, 0x70, 0x70, 0x53, 0x74, 0x64, 0x12, 0x13, 0x0a, 0x05, 0x63, 0x5f,
+	0x73, 0x74, 0x64, 0x18, 0x2b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x53, 0x74, 0x64, 0x12,
+	0x25, 0x0a, 0x0e, 0x66, 0x71, 0x62, 0x6e, 0x5f, 0x73, 0x65, 0x70, 0x61, 0x72, 0x61, 0x74, 0x6f,
+	0x72, 0x18, 0x2c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x66, 0x71, 0x62, 0x6e, 0x53, 0x65, 0x70,
+	0x61, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x2c, 0x0a, 0x12, 0x76, 0x65, 0x72, 0x62, 0x6f, 0x73,
+	0x65, 0x5f, 0x6f, 0x6e, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x18, 0x2d, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x10, 0x76, 0x65, 0x72, 0x62, 0x6f, 0x73, 0x65, 0x4f, 0x6e, 0x46, 0x61, 0x69,
+	0x6c, 0x75, 0x72, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x66,
+	0x6f, 0x72, 0x6d, 0x61, 0x74, 0x73, 0x18, 0x2e, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x6f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x74,
+	0x6f, 0x6f, 0x6c, 0x5f, 0x6f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x73, 0x18, 0x2f, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x0d, 0x74, 0x6f, 0x6f, 0x6c, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64,
+	0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x74, 0x72, 0x69, 0x63, 0x74, 0x5f, 0x66, 0x71, 0x62,
+	0x6e, 0x18, 0x30, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x73, 0x74, 0x72, 0x69, 0x63, 0x74, 0x46,
+	0x71, 0x62, 0x6e, 0x12, 0x27, 0x0a, 0x0f, 0x77, 0x72, 0x69, 0x74, 0x65, 0x5f, 0x63, 0x68, 0x65,
+	0x63, 0x6b, 0x73, 0x75, 0x6d, 0x73, 0x18, 0x31, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x77, 0x72,
+	0x69, 0x74, 0x65, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x73, 0x12, 0x66, 0x0a, 0x16,
+	0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x6f, 0x70, 0x74, 0x69, 0x6d, 0x69,
+	0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x32, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x30, 0x2e, 0x63,
+	0x63, 0x2e, 0x61, 0x72, 0x64, 0x75, 0x69, 0x6e, 0x6f, 0x2e, 0x63, 0x6c, 0x69, 0x2e, 0x63, 0x6f,
+	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x6e, 0x6b, 0x54, 0x69,
+	0x6d, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6d, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x14,
+	0x6c, 0x69, 0x6e, 0x6b, 0x54, 0x69, 0x6d, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6d, 0x69, 0x7a, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x5f, 0x66, 0x69,
+	0x6c, 0x65, 0x18, 0x33, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x46,
+	0x69, 0x6c, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x66, 0x69, 0x6c, 0x65,
+	0x18, 0x34, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x61, 0x69, 0x6e, 0x46, 0x69, 0x6c, 0x65,
+	0x12, 0x1f, 0x0a, 0x0b, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x5f, 0x64, 0x65, 0x70, 0x73, 0x18,
+	0x35, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x44, 0x65, 0x70,
+	0x73, 0x12, 0x21, 0x0a, 0x0c, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x5f, 0x64, 0x65, 0x66, 0x69, 0x6e,
+	0x65, 0x18, 0x36, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x44, 0x65,
+	0x66, 0x69, 0x6e, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x62, 0x6f, 0x6f, 0x74, 0x6c, 0x6f, 0x61, 0x64,
+	0x65, 0x72, 0x18, 0x37, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x62, 0x6f, 0x6f, 0x74, 0x6c, 0x6f,
+	0x61, 0x64, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x72, 0x61, 0x63, 0x65, 0x18, 0x38, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x05, 0x74, 0x72, 0x61, 0x63, 0x65, 0x12, 0x36, 0x0a, 0x17, 0x61, 0x6c,
+	0x6c, 0x6f, 0x77, 0x65, 0x64, 0x5f, 0x6c, 0x69, 0x62, 0x72, 0x61, 0x72, 0x79, 0x5f, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x73, 0x18, 0x39, 0x20, 0x03, 0x28, 0x09, 0x52, 0x15, 0x61, 0x6c, 0x6c,
+	0x6f, 0x77, 0x65, 0x64, 0x4c, 0x69, 0x62, 0x72, 0x61, 0x72, 0x79, 0x53, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x73, 0x12, 0x42, 0x0a, 0x1d, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x63,
+	0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x62,
+	0x61, 0x73, 0x65, 0x18, 0x3a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x1b, 0x76, 0x61, 0x6c, 0x69, 0x64,
+	0x61, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x61,
+	0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x12, 0x22, 0x0a, 0x0d, 0x6e, 0x6f, 0x5f, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x3b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x6e,
+	0x6f, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x30, 0x0a, 0x14, 0x6f, 0x6e,
+	0x6c, 0x79, 0x5f, 0x6e, 0x65, 0x77, 0x5f, 0x64, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69,
+	0x63, 0x73, 0x18, 0x3c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x12, 0x6f, 0x6e, 0x6c, 0x79, 0x4e, 0x65,
+	0x77, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x73, 0x12, 0x17, 0x0a, 0x07,
+	0x63, 0x6f, 0x70, 0x79, 0x5f, 0x74, 0x6f, 0x18, 0x3d, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x63,
+	0x6f, 0x70, 0x79, 0x54, 0x6f, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65,
+	0x18, 0x3e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x12,
+	0x1f, 0x0a, 0x0b, 0x73, 0x6b, 0x65, 0x74, 0x63, 0x68, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x3f,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x6b, 0x65, 0x74, 0x63, 0x68, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x1f, 0x0a, 0x0b, 0x76, 0x65, 0x72, 0x69, 0x66, 0x79, 0x5f, 0x74, 0x6f, 0x6f, 0x6c, 0x18,
+	0x40, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x76, 0x65, 0x72, 0x69, 0x66, 0x79, 0x54, 0x6f, 0x6f,
+	0x6c, 0x12, 0x28, 0x0a, 0x10, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x5f, 0x64, 0x65, 0x70, 0x5f,
+	0x67, 0x72, 0x61, 0x70, 0x68, 0x18, 0x41, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x65, 0x78, 0x70,
+	0x6f, 0x72, 0x74, 0x44, 0x65, 0x70, 0x47, 0x72, 0x61, 0x70, 0x68, 0x12, 0x23, 0x0a, 0x0d, 0x63,
+	0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x65, 0x72, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x42, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0c, 0x63, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x65, 0x72, 0x50, 0x61, 0x74, 0x68,
+	0x12, 0x29, 0x0a, 0x10, 0x65, 0x78, 0x70, 0x6c, 0x61, 0x69, 0x6e, 0x5f, 0x70, 0x72, 0x6f, 0x70,
+	0x65, 0x72, 0x74, 0x79, 0x18, 0x43, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x65, 0x78, 0x70, 0x6c,
+	0x61, 0x69, 0x6e, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x79, 0x12, 0x27, 0x0a, 0x0f, 0x73,
+	0x6b, 0x65, 0x74, 0x63, 0x68, 0x5f, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x44,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x73, 0x6b, 0x65, 0x74, 0x63, 0x68, 0x45, 0x6e, 0x63, 0x6f,
+	0x64, 0x69, 0x6e, 0x67, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x72, 0x65, 0x5f,
+	0x74, 0x6f, 0x18, 0x45, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x72,
+	0x65, 0x54, 0x6f, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x66,
+	0x69, 0x6c, 0x65, 0x73, 0x18, 0x46, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x78, 0x63, 0x6c,
+	0x75, 0x64, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x75, 0x6d, 0x70,
+	0x5f, 0x64, 0x65, 0x66, 0x69, 0x6e, 0x65, 0x73, 0x18, 0x47, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b,
+	0x64, 0x75, 0x6d, 0x70, 0x44, 0x65, 0x66, 0x69, 0x6e, 0x65, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x62,
+	0x75, 0x69, 0x6c, 0x64, 0x65, 0x72, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x48, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x65, 0x72, 0x50, 0x61, 0x74, 0x68, 0x12, 0x1f,
+	0x0a, 0x0b, 0x69, 0x64, 0x65, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x49, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x69, 0x64, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x1d, 0x0a, 0x0a, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x66, 0x6c, 0x61, 0x67, 0x73, 0x18, 0x4a, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x12, 0x18,
+	0x0a, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x18, 0x4b, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x2b, 0x0a, 0x11, 0x64, 0x65, 0x74, 0x61,
+	0x69, 0x6c, 0x65, 0x64, 0x5f, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x4c, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x10, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x50, 0x72, 0x6f,
+	0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x64, 0x35, 0x5f, 0x63, 0x68, 0x65,
+	0x63, 0x6b, 0x73, 0x75, 0x6d, 0x73, 0x18, 0x4d, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x6d, 0x64,
+	0x35, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x76, 0x65,
+	0x72, 0x69, 0x66, 0x79, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x4e, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0a, 0x76, 0x65, 0x72, 0x69, 0x66, 0x79, 0x53, 0x69, 0x7a, 0x65, 0x1a, 0x41, 0x0a, 0x13, 0x53,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xeb,
+	0x01, 0x0a, 0x0f, 0x43, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x1f, 0x0a, 0x0a, 0x6f, 0x75, 0x74, 0x5f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x09, 0x6f, 0x75, 0x74, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x12, 0x1f, 0x0a, 0x0a, 0x65, 0x72, 0x72, 0x5f, 0x73, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x09, 0x65, 0x72, 0x72, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x12, 0x46, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x63, 0x63, 0x2e, 0x61, 0x72, 0x64, 0x75,
+	0x69, 0x6e, 0x6f, 0x2e, 0x63, 0x6c, 0x69, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73,
+	0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73,
+	0x48, 0x00, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x43, 0x0a, 0x06,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x63,
+	0x63, 0x2e, 0x61, 0x72, 0x64, 0x75, 0x69, 0x6e, 0x6f, 0x2e, 0x63, 0x6c, 0x69, 0x2e, 0x63, 0x6f,
+	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x65,
+	0x72, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x48, 0x00, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x42, 0x09, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x9c, 0x08, 0x0a,
+	0x0d, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x65, 0x72, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x1d,
+	0x0a, 0x0a, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x50, 0x61, 0x74, 0x68, 0x12, 0x4a, 0x0a,
+	0x0e, 0x75, 0x73, 0x65, 0x64, 0x5f, 0x6c, 0x69, 0x62, 0x72, 0x61, 0x72, 0x69, 0x65, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x63, 0x63, 0x2e, 0x61, 0x72, 0x64, 0x75, 0x69,
+	0x6e, 0x6f, 0x2e, 0x63, 0x6c, 0x69, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x2e,
+	0x76, 0x31, 0x2e, 0x4c, 0x69, 0x62, 0x72, 0x61, 0x72, 0x79, 0x52, 0x0d, 0x75, 0x73, 0x65, 0x64,
+	0x4c, 0x69, 0x62, 0x72, 0x61, 0x72, 0x69, 0x65, 0x73, 0x12, 0x6b, 0x0a, 0x18, 0x65, 0x78, 0x65,
+	0x63, 0x75, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x73, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x31, 0x2e, 0x63, 0x63,
+	0x2e, 0x61, 0x72, 0x64, 0x75, 0x69, 0x6e, 0x6f, 0x2e, 0x63, 0x6c, 0x69, 0x2e, 0x63, 0x6f, 0x6d,
+	0x6d, 0x61, 0x6e, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x61,
+	0x62, 0x6c, 0x65, 0x53, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x69, 0x7a, 0x65, 0x52, 0x16,
+	0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x5d, 0x0a, 0x0e, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x5f,
+	0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x36,
 	0x2e, 0x63, 0x63, 0x2e, 0x61, 0x72, 0x64, 0x75, 0x69, 0x6e, 0x6f, 0x2e, 0x63, 0x6c, 0x69, 0x2e,
-	0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x75, 0x69, 0x6c,
-	0x64, 0x65, 0x72, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x48, 0x00, 0x52, 0x06, 0x72, 0x65, 0x73,
-	0x75, 0x6c, 0x74, 0x42, 0x09, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0xa1,
-	0x04, 0x0a, 0x0d, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x65, 0x72, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
-	0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x50, 0x61, 0x74, 0x68, 0x12,
-	0x4a, 0x0a, 0x0e, 0x75, 0x73, 0x65, 0x64, 0x5f, 0x6c, 0x69, 0x62, 0x72, 0x61, 0x72, 0x69, 0x65,
-	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x63, 0x63, 0x2e, 0x61, 0x72, 0x64,
-	0x75, 0x69, 0x6e, 0x6f, 0x2e, 0x63, 0x6c, 0x69, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
-	0x73, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x62, 0x72, 0x61, 0x72, 0x79, 0x52, 0x0d, 0x75, 0x73,
-	0x65, 0x64, 0x4c, 0x69, 0x62, 0x72, 0x61, 0x72, 0x69, 0x65, 0x73, 0x12, 0x6b, 0x0a, 0x18, 0x65,
-	0x78, 0x65, 0x63, 0x75, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x73, 0x65, 0x63, 0x74, 0x69, 0x6f,
-	0x6e, 0x73, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x31, 0x2e,
+	0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x73, 0x74,
+	0x61, 0x6c, 0x6c, 0x65, 0x64, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x52, 0x65, 0x66,
+	0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x0d, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x50, 0x6c, 0x61,
+	0x74, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x5d, 0x0a, 0x0e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x70,
+	0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x36, 0x2e,
 	0x63, 0x63, 0x2e, 0x61, 0x72, 0x64, 0x75, 0x69, 0x6e, 0x6f, 0x2e, 0x63, 0x6c, 0x69, 0x2e, 0x63,
-	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x75,
-	0x74, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x69, 0x7a, 0x65,
-	0x52, 0x16, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x65, 0x63, 0x74,
-	0x69, 0x6f, 0x6e, 0x73, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x5d, 0x0a, 0x0e, 0x62, 0x6f, 0x61, 0x72,
-	0x64, 0x5f, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x36, 0x2e, 0x63, 0x63, 0x2e, 0x61, 0x72, 0x64, 0x75, 0x69, 0x6e, 0x6f, 0x2e, 0x63, 0x6c,
-	0x69, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e,
-	0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x52,
-	0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x0d, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x50,
-	0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x5d, 0x0a, 0x0e, 0x62, 0x75, 0x69, 0x6c, 0x64,
-	0x5f, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x36, 0x2e, 0x63, 0x63, 0x2e, 0x61, 0x72, 0x64, 0x75, 0x69, 0x6e, 0x6f, 0x2e, 0x63, 0x6c, 0x69,
-	0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x73,
-	0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x52, 0x65,
-	0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x0d, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x50, 0x6c,
-	0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x29, 0x0a, 0x10, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f,
-	0x70, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x69, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09,
-	0x52, 0x0f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x69, 0x65,
-	0x73, 0x12, 0x4f, 0x0a, 0x0b, 0x64, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x73,
-	0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x63, 0x63, 0x2e, 0x61, 0x72, 0x64, 0x75,
+	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x61,
+	0x6c, 0x6c, 0x65, 0x64, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x52, 0x65, 0x66, 0x65,
+	0x72, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x0d, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x50, 0x6c, 0x61, 0x74,
+	0x66, 0x6f, 0x72, 0x6d, 0x12, 0x29, 0x0a, 0x10, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x70, 0x72,
+	0x6f, 0x70, 0x65, 0x72, 0x74, 0x69, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0f,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x69, 0x65, 0x73, 0x12,
+	0x4f, 0x0a, 0x0b, 0x64, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x73, 0x18, 0x08,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x63, 0x63, 0x2e, 0x61, 0x72, 0x64, 0x75, 0x69, 0x6e,
+	0x6f, 0x2e, 0x63, 0x6c, 0x69, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x2e, 0x76,
+	0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x65, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73,
+	0x74, 0x69, 0x63, 0x52, 0x0b, 0x64, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x73,
+	0x12, 0x47, 0x0a, 0x09, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x18, 0x09, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x63, 0x63, 0x2e, 0x61, 0x72, 0x64, 0x75, 0x69, 0x6e, 0x6f,
+	0x2e, 0x63, 0x6c, 0x69, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x2e, 0x76, 0x31,
+	0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x52, 0x09,
+	0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x12, 0x24, 0x0a, 0x0e, 0x63, 0x6f, 0x72,
+	0x65, 0x5f, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0c, 0x63, 0x6f, 0x72, 0x65, 0x43, 0x61, 0x63, 0x68, 0x65, 0x4b, 0x65, 0x79, 0x12,
+	0x23, 0x0a, 0x0d, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x73,
+	0x18, 0x0b, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x50,
+	0x61, 0x74, 0x68, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x75, 0x73, 0x65, 0x64, 0x5f, 0x74, 0x6f, 0x6f,
+	0x6c, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x75, 0x73, 0x65, 0x64, 0x54, 0x6f,
+	0x6f, 0x6c, 0x73, 0x12, 0x5b, 0x0a, 0x12, 0x70, 0x72, 0x65, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73,
+	0x73, 0x65, 0x64, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x2c, 0x2e, 0x63, 0x63, 0x2e, 0x61, 0x72, 0x64, 0x75, 0x69, 0x6e, 0x6f, 0x2e, 0x63, 0x6c, 0x69,
+	0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x65,
+	0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x65, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x11, 0x70,
+	0x72, 0x65, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x65, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73,
+	0x12, 0x21, 0x0a, 0x0c, 0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6c, 0x69, 0x6e, 0x6b, 0x43, 0x6f, 0x6d, 0x6d,
+	0x61, 0x6e, 0x64, 0x12, 0x62, 0x0a, 0x14, 0x70, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x79, 0x5f,
+	0x65, 0x78, 0x70, 0x6c, 0x61, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0f, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x2f, 0x2e, 0x63, 0x63, 0x2e, 0x61, 0x72, 0x64, 0x75, 0x69, 0x6e, 0x6f, 0x2e, 0x63,
+	0x6c, 0x69, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50,
+	0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x79, 0x45, 0x78, 0x70, 0x6c, 0x61, 0x6e, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x13, 0x70, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x79, 0x45, 0x78, 0x70, 0x6c,
+	0x61, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x66, 0x69, 0x6e,
+	0x65, 0x73, 0x18, 0x10, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x64, 0x65, 0x66, 0x69, 0x6e, 0x65,
+	0x73, 0x12, 0x48, 0x0a, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x18, 0x11, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x63, 0x63, 0x2e, 0x61, 0x72, 0x64, 0x75, 0x69, 0x6e, 0x6f, 0x2e,
+	0x63, 0x6c, 0x69, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e,
+	0x43, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x75, 0x6d, 0x6d, 0x61,
+	0x72, 0x79, 0x52, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x22, 0x97, 0x02, 0x0a, 0x12,
+	0x43, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x75, 0x6d, 0x6d, 0x61,
+	0x72, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x63, 0x6f, 0x6d, 0x6d,
+	0x61, 0x6e, 0x64, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x74, 0x6f, 0x74, 0x61,
+	0x6c, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x75, 0x6e, 0x69,
+	0x71, 0x75, 0x65, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0b, 0x75, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x72, 0x0a, 0x12,
+	0x66, 0x69, 0x6c, 0x65, 0x73, 0x5f, 0x62, 0x79, 0x5f, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x44, 0x2e, 0x63, 0x63, 0x2e, 0x61, 0x72,
+	0x64, 0x75, 0x69, 0x6e, 0x6f, 0x2e, 0x63, 0x6c, 0x69, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
+	0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x42, 0x79,
+	0x45, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x10,
+	0x66, 0x69, 0x6c, 0x65, 0x73, 0x42, 0x79, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e,
+	0x1a, 0x43, 0x0a, 0x15, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x42, 0x79, 0x45, 0x78, 0x74, 0x65, 0x6e,
+	0x73, 0x69, 0x6f, 0x6e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xbb, 0x01, 0x0a, 0x13, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72,
+	0x74, 0x79, 0x45, 0x78, 0x70, 0x6c, 0x61, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x23, 0x0a, 0x0d, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x56,
+	0x61, 0x6c, 0x75, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x14, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x65, 0x78, 0x70, 0x61, 0x6e, 0x64, 0x65, 0x64, 0x5f, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x65, 0x78, 0x70, 0x61,
+	0x6e, 0x64, 0x65, 0x64, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x66,
+	0x69, 0x6e, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x64, 0x65, 0x66, 0x69,
+	0x6e, 0x65, 0x64, 0x22, 0x63, 0x0a, 0x10, 0x50, 0x72, 0x65, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73,
+	0x73, 0x65, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x50, 0x61, 0x74, 0x68, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65,
+	0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x75, 0x0a, 0x0d, 0x42, 0x75, 0x69, 0x6c,
+	0x64, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74,
+	0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x12, 0x0a,
+	0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x73, 0x69, 0x7a,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x12, 0x10, 0x0a,
+	0x03, 0x6d, 0x64, 0x35, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6d, 0x64, 0x35, 0x22,
+	0x5a, 0x0a, 0x15, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x65, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65,
+	0x12, 0x19, 0x0a, 0x08, 0x6d, 0x61, 0x78, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x07, 0x6d, 0x61, 0x78, 0x53, 0x69, 0x7a, 0x65, 0x22, 0xa2, 0x02, 0x0a, 0x11,
+	0x43, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x65, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69,
+	0x63, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x12, 0x18, 0x0a,
+	0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6c,
+	0x69, 0x6e, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x6c, 0x69, 0x6e, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x12, 0x4e, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65,
+	0x78, 0x74, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x34, 0x2e, 0x63, 0x63, 0x2e, 0x61, 0x72,
+	0x64, 0x75, 0x69, 0x6e, 0x6f, 0x2e, 0x63, 0x6c, 0x69, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
+	0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x65, 0x44, 0x69, 0x61,
+	0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x52, 0x07,
+	0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x12, 0x47, 0x0a, 0x05, 0x6e, 0x6f, 0x74, 0x65, 0x73,
+	0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x31, 0x2e, 0x63, 0x63, 0x2e, 0x61, 0x72, 0x64, 0x75,
 	0x69, 0x6e, 0x6f, 0x2e, 0x63, 0x6c, 0x69, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73,
 	0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x65, 0x44, 0x69, 0x61, 0x67, 0x6e,
-	0x6f, 0x73, 0x74, 0x69, 0x63, 0x52, 0x0b, 0x64, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69,
-	0x63, 0x73, 0x22, 0x5a, 0x0a, 0x15, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x61, 0x62, 0x6c, 0x65,
-	0x53, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e,
-	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
-	0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x73,
-	0x69, 0x7a, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x61, 0x78, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x6d, 0x61, 0x78, 0x53, 0x69, 0x7a, 0x65, 0x22, 0xa2,
-	0x02, 0x0a, 0x11, 0x43, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x65, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f,
-	0x73, 0x74, 0x69, 0x63, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79,
-	0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x69,
-	0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x12,
-	0x0a, 0x04, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x6c, 0x69,
-	0x6e, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x18, 0x05, 0x20, 0x01,
-	0x28, 0x03, 0x52, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x12, 0x4e, 0x0a, 0x07, 0x63, 0x6f,
-	0x6e, 0x74, 0x65, 0x78, 0x74, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x34, 0x2e, 0x63, 0x63,
-	0x2e, 0x61, 0x72, 0x64, 0x75, 0x69, 0x6e, 0x6f, 0x2e, 0x63, 0x6c, 0x69, 0x2e, 0x63, 0x6f, 0x6d,
-	0x6d, 0x61, 0x6e, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x65,
-	0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78,
-	0x74, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x12, 0x47, 0x0a, 0x05, 0x6e, 0x6f,
-	0x74, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x31, 0x2e, 0x63, 0x63, 0x2e, 0x61,
-	0x72, 0x64, 0x75, 0x69, 0x6e, 0x6f, 0x2e, 0x63, 0x6c, 0x69, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x61,
-	0x6e, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x65, 0x44, 0x69,
-	0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x4e, 0x6f, 0x74, 0x65, 0x52, 0x05, 0x6e, 0x6f,
-	0x74, 0x65, 0x73, 0x22, 0x74, 0x0a, 0x18, 0x43, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x65, 0x44, 0x69,
-	0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x12,
+	0x6f, 0x73, 0x74, 0x69, 0x63, 0x4e, 0x6f, 0x74, 0x65, 0x52, 0x05, 0x6e, 0x6f, 0x74, 0x65, 0x73,
+	0x22, 0x74, 0x0a, 0x18, 0x43, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x65, 0x44, 0x69, 0x61, 0x67, 0x6e,
+	0x6f, 0x73, 0x74, 0x69, 0x63, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x12, 0x18, 0x0a, 0x07,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x69,
+	0x6e, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x6c, 0x69, 0x6e, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06,
+	0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x22, 0x71, 0x0a, 0x15, 0x43, 0x6f, 0x6d, 0x70, 0x69, 0x6c,
+	0x65, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x4e, 0x6f, 0x74, 0x65, 0x12,
 	0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
 	0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x69, 0x6c,
 	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x12, 0x0a,
 	0x04, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x6c, 0x69, 0x6e,
 	0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x03, 0x52, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x22, 0x71, 0x0a, 0x15, 0x43, 0x6f, 0x6d,
-	0x70, 0x69, 0x6c, 0x65, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x4e, 0x6f,
-	0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04,
-	0x66, 0x69, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65,
-	0x12, 0x12, 0x0a, 0x04, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04,
-	0x6c, 0x69, 0x6e, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x42, 0x48, 0x5a, 0x46,
-	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x72, 0x64, 0x75, 0x69,
-	0x6e, 0x6f, 0x2f, 0x61, 0x72, 0x64, 0x75, 0x69, 0x6e, 0x6f, 0x2d, 0x63, 0x6c, 0x69, 0x2f, 0x72,
-	0x70, 0x63, 0x2f, 0x63, 0x63, 0x2f, 0x61, 0x72, 0x64, 0x75, 0x69, 0x6e, 0x6f, 0x2f, 0x63, 0x6c,
-	0x69, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x2f, 0x76, 0x31, 0x3b, 0x63, 0x6f,
-	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x03, 0x52, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x2a, 0x83, 0x01, 0x0a, 0x14, 0x4c, 0x69,
+	0x6e, 0x6b, 0x54, 0x69, 0x6d, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6d, 0x69, 0x7a, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x1e, 0x4c, 0x49, 0x4e, 0x4b, 0x5f, 0x54, 0x49, 0x4d, 0x45, 0x5f,
+	0x4f, 0x50, 0x54, 0x49, 0x4d, 0x49, 0x5a, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x44, 0x45, 0x46,
+	0x41, 0x55, 0x4c, 0x54, 0x10, 0x00, 0x12, 0x22, 0x0a, 0x1e, 0x4c, 0x49, 0x4e, 0x4b, 0x5f, 0x54,
+	0x49, 0x4d, 0x45, 0x5f, 0x4f, 0x50, 0x54, 0x49, 0x4d, 0x49, 0x5a, 0x41, 0x54, 0x49, 0x4f, 0x4e,
+	0x5f, 0x45, 0x4e, 0x41, 0x42, 0x4c, 0x45, 0x44, 0x10, 0x01, 0x12, 0x23, 0x0a, 0x1f, 0x4c, 0x49,
+	0x4e, 0x4b, 0x5f, 0x54, 0x49, 0x4d, 0x45, 0x5f, 0x4f, 0x50, 0x54, 0x49, 0x4d, 0x49, 0x5a, 0x41,
+	0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x44, 0x49, 0x53, 0x41, 0x42, 0x4c, 0x45, 0x44, 0x10, 0x02, 0x42,
+	0x48, 0x5a, 0x46, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x72,
+	0x64, 0x75, 0x69, 0x6e, 0x6f, 0x2f, 0x61, 0x72, 0x64, 0x75, 0x69, 0x6e, 0x6f, 0x2d, 0x63, 0x6c,
+	0x69, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x63, 0x63, 0x2f, 0x61, 0x72, 0x64, 0x75, 0x69, 0x6e, 0x6f,
+	0x2f, 0x63, 0x6c, 0x69, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x2f, 0x76, 0x31,
+	0x3b, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
 }
 
 var (
@@ -1043,40 +2230,53 @@ func file_cc_arduino_cli_commands_v1_compile_proto_rawDescGZIP() []byte {
 	return file_cc_arduino_cli_commands_v1_compile_proto_rawDescData
 }
 
-var file_cc_arduino_cli_commands_v1_compile_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_cc_arduino_cli_commands_v1_compile_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_cc_arduino_cli_commands_v1_compile_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_cc_arduino_cli_commands_v1_compile_proto_goTypes = []interface{}{
-	(*CompileRequest)(nil),             // 0: cc.arduino.cli.commands.v1.CompileRequest
-	(*CompileResponse)(nil),            // 1: cc.arduino.cli.commands.v1.CompileResponse
-	(*BuilderResult)(nil),              // 2: cc.arduino.cli.commands.v1.BuilderResult
-	(*ExecutableSectionSize)(nil),      // 3: cc.arduino.cli.commands.v1.ExecutableSectionSize
-	(*CompileDiagnostic)(nil),          // 4: cc.arduino.cli.commands.v1.CompileDiagnostic
-	(*CompileDiagnosticContext)(nil),   // 5: cc.arduino.cli.commands.v1.CompileDiagnosticContext
-	(*CompileDiagnosticNote)(nil),      // 6: cc.arduino.cli.commands.v1.CompileDiagnosticNote
-	nil,                                // 7: cc.arduino.cli.commands.v1.CompileRequest.SourceOverrideEntry
-	(*Instance)(nil),                   // 8: cc.arduino.cli.commands.v1.Instance
-	(*wrapperspb.BoolValue)(nil),       // 9: google.protobuf.BoolValue
-	(*TaskProgress)(nil),               // 10: cc.arduino.cli.commands.v1.TaskProgress
-	(*Library)(nil),                    // 11: cc.arduino.cli.commands.v1.Library
-	(*InstalledPlatformReference)(nil), // 12: cc.arduino.cli.commands.v1.InstalledPlatformReference
+	(LinkTimeOptimization)(0),          // 0: cc.arduino.cli.commands.v1.LinkTimeOptimization
+	(*CompileRequest)(nil),             // 1: cc.arduino.cli.commands.v1.CompileRequest
+	(*CompileResponse)(nil),            // 2: cc.arduino.cli.commands.v1.CompileResponse
+	(*BuilderResult)(nil),              // 3: cc.arduino.cli.commands.v1.BuilderResult
+	(*CompilationSummary)(nil),         // 4: cc.arduino.cli.commands.v1.CompilationSummary
+	(*PropertyExplanation)(nil),        // 5: cc.arduino.cli.commands.v1.PropertyExplanation
+	(*PreprocessedFile)(nil),           // 6: cc.arduino.cli.commands.v1.PreprocessedFile
+	(*BuildArtifact)(nil),              // 7: cc.arduino.cli.commands.v1.BuildArtifact
+	(*ExecutableSectionSize)(nil),      // 8: cc.arduino.cli.commands.v1.ExecutableSectionSize
+	(*CompileDiagnostic)(nil),          // 9: cc.arduino.cli.commands.v1.CompileDiagnostic
+	(*CompileDiagnosticContext)(nil),   // 10: cc.arduino.cli.commands.v1.CompileDiagnosticContext
+	(*CompileDiagnosticNote)(nil),      // 11: cc.arduino.cli.commands.v1.CompileDiagnosticNote
+	nil,                                // 12: cc.arduino.cli.commands.v1.CompileRequest.SourceOverrideEntry
+	nil,                                // 13: cc.arduino.cli.commands.v1.CompilationSummary.FilesByExtensionEntry
+	(*Instance)(nil),                   // 14: cc.arduino.cli.commands.v1.Instance
+	(*wrapperspb.BoolValue)(nil),       // 15: google.protobuf.BoolValue
+	(*TaskProgress)(nil),               // 16: cc.arduino.cli.commands.v1.TaskProgress
+	(*Library)(nil),                    // 17: cc.arduino.cli.commands.v1.Library
+	(*InstalledPlatformReference)(nil), // 18: cc.arduino.cli.commands.v1.InstalledPlatformReference
 }
 var file_cc_arduino_cli_commands_v1_compile_proto_depIdxs = []int32{
-	8,  // 0: cc.arduino.cli.commands.v1.CompileRequest.instance:type_name -> cc.arduino.cli.commands.v1.Instance
-	7,  // 1: cc.arduino.cli.commands.v1.CompileRequest.source_override:type_name -> cc.arduino.cli.commands.v1.CompileRequest.SourceOverrideEntry
-	9,  // 2: cc.arduino.cli.commands.v1.CompileRequest.export_binaries:type_name -> google.protobuf.BoolValue
-	10, // 3: cc.arduino.cli.commands.v1.CompileResponse.progress:type_name -> cc.arduino.cli.commands.v1.TaskProgress
-	2,  // 4: cc.arduino.cli.commands.v1.CompileResponse.result:type_name -> cc.arduino.cli.commands.v1.BuilderResult
-	11, // 5: cc.arduino.cli.commands.v1.BuilderResult.used_libraries:type_name -> cc.arduino.cli.commands.v1.Library
-	3,  // 6: cc.arduino.cli.commands.v1.BuilderResult.executable_sections_size:type_name -> cc.arduino.cli.commands.v1.ExecutableSectionSize
-	12, // 7: cc.arduino.cli.commands.v1.BuilderResult.board_platform:type_name -> cc.arduino.cli.commands.v1.InstalledPlatformReference
-	12, // 8: cc.arduino.cli.commands.v1.BuilderResult.build_platform:type_name -> cc.arduino.cli.commands.v1.InstalledPlatformReference
-	4,  // 9: cc.arduino.cli.commands.v1.BuilderResult.diagnostics:type_name -> cc.arduino.cli.commands.v1.CompileDiagnostic
-	5,  // 10: cc.arduino.cli.commands.v1.CompileDiagnostic.context:type_name -> cc.arduino.cli.commands.v1.CompileDiagnosticContext
-	6,  // 11: cc.arduino.cli.commands.v1.CompileDiagnostic.notes:type_name -> cc.arduino.cli.commands.v1.CompileDiagnosticNote
-	12, // [12:12] is the sub-list for method output_type
-	12, // [12:12] is the sub-list for method input_type
-	12, // [12:12] is the sub-list for extension type_name
-	12, // [12:12] is the sub-list for extension extendee
-	0,  // [0:12] is the sub-list for field type_name
+	14, // 0: cc.arduino.cli.commands.v1.CompileRequest.instance:type_name -> cc.arduino.cli.commands.v1.Instance
+	12, // 1: cc.arduino.cli.commands.v1.CompileRequest.source_override:type_name -> cc.arduino.cli.commands.v1.CompileRequest.SourceOverrideEntry
+	15, // 2: cc.arduino.cli.commands.v1.CompileRequest.export_binaries:type_name -> google.protobuf.BoolValue
+	0,  // 3: cc.arduino.cli.commands.v1.CompileRequest.link_time_optimization:type_name -> cc.arduino.cli.commands.v1.LinkTimeOptimization
+	16, // 4: cc.arduino.cli.commands.v1.CompileResponse.progress:type_name -> cc.arduino.cli.commands.v1.TaskProgress
+	3,  // 5: cc.arduino.cli.commands.v1.CompileResponse.result:type_name -> cc.arduino.cli.commands.v1.BuilderResult
+	17, // 6: cc.arduino.cli.commands.v1.BuilderResult.used_libraries:type_name -> cc.arduino.cli.commands.v1.Library
+	8,  // 7: cc.arduino.cli.commands.v1.BuilderResult.executable_sections_size:type_name -> cc.arduino.cli.commands.v1.ExecutableSectionSize
+	18, // 8: cc.arduino.cli.commands.v1.BuilderResult.board_platform:type_name -> cc.arduino.cli.commands.v1.InstalledPlatformReference
+	18, // 9: cc.arduino.cli.commands.v1.BuilderResult.build_platform:type_name -> cc.arduino.cli.commands.v1.InstalledPlatformReference
+	9,  // 10: cc.arduino.cli.commands.v1.BuilderResult.diagnostics:type_name -> cc.arduino.cli.commands.v1.CompileDiagnostic
+	7,  // 11: cc.arduino.cli.commands.v1.BuilderResult.artifacts:type_name -> cc.arduino.cli.commands.v1.BuildArtifact
+	6,  // 12: cc.arduino.cli.commands.v1.BuilderResult.preprocessed_files:type_name -> cc.arduino.cli.commands.v1.PreprocessedFile
+	5,  // 13: cc.arduino.cli.commands.v1.BuilderResult.property_explanation:type_name -> cc.arduino.cli.commands.v1.PropertyExplanation
+	4,  // 14: cc.arduino.cli.commands.v1.BuilderResult.summary:type_name -> cc.arduino.cli.commands.v1.CompilationSummary
+	13, // 15: cc.arduino.cli.commands.v1.CompilationSummary.files_by_extension:type_name -> cc.arduino.cli.commands.v1.CompilationSummary.FilesByExtensionEntry
+	10, // 16: cc.arduino.cli.commands.v1.CompileDiagnostic.context:type_name -> cc.arduino.cli.commands.v1.CompileDiagnosticContext
+	11, // 17: cc.arduino.cli.commands.v1.CompileDiagnostic.notes:type_name -> cc.arduino.cli.commands.v1.CompileDiagnosticNote
+	18, // [18:18] is the sub-list for method output_type
+	18, // [18:18] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
 }
 
 func init() { file_cc_arduino_cli_commands_v1_compile_proto_init() }
@@ -1124,7 +2324,7 @@ func file_cc_arduino_cli_commands_v1_compile_proto_init() {
 			}
 		}
 		file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ExecutableSectionSize); i {
+			switch v := v.(*CompilationSummary); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1136,7 +2336,7 @@ func file_cc_arduino_cli_commands_v1_compile_proto_init() {
 			}
 		}
 		file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CompileDiagnostic); i {
+			switch v := v.(*PropertyExplanation); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1148,7 +2348,7 @@ func file_cc_arduino_cli_commands_v1_compile_proto_init() {
 			}
 		}
 		file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CompileDiagnosticContext); i {
+			switch v := v.(*PreprocessedFile); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1160,6 +2360,54 @@ func file_cc_arduino_cli_commands_v1_compile_proto_init() {
 			}
 		}
 		file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BuildArtifact); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecutableSectionSize); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CompileDiagnostic); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CompileDiagnosticContext); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cc_arduino_cli_commands_v1_compile_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*CompileDiagnosticNote); i {
 			case 0:
 				return &v.state
@@ -1183,13 +2431,14 @@ func file_cc_arduino_cli_commands_v1_compile_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_cc_arduino_cli_commands_v1_compile_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   8,
+			NumEnums:      1,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_cc_arduino_cli_commands_v1_compile_proto_goTypes,
 		DependencyIndexes: file_cc_arduino_cli_commands_v1_compile_proto_depIdxs,
+		EnumInfos:         file_cc_arduino_cli_commands_v1_compile_proto_enumTypes,
 		MessageInfos:      file_cc_arduino_cli_commands_v1_compile_proto_msgTypes,
 	}.Build()
 	File_cc_arduino_cli_commands_v1_compile_proto = out.File